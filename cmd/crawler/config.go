@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// applyConfigFile sets the arguments not given in the command line from the JSON config file at the
+// given path. Its keys are the names of the arguments, and the lists of the comma separated arguments
+// can be given as JSON arrays. The keys setting up the crawl are the ones config.Load reads, and the
+// others, like the ones of the session, the outputs and the audits, are only arguments of the command line.
+func applyConfigFile(configFileArg string) {
+	content, err := os.ReadFile(configFileArg)
+	if err != nil {
+		log.Fatalf("argument error: invalid config. %v\n", err)
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(content, &values); err != nil {
+		log.Fatalf("argument error: invalid config. %v\n", err)
+	}
+
+	givenArgs := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		givenArgs[f.Name] = true
+	})
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == "config" || flag.Lookup(key) == nil {
+			log.Fatalf("argument error: invalid config. unknown key %s\n", key)
+		}
+		if givenArgs[key] {
+			continue
+		}
+		argValues := []string{configValue(values[key])}
+		// the arguments given many times take the values of the list one by one
+		if _, repeatable := flag.Lookup(key).Value.(*repeatedFlags); repeatable {
+			if err := json.Unmarshal(values[key], &argValues); err != nil {
+				log.Fatalf("argument error: invalid %s in config. must be a list. %v\n", key, err)
+			}
+		}
+		for _, argValue := range argValues {
+			if err := flag.Set(key, argValue); err != nil {
+				log.Fatalf("argument error: invalid %s in config. %v\n", key, err)
+			}
+		}
+	}
+}
+
+// configValue returns the value of a key of the config file as the value of its argument.
+func configValue(value json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(value, &text); err == nil {
+		return text
+	}
+	var list []string
+	if err := json.Unmarshal(value, &list); err == nil {
+		return strings.Join(list, ",")
+	}
+	return strings.TrimSpace(string(value))
+}
+
+// listArg splits a comma separated argument into the list of its key in a config.Config, without the
+// empty values.
+func listArg(arg string) []string {
+	var values []string
+	for _, value := range strings.Split(arg, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+	"github.com/andiblas/website-crawler/pkg/robots"
+)
+
+// findPages crawls the site until it finds the pages whose URL or HTML matches a pattern, and prints
+// the click path from the seed to each of them. It's run with `crawler find`.
+func findPages(args []string) {
+	flags := flag.NewFlagSet("find", flag.ExitOnError)
+	urlToCrawlArg := flags.String("url", "", "URL to start the search from.")
+	matchArg := flags.String("match", "", "Regular expression matched against the URL and the HTML of every crawled page. example: --match=\"partner\\.com\"")
+	matchesArg := flags.Int("matches", 1, "Number of matching pages after which the search ends. Must be greater than 0.")
+	depthArg := flags.Int("depth", defaultDepth, "Sets the crawling depth. Must be greater than 0.")
+	maxConcurrencyArg := flags.Int("max_concurrency", defaultMaxConcurrency, "Sets the maximum concurrent requests the crawler can do. Must be greater than 0.")
+	timeoutArg := flags.Int("timeout", defaultTimeout, "Please set the timeout in milliseconds. Must be greater than 0.")
+	respectRobotsArg := flags.Bool("respect_robots", true, "Skips the links disallowed by the robots.txt file of their host.")
+	staticDirArg := flags.String("static_dir", "", "Searches the static site in this directory, like the output of a static site generator, served in-process at the host of the url.")
+	_ = flags.Parse(args)
+
+	parsedUrl := validateUrlToCrawl(*urlToCrawlArg)
+	pattern, maxMatches := validateMatch(*matchArg, *matchesArg)
+	depth := validateDepth(*depthArg)
+	maxConcurrency := validateMaxConcurrency(*maxConcurrencyArg)
+	timeout := validateTimeoutArg(*timeoutArg)
+
+	var roundTripper http.RoundTripper = http.DefaultTransport
+	if site := validateStaticDir(*staticDirArg, parsedUrl); site != nil {
+		defer func() { _ = site.Close() }()
+		roundTripper = site.Transport(roundTripper)
+	}
+	httpClient := &http.Client{Timeout: time.Duration(timeout) * time.Millisecond, Transport: roundTripper}
+
+	ctx, cancelFunc := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancelFunc()
+
+	fetcherChain := []fetcher.ChainOption{fetcher.WithContext(ctx), fetcher.WithRetry(defaultNumberOfRetries, time.Second*4, 0)}
+	fileFetcher := fetcher.Chain(fetcher.NewHTTPFetcher(httpClient), fetcherChain...)
+	pageFetcher := &matchingFetcher{
+		innerFetcher: fetcher.Chain(fetcher.NewHTTPFetcher(httpClient, fetcher.WithContentTypes(fetcher.DefaultContentTypes...)), fetcherChain...),
+		pattern:      pattern,
+		matches:      make(map[string]bool),
+	}
+
+	var matches []url.URL
+	crawlerOptions := []crawler.Option{
+		crawler.WithSeedCheck(fetcher.NewHTTPFetcher(httpClient), false),
+		crawler.WithStopWhen(func(result crawler.CrawlResult) bool {
+			if result.Err == nil && (pattern.MatchString(result.URL.String()) || pageFetcher.matched(result.URL)) {
+				matches = append(matches, result.URL)
+			}
+			return len(matches) >= maxMatches
+		}),
+	}
+	if *respectRobotsArg {
+		crawlerOptions = append(crawlerOptions, crawler.WithRobotsPolicy(robots.NewPolicy(fileFetcher, userAgent)))
+	}
+	graph, err := crawler.NewBreadthFirstCrawler(pageFetcher, crawlerOptions...).CrawlWithGraph(ctx, parsedUrl, depth, maxConcurrency)
+	if err != nil {
+		log.Fatalf("error searching %s: %v\n", parsedUrl.String(), err)
+	}
+	if len(matches) == 0 {
+		fmt.Printf("No pages match %q\n", pattern.String())
+		os.Exit(1)
+	}
+	for _, match := range matches {
+		fmt.Printf("[MATCH] %s\n", match.String())
+		fmt.Printf("  %s\n", strings.Join(clickPath(graph, match), " > "))
+	}
+	fmt.Printf("Pages found: %d\n", len(matches))
+}
+
+// matchingFetcher records the pages whose HTML matches the pattern of the search.
+type matchingFetcher struct {
+	innerFetcher fetcher.Fetcher
+	pattern      *regexp.Regexp
+	mu           sync.Mutex
+	matches      map[string]bool
+}
+
+func (f *matchingFetcher) FetchWebpageContent(link url.URL) (io.ReadCloser, error) {
+	result, err := f.FetchContext(context.Background(), link)
+	return result.Body, err
+}
+
+func (f *matchingFetcher) FetchContext(ctx context.Context, link url.URL) (fetcher.FetchResult, error) {
+	result, err := fetcher.FetchContext(ctx, f.innerFetcher, link)
+	if err != nil {
+		return result, err
+	}
+	html, err := io.ReadAll(result.Body)
+	_ = result.Body.Close()
+	if err != nil {
+		return fetcher.FetchResult{StatusCode: result.StatusCode, Header: result.Header, FinalURL: result.FinalURL}, err
+	}
+	if f.pattern.Match(html) {
+		f.mu.Lock()
+		f.matches[link.String()] = true
+		f.mu.Unlock()
+	}
+	result.Body = io.NopCloser(bytes.NewReader(html))
+	return result, nil
+}
+
+func (f *matchingFetcher) matched(link url.URL) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.matches[link.String()]
+}
+
+// clickPath returns the click path from the seed to the page in the link graph of the crawl, starting
+// with the seed as given when it redirected.
+func clickPath(graph *crawler.CrawlGraph, page url.URL) []string {
+	var path []string
+	if graph.SeedRedirect != nil {
+		path = append(path, graph.SeedRedirect.From)
+	}
+	if pagePath := graph.ClickPath(linkextractor.Key(page)); pagePath != nil {
+		return append(path, pagePath...)
+	}
+	return append(path, linkextractor.Key(page))
+}
+
+func validateMatch(matchArg string, matchesArg int) (*regexp.Regexp, int) {
+	if strings.TrimSpace(matchArg) == "" {
+		log.Fatalln("argument error: find requires a pattern to match. example: crawler find --url=https://example.com --match=\"partner\\.com\"")
+	}
+	pattern, err := regexp.Compile(matchArg)
+	if err != nil {
+		log.Fatalf("argument error: invalid match. %v example: --match=\"partner\\.com\"\n", err)
+	}
+	if matchesArg <= 0 {
+		log.Fatalln("argument error: invalid matches. must be greater than 0. example: --matches=3")
+	}
+	return pattern, matchesArg
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/andiblas/website-crawler/pkg/frontier"
+	"github.com/andiblas/website-crawler/pkg/report"
+)
+
+// stateShow prints the progress of the crawl saved in a state file or a Redis server, so an interrupted
+// crawl can be inspected before resuming it. It's run with `crawler state show`.
+func stateShow(args []string) {
+	flags := flag.NewFlagSet("state show", flag.ExitOnError)
+	stateFileArg := flags.String("state_file", "", "Path of the file where the state of the crawl was saved.")
+	redisURLArg := flags.String("redis_url", "", "URL of the Redis server where the state of the crawl is shared. example: redis://localhost:6379/0")
+	redisKeyPrefixArg := flags.String("redis_key_prefix", "website-crawler", "Prefix of the Redis keys where the state of the crawl is saved.")
+	redactArg := flags.Bool("redact", false, "Redacts the URLs of the output and the logs: removes their credentials and replaces the values of their query parameters and the path segments that look like tokens.")
+	_ = flags.Parse(args)
+
+	var output io.Writer = os.Stdout
+	if *redactArg {
+		output = report.NewRedactingOutput(os.Stdout)
+		log.SetOutput(report.NewRedactingOutput(os.Stderr))
+	}
+
+	store := openStateStore(*stateFileArg, *redisURLArg, *redisKeyPrefixArg)
+	state, err := frontier.Snapshot(store)
+	if err != nil {
+		log.Fatalf("error reading the state of the crawl: %v\n", err)
+	}
+	if state.CrawlInfo == nil {
+		log.Fatalln(frontier.NoCrawlInfo)
+	}
+	printState(output, state)
+}
+
+// openStateStore opens the frontier store of the crawl to inspect.
+func openStateStore(stateFileArg, redisURLArg, redisKeyPrefixArg string) frontier.Store {
+	hasStateFile := strings.TrimSpace(stateFileArg) != ""
+	hasRedisURL := strings.TrimSpace(redisURLArg) != ""
+	if hasStateFile == hasRedisURL {
+		log.Fatalln("argument error: state show requires either a state_file or a redis_url. example: crawler state show --state_file=crawl.state")
+	}
+	if hasRedisURL {
+		store, err := frontier.NewRedisStore(redisURLArg, redisKeyPrefixArg)
+		if err != nil {
+			log.Fatalf("error connecting to redis: %v\n", err)
+		}
+		return store
+	}
+	// the file store creates the file if it doesn't exist
+	if _, err := os.Stat(stateFileArg); err != nil {
+		log.Fatalf("argument error: invalid state_file. %v\n", err)
+	}
+	store, err := frontier.NewBoltStore(stateFileArg)
+	if err != nil {
+		log.Fatalf("error opening state file: %v\n", err)
+	}
+	return store
+}
+
+// printState prints the size of the frontier of the crawl, per host and per depth level, and how much
+// of it was completed: the share of the pages visited out of the visited and pending ones. The links
+// found at the last depth level are never crawled, so they don't count as pending.
+func printState(output io.Writer, state frontier.State) {
+	info := state.CrawlInfo
+	_, _ = fmt.Fprintf(output, "Crawl: %s (depth %d of %d, max concurrency %d)\n", info.URL, info.CurrentDepth, info.Depth, info.MaxConcurrency)
+	_, _ = fmt.Fprintf(output, "Links found: %d\n", len(state.Found)+len(state.Visited))
+	_, _ = fmt.Fprintf(output, "Pages visited: %d\n", len(state.Visited))
+
+	pending := 0
+	pendingPerHost := make(map[string]int)
+	depths := make([]int, 0, len(state.Frontiers))
+	for depth, links := range state.Frontiers {
+		depths = append(depths, depth)
+		pending += len(links)
+		for _, link := range links {
+			host := link
+			if parsedLink, err := url.Parse(link); err == nil {
+				host = parsedLink.Host
+			}
+			pendingPerHost[host]++
+		}
+	}
+	_, _ = fmt.Fprintf(output, "Frontier size: %d\n", pending)
+	completion := 100.0
+	if pending > 0 {
+		completion = float64(len(state.Visited)) / float64(len(state.Visited)+pending) * 100
+	}
+	_, _ = fmt.Fprintf(output, "Completion: %.1f%%\n", completion)
+
+	sort.Ints(depths)
+	for _, depth := range depths {
+		_, _ = fmt.Fprintf(output, "[DEPTH] %d: %d pending\n", depth, len(state.Frontiers[depth]))
+	}
+	hosts := make([]string, 0, len(pendingPerHost))
+	for host := range pendingPerHost {
+		hosts = append(hosts, host)
+	}
+	// the hosts with the longest queues first
+	sort.Slice(hosts, func(i, j int) bool {
+		if pendingPerHost[hosts[i]] == pendingPerHost[hosts[j]] {
+			return hosts[i] < hosts[j]
+		}
+		return pendingPerHost[hosts[i]] > pendingPerHost[hosts[j]]
+	})
+	for _, host := range hosts {
+		_, _ = fmt.Fprintf(output, "[HOST] %s: %d pending\n", host, pendingPerHost[host])
+	}
+}
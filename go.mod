@@ -1,5 +1,14 @@
 module github.com/andiblas/website-crawler
 
-go 1.20
+go 1.23
 
-require golang.org/x/net v0.33.0
+require (
+	github.com/andybalholm/brotli v1.2.5
+	go.etcd.io/bbolt v1.3.11
+	golang.org/x/net v0.33.0
+)
+
+require (
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
@@ -0,0 +1,33 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+)
+
+const BrokenAnchorCheck = "broken-anchor"
+
+// BrokenAnchors reports the links to a section of a crawled page, like /docs/setup#install, whose
+// page has no element with that id, using the anchors gathered by the PageCollector. Links to pages
+// that were not crawled can't be checked, so they're skipped. The #top fragment, which browsers
+// scroll to the top of any page, is always valid.
+func BrokenAnchors(pages map[string]Anchors) []Finding {
+	var findings []Finding
+	for page, anchors := range pages {
+		for _, link := range anchors.FragmentLinks {
+			target := link
+			target.Fragment = ""
+			targetAnchors, crawled := pages[target.String()]
+			if !crawled || strings.EqualFold(link.Fragment, "top") || containsString(targetAnchors.IDs, link.Fragment) {
+				continue
+			}
+			findings = append(findings, Finding{
+				Check:  BrokenAnchorCheck,
+				URL:    page,
+				Detail: fmt.Sprintf("links to %s, but %s has no element with id %q", link.String(), target.String(), link.Fragment),
+			})
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
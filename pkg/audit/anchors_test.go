@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestBrokenAnchors(t *testing.T) {
+	link := func(rawURL string) url.URL {
+		parsedURL, _ := url.Parse(rawURL)
+		return *parsedURL
+	}
+	pages := map[string]Anchors{
+		"https://test.com/docs": {
+			IDs: []string{"intro"},
+			FragmentLinks: []url.URL{
+				link("https://test.com/docs#intro"),
+				link("https://test.com/docs#usage"),
+				link("https://test.com/docs/setup#install"),
+				link("https://test.com/docs/setup#uninstall"),
+				link("https://test.com/blog#latest"),
+			},
+		},
+		"https://test.com/docs/setup": {
+			IDs:           []string{"install"},
+			FragmentLinks: []url.URL{link("https://test.com/docs#top")},
+		},
+	}
+
+	got := BrokenAnchors(pages)
+
+	want := []Finding{
+		{Check: BrokenAnchorCheck, URL: "https://test.com/docs", Detail: `links to https://test.com/docs#usage, but https://test.com/docs has no element with id "usage"`},
+		{Check: BrokenAnchorCheck, URL: "https://test.com/docs", Detail: `links to https://test.com/docs/setup#uninstall, but https://test.com/docs/setup has no element with id "uninstall"`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BrokenAnchors() got = %v, want %v", got, want)
+	}
+}
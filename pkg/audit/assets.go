@@ -0,0 +1,153 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+const (
+	BrokenAssetCheck    = "broken-asset"
+	OversizedAssetCheck = "oversized-asset"
+)
+
+// assetPageExamples is the number of loading pages listed in an asset finding.
+const assetPageExamples = 3
+
+// loadedAsset is an asset along with the pages that load it.
+type loadedAsset struct {
+	resource linkextractor.Resource
+	pages    []string
+}
+
+// CheckAssets fetches the assets loaded by the crawled pages, given by page as gathered by a
+// PageCollector, and reports the ones that are missing or fail, like broken images, and the ones
+// larger than maxSize bytes, which slow the pages down. 0 doesn't limit the size. Every asset is
+// fetched once with CheckURLs, honoring the given crawler options like the robots policy, and gets
+// a single finding listing some of the pages that load it. The assetFetcher shouldn't check the
+// content type of the responses, as the assets are not HTML. The size of an asset is its
+// Content-Length, or the length of its body when the response has none.
+func CheckAssets(ctx context.Context, assetFetcher fetcher.Fetcher, resources map[string][]linkextractor.Resource, maxSize int64, maxConcurrency int, opts ...crawler.Option) ([]Finding, error) {
+	assets := make(map[string]*loadedAsset)
+	for page, pageResources := range resources {
+		for _, resource := range pageResources {
+			// the frames are pages, which are crawled instead
+			if resource.Type == linkextractor.FrameResource {
+				continue
+			}
+			asset, ok := assets[resource.URL.String()]
+			if !ok {
+				asset = &loadedAsset{resource: resource}
+				assets[resource.URL.String()] = asset
+			}
+			if !containsString(asset.pages, page) {
+				asset.pages = append(asset.pages, page)
+			}
+		}
+	}
+	links := make([]string, 0, len(assets))
+	for link := range assets {
+		links = append(links, link)
+	}
+	sort.Strings(links)
+	urls := make([]url.URL, len(links))
+	for i, link := range links {
+		urls[i] = assets[link].resource.URL
+	}
+
+	sizer := &assetSizer{innerFetcher: assetFetcher, sizes: make(map[string]int64)}
+	statuses, err := crawler.CheckURLs(ctx, sizer, urls, maxConcurrency, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for i, status := range statuses {
+		if status.Disallowed || errors.Is(status.Err, crawler.NotChecked) {
+			continue
+		}
+		asset := assets[links[i]]
+		sort.Strings(asset.pages)
+		switch {
+		case status.StatusCode != 0:
+			findings = append(findings, Finding{
+				Check:  BrokenAssetCheck,
+				URL:    links[i],
+				Detail: fmt.Sprintf("%s responds with status %d, %s", asset.resource.Type, status.StatusCode, loadedBy(asset.pages)),
+			})
+		case status.Err != nil:
+			findings = append(findings, Finding{
+				Check:  BrokenAssetCheck,
+				URL:    links[i],
+				Detail: fmt.Sprintf("%s failed: %v, %s", asset.resource.Type, status.Err, loadedBy(asset.pages)),
+			})
+		case maxSize > 0 && sizer.size(status.URL) > maxSize:
+			findings = append(findings, Finding{
+				Check:  OversizedAssetCheck,
+				URL:    links[i],
+				Detail: fmt.Sprintf("%s of %s, over the limit of %s, %s", asset.resource.Type, formatSize(sizer.size(status.URL)), formatSize(maxSize), loadedBy(asset.pages)),
+			})
+		}
+	}
+	sortFindings(findings)
+	return findings, nil
+}
+
+// loadedBy describes the pages that load an asset, listing the first ones.
+func loadedBy(pages []string) string {
+	if len(pages) == 1 {
+		return "loaded by " + pages[0]
+	}
+	examples := pages[:min(len(pages), assetPageExamples)]
+	return fmt.Sprintf("loaded by %d pages, like %s", len(pages), strings.Join(examples, ", "))
+}
+
+// formatSize formats a number of bytes in KB or MB.
+func formatSize(size int64) string {
+	if size >= 1<<20 {
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	}
+	return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+}
+
+// assetSizer is a fetcher decorator that records the size of the fetched assets.
+type assetSizer struct {
+	innerFetcher fetcher.Fetcher
+	mu           sync.Mutex
+	sizes        map[string]int64
+}
+
+func (s *assetSizer) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := fetcher.Fetch(s.innerFetcher, url)
+	if err != nil {
+		return result.Body, err
+	}
+	size, parseErr := strconv.ParseInt(result.Header.Get("Content-Length"), 10, 64)
+	if parseErr != nil {
+		size, err = io.Copy(io.Discard, result.Body)
+		if err != nil {
+			_ = result.Body.Close()
+			return nil, err
+		}
+	}
+	s.mu.Lock()
+	s.sizes[url.String()] = size
+	s.mu.Unlock()
+	return result.Body, nil
+}
+
+func (s *assetSizer) size(url url.URL) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sizes[url.String()]
+}
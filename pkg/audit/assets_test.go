@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+// mockAssetFetcher responds with the assets of its map, and with 404 for the rest.
+type mockAssetFetcher struct {
+	assets map[string]string
+}
+
+func (m mockAssetFetcher) FetchWebpageContent(link url.URL) (io.ReadCloser, error) {
+	asset, ok := m.assets[link.String()]
+	if !ok {
+		return nil, &fetcher.UnexpectedStatusError{URL: link, StatusCode: http.StatusNotFound, Status: http.StatusText(http.StatusNotFound)}
+	}
+	return io.NopCloser(strings.NewReader(asset)), nil
+}
+
+func TestCheckAssets(t *testing.T) {
+	resource := func(resourceType linkextractor.ResourceType, link string) linkextractor.Resource {
+		parsedLink, _ := url.Parse(link)
+		return linkextractor.Resource{Type: resourceType, URL: *parsedLink}
+	}
+	resources := map[string][]linkextractor.Resource{
+		"https://test.com": {
+			resource(linkextractor.ImageResource, "https://test.com/logo.png"),
+			resource(linkextractor.ImageResource, "https://test.com/hero.jpg"),
+			resource(linkextractor.ScriptResource, "https://test.com/app.js"),
+			resource(linkextractor.FrameResource, "https://test.com/embed"),
+		},
+		"https://test.com/about": {
+			resource(linkextractor.ImageResource, "https://test.com/logo.png"),
+		},
+	}
+	assetFetcher := mockAssetFetcher{assets: map[string]string{
+		"https://test.com/hero.jpg": strings.Repeat("x", 2000),
+		"https://test.com/app.js":   "console.log()",
+	}}
+
+	got, err := CheckAssets(context.Background(), assetFetcher, resources, 1000, 2)
+	if err != nil {
+		t.Fatalf("should not throw error at CheckAssets. err: %v", err)
+	}
+	want := []Finding{
+		{Check: OversizedAssetCheck, URL: "https://test.com/hero.jpg", Detail: "image of 2.0 KB, over the limit of 1.0 KB, loaded by https://test.com"},
+		{Check: BrokenAssetCheck, URL: "https://test.com/logo.png", Detail: "image responds with status 404, loaded by 2 pages, like https://test.com, https://test.com/about"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckAssets() got = %v, want %v", got, want)
+	}
+
+	got, err = CheckAssets(context.Background(), assetFetcher, resources, 0, 2)
+	if err != nil || len(got) != 1 || got[0].Check != BrokenAssetCheck {
+		t.Errorf("CheckAssets() got = %v, %v, want only the broken image without a size limit", got, err)
+	}
+}
@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"net/url"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+)
+
+// Finding is an issue found by an audit of the crawled site.
+type Finding struct {
+	// Check is the name of the check that found the issue, e.g. "locale-parity".
+	Check string
+	// URL is the page the issue is about.
+	URL string
+	// Detail describes the issue.
+	Detail string
+	// Severity is the severity of the issue, like HighSeverity, or empty for the checks that don't rate it.
+	Severity string
+	// Metadata is the metadata of the seed the page belongs to, set by AttributeToSeeds.
+	Metadata map[string]string
+	// Owners are the owners of the page, set by AssignOwners.
+	Owners []string
+}
+
+// HighSeverity rates the issues that should be fixed right away, like an exposed sensitive file.
+const HighSeverity = "high"
+
+// ownerRules tells the owners of a link, e.g. ownership.Rules.
+type ownerRules interface {
+	Owners(link url.URL) []string
+}
+
+// AttributeToSeeds sets the metadata of the seed every finding belongs to, as told by
+// crawler.SeedMetadata, so the findings of a multi-section audit can be attributed to the right team.
+func AttributeToSeeds(findings []Finding, seeds []crawler.Seed) []Finding {
+	for i, finding := range findings {
+		if link, err := url.Parse(finding.URL); err == nil {
+			findings[i].Metadata = crawler.SeedMetadata(seeds, *link)
+		}
+	}
+	return findings
+}
+
+// AssignOwners sets the owners of the page every finding is about, so the findings can be grouped
+// and routed per owner.
+func AssignOwners(findings []Finding, rules ownerRules) []Finding {
+	for i, finding := range findings {
+		if link, err := url.Parse(finding.URL); err == nil {
+			findings[i].Owners = rules.Owners(*link)
+		}
+	}
+	return findings
+}
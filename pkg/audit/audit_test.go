@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+)
+
+func TestAttributeToSeeds(t *testing.T) {
+	docsURL, _ := url.Parse("https://test.com/docs")
+	seeds := []crawler.Seed{{URL: *docsURL, Metadata: map[string]string{"owner": "docs-team"}}}
+	findings := []Finding{
+		{Check: SitemapMissingCheck, URL: "https://test.com/docs/api", Detail: "reachable through links but missing from the sitemap"},
+		{Check: SitemapMissingCheck, URL: "https://test.com/pricing", Detail: "reachable through links but missing from the sitemap"},
+	}
+
+	got := AttributeToSeeds(findings, seeds)
+
+	want := []Finding{
+		{Check: SitemapMissingCheck, URL: "https://test.com/docs/api", Detail: "reachable through links but missing from the sitemap", Metadata: map[string]string{"owner": "docs-team"}},
+		{Check: SitemapMissingCheck, URL: "https://test.com/pricing", Detail: "reachable through links but missing from the sitemap"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AttributeToSeeds() got = %v, want %v", got, want)
+	}
+}
+
+type mockOwnerRules map[string][]string
+
+func (m mockOwnerRules) Owners(link url.URL) []string {
+	return m[link.Path]
+}
+
+func TestAssignOwners(t *testing.T) {
+	findings := []Finding{
+		{Check: SitemapMissingCheck, URL: "https://test.com/docs", Detail: "reachable through links but missing from the sitemap"},
+		{Check: SitemapMissingCheck, URL: "https://test.com/pricing", Detail: "reachable through links but missing from the sitemap"},
+	}
+
+	got := AssignOwners(findings, mockOwnerRules{"/docs": {"@docs-team"}})
+
+	want := []Finding{
+		{Check: SitemapMissingCheck, URL: "https://test.com/docs", Detail: "reachable through links but missing from the sitemap", Owners: []string{"@docs-team"}},
+		{Check: SitemapMissingCheck, URL: "https://test.com/pricing", Detail: "reachable through links but missing from the sitemap"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AssignOwners() got = %v, want %v", got, want)
+	}
+}
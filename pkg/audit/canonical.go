@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+const CanonicalCheck = "canonical"
+
+// maxCanonicalChain limits how many canonical hops are followed looking for loops.
+const maxCanonicalChain = 10
+
+type httpGetter interface {
+	Get(url string) (resp *http.Response, err error)
+}
+
+// canonicalTarget is the state of a canonical URL as seen by a request that doesn't follow redirects.
+type canonicalTarget struct {
+	statusCode int
+	location   string
+	meta       linkextractor.Meta
+	noIndex    bool
+	err        error
+}
+
+// CanonicalChecker checks that the canonical URLs declared by the crawled pages point to
+// indexable pages that are canonical themselves.
+type CanonicalChecker struct {
+	httpClient httpGetter
+	mu         sync.Mutex
+	targets    map[string]canonicalTarget
+}
+
+// NewCanonicalChecker creates a new CanonicalChecker that requests the canonical URLs with the
+// given HTTP client. The client must not follow redirects, so redirecting canonicals can be
+// detected, e.g. an http.Client with a CheckRedirect function that returns http.ErrUseLastResponse.
+func NewCanonicalChecker(httpClient httpGetter) *CanonicalChecker {
+	return &CanonicalChecker{httpClient: httpClient, targets: make(map[string]canonicalTarget)}
+}
+
+// Check reports the pages, given their metadata as gathered by a PageCollector, whose canonical
+// URL redirects, responds with an error status, is noindexed, or declares another canonical URL,
+// which forms a canonical chain or loop. Every canonical URL is requested once.
+func (c *CanonicalChecker) Check(pages map[string]linkextractor.Meta) []Finding {
+	var findings []Finding
+	for page, meta := range pages {
+		if meta.Canonical == nil || meta.Canonical.String() == page {
+			continue
+		}
+		if detail := c.problem(page, *meta.Canonical); detail != "" {
+			findings = append(findings, Finding{Check: CanonicalCheck, URL: page, Detail: detail})
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+func (c *CanonicalChecker) problem(page string, canonical url.URL) string {
+	target := c.target(canonical)
+	switch {
+	case target.err != nil:
+		return fmt.Sprintf("canonical %s failed: %v", canonical.String(), target.err)
+	case target.statusCode >= 300 && target.statusCode < 400:
+		return fmt.Sprintf("canonical %s redirects to %s", canonical.String(), target.location)
+	case target.statusCode >= 400:
+		return fmt.Sprintf("canonical %s responds with status %d", canonical.String(), target.statusCode)
+	case target.noIndex:
+		return fmt.Sprintf("canonical %s is noindexed", canonical.String())
+	}
+
+	chain := []string{page, canonical.String()}
+	next := target.meta.Canonical
+	for next != nil && next.String() != chain[len(chain)-1] && len(chain) <= maxCanonicalChain {
+		for _, link := range chain {
+			if link == next.String() {
+				return fmt.Sprintf("canonical loop %s", strings.Join(append(chain, next.String()), " -> "))
+			}
+		}
+		chain = append(chain, next.String())
+		next = c.target(*next).meta.Canonical
+	}
+	if len(chain) > 2 {
+		return fmt.Sprintf("canonical chain %s", strings.Join(chain, " -> "))
+	}
+	return ""
+}
+
+func (c *CanonicalChecker) target(canonical url.URL) canonicalTarget {
+	c.mu.Lock()
+	target, ok := c.targets[canonical.String()]
+	c.mu.Unlock()
+	if ok {
+		return target
+	}
+
+	target = c.fetchTarget(canonical)
+	c.mu.Lock()
+	c.targets[canonical.String()] = target
+	c.mu.Unlock()
+	return target
+}
+
+func (c *CanonicalChecker) fetchTarget(canonical url.URL) canonicalTarget {
+	res, err := c.httpClient.Get(canonical.String())
+	if err != nil {
+		return canonicalTarget{err: err}
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	target := canonicalTarget{statusCode: res.StatusCode}
+	if location := res.Header.Get("Location"); location != "" {
+		target.location = location
+		if locationURL, err := canonical.Parse(location); err == nil {
+			target.location = locationURL.String()
+		}
+	}
+	if res.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, res.Body)
+		return target
+	}
+
+	if meta, err := linkextractor.ExtractMeta(canonical, res.Body); err == nil {
+		target.meta = meta
+	}
+	headerMeta := linkextractor.Meta{Robots: linkextractor.ParseRobotsDirectives(strings.Join(res.Header.Values("X-Robots-Tag"), ","))}
+	target.noIndex = target.meta.NoIndex() || headerMeta.NoIndex()
+	return target
+}
@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+type mockResponse struct {
+	statusCode int
+	header     http.Header
+	body       string
+}
+
+type mockHttpGetter struct {
+	responses map[string]mockResponse
+	requested map[string]int
+}
+
+func (m mockHttpGetter) Get(url string) (*http.Response, error) {
+	m.requested[url]++
+	res, ok := m.responses[url]
+	if !ok {
+		return nil, errors.New("connection refused")
+	}
+	header := res.header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{StatusCode: res.statusCode, Header: header, Body: io.NopCloser(strings.NewReader(res.body))}, nil
+}
+
+func canonicalTo(link string) string {
+	return `<link rel="canonical" href="` + link + `">`
+}
+
+func TestCanonicalChecker_Check(t *testing.T) {
+	httpClient := mockHttpGetter{requested: make(map[string]int), responses: map[string]mockResponse{
+		"https://test.com/ok":       {statusCode: http.StatusOK, body: canonicalTo("https://test.com/ok")},
+		"https://test.com/moved":    {statusCode: http.StatusMovedPermanently, header: http.Header{"Location": {"/ok"}}},
+		"https://test.com/missing":  {statusCode: http.StatusNotFound},
+		"https://test.com/noindex":  {statusCode: http.StatusOK, body: `<meta name="robots" content="noindex">`},
+		"https://test.com/header":   {statusCode: http.StatusOK, header: http.Header{"X-Robots-Tag": {"noindex"}}},
+		"https://test.com/chain":    {statusCode: http.StatusOK, body: canonicalTo("https://test.com/ok")},
+		"https://test.com/loop-a":   {statusCode: http.StatusOK, body: canonicalTo("https://test.com/loop-b")},
+		"https://test.com/loop-b":   {statusCode: http.StatusOK, body: canonicalTo("https://test.com/loop-a")},
+		"https://test.com/no-canon": {statusCode: http.StatusOK},
+	}}
+	canonical := func(link string) linkextractor.Meta {
+		parsedLink, _ := url.Parse(link)
+		return linkextractor.Meta{Canonical: parsedLink}
+	}
+	pages := map[string]linkextractor.Meta{
+		"https://test.com/1":      canonical("https://test.com/ok"),
+		"https://test.com/2":      canonical("https://test.com/moved"),
+		"https://test.com/3":      canonical("https://test.com/missing"),
+		"https://test.com/4":      canonical("https://test.com/noindex"),
+		"https://test.com/5":      canonical("https://test.com/header"),
+		"https://test.com/6":      canonical("https://test.com/chain"),
+		"https://test.com/7":      canonical("https://test.com/loop-a"),
+		"https://test.com/8":      canonical("https://test.com/no-canon"),
+		"https://test.com/9":      canonical("https://test.com/ok"),
+		"https://test.com/loop-a": canonical("https://test.com/loop-a"),
+		"https://test.com/self":   {},
+	}
+
+	got := NewCanonicalChecker(httpClient).Check(pages)
+
+	want := []Finding{
+		{Check: CanonicalCheck, URL: "https://test.com/2", Detail: "canonical https://test.com/moved redirects to https://test.com/ok"},
+		{Check: CanonicalCheck, URL: "https://test.com/3", Detail: "canonical https://test.com/missing responds with status 404"},
+		{Check: CanonicalCheck, URL: "https://test.com/4", Detail: "canonical https://test.com/noindex is noindexed"},
+		{Check: CanonicalCheck, URL: "https://test.com/5", Detail: "canonical https://test.com/header is noindexed"},
+		{Check: CanonicalCheck, URL: "https://test.com/6", Detail: "canonical chain https://test.com/6 -> https://test.com/chain -> https://test.com/ok"},
+		{Check: CanonicalCheck, URL: "https://test.com/7", Detail: "canonical loop https://test.com/7 -> https://test.com/loop-a -> https://test.com/loop-b -> https://test.com/loop-a"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Check() got\n%v\nwant\n%v", got, want)
+	}
+	if httpClient.requested["https://test.com/ok"] != 1 {
+		t.Errorf("Check() requested https://test.com/ok %d times, want once", httpClient.requested["https://test.com/ok"])
+	}
+}
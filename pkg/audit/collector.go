@@ -0,0 +1,174 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+// Anchors are the fragments the sections of a page can be linked with, and the links with a
+// fragment found in the page.
+type Anchors struct {
+	IDs           []string
+	FragmentLinks []url.URL
+}
+
+// PageCollector is a fetcher decorator that extracts the metadata of every page fetched during
+// a crawl, like its canonical URL and hreflang alternates, its anchors, its links to other sites, the
+// assets it loads, from its host and from third parties, the hash of its main content and whether it's a directory listing, so
+// they can be audited once the crawl ends.
+type PageCollector struct {
+	innerFetcher  fetcher.Fetcher
+	mu            sync.Mutex
+	pages         map[string]linkextractor.Meta
+	anchors       map[string]Anchors
+	resources     map[string][]linkextractor.Resource
+	thirdParty    map[string][]linkextractor.Resource
+	external      map[string][]url.URL
+	contentHashes map[string]string
+	listings      map[string]bool
+}
+
+func NewPageCollector(innerFetcher fetcher.Fetcher) *PageCollector {
+	return &PageCollector{
+		innerFetcher:  innerFetcher,
+		pages:         make(map[string]linkextractor.Meta),
+		anchors:       make(map[string]Anchors),
+		resources:     make(map[string][]linkextractor.Resource),
+		thirdParty:    make(map[string][]linkextractor.Resource),
+		external:      make(map[string][]url.URL),
+		contentHashes: make(map[string]string),
+		listings:      make(map[string]bool),
+	}
+}
+
+// FetchWebpageContent fetches the webpage using the inner fetcher and extracts its metadata, anchors,
+// assets and content hash before handing the content over.
+func (c *PageCollector) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := c.FetchContext(context.Background(), url)
+	return result.Body, err
+}
+
+// FetchContext fetches the webpage like FetchWebpageContent, passing the context to the inner fetcher,
+// along with its response.
+func (c *PageCollector) FetchContext(ctx context.Context, url url.URL) (fetcher.FetchResult, error) {
+	result, err := fetcher.FetchContext(ctx, c.innerFetcher, url)
+	if err != nil {
+		return result, err
+	}
+	body, err := io.ReadAll(result.Body)
+	_ = result.Body.Close()
+	if err != nil {
+		return fetcher.FetchResult{StatusCode: result.StatusCode, Header: result.Header, FinalURL: result.FinalURL}, err
+	}
+	if extractedPage, err := linkextractor.ExtractPage(url, bytes.NewReader(body), linkextractor.WithAnchors(), linkextractor.WithResources(), linkextractor.WithThirdPartyResources(), linkextractor.WithExternalLinks(), linkextractor.WithContentHash()); err == nil {
+		// the query string is kept, as the crawler fetches the links with it only when it keeps them
+		page := linkextractor.Normalize(url, linkextractor.WithQueryStrings())
+		c.mu.Lock()
+		c.pages[page.String()] = extractedPage.Meta
+		c.anchors[page.String()] = Anchors{IDs: extractedPage.IDs, FragmentLinks: extractedPage.FragmentLinks}
+		c.resources[page.String()] = extractedPage.Resources
+		c.thirdParty[page.String()] = extractedPage.ThirdPartyResources
+		c.external[page.String()] = extractedPage.ExternalLinks
+		c.contentHashes[page.String()] = extractedPage.ContentHash
+		if extractedPage.DirectoryListing {
+			c.listings[page.String()] = true
+		}
+		c.mu.Unlock()
+	}
+	result.Body = io.NopCloser(bytes.NewReader(body))
+	return result, nil
+}
+
+// Pages returns the metadata of every fetched page, by normalized page URL.
+func (c *PageCollector) Pages() map[string]linkextractor.Meta {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pages := make(map[string]linkextractor.Meta, len(c.pages))
+	for page, meta := range c.pages {
+		pages[page] = meta
+	}
+	return pages
+}
+
+// Anchors returns the anchors of every fetched page, by normalized page URL.
+func (c *PageCollector) Anchors() map[string]Anchors {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	anchors := make(map[string]Anchors, len(c.anchors))
+	for page, pageAnchors := range c.anchors {
+		anchors[page] = pageAnchors
+	}
+	return anchors
+}
+
+// Resources returns the same-domain assets loaded by every fetched page, by normalized page URL.
+func (c *PageCollector) Resources() map[string][]linkextractor.Resource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	resources := make(map[string][]linkextractor.Resource, len(c.resources))
+	for page, pageResources := range c.resources {
+		resources[page] = pageResources
+	}
+	return resources
+}
+
+// ThirdPartyResources returns the assets loaded by every fetched page from other hosts, by normalized
+// page URL.
+func (c *PageCollector) ThirdPartyResources() map[string][]linkextractor.Resource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	thirdParty := make(map[string][]linkextractor.Resource, len(c.thirdParty))
+	for page, pageResources := range c.thirdParty {
+		thirdParty[page] = pageResources
+	}
+	return thirdParty
+}
+
+// ExternalLinks returns the links of every fetched page to other hosts, by normalized page URL.
+func (c *PageCollector) ExternalLinks() map[string][]url.URL {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	external := make(map[string][]url.URL, len(c.external))
+	for page, links := range c.external {
+		external[page] = links
+	}
+	return external
+}
+
+// ContentHashes returns the hash of the main content of every fetched page, by normalized page URL.
+func (c *PageCollector) ContentHashes() map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	contentHashes := make(map[string]string, len(c.contentHashes))
+	for page, contentHash := range c.contentHashes {
+		contentHashes[page] = contentHash
+	}
+	return contentHashes
+}
+
+// DirectoryListings returns the normalized URLs of the fetched pages that are directory listings
+// generated by the web server, sorted.
+func (c *PageCollector) DirectoryListings() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	listings := make([]string, 0, len(c.listings))
+	for page := range c.listings {
+		listings = append(listings, page)
+	}
+	sort.Strings(listings)
+	return listings
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+type mockFetcher struct {
+	pages map[string]string
+}
+
+func (m mockFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(m.pages[url.String()])), nil
+}
+
+func TestPageCollector_FetchWebpageContent(t *testing.T) {
+	page := `<link rel="alternate" hreflang="de" href="/de/about"><a href="/en/contact">contact</a><h2 id="team">Team</h2><a href="/en/contact#form">form</a><img src="/logo.png"><script src="https://cdn.other.com/lib.js"></script><a href="https://other.com/ref">ref</a>`
+	collector := NewPageCollector(mockFetcher{pages: map[string]string{"https://test.com/en/about/": page}})
+
+	content, err := collector.FetchWebpageContent(url.URL{Scheme: "https", Host: "test.com", Path: "/en/about/"})
+	if err != nil {
+		t.Fatalf("should not throw error at collector.FetchWebpageContent. err: %v", err)
+	}
+	if body, _ := io.ReadAll(content); string(body) != page {
+		t.Errorf("FetchWebpageContent() content got = %v, want %v", string(body), page)
+	}
+	meta := collector.Pages()["https://test.com/en/about"]
+	if len(meta.Alternates) != 1 || meta.Alternates[0].URL.String() != "https://test.com/de/about" {
+		t.Errorf("Pages() got = %+v, want the de alternate", meta)
+	}
+	anchors := collector.Anchors()["https://test.com/en/about"]
+	if len(anchors.IDs) != 1 || anchors.IDs[0] != "team" || len(anchors.FragmentLinks) != 1 || anchors.FragmentLinks[0].String() != "https://test.com/en/contact#form" {
+		t.Errorf("Anchors() got = %+v, want the team id and the form link", anchors)
+	}
+	resources := collector.Resources()["https://test.com/en/about"]
+	if len(resources) != 1 || resources[0].Type != linkextractor.ImageResource || resources[0].URL.String() != "https://test.com/logo.png" {
+		t.Errorf("Resources() got = %+v, want the logo image", resources)
+	}
+	thirdParty := collector.ThirdPartyResources()["https://test.com/en/about"]
+	if len(thirdParty) != 1 || thirdParty[0].Type != linkextractor.ScriptResource || thirdParty[0].URL.String() != "https://cdn.other.com/lib.js" {
+		t.Errorf("ThirdPartyResources() got = %+v, want the script of the CDN", thirdParty)
+	}
+	external := collector.ExternalLinks()["https://test.com/en/about"]
+	if len(external) != 1 || external[0].String() != "https://other.com/ref" {
+		t.Errorf("ExternalLinks() got = %v, want the reference to the other site", external)
+	}
+	if contentHash := collector.ContentHashes()["https://test.com/en/about"]; len(contentHash) != 64 {
+		t.Errorf("ContentHashes() got = %v, want a SHA-256 hex hash", contentHash)
+	}
+}
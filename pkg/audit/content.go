@@ -0,0 +1,25 @@
+package audit
+
+import "fmt"
+
+const ContentChangedCheck = "content-changed"
+
+// ContentChanges reports the pages whose main content changed since a previous crawl, comparing the
+// content hashes gathered by the PageCollector with the ones saved in the previous crawl. Pages that
+// were not crawled in both are skipped.
+func ContentChanges(previous, current map[string]string) []Finding {
+	var findings []Finding
+	for page, contentHash := range current {
+		previousHash, crawled := previous[page]
+		if !crawled || previousHash == contentHash {
+			continue
+		}
+		findings = append(findings, Finding{
+			Check:  ContentChangedCheck,
+			URL:    page,
+			Detail: fmt.Sprintf("main content changed since the previous crawl (hash %.12s, was %.12s)", contentHash, previousHash),
+		})
+	}
+	sortFindings(findings)
+	return findings
+}
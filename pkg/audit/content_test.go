@@ -0,0 +1,28 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContentChanges(t *testing.T) {
+	previous := map[string]string{
+		"https://test.com":         "aaaaaaaaaaaaaaaa",
+		"https://test.com/pricing": "bbbbbbbbbbbbbbbb",
+		"https://test.com/old":     "cccccccccccccccc",
+	}
+	current := map[string]string{
+		"https://test.com":         "aaaaaaaaaaaaaaaa",
+		"https://test.com/pricing": "dddddddddddddddd",
+		"https://test.com/new":     "eeeeeeeeeeeeeeee",
+	}
+
+	got := ContentChanges(previous, current)
+
+	want := []Finding{
+		{Check: ContentChangedCheck, URL: "https://test.com/pricing", Detail: "main content changed since the previous crawl (hash dddddddddddd, was bbbbbbbbbbbb)"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ContentChanges() got = %v, want %v", got, want)
+	}
+}
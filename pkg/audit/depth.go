@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+)
+
+const DeepPageCheck = "deep-page"
+
+// DeepPages reports the crawled pages that are at least minDepth clicks away from the seed, following
+// the shortest click paths of the link graph of the crawl. The detail of every finding has the depth
+// of the page and an example path to it, which shows through which pages it's buried.
+func DeepPages(graph *crawler.CrawlGraph, minDepth int) []Finding {
+	var findings []Finding
+	for page, path := range graph.ClickPaths() {
+		depth := len(path) - 1
+		if depth < minDepth {
+			continue
+		}
+		findings = append(findings, Finding{
+			Check:  DeepPageCheck,
+			URL:    page,
+			Detail: fmt.Sprintf("%d clicks from %s: %s", depth, path[0], strings.Join(path, " > ")),
+		})
+	}
+	sortFindings(findings)
+	return findings
+}
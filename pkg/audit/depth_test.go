@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+)
+
+func TestDeepPages(t *testing.T) {
+	graph := &crawler.CrawlGraph{
+		Seed:  "https://test.com",
+		Nodes: []string{"https://test.com", "https://test.com/blog", "https://test.com/blog/page/2", "https://test.com/blog/post", "https://test.com/about"},
+		Edges: []crawler.Edge{
+			{From: "https://test.com", To: "https://test.com/blog"},
+			{From: "https://test.com", To: "https://test.com/about"},
+			{From: "https://test.com/blog", To: "https://test.com/blog/page/2"},
+			{From: "https://test.com/blog/page/2", To: "https://test.com/blog/post"},
+			{From: "https://test.com/about", To: "https://test.com/blog/page/2"},
+		},
+	}
+
+	got := DeepPages(graph, 2)
+	want := []Finding{
+		{Check: DeepPageCheck, URL: "https://test.com/blog/page/2", Detail: "2 clicks from https://test.com: https://test.com > https://test.com/blog > https://test.com/blog/page/2"},
+		{Check: DeepPageCheck, URL: "https://test.com/blog/post", Detail: "3 clicks from https://test.com: https://test.com > https://test.com/blog > https://test.com/blog/page/2 > https://test.com/blog/post"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DeepPages() got = %v, want %v", got, want)
+	}
+
+	if got := DeepPages(graph, 4); len(got) != 0 {
+		t.Errorf("DeepPages() got = %v, want no findings", got)
+	}
+}
@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sync"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+const SensitiveFileCheck = "sensitive-file"
+
+// sensitiveFileSampleSize is the number of bytes of a probed file matched against its signature.
+const sensitiveFileSampleSize = 4096
+
+// SensitiveFile is a file that should never be served, like the metadata of a repository, a file of
+// secrets or a backup of the site.
+type SensitiveFile struct {
+	// Path is the path of the file, probed at the root of every host.
+	Path string
+	// Signature matches the beginning of the file, so the pages served for any path, like the soft 404
+	// pages, are not reported. A nil signature matches any response that is not an HTML page.
+	Signature *regexp.Regexp
+}
+
+// DefaultSensitiveFiles are the sensitive files most often exposed by mistake.
+var DefaultSensitiveFiles = []SensitiveFile{
+	{Path: "/.git/HEAD", Signature: regexp.MustCompile(`^(ref: refs/|[0-9a-f]{40}\s*$)`)},
+	{Path: "/.git/config", Signature: regexp.MustCompile(`^\s*\[core\]`)},
+	{Path: "/.svn/entries", Signature: regexp.MustCompile(`^(\d+\s*$|<\?xml)`)},
+	{Path: "/.env", Signature: regexp.MustCompile(`(?m)^[A-Z][A-Z0-9_]*=`)},
+	{Path: "/.htpasswd", Signature: regexp.MustCompile(`(?m)^[^:\s<]+:(\$apr1\$|\$2[aby]\$|\{SHA\}|[./0-9A-Za-z]{13}$)`)},
+	{Path: "/.DS_Store", Signature: regexp.MustCompile(`^\x00\x00\x00\x01Bud1`)},
+	{Path: "/id_rsa", Signature: regexp.MustCompile(`-----BEGIN (RSA |OPENSSH )?PRIVATE KEY-----`)},
+	{Path: "/wp-config.php.bak", Signature: regexp.MustCompile(`DB_PASSWORD`)},
+	{Path: "/backup.zip", Signature: regexp.MustCompile(`^PK\x03\x04`)},
+	{Path: "/backup.sql", Signature: regexp.MustCompile(`(?i)(CREATE TABLE|INSERT INTO)`)},
+	{Path: "/dump.sql", Signature: regexp.MustCompile(`(?i)(CREATE TABLE|INSERT INTO)`)},
+	{Path: "/server-status", Signature: regexp.MustCompile(`Apache Server Status`)},
+}
+
+// htmlPattern matches the beginning of an HTML page.
+var htmlPattern = regexp.MustCompile(`(?i)^\s*(<!doctype html|<html|<head|<body)`)
+
+// ExposureScanner is a fetcher decorator that probes every host of the pages fetched during a crawl,
+// once, for exposed sensitive files. The probes run in the background while the crawl goes on, with
+// their own fetcher, usually one that doesn't check the content type of the responses, sharing the
+// rate limit of the crawl.
+type ExposureScanner struct {
+	innerFetcher fetcher.Fetcher
+	probeFetcher fetcher.Fetcher
+	files        []SensitiveFile
+	wg           sync.WaitGroup
+	mu           sync.Mutex
+	scannedHosts map[string]bool
+	findings     []Finding
+}
+
+// NewExposureScanner creates a new ExposureScanner that probes the given files, like
+// DefaultSensitiveFiles, with the probeFetcher.
+func NewExposureScanner(innerFetcher fetcher.Fetcher, probeFetcher fetcher.Fetcher, files []SensitiveFile) *ExposureScanner {
+	return &ExposureScanner{innerFetcher: innerFetcher, probeFetcher: probeFetcher, files: files, scannedHosts: make(map[string]bool)}
+}
+
+// FetchWebpageContent fetches the webpage using the inner fetcher, and starts probing its host if it
+// wasn't probed yet.
+func (s *ExposureScanner) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := s.FetchContext(context.Background(), url)
+	return result.Body, err
+}
+
+// FetchContext fetches the webpage like FetchWebpageContent, passing the context to the inner fetcher,
+// along with its response.
+func (s *ExposureScanner) FetchContext(ctx context.Context, url url.URL) (fetcher.FetchResult, error) {
+	s.mu.Lock()
+	if !s.scannedHosts[url.Host] {
+		s.scannedHosts[url.Host] = true
+		s.wg.Add(1)
+		go s.scan(url.Scheme, url.Host)
+	}
+	s.mu.Unlock()
+
+	return fetcher.FetchContext(ctx, s.innerFetcher, url)
+}
+
+func (s *ExposureScanner) scan(scheme, host string) {
+	defer s.wg.Done()
+	for _, file := range s.files {
+		link := url.URL{Scheme: scheme, Host: host, Path: file.Path}
+		if !s.exposed(link, file.Signature) {
+			continue
+		}
+		s.mu.Lock()
+		s.findings = append(s.findings, Finding{
+			Check:    SensitiveFileCheck,
+			URL:      link.String(),
+			Detail:   fmt.Sprintf("sensitive file %s is publicly accessible", file.Path),
+			Severity: HighSeverity,
+		})
+		s.mu.Unlock()
+	}
+}
+
+// exposed reports whether the file is served, matching its signature.
+func (s *ExposureScanner) exposed(link url.URL, signature *regexp.Regexp) bool {
+	content, err := s.probeFetcher.FetchWebpageContent(link)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = content.Close() }()
+
+	sample, err := io.ReadAll(io.LimitReader(content, sensitiveFileSampleSize))
+	if err != nil || len(sample) == 0 {
+		return false
+	}
+	if signature == nil {
+		return !htmlPattern.Match(sample)
+	}
+	return signature.Match(sample)
+}
+
+// Findings waits for the probes to finish and returns the exposed sensitive files, sorted by URL.
+func (s *ExposureScanner) Findings() []Finding {
+	s.wg.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	findings := append([]Finding(nil), s.findings...)
+	sortFindings(findings)
+	return findings
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// probeFetcher serves the given files, counting the probes.
+type probeFetcher struct {
+	mu     sync.Mutex
+	files  map[string]string
+	probes map[string]int
+}
+
+func (p *probeFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.probes[url.String()]++
+	file, ok := p.files[url.String()]
+	if !ok {
+		return nil, errors.New("unexpected status 404 Not Found")
+	}
+	return io.NopCloser(strings.NewReader(file)), nil
+}
+
+func TestExposureScanner_Findings(t *testing.T) {
+	probes := &probeFetcher{probes: make(map[string]int), files: map[string]string{
+		"https://test.com/.git/HEAD":   "ref: refs/heads/main\n",
+		"https://test.com/.env":        "<!DOCTYPE html><html>Page not found</html>",
+		"https://test.com/secrets.txt": "password: hunter2",
+		"https://cdn.test.com/.env":    "DB_PASSWORD=hunter2\n",
+	}}
+	files := []SensitiveFile{DefaultSensitiveFiles[0], DefaultSensitiveFiles[3], DefaultSensitiveFiles[8], {Path: "/secrets.txt"}}
+	scanner := NewExposureScanner(mockFetcher{}, probes, files)
+
+	for _, link := range []string{"https://test.com/", "https://test.com/about", "https://cdn.test.com/"} {
+		parsedLink, _ := url.Parse(link)
+		if _, err := scanner.FetchWebpageContent(*parsedLink); err != nil {
+			t.Fatalf("should not throw error at FetchWebpageContent. err: %v", err)
+		}
+	}
+
+	var got []string
+	for _, finding := range scanner.Findings() {
+		if finding.Check != SensitiveFileCheck || finding.Severity != HighSeverity {
+			t.Errorf("Findings() got = %+v, want a high severity sensitive file", finding)
+		}
+		got = append(got, finding.URL)
+	}
+	want := []string{"https://cdn.test.com/.env", "https://test.com/.git/HEAD", "https://test.com/secrets.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Findings() got = %v, want %v", got, want)
+	}
+	if probes.probes["https://test.com/.git/HEAD"] != 1 {
+		t.Errorf("FetchWebpageContent() probed the host %d times, want once", probes.probes["https://test.com/.git/HEAD"])
+	}
+}
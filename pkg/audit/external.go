@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+const BrokenExternalLinkCheck = "broken-external-link"
+
+// SelectExternalLinks picks the links to other sites to verify with CheckExternalLinks, given by page as
+// gathered by a PageCollector, to keep the audit cheap on the sites with tens of thousands of them. Only
+// the links of the pages whose path starts with one of the pathPrefixes, like /docs/, are kept, or the
+// ones of every page if there are none. Of those, only samplePercent percent of the distinct links are
+// kept, 100 keeping them all. The sample is picked by hashing the links, so the same links are kept by
+// every page that has them and by every crawl, and the findings of consecutive crawls can be compared.
+func SelectExternalLinks(externalLinks map[string][]url.URL, pathPrefixes []string, samplePercent int) map[string][]url.URL {
+	selected := make(map[string][]url.URL)
+	for page, links := range externalLinks {
+		if len(pathPrefixes) > 0 && !hasPathPrefix(page, pathPrefixes) {
+			continue
+		}
+		var pageLinks []url.URL
+		for _, link := range links {
+			if samplePercent >= 100 || sampled(link, samplePercent) {
+				pageLinks = append(pageLinks, link)
+			}
+		}
+		if len(pageLinks) > 0 {
+			selected[page] = pageLinks
+		}
+	}
+	return selected
+}
+
+// hasPathPrefix reports whether the path of the page starts with one of the prefixes.
+func hasPathPrefix(page string, pathPrefixes []string) bool {
+	parsedPage, err := url.Parse(page)
+	if err != nil {
+		return false
+	}
+	pagePath := parsedPage.Path
+	if pagePath == "" {
+		pagePath = "/"
+	}
+	for _, prefix := range pathPrefixes {
+		if strings.HasPrefix(pagePath, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sampled reports whether the link is in the sample of the given percentage of the links.
+func sampled(link url.URL, samplePercent int) bool {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(link.String()))
+	return int(hash.Sum32()%100) < samplePercent
+}
+
+// CheckExternalLinks fetches the links of the crawled pages to other sites, given by page as gathered
+// by a PageCollector and usually picked with SelectExternalLinks, and reports the ones that respond
+// with an error status or fail, like the references to moved or closed sites. Every link is fetched
+// once with CheckURLs, honoring the given crawler options like the robots policy, and gets a single
+// finding listing some of the pages that link to it. The linkFetcher shouldn't check the content type
+// of the responses, as the links can point to any kind of document, and can send HEAD requests, see
+// fetcher.WithHeadRequests.
+func CheckExternalLinks(ctx context.Context, linkFetcher fetcher.Fetcher, externalLinks map[string][]url.URL, maxConcurrency int, opts ...crawler.Option) ([]Finding, error) {
+	linkingPages := make(map[string][]string)
+	targets := make(map[string]url.URL)
+	for page, links := range externalLinks {
+		for _, link := range links {
+			targets[link.String()] = link
+			if !containsString(linkingPages[link.String()], page) {
+				linkingPages[link.String()] = append(linkingPages[link.String()], page)
+			}
+		}
+	}
+	links := make([]string, 0, len(targets))
+	for link := range targets {
+		links = append(links, link)
+	}
+	sort.Strings(links)
+	urls := make([]url.URL, len(links))
+	for i, link := range links {
+		urls[i] = targets[link]
+	}
+
+	statuses, err := crawler.CheckURLs(ctx, linkFetcher, urls, maxConcurrency, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for i, status := range statuses {
+		if status.Disallowed || errors.Is(status.Err, crawler.NotChecked) {
+			continue
+		}
+		pages := linkingPages[links[i]]
+		sort.Strings(pages)
+		switch {
+		case status.StatusCode != 0:
+			findings = append(findings, Finding{
+				Check:  BrokenExternalLinkCheck,
+				URL:    links[i],
+				Detail: fmt.Sprintf("responds with status %d, %s", status.StatusCode, linkedBy(pages)),
+			})
+		case status.Err != nil:
+			findings = append(findings, Finding{
+				Check:  BrokenExternalLinkCheck,
+				URL:    links[i],
+				Detail: fmt.Sprintf("failed: %v, %s", status.Err, linkedBy(pages)),
+			})
+		}
+	}
+	sortFindings(findings)
+	return findings, nil
+}
+
+// linkedBy describes the pages that link to an external link, listing the first ones.
+func linkedBy(pages []string) string {
+	if len(pages) == 1 {
+		return "linked from " + pages[0]
+	}
+	examples := pages[:min(len(pages), assetPageExamples)]
+	return fmt.Sprintf("linked from %d pages, like %s", len(pages), strings.Join(examples, ", "))
+}
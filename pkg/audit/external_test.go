@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestCheckExternalLinks(t *testing.T) {
+	parse := func(link string) url.URL {
+		parsedLink, _ := url.Parse(link)
+		return *parsedLink
+	}
+	externalLinks := map[string][]url.URL{
+		"https://test.com":       {parse("https://other.com/moved"), parse("https://other.com/ok")},
+		"https://test.com/about": {parse("https://other.com/moved")},
+	}
+	linkFetcher := mockAssetFetcher{assets: map[string]string{"https://other.com/ok": "<html></html>"}}
+
+	got, err := CheckExternalLinks(context.Background(), linkFetcher, externalLinks, 2)
+	if err != nil {
+		t.Fatalf("should not throw error at CheckExternalLinks. err: %v", err)
+	}
+	want := []Finding{
+		{Check: BrokenExternalLinkCheck, URL: "https://other.com/moved", Detail: "responds with status 404, linked from 2 pages, like https://test.com, https://test.com/about"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckExternalLinks() got = %v, want %v", got, want)
+	}
+}
+
+func TestSelectExternalLinks(t *testing.T) {
+	var links []url.URL
+	for i := 0; i < 200; i++ {
+		links = append(links, url.URL{Scheme: "https", Host: "other.com", Path: fmt.Sprintf("/ref/%d", i)})
+	}
+	externalLinks := map[string][]url.URL{
+		"https://test.com":            links,
+		"https://test.com/docs/setup": links,
+		"https://test.com/blog/post":  links[:1],
+	}
+
+	t.Run("keeps the links of the pages under the paths", func(t *testing.T) {
+		got := SelectExternalLinks(externalLinks, []string{"/docs/", "/blog/"}, 100)
+		if len(got) != 2 || len(got["https://test.com/docs/setup"]) != len(links) || len(got["https://test.com/blog/post"]) != 1 {
+			t.Errorf("SelectExternalLinks() got the links of %d pages, want the ones of the docs and blog pages", len(got))
+		}
+	})
+	t.Run("keeps the same sample of the links in every page", func(t *testing.T) {
+		got := SelectExternalLinks(externalLinks, nil, 25)
+		sample := len(got["https://test.com"])
+		if sample < 25 || sample > 75 {
+			t.Errorf("SelectExternalLinks() sampled %d of %d links, want about a quarter of them", sample, len(links))
+		}
+		if !reflect.DeepEqual(got["https://test.com"], got["https://test.com/docs/setup"]) {
+			t.Errorf("SelectExternalLinks() sampled different links in pages with the same links")
+		}
+		if again := SelectExternalLinks(externalLinks, nil, 25); !reflect.DeepEqual(got, again) {
+			t.Errorf("SelectExternalLinks() sampled different links in every call")
+		}
+	})
+}
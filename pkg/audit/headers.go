@@ -0,0 +1,189 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+const (
+	UncompressedCheck   = "uncompressed"
+	NoCacheHeadersCheck = "no-cache-headers"
+)
+
+// headerExamples is the number of responses listed in a compression or caching finding.
+const headerExamples = 3
+
+// minCompressibleSize is the size under which the responses are not worth compressing, as most servers
+// don't compress them either.
+const minCompressibleSize = 1024
+
+// Response is the response a URL was served with, as recorded by a HeaderCollector.
+type Response struct {
+	// Size is the size of the body in bytes, its Content-Length or the length of the decoded body if it
+	// has none.
+	Size int64
+	// Header holds the headers of the response.
+	Header http.Header
+}
+
+// HeaderCollector is a fetcher decorator that records the headers and the size of the responses of
+// every webpage or asset fetched successfully, so their compression and caching can be audited once the
+// crawl ends. The inner fetcher must return the responses along with the webpages, see
+// fetcher.ResultFetcher.
+type HeaderCollector struct {
+	innerFetcher fetcher.Fetcher
+	mu           sync.Mutex
+	responses    map[string]Response
+}
+
+func NewHeaderCollector(innerFetcher fetcher.Fetcher) *HeaderCollector {
+	return &HeaderCollector{innerFetcher: innerFetcher, responses: make(map[string]Response)}
+}
+
+// FetchWebpageContent fetches the webpage using the inner fetcher and records its response.
+func (c *HeaderCollector) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := c.Fetch(url)
+	return result.Body, err
+}
+
+// Fetch fetches the webpage like FetchWebpageContent, along with its response.
+func (c *HeaderCollector) Fetch(url url.URL) (fetcher.FetchResult, error) {
+	return c.FetchContext(context.Background(), url)
+}
+
+// FetchContext fetches the webpage like Fetch, passing the context to the inner fetcher.
+func (c *HeaderCollector) FetchContext(ctx context.Context, url url.URL) (fetcher.FetchResult, error) {
+	result, err := fetcher.FetchContext(ctx, c.innerFetcher, url)
+	if err != nil || result.Header == nil {
+		return result, err
+	}
+	size, parseErr := strconv.ParseInt(result.Header.Get("Content-Length"), 10, 64)
+	if parseErr != nil {
+		body, err := io.ReadAll(result.Body)
+		_ = result.Body.Close()
+		if err != nil {
+			return fetcher.FetchResult{StatusCode: result.StatusCode, Header: result.Header, FinalURL: result.FinalURL}, err
+		}
+		size = int64(len(body))
+		result.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	c.mu.Lock()
+	c.responses[url.String()] = Response{Size: size, Header: result.Header}
+	c.mu.Unlock()
+	return result, nil
+}
+
+// Responses returns the response of every fetched webpage or asset, by URL.
+func (c *HeaderCollector) Responses() map[string]Response {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	responses := make(map[string]Response, len(c.responses))
+	for link, response := range c.responses {
+		responses[link] = response
+	}
+	return responses
+}
+
+// headerGroup is the responses of a type served by a host without compression or cache headers.
+type headerGroup struct {
+	check        string
+	host         string
+	responseType string
+}
+
+// CompressionAndCaching reports the pages and the assets served without compression, among the text
+// ones larger than 1 KB, like the HTML, CSS, JavaScript, JSON and SVG files, and the ones served without
+// the Cache-Control or Expires headers that let the browsers cache them. The responses, as recorded by a
+// HeaderCollector, are aggregated by host and by type, like the scripts or the images, into a finding
+// about the homepage of the host with the number of responses, their total size and the largest ones,
+// so the fixes with the most impact come first.
+func CompressionAndCaching(responses map[string]Response) []Finding {
+	groups := make(map[headerGroup][]string)
+	for link, response := range responses {
+		parsedLink, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		host := parsedLink.Scheme + "://" + parsedLink.Host + "/"
+		responseType, compressible := typeOf(response.Header.Get("Content-Type"))
+		if compressible && response.Size >= minCompressibleSize && !compressed(response.Header) {
+			group := headerGroup{check: UncompressedCheck, host: host, responseType: responseType}
+			groups[group] = append(groups[group], link)
+		}
+		if response.Header.Get("Cache-Control") == "" && response.Header.Get("Expires") == "" {
+			group := headerGroup{check: NoCacheHeadersCheck, host: host, responseType: responseType}
+			groups[group] = append(groups[group], link)
+		}
+	}
+
+	var findings []Finding
+	for group, links := range groups {
+		// the largest responses first, as they have the most impact
+		sort.Slice(links, func(i, j int) bool {
+			if responses[links[i]].Size != responses[links[j]].Size {
+				return responses[links[i]].Size > responses[links[j]].Size
+			}
+			return links[i] < links[j]
+		})
+		var totalSize int64
+		for _, link := range links {
+			totalSize += responses[link].Size
+		}
+		examples := make([]string, 0, headerExamples)
+		for _, link := range links[:min(len(links), headerExamples)] {
+			examples = append(examples, fmt.Sprintf("%s (%s)", link, formatSize(responses[link].Size)))
+		}
+		problem := "without compression"
+		if group.check == NoCacheHeadersCheck {
+			problem = "without Cache-Control or Expires headers"
+		}
+		findings = append(findings, Finding{
+			Check:  group.check,
+			URL:    group.host,
+			Detail: fmt.Sprintf("%s served %s: %d, %s in total, like %s", group.responseType, problem, len(links), formatSize(totalSize), strings.Join(examples, ", ")),
+		})
+	}
+	sortFindings(findings)
+	return findings
+}
+
+// typeOf returns the type of the responses with the given Content-Type, in plural, and whether they're
+// text, which compresses well, unlike the images other than SVG, the fonts other than TTF and OTF, and the media.
+func typeOf(contentType string) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "other files", false
+	}
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return "pages", true
+	case mediaType == "text/css":
+		return "stylesheets", true
+	case strings.Contains(mediaType, "javascript") || strings.Contains(mediaType, "ecmascript"):
+		return "scripts", true
+	case strings.HasPrefix(mediaType, "image/"):
+		return "images", mediaType == "image/svg+xml"
+	case strings.HasPrefix(mediaType, "font/") || strings.Contains(mediaType, "font"):
+		return "fonts", mediaType == "font/ttf" || mediaType == "font/otf"
+	}
+	compressible := strings.HasPrefix(mediaType, "text/") || strings.Contains(mediaType, "json") || strings.Contains(mediaType, "xml")
+	return "other files", compressible
+}
+
+// compressed reports whether the body of the response is compressed with a content encoding.
+func compressed(header http.Header) bool {
+	contentEncoding := strings.ToLower(strings.TrimSpace(header.Get("Content-Encoding")))
+	return contentEncoding != "" && contentEncoding != "identity"
+}
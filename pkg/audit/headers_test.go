@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+func TestHeaderCollector_FetchWebpageContent(t *testing.T) {
+	page := "<html>" + strings.Repeat("text ", 500) + "</html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Cache-Control", "max-age=60")
+		// flushing sends the body chunked, without a Content-Length
+		_, _ = w.Write([]byte(page[:10]))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(page[10:]))
+	}))
+	defer server.Close()
+	collector := NewHeaderCollector(fetcher.NewHTTPFetcher(server.Client()))
+
+	link, _ := url.Parse(server.URL + "/about")
+	content, err := collector.FetchWebpageContent(*link)
+	if err != nil {
+		t.Fatalf("should not throw error at collector.FetchWebpageContent. err: %v", err)
+	}
+	if body, _ := io.ReadAll(content); string(body) != page {
+		t.Errorf("FetchWebpageContent() content got = %v, want %v", string(body), page)
+	}
+	response := collector.Responses()[link.String()]
+	if response.Size != int64(len(page)) || response.Header.Get("Cache-Control") != "max-age=60" {
+		t.Errorf("Responses() got = %+v, want the size and the headers of the page", response)
+	}
+}
+
+func TestCompressionAndCaching(t *testing.T) {
+	header := func(values ...string) http.Header {
+		header := make(http.Header)
+		for i := 0; i < len(values); i += 2 {
+			header.Set(values[i], values[i+1])
+		}
+		return header
+	}
+	responses := map[string]Response{
+		"https://test.com":              {Size: 20000, Header: header("Content-Type", "text/html; charset=utf-8", "Content-Encoding", "gzip", "Cache-Control", "no-cache")},
+		"https://test.com/app.js":       {Size: 300000, Header: header("Content-Type", "application/javascript", "Cache-Control", "max-age=3600")},
+		"https://test.com/vendor.js":    {Size: 900000, Header: header("Content-Type", "text/javascript", "Expires", "Thu, 01 Dec 2030 16:00:00 GMT")},
+		"https://test.com/tiny.js":      {Size: 200, Header: header("Content-Type", "text/javascript", "Cache-Control", "max-age=3600")},
+		"https://test.com/hero.jpg":     {Size: 2 << 20, Header: header("Content-Type", "image/jpeg")},
+		"https://cdn.test.com/logo.svg": {Size: 4096, Header: header("Content-Type", "image/svg+xml", "Cache-Control", "max-age=3600")},
+	}
+
+	got := CompressionAndCaching(responses)
+	want := []Finding{
+		{Check: UncompressedCheck, URL: "https://cdn.test.com/", Detail: "images served without compression: 1, 4.0 KB in total, like https://cdn.test.com/logo.svg (4.0 KB)"},
+		{Check: NoCacheHeadersCheck, URL: "https://test.com/", Detail: "images served without Cache-Control or Expires headers: 1, 2.0 MB in total, like https://test.com/hero.jpg (2.0 MB)"},
+		{Check: UncompressedCheck, URL: "https://test.com/", Detail: "scripts served without compression: 2, 1.1 MB in total, like https://test.com/vendor.js (878.9 KB), https://test.com/app.js (293.0 KB)"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompressionAndCaching() got = %v, want %v", got, want)
+	}
+}
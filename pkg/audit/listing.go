@@ -0,0 +1,19 @@
+package audit
+
+const DirectoryListingCheck = "directory-listing"
+
+// ExposedDirectories reports the directory listings gathered by the PageCollector, the directory
+// indexes generated by a web server with directory browsing enabled, which usually expose files that
+// were never meant to be linked.
+func ExposedDirectories(listings []string) []Finding {
+	var findings []Finding
+	for _, page := range listings {
+		findings = append(findings, Finding{
+			Check:  DirectoryListingCheck,
+			URL:    page,
+			Detail: "directory listing is publicly accessible",
+		})
+	}
+	sortFindings(findings)
+	return findings
+}
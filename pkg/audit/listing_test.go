@@ -0,0 +1,31 @@
+package audit
+
+import (
+	"io"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestExposedDirectories(t *testing.T) {
+	collector := NewPageCollector(mockFetcher{pages: map[string]string{
+		"https://test.com/uploads/": `<html><head><title>Index of /uploads</title></head><body><h1>Index of /uploads</h1></body></html>`,
+		"https://test.com/about":    `<html><head><title>About</title></head><body><h1>About us</h1></body></html>`,
+	}})
+	for _, path := range []string{"/uploads/", "/about"} {
+		content, err := collector.FetchWebpageContent(url.URL{Scheme: "https", Host: "test.com", Path: path})
+		if err != nil {
+			t.Fatalf("should not throw error at collector.FetchWebpageContent. err: %v", err)
+		}
+		_, _ = io.ReadAll(content)
+	}
+
+	got := ExposedDirectories(collector.DirectoryListings())
+
+	want := []Finding{
+		{Check: DirectoryListingCheck, URL: "https://test.com/uploads", Detail: "directory listing is publicly accessible"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExposedDirectories() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+const (
+	LocaleParityCheck = "locale-parity"
+	HreflangCheck     = "hreflang"
+)
+
+// localizedPage is a page of the site without its locale prefix.
+type localizedPage struct {
+	host string
+	path string
+}
+
+// LocaleParity compares the pages found under every locale path prefix (e.g. "en" for /en/) and
+// reports the pages that exist in some locales but are missing in others. Links outside the
+// locale prefixes are ignored.
+func LocaleParity(links []string, locales []string) []Finding {
+	pagesByLocale := make(map[localizedPage]map[string]url.URL)
+	for _, link := range links {
+		parsedLink, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		locale, path, ok := splitLocale(parsedLink.Path, locales)
+		if !ok {
+			continue
+		}
+		page := localizedPage{host: parsedLink.Host, path: path}
+		if pagesByLocale[page] == nil {
+			pagesByLocale[page] = make(map[string]url.URL)
+		}
+		pagesByLocale[page][locale] = *parsedLink
+	}
+
+	var findings []Finding
+	for page, pageLocales := range pagesByLocale {
+		if len(pageLocales) == len(locales) {
+			continue
+		}
+		var presentIn []string
+		var example url.URL
+		for _, locale := range locales {
+			if link, ok := pageLocales[locale]; ok {
+				presentIn = append(presentIn, locale)
+				example = link
+			}
+		}
+		for _, locale := range locales {
+			if _, ok := pageLocales[locale]; ok {
+				continue
+			}
+			missing := example
+			missing.Path = "/" + locale + page.path
+			findings = append(findings, Finding{
+				Check:  LocaleParityCheck,
+				URL:    missing.String(),
+				Detail: fmt.Sprintf("missing in locale %s, found in %s", locale, strings.Join(presentIn, ", ")),
+			})
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+// splitLocale returns the locale prefix of the path and the rest of the path.
+func splitLocale(path string, locales []string) (string, string, bool) {
+	for _, locale := range locales {
+		prefix := "/" + locale
+		if path == prefix {
+			return locale, "", true
+		}
+		if strings.HasPrefix(path, prefix+"/") {
+			return locale, strings.TrimSuffix(strings.TrimPrefix(path, prefix), "/"), true
+		}
+	}
+	return "", "", false
+}
+
+// HreflangConsistency checks the hreflang annotations of the crawled pages, given the metadata
+// of every page URL, as gathered by a PageCollector. It reports alternates that are not crawled
+// pages, and alternates that don't declare the page back as one of their own alternates.
+func HreflangConsistency(pages map[string]linkextractor.Meta) []Finding {
+	var findings []Finding
+	for page, meta := range pages {
+		for _, alternate := range meta.Alternates {
+			alternateURL := alternate.URL.String()
+			if alternateURL == page {
+				continue
+			}
+			alternateMeta, crawled := pages[alternateURL]
+			if !crawled {
+				findings = append(findings, Finding{
+					Check:  HreflangCheck,
+					URL:    page,
+					Detail: fmt.Sprintf("hreflang %s alternate %s was not crawled", alternate.Lang, alternateURL),
+				})
+				continue
+			}
+			if !declaresAlternate(alternateMeta.Alternates, page) {
+				findings = append(findings, Finding{
+					Check:  HreflangCheck,
+					URL:    page,
+					Detail: fmt.Sprintf("hreflang %s alternate %s doesn't link back", alternate.Lang, alternateURL),
+				})
+			}
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+func declaresAlternate(alternates []linkextractor.Alternate, page string) bool {
+	for _, alternate := range alternates {
+		if alternate.URL.String() == page {
+			return true
+		}
+	}
+	return false
+}
+
+func sortFindings(findings []Finding) {
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].URL != findings[j].URL {
+			return findings[i].URL < findings[j].URL
+		}
+		return findings[i].Detail < findings[j].Detail
+	})
+}
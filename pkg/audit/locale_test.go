@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+func TestLocaleParity(t *testing.T) {
+	links := []string{
+		"https://test.com",
+		"https://test.com/en",
+		"https://test.com/de",
+		"https://test.com/en/about",
+		"https://test.com/de/about",
+		"https://test.com/fr/about",
+		"https://test.com/en/pricing",
+		"https://test.com/blog",
+	}
+
+	got := LocaleParity(links, []string{"en", "de", "fr"})
+
+	want := []Finding{
+		{Check: LocaleParityCheck, URL: "https://test.com/de/pricing", Detail: "missing in locale de, found in en"},
+		{Check: LocaleParityCheck, URL: "https://test.com/fr", Detail: "missing in locale fr, found in en, de"},
+		{Check: LocaleParityCheck, URL: "https://test.com/fr/pricing", Detail: "missing in locale fr, found in en"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LocaleParity() got = %v, want %v", got, want)
+	}
+}
+
+func TestHreflangConsistency(t *testing.T) {
+	alternate := func(lang, link string) linkextractor.Alternate {
+		parsedLink, _ := url.Parse(link)
+		return linkextractor.Alternate{Lang: lang, URL: *parsedLink}
+	}
+	pages := map[string]linkextractor.Meta{
+		"https://test.com/en/about": {Alternates: []linkextractor.Alternate{alternate("en", "https://test.com/en/about"), alternate("de", "https://test.com/de/about"), alternate("fr", "https://test.com/fr/about")}},
+		"https://test.com/de/about": {Alternates: []linkextractor.Alternate{alternate("de", "https://test.com/de/about"), alternate("en", "https://test.com/en/about")}},
+		"https://test.com/fr/about": {Alternates: []linkextractor.Alternate{alternate("fr", "https://test.com/fr/about"), alternate("it", "https://test.com/it/about")}},
+	}
+
+	got := HreflangConsistency(pages)
+
+	want := []Finding{
+		{Check: HreflangCheck, URL: "https://test.com/en/about", Detail: "hreflang fr alternate https://test.com/fr/about doesn't link back"},
+		{Check: HreflangCheck, URL: "https://test.com/fr/about", Detail: "hreflang it alternate https://test.com/it/about was not crawled"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HreflangConsistency() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+const HomepageRedirectsCheck = "homepage-redirects"
+
+// homepageRedirectExamples is the number of redirected URLs listed in a homepage redirects finding.
+const homepageRedirectExamples = 3
+
+// RedirectsToHomepage detects the site migration antipattern where many distinct URLs redirect
+// to the homepage instead of to their new pages. It reports a single finding per homepage
+// redirected to by at least minRedirects distinct URLs, with the count and a few examples.
+func RedirectsToHomepage(redirects []fetcher.Redirect, minRedirects int) []Finding {
+	redirectedURLs := make(map[string][]string)
+	var homepages []string
+	for _, redirect := range redirects {
+		if !isHomepage(redirect.To.Path) || isHomepage(redirect.From.Path) {
+			continue
+		}
+		homepage := redirect.To.Scheme + "://" + redirect.To.Host + "/"
+		if _, ok := redirectedURLs[homepage]; !ok {
+			homepages = append(homepages, homepage)
+		}
+		redirectedURLs[homepage] = append(redirectedURLs[homepage], redirect.From.String())
+	}
+
+	var findings []Finding
+	for _, homepage := range homepages {
+		urls := redirectedURLs[homepage]
+		if len(urls) < minRedirects {
+			continue
+		}
+		examples := urls
+		if len(examples) > homepageRedirectExamples {
+			examples = examples[:homepageRedirectExamples]
+		}
+		findings = append(findings, Finding{
+			Check:  HomepageRedirectsCheck,
+			URL:    homepage,
+			Detail: fmt.Sprintf("%d distinct URLs redirect to the homepage, e.g. %s", len(urls), strings.Join(examples, ", ")),
+		})
+	}
+	sortFindings(findings)
+	return findings
+}
+
+func isHomepage(path string) bool {
+	return path == "" || path == "/" || strings.EqualFold(path, "/index.html")
+}
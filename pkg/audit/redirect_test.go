@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+func TestRedirectsToHomepage(t *testing.T) {
+	redirect := func(from, to string) fetcher.Redirect {
+		fromURL, _ := url.Parse(from)
+		toURL, _ := url.Parse(to)
+		return fetcher.Redirect{From: *fromURL, To: *toURL, StatusCode: 301, Hops: 1}
+	}
+	redirects := []fetcher.Redirect{
+		redirect("https://test.com/old-1", "https://test.com/"),
+		redirect("https://test.com/old-2", "https://test.com"),
+		redirect("https://test.com/old-3", "https://test.com/"),
+		redirect("https://test.com/old-4", "https://test.com/index.html"),
+		redirect("http://test.com", "https://test.com/"),
+		redirect("https://test.com/old-5", "https://test.com/new-5"),
+		redirect("https://other.com/old-1", "https://other.com/"),
+	}
+
+	got := RedirectsToHomepage(redirects, 3)
+
+	want := []Finding{{
+		Check:  HomepageRedirectsCheck,
+		URL:    "https://test.com/",
+		Detail: "4 distinct URLs redirect to the homepage, e.g. https://test.com/old-1, https://test.com/old-2, https://test.com/old-3",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RedirectsToHomepage() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+)
+
+const (
+	RobotsDisallowedLinkCheck = "robots-disallowed-link"
+	RobotsLinkedSectionCheck  = "robots-linked-section"
+)
+
+// robotsLinkExamples is the number of linking pages listed in a robots finding.
+const robotsLinkExamples = 3
+
+// robotsRules tells which robots.txt rule disallows a link, e.g. a robots.Policy.
+type robotsRules interface {
+	DisallowedBy(link url.URL) (string, bool)
+}
+
+// disallowedSection is a disallow rule of the robots.txt file of a host.
+type disallowedSection struct {
+	robotsURL string
+	pattern   string
+}
+
+// RobotsConflicts reports the crawled pages that link to URLs disallowed by robots.txt, a common
+// SEO misconfiguration, using the link graph of the crawl. Both the edges and the links excluded
+// from the crawl are checked, so it works whether the crawl respected robots.txt or not. Every
+// disallowed URL gets a finding, and every disallowed section linked from at least
+// minLinkingPages distinct pages, like a section linked from the navigation, gets another one.
+func RobotsConflicts(graph *crawler.CrawlGraph, rules robotsRules, minLinkingPages int) []Finding {
+	linkingPages := make(map[string][]string)
+	sectionPages := make(map[disallowedSection]map[string]bool)
+	sectionPatterns := make(map[string]string)
+	edges := append(append([]crawler.Edge{}, graph.Edges...), graph.Excluded...)
+	for _, edge := range edges {
+		link, err := url.Parse(edge.To)
+		if err != nil {
+			continue
+		}
+		pattern, disallowed := rules.DisallowedBy(*link)
+		if !disallowed {
+			continue
+		}
+		if !containsString(linkingPages[edge.To], edge.From) {
+			linkingPages[edge.To] = append(linkingPages[edge.To], edge.From)
+		}
+		sectionPatterns[edge.To] = pattern
+
+		section := disallowedSection{robotsURL: link.Scheme + "://" + link.Host + "/robots.txt", pattern: pattern}
+		if sectionPages[section] == nil {
+			sectionPages[section] = make(map[string]bool)
+		}
+		sectionPages[section][edge.From] = true
+	}
+
+	var findings []Finding
+	for link, pages := range linkingPages {
+		findings = append(findings, Finding{
+			Check:  RobotsDisallowedLinkCheck,
+			URL:    link,
+			Detail: fmt.Sprintf("disallowed by robots.txt rule %s but linked from %d crawled pages, e.g. %s", sectionPatterns[link], len(pages), strings.Join(linkExamples(pages), ", ")),
+		})
+	}
+	for section, pages := range sectionPages {
+		if len(pages) < minLinkingPages {
+			continue
+		}
+		findings = append(findings, Finding{
+			Check:  RobotsLinkedSectionCheck,
+			URL:    section.robotsURL,
+			Detail: fmt.Sprintf("section disallowed by rule %s is linked from %d crawled pages", section.pattern, len(pages)),
+		})
+	}
+	sortFindings(findings)
+	return findings
+}
+
+func linkExamples(pages []string) []string {
+	if len(pages) > robotsLinkExamples {
+		return pages[:robotsLinkExamples]
+	}
+	return pages
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
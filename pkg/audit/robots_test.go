@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+)
+
+type mockRobotsRules struct {
+	disallowedPrefixes []string
+}
+
+func (m mockRobotsRules) DisallowedBy(link url.URL) (string, bool) {
+	for _, prefix := range m.disallowedPrefixes {
+		if strings.HasPrefix(link.Path, prefix) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+func TestRobotsConflicts(t *testing.T) {
+	graph := &crawler.CrawlGraph{
+		Nodes: []string{"https://test.com", "https://test.com/about", "https://test.com/blog", "https://test.com/private/crawled"},
+		Edges: []crawler.Edge{
+			{From: "https://test.com", To: "https://test.com/about"},
+			{From: "https://test.com", To: "https://test.com/private/crawled"},
+		},
+		Excluded: []crawler.Edge{
+			{From: "https://test.com", To: "https://test.com/cart"},
+			{From: "https://test.com/about", To: "https://test.com/cart"},
+			{From: "https://test.com/blog", To: "https://test.com/cart"},
+			{From: "https://test.com/blog", To: "https://test.com/private/report"},
+		},
+	}
+	rules := mockRobotsRules{disallowedPrefixes: []string{"/cart", "/private"}}
+
+	got := RobotsConflicts(graph, rules, 3)
+
+	want := []Finding{
+		{Check: RobotsDisallowedLinkCheck, URL: "https://test.com/cart", Detail: "disallowed by robots.txt rule /cart but linked from 3 crawled pages, e.g. https://test.com, https://test.com/about, https://test.com/blog"},
+		{Check: RobotsDisallowedLinkCheck, URL: "https://test.com/private/crawled", Detail: "disallowed by robots.txt rule /private but linked from 1 crawled pages, e.g. https://test.com"},
+		{Check: RobotsDisallowedLinkCheck, URL: "https://test.com/private/report", Detail: "disallowed by robots.txt rule /private but linked from 1 crawled pages, e.g. https://test.com/blog"},
+		{Check: RobotsLinkedSectionCheck, URL: "https://test.com/robots.txt", Detail: "section disallowed by rule /cart is linked from 3 crawled pages"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RobotsConflicts() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+const InsecureLinkCheck = "insecure-internal-link"
+
+// InsecureLinks reports the http:// links to hosts served over https, like the ones upgraded by a
+// fetcher.SchemeUpgrader during the crawl. Even when the server redirects them, every such link
+// costs a redirect and can leak the first request over plain http. Every link gets a finding with
+// the number of distinct pages linking to it and a few examples.
+func InsecureLinks(insecureLinks []fetcher.InsecureLink) []Finding {
+	linkingPages := make(map[string][]string)
+	for _, insecureLink := range insecureLinks {
+		link, page := insecureLink.Link.String(), insecureLink.Page.String()
+		if !containsString(linkingPages[link], page) {
+			linkingPages[link] = append(linkingPages[link], page)
+		}
+	}
+
+	var findings []Finding
+	for link, pages := range linkingPages {
+		findings = append(findings, Finding{
+			Check:  InsecureLinkCheck,
+			URL:    link,
+			Detail: fmt.Sprintf("http:// link to a host served over https, linked from %d crawled pages, e.g. %s", len(pages), strings.Join(linkExamples(pages), ", ")),
+		})
+	}
+	sortFindings(findings)
+	return findings
+}
@@ -0,0 +1,33 @@
+package audit
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+func TestInsecureLinks(t *testing.T) {
+	insecureLink := func(page, link string) fetcher.InsecureLink {
+		pageURL, _ := url.Parse(page)
+		linkURL, _ := url.Parse(link)
+		return fetcher.InsecureLink{Page: *pageURL, Link: *linkURL}
+	}
+	insecureLinks := []fetcher.InsecureLink{
+		insecureLink("https://test.com", "http://test.com/pricing"),
+		insecureLink("https://test.com/about", "http://test.com/pricing"),
+		insecureLink("https://test.com", "http://test.com/pricing"),
+		insecureLink("https://test.com/about", "http://test.com/contact"),
+	}
+
+	got := InsecureLinks(insecureLinks)
+
+	want := []Finding{
+		{Check: InsecureLinkCheck, URL: "http://test.com/contact", Detail: "http:// link to a host served over https, linked from 1 crawled pages, e.g. https://test.com/about"},
+		{Check: InsecureLinkCheck, URL: "http://test.com/pricing", Detail: "http:// link to a host served over https, linked from 2 crawled pages, e.g. https://test.com, https://test.com/about"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InsecureLinks() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package audit
+
+import (
+	"net/url"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+const (
+	SitemapUnreachableCheck = "sitemap-unreachable"
+	SitemapMissingCheck     = "sitemap-missing"
+)
+
+// SitemapCoverage compares the URLs listed in the sitemaps of the site with the pages reachable
+// through links from the crawled URL, following the edges of the link graph of the crawl. It reports
+// the URLs in the sitemaps that no crawled page links to, and the reachable pages missing from the
+// sitemaps. Only the URLs of the host of the crawled URL are compared, and as the graph is limited
+// by the depth of the crawl, pages deeper than it are reported as unreachable.
+func SitemapCoverage(crawledURL url.URL, sitemapURLs []url.URL, graph *crawler.CrawlGraph) []Finding {
+	startURL := linkextractor.Normalize(crawledURL)
+
+	inSitemap := make(map[string]bool)
+	for _, sitemapURL := range sitemapURLs {
+		normalizedURL := linkextractor.Normalize(sitemapURL)
+		if normalizedURL.Host == startURL.Host {
+			inSitemap[normalizedURL.String()] = true
+		}
+	}
+	reachable := reachableFrom(startURL.String(), graph.Edges)
+
+	var findings []Finding
+	for link := range inSitemap {
+		if !reachable[link] {
+			findings = append(findings, Finding{
+				Check:  SitemapUnreachableCheck,
+				URL:    link,
+				Detail: "listed in the sitemap but not linked from any crawled page",
+			})
+		}
+	}
+	for link := range reachable {
+		parsedLink, err := url.Parse(link)
+		if err != nil || parsedLink.Host != startURL.Host || inSitemap[link] {
+			continue
+		}
+		findings = append(findings, Finding{
+			Check:  SitemapMissingCheck,
+			URL:    link,
+			Detail: "reachable through links but missing from the sitemap",
+		})
+	}
+	sortFindings(findings)
+	return findings
+}
+
+// reachableFrom returns the pages reachable from the start page following the edges.
+func reachableFrom(start string, edges []crawler.Edge) map[string]bool {
+	linksFrom := make(map[string][]string)
+	for _, edge := range edges {
+		linksFrom[edge.From] = append(linksFrom[edge.From], edge.To)
+	}
+
+	reachable := map[string]bool{start: true}
+	pending := []string{start}
+	for len(pending) > 0 {
+		page := pending[0]
+		pending = pending[1:]
+		for _, link := range linksFrom[page] {
+			if !reachable[link] {
+				reachable[link] = true
+				pending = append(pending, link)
+			}
+		}
+	}
+	return reachable
+}
@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+)
+
+func TestSitemapCoverage(t *testing.T) {
+	parse := func(link string) url.URL {
+		parsedURL, _ := url.Parse(link)
+		return *parsedURL
+	}
+	sitemapURLs := []url.URL{
+		parse("https://www.test.com/"),
+		parse("https://test.com/about/"),
+		parse("https://test.com/landing"),
+		parse("https://test.com/seeded"),
+		parse("https://other.com/page"),
+	}
+	graph := &crawler.CrawlGraph{
+		Nodes: []string{"https://test.com", "https://test.com/about", "https://test.com/contact", "https://test.com/seeded", "https://test.com/team"},
+		Edges: []crawler.Edge{
+			{From: "https://test.com", To: "https://test.com/about"},
+			{From: "https://test.com", To: "https://test.com/contact"},
+			{From: "https://test.com/about", To: "https://test.com/team"},
+			{From: "https://test.com/seeded", To: "https://test.com/about"},
+		},
+	}
+
+	got := SitemapCoverage(parse("https://test.com/"), sitemapURLs, graph)
+
+	want := []Finding{
+		{Check: SitemapMissingCheck, URL: "https://test.com/contact", Detail: "reachable through links but missing from the sitemap"},
+		{Check: SitemapUnreachableCheck, URL: "https://test.com/landing", Detail: "listed in the sitemap but not linked from any crawled page"},
+		{Check: SitemapUnreachableCheck, URL: "https://test.com/seeded", Detail: "listed in the sitemap but not linked from any crawled page"},
+		{Check: SitemapMissingCheck, URL: "https://test.com/team", Detail: "reachable through links but missing from the sitemap"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SitemapCoverage() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"sort"
+
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+// ThirdPartyHost is a host out of the site that the crawled pages load assets from.
+type ThirdPartyHost struct {
+	Host string
+	// Resources is the number of distinct assets loaded from the host, by type.
+	Resources map[linkextractor.ResourceType]int
+	// Pages are the crawled pages that load assets from the host, sorted.
+	Pages []string
+}
+
+// ThirdPartyInventory aggregates by host the third-party assets loaded by the crawled pages, given by
+// page as gathered by a PageCollector, like the scripts of analytics and ads providers, the styles and
+// fonts of CDNs, the images and the embedded iframes, for the supply-chain and privacy reviews of the
+// site. The hosts are sorted by the number of pages that load assets from them, most first.
+func ThirdPartyInventory(thirdPartyResources map[string][]linkextractor.Resource) []ThirdPartyHost {
+	hosts := make(map[string]*ThirdPartyHost)
+	assets := make(map[string]bool)
+	for page, pageResources := range thirdPartyResources {
+		for _, resource := range pageResources {
+			host, ok := hosts[resource.URL.Host]
+			if !ok {
+				host = &ThirdPartyHost{Host: resource.URL.Host, Resources: make(map[linkextractor.ResourceType]int)}
+				hosts[resource.URL.Host] = host
+			}
+			if asset := string(resource.Type) + " " + resource.URL.String(); !assets[asset] {
+				assets[asset] = true
+				host.Resources[resource.Type]++
+			}
+			if !containsString(host.Pages, page) {
+				host.Pages = append(host.Pages, page)
+			}
+		}
+	}
+
+	inventory := make([]ThirdPartyHost, 0, len(hosts))
+	for _, host := range hosts {
+		sort.Strings(host.Pages)
+		inventory = append(inventory, *host)
+	}
+	sort.Slice(inventory, func(i, j int) bool {
+		if len(inventory[i].Pages) != len(inventory[j].Pages) {
+			return len(inventory[i].Pages) > len(inventory[j].Pages)
+		}
+		return inventory[i].Host < inventory[j].Host
+	})
+	return inventory
+}
@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+func TestThirdPartyInventory(t *testing.T) {
+	resource := func(resourceType linkextractor.ResourceType, link string) linkextractor.Resource {
+		parsedLink, _ := url.Parse(link)
+		return linkextractor.Resource{Type: resourceType, URL: *parsedLink}
+	}
+	thirdPartyResources := map[string][]linkextractor.Resource{
+		"https://test.com": {
+			resource(linkextractor.ScriptResource, "https://analytics.com/tag.js"),
+			resource(linkextractor.StylesheetResource, "https://fonts.cdn.com/css"),
+			resource(linkextractor.LinkResource, "https://fonts.cdn.com/font.woff2"),
+		},
+		"https://test.com/about": {
+			resource(linkextractor.ScriptResource, "https://analytics.com/tag.js"),
+			resource(linkextractor.ImageResource, "https://analytics.com/pixel.gif"),
+		},
+		"https://test.com/contact": {},
+	}
+
+	got := ThirdPartyInventory(thirdPartyResources)
+	want := []ThirdPartyHost{
+		{Host: "analytics.com", Resources: map[linkextractor.ResourceType]int{linkextractor.ScriptResource: 1, linkextractor.ImageResource: 1}, Pages: []string{"https://test.com", "https://test.com/about"}},
+		{Host: "fonts.cdn.com", Resources: map[linkextractor.ResourceType]int{linkextractor.StylesheetResource: 1, linkextractor.LinkResource: 1}, Pages: []string{"https://test.com"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ThirdPartyInventory() got = %v, want %v", got, want)
+	}
+}
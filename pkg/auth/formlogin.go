@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// LoginFormNotFound indicates that the login page doesn't contain a form to submit the credentials to.
+var LoginFormNotFound = errors.New("login form not found")
+
+// LoginFailed indicates that the credentials were submitted but the site didn't accept them.
+var LoginFailed = errors.New("login failed")
+
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type loginForm struct {
+	action url.URL
+	method string
+	fields url.Values
+}
+
+// FormLogin authenticates against a site by submitting its HTML login form. The session cookies set
+// by the site are stored in the cookie jar of the provided client, so the client must have one.
+type FormLogin struct {
+	httpClient  httpDoer
+	loginURL    url.URL
+	credentials url.Values
+}
+
+// NewFormLogin creates a new FormLogin that submits the given credentials to the login form found at
+// loginURL. credentials maps the names of the form fields to their values, for example
+// url.Values{"username": {"admin"}, "password": {"secret"}}.
+func NewFormLogin(httpClient httpDoer, loginURL url.URL, credentials url.Values) *FormLogin {
+	return &FormLogin{httpClient: httpClient, loginURL: loginURL, credentials: credentials}
+}
+
+// Login fetches the login page, extracts its login form including the hidden fields (like CSRF tokens),
+// fills in the credentials, and submits it.
+// The method returns LoginFormNotFound if the login page has no form, and LoginFailed if after submitting
+// the form the site responds with an error or with the login form again.
+func (l *FormLogin) Login() error {
+	form, err := l.fetchLoginForm()
+	if err != nil {
+		return err
+	}
+
+	for name, values := range l.credentials {
+		form.fields[name] = values
+	}
+
+	res, err := l.submit(form)
+	if err != nil {
+		return err
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(res.Body)
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%w: status %s", LoginFailed, res.Status)
+	}
+	// sites usually render the login form again when the credentials are not valid
+	if _, err := findLoginForm(*res.Request.URL, res.Body); err == nil {
+		return LoginFailed
+	}
+	return nil
+}
+
+// IsLoginPage reports whether the link is the login page, ignoring its query and trailing slash, like
+// the ?redirect_to= parameter the sites add when they send an expired session to it. Use it with
+// fetcher.DetectLoginRedirect and fetcher.WithReauth to log in again once the session expires mid-crawl.
+func (l *FormLogin) IsLoginPage(link url.URL) bool {
+	return strings.EqualFold(link.Host, l.loginURL.Host) && strings.TrimSuffix(link.Path, "/") == strings.TrimSuffix(l.loginURL.Path, "/")
+}
+
+func (l *FormLogin) fetchLoginForm() (*loginForm, error) {
+	req, err := http.NewRequest(http.MethodGet, l.loginURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(res.Body)
+
+	return findLoginForm(*res.Request.URL, res.Body)
+}
+
+func (l *FormLogin) submit(form *loginForm) (*http.Response, error) {
+	var req *http.Request
+	var err error
+	if form.method == http.MethodGet {
+		action := form.action
+		action.RawQuery = form.fields.Encode()
+		req, err = http.NewRequest(http.MethodGet, action.String(), nil)
+	} else {
+		req, err = http.NewRequest(http.MethodPost, form.action.String(), strings.NewReader(form.fields.Encode()))
+		if req != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l.httpClient.Do(req)
+}
+
+// findLoginForm returns the first form of the page that has a password field.
+func findLoginForm(pageURL url.URL, pageContent io.Reader) (*loginForm, error) {
+	parsedHtmlContent, err := html.Parse(pageContent)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, formNode := range searchElements(parsedHtmlContent, "form") {
+		if hasPasswordField(formNode) {
+			return buildLoginForm(pageURL, formNode), nil
+		}
+	}
+	return nil, LoginFormNotFound
+}
+
+func buildLoginForm(pageURL url.URL, formNode *html.Node) *loginForm {
+	form := &loginForm{action: pageURL, method: http.MethodPost, fields: url.Values{}}
+	if action := attribute(formNode, "action"); action != "" {
+		if actionURL, err := pageURL.Parse(action); err == nil {
+			form.action = *actionURL
+		}
+	}
+	if strings.EqualFold(attribute(formNode, "method"), http.MethodGet) {
+		form.method = http.MethodGet
+	}
+
+	// prefilled fields carry the CSRF tokens and any other value the site expects back
+	for _, input := range searchElements(formNode, "input") {
+		name := attribute(input, "name")
+		inputType := strings.ToLower(attribute(input, "type"))
+		if name == "" || inputType == "submit" || inputType == "button" || inputType == "image" {
+			continue
+		}
+		if (inputType == "checkbox" || inputType == "radio") && !hasAttribute(input, "checked") {
+			continue
+		}
+		form.fields.Add(name, attribute(input, "value"))
+	}
+	return form
+}
+
+func hasPasswordField(formNode *html.Node) bool {
+	for _, input := range searchElements(formNode, "input") {
+		if strings.EqualFold(attribute(input, "type"), "password") {
+			return true
+		}
+	}
+	return false
+}
+
+func searchElements(node *html.Node, tag string) []*html.Node {
+	var elements []*html.Node
+	if node.Type == html.ElementNode && node.Data == tag {
+		elements = append(elements, node)
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		elements = append(elements, searchElements(child, tag)...)
+	}
+	return elements
+}
+
+func attribute(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func hasAttribute(node *html.Node, key string) bool {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
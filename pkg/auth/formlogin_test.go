@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+const loginPage = `<html><body>
+<form action="/search"><input name="q"></form>
+<form method="post" action="/session">
+	<input type="hidden" name="csrf_token" value="token123">
+	<input type="text" name="username">
+	<input type="password" name="password">
+	<input type="checkbox" name="remember_me" value="1">
+	<input type="submit" name="commit" value="Log in">
+</form>
+</body></html>`
+
+func newLoginServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(loginPage))
+	})
+	mux.HandleFunc("/session", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("login form could not be parsed. err: %v", err)
+		}
+		if r.PostForm.Get("csrf_token") != "token123" {
+			http.Error(w, "invalid csrf token", http.StatusForbidden)
+			return
+		}
+		if r.PostForm.Has("remember_me") || r.PostForm.Has("commit") {
+			t.Errorf("unchecked and submit fields should not be submitted. form: %v", r.PostForm)
+		}
+		if r.PostForm.Get("username") != "admin" || r.PostForm.Get("password") != "secret" {
+			_, _ = w.Write([]byte(loginPage))
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "valid"})
+		http.Redirect(w, r, "/dashboard", http.StatusFound)
+	})
+	mux.HandleFunc("/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<p>dashboard</p>"))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestFormLogin_Login(t *testing.T) {
+	server := newLoginServer(t)
+	defer server.Close()
+	loginUrl, _ := url.Parse(server.URL + "/login")
+
+	t.Run("submits the csrf token along with the credentials and keeps the session cookie", func(t *testing.T) {
+		jar, _ := cookiejar.New(nil)
+		formLogin := NewFormLogin(&http.Client{Jar: jar}, *loginUrl, url.Values{
+			"username": {"admin"},
+			"password": {"secret"},
+		})
+
+		if err := formLogin.Login(); err != nil {
+			t.Fatalf("should not throw error at Login. err: %v", err)
+		}
+		if cookies := jar.Cookies(loginUrl); len(cookies) != 1 || cookies[0].Value != "valid" {
+			t.Errorf("Login() session cookies got = %v, want session=valid", cookies)
+		}
+	})
+
+	t.Run("returns LoginFailed when the login form is rendered again", func(t *testing.T) {
+		jar, _ := cookiejar.New(nil)
+		formLogin := NewFormLogin(&http.Client{Jar: jar}, *loginUrl, url.Values{
+			"username": {"admin"},
+			"password": {"wrong"},
+		})
+
+		if err := formLogin.Login(); !errors.Is(err, LoginFailed) {
+			t.Errorf("Login() error = %v, want %v", err, LoginFailed)
+		}
+	})
+
+	t.Run("returns LoginFormNotFound when the page has no login form", func(t *testing.T) {
+		dashboardUrl, _ := url.Parse(server.URL + "/dashboard")
+		formLogin := NewFormLogin(&http.Client{}, *dashboardUrl, url.Values{})
+
+		if err := formLogin.Login(); !errors.Is(err, LoginFormNotFound) {
+			t.Errorf("Login() error = %v, want %v", err, LoginFormNotFound)
+		}
+	})
+}
+
+func TestFormLogin_ReauthenticatesMidCrawl(t *testing.T) {
+	var mu sync.Mutex
+	session, logins, served := "first", 0, 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(loginPage))
+	})
+	mux.HandleFunc("/session", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		logins++
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: session})
+		http.Redirect(w, r, "/welcome", http.StatusFound)
+	})
+	mux.HandleFunc("/welcome", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<p>welcome</p>"))
+	})
+	pages := map[string]string{
+		"/":  `<a href="/a">a</a><a href="/b">b</a><a href="/c">c</a>`,
+		"/a": `<p>a</p>`,
+		"/b": `<p>b</p>`,
+		"/c": `<p>c</p>`,
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != session {
+			http.Redirect(w, r, "/login?redirect_to="+url.QueryEscape(r.URL.Path), http.StatusFound)
+			return
+		}
+		served++
+		// the session expires after the second page
+		if served == 2 {
+			session = "second"
+		}
+		_, _ = w.Write([]byte(pages[r.URL.Path]))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	loginUrl, _ := url.Parse(server.URL + "/login")
+	seedUrl, _ := url.Parse(server.URL + "/")
+
+	jar, _ := cookiejar.New(nil)
+	formLogin := NewFormLogin(&http.Client{Jar: jar}, *loginUrl, url.Values{"username": {"admin"}, "password": {"secret"}})
+	if err := formLogin.Login(); err != nil {
+		t.Fatalf("should not throw error at Login. err: %v", err)
+	}
+	crawlClient := &http.Client{Jar: jar, CheckRedirect: fetcher.DetectLoginRedirect(formLogin.IsLoginPage)}
+	pageFetcher := fetcher.Chain(fetcher.NewHTTPFetcher(crawlClient), fetcher.WithReauth(formLogin.Login))
+
+	var crawlErrors []error
+	bfCrawler := crawler.NewBreadthFirstCrawler(pageFetcher, crawler.WithWaitForCallbacks(), crawler.WithOnErrorCallback(func(_ url.URL, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		crawlErrors = append(crawlErrors, err)
+	}))
+	links, err := bfCrawler.Crawl(context.Background(), *seedUrl, 2, 1)
+	if err != nil {
+		t.Fatalf("should not throw error at Crawl. err: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(crawlErrors) > 0 {
+		t.Errorf("Crawl() errors got = %v, want none once logged in again", crawlErrors)
+	}
+	if len(links) != 4 || served != 4 {
+		t.Errorf("Crawl() got = %v and served %d pages, want the 4 pages", links, served)
+	}
+	if logins != 2 {
+		t.Errorf("logged in %d times, want 2", logins)
+	}
+}
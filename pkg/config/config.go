@@ -0,0 +1,666 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+	"github.com/andiblas/website-crawler/pkg/resolver"
+	"github.com/andiblas/website-crawler/pkg/robots"
+	"github.com/andiblas/website-crawler/pkg/sitemap"
+)
+
+// userAgent is the user agent whose robots.txt rules are respected.
+const userAgent = "website-crawler"
+
+// InvalidValueError is returned when a key of the config has an invalid value.
+type InvalidValueError struct {
+	Key    string
+	Reason string
+}
+
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Key, e.Reason)
+}
+
+// Config is the declarative setup of a crawl: the fetcher chain and the crawler options. Its keys are
+// the names of the arguments of the command line, with the lists as JSON arrays instead of comma
+// separated values, and the command line builds its crawl from them, so a crawl set up with a Config
+// runs the same way from Go with NewCrawler. The other arguments of the command line, like the ones of
+// its session, its state, its outputs and its audits, are not part of the setup, and Parse rejects
+// them along with any other unknown key.
+type Config struct {
+	URL            string `json:"url"`
+	Depth          int    `json:"depth"`
+	MaxConcurrency int    `json:"max_concurrency"`
+	// CheckSeed verifies the URL responds with a page before crawling it, following its redirects, and
+	// ProbeSeedScheme tries both its https and http variants. Only applied by NewCrawler.
+	CheckSeed       bool `json:"check_seed"`
+	ProbeSeedScheme bool `json:"probe_seed_scheme"`
+	// Seeds is the path of a file with more URLs to start the crawl from, one per line, followed by
+	// their metadata as key=value pairs. See crawler.ParseSeeds.
+	Seeds string `json:"seeds"`
+
+	// Timeout is the timeout of the requests in milliseconds. DNSTimeout, ConnectTimeout, TLSTimeout and
+	// ReadTimeout bound their DNS lookups, connections, TLS handshakes, and waits for the response
+	// headers and for more data of the body, in milliseconds. 0 doesn't bound the DNS lookups nor the
+	// reads. Only applied to the client built by NewFetchers.
+	Timeout        int `json:"timeout"`
+	DNSTimeout     int `json:"dns_timeout"`
+	ConnectTimeout int `json:"connect_timeout"`
+	TLSTimeout     int `json:"tls_timeout"`
+	ReadTimeout    int `json:"read_timeout"`
+	// MaxIdleConnsPerHost is the number of idle connections kept to every host, 0 for one for every
+	// concurrent request, MaxConnsPerHost limits the connections to every host, 0 for unlimited, and
+	// HTTP2 sends the requests with HTTP/2 to the hosts that support it. Only applied to the client built
+	// by NewFetchers.
+	MaxIdleConnsPerHost int  `json:"max_idle_conns_per_host"`
+	MaxConnsPerHost     int  `json:"max_conns_per_host"`
+	HTTP2               bool `json:"http2"`
+	// LocalAddrs are the local IP addresses the requests are sent from, round-robin, and Proxy the URLs
+	// of the HTTP or HTTPS proxies they're sent through, round-robin. Only applied to the client built
+	// by NewFetchers.
+	LocalAddrs []string `json:"local_addrs"`
+	Proxy      []string `json:"proxy"`
+	Retries    int      `json:"retries"`
+	// RetryJitter randomizes the back offs of the retries by up to this fraction of the delay, between 0 and 1.
+	RetryJitter float64 `json:"retry_jitter"`
+	// CircuitBreaker is the number of consecutive failures of a host after which its requests fail right
+	// away for CircuitBreakerCooldown seconds. 0 disables it.
+	CircuitBreaker         int      `json:"circuit_breaker"`
+	CircuitBreakerCooldown int      `json:"circuit_breaker_cooldown"`
+	RateLimit              float64  `json:"rate_limit"`
+	ContentTypes           []string `json:"content_types"`
+	MaxBodySize            int64    `json:"max_body_size"`
+	ErrorBodySample        int      `json:"error_body_sample"`
+	TolerantCheck          bool     `json:"tolerant_check"`
+	// HeadRequests validates the links to binary files, and the ones of NewAssetFetcher, with HEAD
+	// requests instead of downloading them.
+	HeadRequests bool   `json:"head_requests"`
+	UserAgent    string `json:"user_agent"`
+	// Header are the headers sent with every request, in the "Name: value" format of an HTTP header.
+	Header []string `json:"header"`
+	// BasicAuth are the credentials sent with the HTTP Basic scheme, in the user:password format, and
+	// BearerToken the token sent with the Bearer scheme. Only one of them can be set.
+	BasicAuth   string `json:"basic_auth"`
+	BearerToken string `json:"bearer_token"`
+	// RotateUserAgent are the User-Agent headers the requests are rotated between, in the
+	// UserAgentRotation order, round_robin or random.
+	RotateUserAgent   []string `json:"rotate_user_agent"`
+	UserAgentRotation string   `json:"user_agent_rotation"`
+	CacheDir          string   `json:"cache_dir"`
+	// CacheTTL is the time in seconds the pages saved in the CacheDir are fresh.
+	CacheTTL int `json:"cache_ttl"`
+
+	MaxPages int `json:"max_pages"`
+	// MaxDuration is the time budget of the crawl in seconds.
+	MaxDuration int `json:"max_duration"`
+	// CrawlDelay is the time every worker waits between fetches in milliseconds.
+	CrawlDelay           int      `json:"crawl_delay"`
+	RespectRobots        bool     `json:"respect_robots"`
+	Sitemap              bool     `json:"sitemap"`
+	Scope                string   `json:"scope"`
+	Include              []string `json:"include"`
+	Exclude              []string `json:"exclude"`
+	TrailingSlash        string   `json:"trailing_slash"`
+	Fragments            string   `json:"fragments"`
+	CaseInsensitivePaths bool     `json:"case_insensitive_paths"`
+	Nofollow             string   `json:"nofollow"`
+	DirectoryListings    string   `json:"directory_listings"`
+	MetaRobots           bool     `json:"meta_robots"`
+	DefaultDocuments     []string `json:"default_documents"`
+	// QueryStrings keeps the query strings of the links, removed by default, and StripQueryParams removes
+	// the given parameters from the ones kept. "tracking" stands for linkextractor.TrackingParameters.
+	QueryStrings     bool     `json:"query_strings"`
+	StripQueryParams []string `json:"strip_query_params"`
+	CanonicalURLs    bool     `json:"canonical_urls"`
+	HreflangSeeds    bool     `json:"hreflang_seeds"`
+}
+
+// Default returns the config with the same defaults as the command line.
+func Default() Config {
+	return Config{
+		Depth:                  4,
+		MaxConcurrency:         5,
+		CheckSeed:              true,
+		Timeout:                15000,
+		ConnectTimeout:         30000,
+		TLSTimeout:             10000,
+		HTTP2:                  true,
+		Retries:                3,
+		CircuitBreakerCooldown: 60,
+		ContentTypes:           append([]string(nil), fetcher.DefaultContentTypes...),
+		MaxBodySize:            10 << 20,
+		ErrorBodySample:        1024,
+		UserAgentRotation:      "round_robin",
+		RespectRobots:          true,
+		Scope:                  "host",
+		TrailingSlash:          "strip",
+		Fragments:              "strip",
+		Nofollow:               "follow",
+		DirectoryListings:      "follow",
+	}
+}
+
+// Load reads the JSON config file at the given path. See Parse.
+func Load(path string) (Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Config{}, err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+	return Parse(file)
+}
+
+// Parse reads a JSON config and validates it. The keys missing from it keep their default values, and
+// the unknown ones are rejected, so a misspelled key doesn't leave its default value unnoticed.
+//
+// Example usage:
+//
+//	cfg, err := config.Parse(strings.NewReader(`{"url": "https://example.com", "depth": 2, "rate_limit": 5}`))
+func Parse(reader io.Reader) (Config, error) {
+	cfg := Default()
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate checks the values of the config. The URL is checked last, so an InvalidValueError of the url
+// key means every other value is valid, for the callers not crawling it.
+func (c Config) Validate() error {
+	if c.Depth <= 0 {
+		return &InvalidValueError{Key: "depth", Reason: "must be greater than 0"}
+	}
+	if c.MaxConcurrency <= 0 {
+		return &InvalidValueError{Key: "max_concurrency", Reason: "must be greater than 0"}
+	}
+	if c.Timeout <= 0 {
+		return &InvalidValueError{Key: "timeout", Reason: "must be greater than 0"}
+	}
+	if c.ConnectTimeout <= 0 {
+		return &InvalidValueError{Key: "connect_timeout", Reason: "must be greater than 0"}
+	}
+	if c.TLSTimeout <= 0 {
+		return &InvalidValueError{Key: "tls_timeout", Reason: "must be greater than 0"}
+	}
+	if c.RetryJitter < 0 || c.RetryJitter > 1 {
+		return &InvalidValueError{Key: "retry_jitter", Reason: "must be between 0 and 1"}
+	}
+	if c.CircuitBreaker > 0 && c.CircuitBreakerCooldown <= 0 {
+		return &InvalidValueError{Key: "circuit_breaker_cooldown", Reason: "must be greater than 0"}
+	}
+	nonNegative := []struct {
+		key   string
+		value float64
+	}{
+		{"dns_timeout", float64(c.DNSTimeout)},
+		{"read_timeout", float64(c.ReadTimeout)},
+		{"max_idle_conns_per_host", float64(c.MaxIdleConnsPerHost)},
+		{"max_conns_per_host", float64(c.MaxConnsPerHost)},
+		{"retries", float64(c.Retries)},
+		{"circuit_breaker", float64(c.CircuitBreaker)},
+		{"rate_limit", c.RateLimit},
+		{"max_body_size", float64(c.MaxBodySize)},
+		{"error_body_sample", float64(c.ErrorBodySample)},
+		{"max_pages", float64(c.MaxPages)},
+		{"max_duration", float64(c.MaxDuration)},
+		{"crawl_delay", float64(c.CrawlDelay)},
+		{"cache_ttl", float64(c.CacheTTL)},
+	}
+	for _, value := range nonNegative {
+		if value.value < 0 {
+			return &InvalidValueError{Key: value.key, Reason: "must be 0 or greater than 0"}
+		}
+	}
+	for _, localAddr := range c.LocalAddrs {
+		if net.ParseIP(strings.TrimSpace(localAddr)) == nil {
+			return &InvalidValueError{Key: "local_addrs", Reason: fmt.Sprintf("%q is not an IP address", localAddr)}
+		}
+	}
+	if _, err := c.proxies(); err != nil {
+		return err
+	}
+	for _, contentType := range c.ContentTypes {
+		if _, _, err := mime.ParseMediaType(contentType); err != nil || !strings.Contains(contentType, "/") {
+			return &InvalidValueError{Key: "content_types", Reason: fmt.Sprintf("%q is not a media type", contentType)}
+		}
+	}
+	if _, err := c.headers(); err != nil {
+		return err
+	}
+	if _, err := c.userAgentRotation(); err != nil {
+		return err
+	}
+	if _, err := c.scope(); err != nil {
+		return err
+	}
+	if _, err := c.trailingSlashPolicy(); err != nil {
+		return err
+	}
+	if _, err := c.fragmentPolicy(); err != nil {
+		return err
+	}
+	if _, err := c.nofollowPolicy(); err != nil {
+		return err
+	}
+	if _, err := c.directoryListingPolicy(); err != nil {
+		return err
+	}
+	for _, fileName := range c.DefaultDocuments {
+		if strings.TrimSpace(fileName) == "" || strings.Contains(fileName, "/") {
+			return &InvalidValueError{Key: "default_documents", Reason: fmt.Sprintf("%q is not a file name", fileName)}
+		}
+	}
+	for _, name := range c.StripQueryParams {
+		if name = strings.TrimSpace(name); name == "" || name == "*" {
+			return &InvalidValueError{Key: "strip_query_params", Reason: fmt.Sprintf("%q is not a query parameter", name)}
+		}
+	}
+	if _, err := c.URLToCrawl(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewFetchers builds the fetcher chains of the crawl sending the requests with the given client: the
+// one of the pages, checking their content type and size, and the one of the robots.txt files and the
+// sitemaps. A nil client is replaced by one with the timeout and the transport of the config. If the
+// config rotates the user agents, the requests are sent with a copy of the client whose transport
+// rotates them. The given options, like WithContext or WithReauth, are added to the ones of the config.
+func (c Config) NewFetchers(httpClient *http.Client, opts ...fetcher.ChainOption) (pageFetcher fetcher.Fetcher, fileFetcher fetcher.Fetcher) {
+	httpClient = c.client(httpClient)
+	pageFetcherOptions, fileFetcherOptions := c.fetcherOptions()
+	pacer := fetcher.NewPacer()
+	fileFetcher = fetcher.Chain(fetcher.NewHTTPFetcher(httpClient, fileFetcherOptions...), c.chainOptions(pacer, false, opts)...)
+	// variants are only tried for the crawled pages, not for robots.txt or sitemap.xml
+	if c.TolerantCheck {
+		opts = append(opts[:len(opts):len(opts)], fetcher.WithVariants())
+	}
+	pageFetcher = fetcher.Chain(fetcher.NewHTTPFetcher(httpClient, pageFetcherOptions...), c.chainOptions(pacer, false, opts)...)
+	if c.HeadRequests {
+		headRequests := []fetcher.HTTPFetcherOption{fetcher.WithHeadRequests()}
+		headPageFetcher := fetcher.Chain(fetcher.NewHTTPFetcher(httpClient, append(headRequests, pageFetcherOptions...)...), c.chainOptions(pacer, true, opts)...)
+		pageFetcher = fetcher.NewValidationFetcher(pageFetcher, headPageFetcher, fetcher.DefaultBinaryExtensions...)
+	}
+	return pageFetcher, fileFetcher
+}
+
+// NewAssetFetcher builds the fetcher chain validating the links to the assets and to other sites, like
+// the one of the files of NewFetchers but with HEAD requests if the config sets HeadRequests. A
+// WithPacing option shares the back offs of its retries with the chains of NewFetchers.
+func (c Config) NewAssetFetcher(httpClient *http.Client, opts ...fetcher.ChainOption) fetcher.Fetcher {
+	_, fileFetcherOptions := c.fetcherOptions()
+	if c.HeadRequests {
+		fileFetcherOptions = append([]fetcher.HTTPFetcherOption{fetcher.WithHeadRequests()}, fileFetcherOptions...)
+	}
+	return fetcher.Chain(fetcher.NewHTTPFetcher(c.client(httpClient), fileFetcherOptions...), c.chainOptions(fetcher.NewPacer(), c.HeadRequests, opts)...)
+}
+
+// NewSeedResolver builds the fetcher the URL of the config is checked with before crawling it, sending
+// the requests with the given client like NewFetchers, but without retrying them nor caching the page.
+func (c Config) NewSeedResolver(httpClient *http.Client) *fetcher.HTTPFetcher {
+	pageFetcherOptions, _ := c.fetcherOptions()
+	return fetcher.NewHTTPFetcher(c.client(httpClient), pageFetcherOptions...)
+}
+
+// chainOptions returns the options of the fetcher chains sharing the given pacer, followed by the given
+// ones. The empty contents of the HEAD requests are not cached, so they're never served for GET ones.
+func (c Config) chainOptions(pacer *fetcher.Pacer, headRequests bool, opts []fetcher.ChainOption) []fetcher.ChainOption {
+	chainOptions := []fetcher.ChainOption{fetcher.WithPacing(pacer)}
+	if c.RateLimit > 0 {
+		chainOptions = append(chainOptions, fetcher.WithRateLimit(c.RateLimit, 1))
+	}
+	if c.Retries > 0 {
+		chainOptions = append(chainOptions, fetcher.WithRetry(c.Retries, time.Second*4, c.RetryJitter))
+	}
+	if c.CircuitBreaker > 0 {
+		chainOptions = append(chainOptions, fetcher.WithCircuitBreaker(c.CircuitBreaker, time.Duration(c.CircuitBreakerCooldown)*time.Second))
+	}
+	if c.CacheDir != "" && !headRequests {
+		chainOptions = append(chainOptions, fetcher.WithDiskCache(c.CacheDir, time.Duration(c.CacheTTL)*time.Second))
+	}
+	return append(chainOptions, opts...)
+}
+
+// client returns the client the requests are sent with. See NewFetchers.
+func (c Config) client(httpClient *http.Client) *http.Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: time.Duration(c.Timeout) * time.Millisecond, Transport: c.NewTransport(c.NewResolver())}
+	}
+	if len(c.RotateUserAgent) > 0 {
+		userAgentRotation, _ := c.userAgentRotation()
+		transport := httpClient.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		rotatingClient := *httpClient
+		rotatingClient.Transport = fetcher.NewUserAgentRotator(transport, userAgentRotation, c.RotateUserAgent...)
+		httpClient = &rotatingClient
+	}
+	return httpClient
+}
+
+// NewResolver returns the caching DNS resolver the transport of NewTransport dials through, bounding the
+// lookups with the DNSTimeout of the config.
+func (c Config) NewResolver() *resolver.CachingResolver {
+	return resolver.NewCachingResolver(net.DefaultResolver, c.MaxConcurrency, resolver.WithLookupTimeout(time.Duration(c.DNSTimeout)*time.Millisecond))
+}
+
+// NewTransport returns the transport of the client built by NewFetchers, dialing through the given
+// resolver from the local addresses of the config, bounding the phases of the requests with its timeouts
+// and the connections to every host with its limits, and sending them through its proxies.
+func (c Config) NewTransport(dnsResolver *resolver.CachingResolver) http.RoundTripper {
+	dialer := &net.Dialer{Timeout: time.Duration(c.ConnectTimeout) * time.Millisecond, KeepAlive: 30 * time.Second}
+	maxIdleConnsPerHost := c.MaxIdleConnsPerHost
+	if maxIdleConnsPerHost == 0 {
+		maxIdleConnsPerHost = c.MaxConcurrency
+	}
+	transport := fetcher.NewTransport(
+		fetcher.WithMaxIdleConnsPerHost(maxIdleConnsPerHost),
+		fetcher.WithMaxConnsPerHost(c.MaxConnsPerHost),
+		fetcher.WithTLSHandshakeTimeout(time.Duration(c.TLSTimeout)*time.Millisecond),
+		fetcher.WithHTTP2(c.HTTP2),
+	)
+	transport.DialContext = dnsResolver.WrapDialContext(dialer.DialContext)
+	transport.ResponseHeaderTimeout = time.Duration(c.ReadTimeout) * time.Millisecond
+	if len(c.LocalAddrs) > 0 {
+		var localAddrs []net.IP
+		for _, localAddr := range c.LocalAddrs {
+			localAddrs = append(localAddrs, net.ParseIP(strings.TrimSpace(localAddr)))
+		}
+		localAddrRotator := fetcher.NewLocalAddrRotator(*dialer, localAddrs)
+		transport.DialContext = dnsResolver.WrapDialContext(localAddrRotator.DialContext)
+	}
+	if proxies, _ := c.proxies(); len(proxies) > 0 {
+		transport.Proxy = fetcher.NewProxyRotator(proxies...).Proxy
+	}
+	if c.ReadTimeout <= 0 {
+		return transport
+	}
+	return fetcher.NewReadTimeoutTransport(transport, time.Duration(c.ReadTimeout)*time.Millisecond)
+}
+
+// fetcherOptions returns the options of the HTTPFetcher of the pages and of the one of the files.
+func (c Config) fetcherOptions() (pageFetcherOptions, fileFetcherOptions []fetcher.HTTPFetcherOption) {
+	headers, _ := c.headers()
+	fileFetcherOptions = append([]fetcher.HTTPFetcherOption{fetcher.WithErrorBodySampleSize(c.ErrorBodySample)}, headers...)
+	pageFetcherOptions = append(append([]fetcher.HTTPFetcherOption{}, fileFetcherOptions...), fetcher.WithContentTypes(c.ContentTypes...), fetcher.WithMaxBodySize(c.MaxBodySize))
+	return pageFetcherOptions, fileFetcherOptions
+}
+
+// CrawlerOptions returns the crawler options of the config. The robots.txt files and the sitemaps are
+// fetched with the given fetcher.
+func (c Config) CrawlerOptions(fileFetcher fetcher.Fetcher) ([]crawler.Option, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	scope, _ := c.scope()
+	trailingSlashPolicy, _ := c.trailingSlashPolicy()
+	fragmentPolicy, _ := c.fragmentPolicy()
+	nofollowPolicy, _ := c.nofollowPolicy()
+	directoryListingPolicy, _ := c.directoryListingPolicy()
+	seeds, err := c.ReadSeeds()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []crawler.Option{crawler.WithCrawlDelay(time.Duration(c.CrawlDelay) * time.Millisecond)}
+	if trailingSlashPolicy != linkextractor.StripTrailingSlash {
+		opts = append(opts, crawler.WithTrailingSlashPolicy(trailingSlashPolicy))
+	}
+	if fragmentPolicy != linkextractor.StripFragments {
+		opts = append(opts, crawler.WithFragmentPolicy(fragmentPolicy))
+	}
+	if nofollowPolicy != crawler.FollowNofollow {
+		opts = append(opts, crawler.WithNofollowPolicy(nofollowPolicy))
+	}
+	if directoryListingPolicy != crawler.FollowDirectoryListings {
+		opts = append(opts, crawler.WithDirectoryListingPolicy(directoryListingPolicy))
+	}
+	if scope != nil {
+		opts = append(opts, crawler.WithScope(scope))
+	}
+	if len(c.Include) > 0 || len(c.Exclude) > 0 {
+		opts = append(opts, crawler.WithURLFilters(c.Include, c.Exclude))
+	}
+	if c.CaseInsensitivePaths {
+		opts = append(opts, crawler.WithCaseInsensitivePaths())
+	}
+	if len(c.DefaultDocuments) > 0 {
+		opts = append(opts, crawler.WithDefaultDocuments(c.DefaultDocuments...))
+	}
+	if c.QueryStrings {
+		opts = append(opts, crawler.WithQueryStrings())
+	}
+	if len(c.StripQueryParams) > 0 {
+		opts = append(opts, crawler.WithStrippedQueryParameters(c.strippedQueryParams()...))
+	}
+	if c.MetaRobots {
+		opts = append(opts, crawler.WithMetaRobots())
+	}
+	if c.CanonicalURLs {
+		opts = append(opts, crawler.WithCanonicalURLs())
+	}
+	if c.HreflangSeeds {
+		opts = append(opts, crawler.WithHreflangSeeds())
+	}
+	if len(seeds) > 0 {
+		opts = append(opts, crawler.WithSeeds(seeds...))
+	}
+	if c.Sitemap {
+		opts = append(opts, crawler.WithSitemapSeeding(sitemap.NewSeeder(fileFetcher)))
+	}
+	if c.RespectRobots {
+		opts = append(opts, crawler.WithRobotsPolicy(robots.NewPolicy(fileFetcher, userAgent)))
+	}
+	if c.MaxPages > 0 {
+		opts = append(opts, crawler.WithMaxPages(c.MaxPages))
+	}
+	if c.MaxDuration > 0 {
+		opts = append(opts, crawler.WithMaxDuration(time.Duration(c.MaxDuration)*time.Second))
+	}
+	return opts, nil
+}
+
+// NewCrawler builds a fully wired crawler from the config, sending the requests with the given client,
+// or with one with the timeout of the config if nil. The given options, like the callbacks receiving
+// the links found and the errors, are added to the ones of the config.
+//
+// Example usage:
+//
+//	cfg, err := config.Load("crawl.json")
+//	bfCrawler, err := cfg.NewCrawler(nil, crawler.WithLinkFoundCallback(func(link url.URL) {
+//	    fmt.Println(link.String())
+//	}))
+//	links, err := cfg.Crawl(ctx, bfCrawler)
+func (c Config) NewCrawler(httpClient *http.Client, opts ...crawler.Option) (*crawler.BreadthFirstCrawler, error) {
+	pageFetcher, fileFetcher := c.NewFetchers(httpClient)
+	crawlerOptions, err := c.CrawlerOptions(fileFetcher)
+	if err != nil {
+		return nil, err
+	}
+	if c.CheckSeed {
+		crawlerOptions = append(crawlerOptions, crawler.WithSeedCheck(c.NewSeedResolver(httpClient), c.ProbeSeedScheme))
+	}
+	return crawler.NewBreadthFirstCrawler(pageFetcher, append(crawlerOptions, opts...)...), nil
+}
+
+// Crawl crawls the URL of the config with the given crawler, up to the depth and with the maximum
+// concurrency of the config.
+func (c Config) Crawl(ctx context.Context, crawlerToRun crawler.Crawler) ([]string, error) {
+	urlToCrawl, err := c.URLToCrawl()
+	if err != nil {
+		return nil, err
+	}
+	return crawlerToRun.Crawl(ctx, urlToCrawl, c.Depth, c.MaxConcurrency)
+}
+
+// URLToCrawl returns the parsed URL of the config.
+func (c Config) URLToCrawl() (url.URL, error) {
+	parsedURL, err := url.ParseRequestURI(strings.TrimSpace(c.URL))
+	if err != nil || parsedURL.Host == "" {
+		return url.URL{}, &InvalidValueError{Key: "url", Reason: fmt.Sprintf("%q is not an absolute URL", c.URL)}
+	}
+	return *parsedURL, nil
+}
+
+// ReadSeeds reads the seeds of the Seeds file of the config, none if it's empty.
+func (c Config) ReadSeeds() ([]crawler.Seed, error) {
+	if strings.TrimSpace(c.Seeds) == "" {
+		return nil, nil
+	}
+	seedsFile, err := os.Open(c.Seeds)
+	if err != nil {
+		return nil, &InvalidValueError{Key: "seeds", Reason: err.Error()}
+	}
+	defer func(seedsFile *os.File) {
+		_ = seedsFile.Close()
+	}(seedsFile)
+	seeds, err := crawler.ParseSeeds(seedsFile)
+	if err != nil {
+		return nil, &InvalidValueError{Key: "seeds", Reason: err.Error()}
+	}
+	return seeds, nil
+}
+
+func (c Config) proxies() ([]url.URL, error) {
+	var proxies []url.URL
+	for _, proxy := range c.Proxy {
+		proxyURL, err := url.Parse(strings.TrimSpace(proxy))
+		if err != nil || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") || proxyURL.Host == "" {
+			return nil, &InvalidValueError{Key: "proxy", Reason: fmt.Sprintf("%q is not an http or https URL", proxy)}
+		}
+		proxies = append(proxies, *proxyURL)
+	}
+	return proxies, nil
+}
+
+// strippedQueryParams returns the StripQueryParams of the config, with the tracking parameters in place
+// of "tracking".
+func (c Config) strippedQueryParams() []string {
+	var names []string
+	for _, name := range c.StripQueryParams {
+		if name = strings.TrimSpace(name); name == "tracking" {
+			names = append(names, linkextractor.TrackingParameters...)
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// headers returns the options of the fetchers sending the user agent, the headers and the credentials
+// of the config.
+func (c Config) headers() ([]fetcher.HTTPFetcherOption, error) {
+	var headers []fetcher.HTTPFetcherOption
+	if c.UserAgent != "" {
+		headers = append(headers, fetcher.WithUserAgent(c.UserAgent))
+	}
+	for _, header := range c.Header {
+		name, value, ok := strings.Cut(header, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, &InvalidValueError{Key: "header", Reason: fmt.Sprintf("%q is not in the format of an HTTP header", header)}
+		}
+		headers = append(headers, fetcher.WithHeader(name, strings.TrimSpace(value)))
+	}
+	if c.BasicAuth != "" && c.BearerToken != "" {
+		return nil, &InvalidValueError{Key: "bearer_token", Reason: "can't be used with basic_auth"}
+	}
+	if c.BasicAuth != "" {
+		username, password, ok := strings.Cut(c.BasicAuth, ":")
+		if !ok || username == "" {
+			return nil, &InvalidValueError{Key: "basic_auth", Reason: "must be in the user:password format"}
+		}
+		headers = append(headers, fetcher.WithBasicAuth(username, password))
+	}
+	if c.BearerToken != "" {
+		headers = append(headers, fetcher.WithBearerToken(c.BearerToken))
+	}
+	return headers, nil
+}
+
+func (c Config) userAgentRotation() (fetcher.UserAgentRotation, error) {
+	switch strings.ToLower(strings.TrimSpace(c.UserAgentRotation)) {
+	case "round_robin":
+		return fetcher.RoundRobinUserAgents, nil
+	case "random":
+		return fetcher.RandomUserAgents, nil
+	}
+	return fetcher.RoundRobinUserAgents, &InvalidValueError{Key: "user_agent_rotation", Reason: "must be one of round_robin, random"}
+}
+
+func (c Config) scope() (linkextractor.Scope, error) {
+	switch strings.ToLower(strings.TrimSpace(c.Scope)) {
+	case "host":
+		return nil, nil
+	case "domain":
+		return linkextractor.SameRegistrableDomain, nil
+	}
+	return nil, &InvalidValueError{Key: "scope", Reason: "must be one of host, domain"}
+}
+
+func (c Config) trailingSlashPolicy() (linkextractor.TrailingSlashPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(c.TrailingSlash)) {
+	case "strip":
+		return linkextractor.StripTrailingSlash, nil
+	case "keep":
+		return linkextractor.KeepTrailingSlash, nil
+	case "distinct":
+		return linkextractor.DistinctTrailingSlash, nil
+	}
+	return linkextractor.StripTrailingSlash, &InvalidValueError{Key: "trailing_slash", Reason: "must be one of strip, keep, distinct"}
+}
+
+func (c Config) fragmentPolicy() (linkextractor.FragmentPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(c.Fragments)) {
+	case "strip":
+		return linkextractor.StripFragments, nil
+	case "keep":
+		return linkextractor.KeepFragments, nil
+	}
+	return linkextractor.StripFragments, &InvalidValueError{Key: "fragments", Reason: "must be one of strip, keep"}
+}
+
+func (c Config) nofollowPolicy() (crawler.NofollowPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(c.Nofollow)) {
+	case "follow":
+		return crawler.FollowNofollow, nil
+	case "skip":
+		return crawler.SkipNofollow, nil
+	case "record":
+		return crawler.RecordNofollow, nil
+	}
+	return crawler.FollowNofollow, &InvalidValueError{Key: "nofollow", Reason: "must be one of follow, skip, record"}
+}
+
+func (c Config) directoryListingPolicy() (crawler.DirectoryListingPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(c.DirectoryListings)) {
+	case "follow":
+		return crawler.FollowDirectoryListings, nil
+	case "entries":
+		return crawler.FollowDirectoryEntries, nil
+	case "skip":
+		return crawler.SkipDirectoryListings, nil
+	}
+	return crawler.FollowDirectoryListings, &InvalidValueError{Key: "directory_listings", Reason: "must be one of follow, entries, skip"}
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantKey string
+	}{
+		{name: "valid config", config: `{"url": "https://example.com", "depth": 2, "rate_limit": 5, "exclude": ["^/search"]}`},
+		{name: "missing url", config: `{"depth": 2}`, wantKey: "url"},
+		{name: "invalid depth", config: `{"url": "https://example.com", "depth": 0}`, wantKey: "depth"},
+		{name: "negative retries", config: `{"url": "https://example.com", "retries": -1}`, wantKey: "retries"},
+		{name: "invalid content type", config: `{"url": "https://example.com", "content_types": ["html"]}`, wantKey: "content_types"},
+		{name: "invalid header", config: `{"url": "https://example.com", "header": ["X-API-Key abc"]}`, wantKey: "header"},
+		{name: "invalid scope", config: `{"url": "https://example.com", "scope": "world"}`, wantKey: "scope"},
+		{name: "invalid nofollow", config: `{"url": "https://example.com", "nofollow": "maybe"}`, wantKey: "nofollow"},
+		{name: "circuit breaker without cool-down", config: `{"url": "https://example.com", "circuit_breaker": 5, "circuit_breaker_cooldown": 0}`, wantKey: "circuit_breaker_cooldown"},
+		{name: "invalid connect_timeout", config: `{"url": "https://example.com", "connect_timeout": 0}`, wantKey: "connect_timeout"},
+		{name: "negative max_conns_per_host", config: `{"url": "https://example.com", "max_conns_per_host": -1}`, wantKey: "max_conns_per_host"},
+		{name: "invalid retry_jitter", config: `{"url": "https://example.com", "retry_jitter": 1.5}`, wantKey: "retry_jitter"},
+		{name: "invalid basic_auth", config: `{"url": "https://example.com", "basic_auth": "admin"}`, wantKey: "basic_auth"},
+		{name: "invalid user_agent_rotation", config: `{"url": "https://example.com", "user_agent_rotation": "sometimes"}`, wantKey: "user_agent_rotation"},
+		{name: "invalid directory_listings", config: `{"url": "https://example.com", "directory_listings": "maybe"}`, wantKey: "directory_listings"},
+		{name: "invalid default_documents", config: `{"url": "https://example.com", "default_documents": ["docs/index.html"]}`, wantKey: "default_documents"},
+		{name: "invalid strip_query_params", config: `{"url": "https://example.com", "strip_query_params": ["*"]}`, wantKey: "strip_query_params"},
+		{name: "invalid local_addrs", config: `{"url": "https://example.com", "local_addrs": ["localhost"]}`, wantKey: "local_addrs"},
+		{name: "invalid proxy", config: `{"url": "https://example.com", "proxy": ["socks5://proxy.example.com:1080"]}`, wantKey: "proxy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.config))
+			var valueErr *InvalidValueError
+			if tt.wantKey == "" && err != nil {
+				t.Errorf("should not throw error at Parse. err: %v", err)
+			}
+			if tt.wantKey != "" && (!errors.As(err, &valueErr) || valueErr.Key != tt.wantKey) {
+				t.Errorf("Parse() error = %v, want an invalid %s", err, tt.wantKey)
+			}
+		})
+	}
+
+	t.Run("keeps the defaults of the missing keys", func(t *testing.T) {
+		cfg, err := Parse(strings.NewReader(`{"url": "https://example.com", "depth": 2}`))
+		if err != nil {
+			t.Fatalf("should not throw error at Parse. err: %v", err)
+		}
+		if cfg.Depth != 2 || cfg.MaxConcurrency != Default().MaxConcurrency || !cfg.RespectRobots {
+			t.Errorf("Parse() got = %+v, want the defaults but the depth", cfg)
+		}
+	})
+
+	t.Run("rejects the unknown keys", func(t *testing.T) {
+		_, err := Parse(strings.NewReader(`{"url": "https://example.com", "dept": 2}`))
+		if err == nil || !strings.Contains(err.Error(), `"dept"`) {
+			t.Errorf("Parse() error = %v, want the unknown key dept", err)
+		}
+	})
+}
+
+func TestConfig_NewCrawler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			_, _ = io.WriteString(w, `<a href="/docs">Docs</a><a href="/search?q=go">Search</a>`)
+		case "/docs":
+			_, _ = io.WriteString(w, `<a href="/">Home</a>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg, err := Parse(strings.NewReader(`{"url": "` + server.URL + `", "depth": 2, "respect_robots": false, "exclude": ["^/search"]}`))
+	if err != nil {
+		t.Fatalf("should not throw error at Parse. err: %v", err)
+	}
+	bfCrawler, err := cfg.NewCrawler(server.Client())
+	if err != nil {
+		t.Fatalf("should not throw error at NewCrawler. err: %v", err)
+	}
+	links, err := cfg.Crawl(context.Background(), bfCrawler)
+	if err != nil {
+		t.Fatalf("should not throw error at Crawl. err: %v", err)
+	}
+	if len(links) != 2 {
+		t.Errorf("Crawl() got = %v, want the home and docs pages", links)
+	}
+}
+
+func TestConfig_CrawlerOptions_queryStrings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = io.WriteString(w, `<a href="/products?page=2&utm_source=home">Page 2</a><a href="/products?page=3">Page 3</a>`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		queryStrings string
+		want         []string
+	}{
+		{name: "removes the query strings by default", want: []string{server.URL, server.URL + "/products"}},
+		{name: "keeps the query strings", queryStrings: `, "query_strings": true, "strip_query_params": ["utm_*"]`, want: []string{server.URL, server.URL + "/products?page=2", server.URL + "/products?page=3"}},
+		{name: "strips the tracking parameters", queryStrings: `, "query_strings": true, "strip_query_params": ["tracking"]`, want: []string{server.URL, server.URL + "/products?page=2", server.URL + "/products?page=3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Parse(strings.NewReader(`{"url": "` + server.URL + `", "depth": 2, "respect_robots": false` + tt.queryStrings + `}`))
+			if err != nil {
+				t.Fatalf("should not throw error at Parse. err: %v", err)
+			}
+			bfCrawler, err := cfg.NewCrawler(server.Client())
+			if err != nil {
+				t.Fatalf("should not throw error at NewCrawler. err: %v", err)
+			}
+			got, err := cfg.Crawl(context.Background(), bfCrawler)
+			if err != nil {
+				t.Fatalf("should not throw error at Crawl. err: %v", err)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Crawl() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_CrawlerOptions_seeds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = io.WriteString(w, `<a href="/docs">Docs</a>`)
+	}))
+	defer server.Close()
+	seedsPath := filepath.Join(t.TempDir(), "seeds.txt")
+	if err := os.WriteFile(seedsPath, []byte(server.URL+"/orphan owner=docs-team\n"), 0644); err != nil {
+		t.Fatalf("should not throw error at WriteFile. err: %v", err)
+	}
+
+	cfg, err := Parse(strings.NewReader(`{"url": "` + server.URL + `", "depth": 1, "respect_robots": false, "seeds": "` + seedsPath + `"}`))
+	if err != nil {
+		t.Fatalf("should not throw error at Parse. err: %v", err)
+	}
+	bfCrawler, err := cfg.NewCrawler(server.Client())
+	if err != nil {
+		t.Fatalf("should not throw error at NewCrawler. err: %v", err)
+	}
+	got, err := cfg.Crawl(context.Background(), bfCrawler)
+	if err != nil {
+		t.Fatalf("should not throw error at Crawl. err: %v", err)
+	}
+	sort.Strings(got)
+	want := []string{server.URL, server.URL + "/docs", server.URL + "/orphan"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl() got = %v, want %v", got, want)
+	}
+
+	t.Run("fails without the seeds file", func(t *testing.T) {
+		cfg.Seeds = filepath.Join(t.TempDir(), "missing.txt")
+		var valueErr *InvalidValueError
+		if _, err := cfg.CrawlerOptions(nil); !errors.As(err, &valueErr) || valueErr.Key != "seeds" {
+			t.Errorf("CrawlerOptions() error = %v, want an invalid seeds", err)
+		}
+	})
+}
@@ -2,23 +2,23 @@ package crawler
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"io"
+	"iter"
+	"net/http"
 	"net/url"
+	"sort"
 	"sync"
 
 	"github.com/andiblas/website-crawler/pkg/fetcher"
-	"github.com/andiblas/website-crawler/pkg/linkextractor"
+	"github.com/andiblas/website-crawler/pkg/frontier"
 )
 
-type linkFoundCallback func(link url.URL)
-type crawlingErrorCallback func(link url.URL, err error)
-
 type BreadthFirstCrawler struct {
-	fetcher   fetcher.Fetcher
-	linkFound linkFoundCallback
-	onError   crawlingErrorCallback
+	crawlerConfig
+
+	mu sync.Mutex
+	// store is the frontier store of the last crawl, kept for Snapshot
+	store frontier.Store
 }
 
 // NewBreadthFirstCrawler creates a new breadth first crawler with the given fetcher and options.
@@ -35,15 +35,83 @@ type BreadthFirstCrawler struct {
 //	fetcher := &MyFetcher{} // Replace with your fetcher implementation
 //	crawler := NewBreadthFirstCrawler(fetcher, WithLinkFoundCallback(myLinkFoundCallback), WithOnErrorCallback(myErrorCallback))
 func NewBreadthFirstCrawler(fetcher fetcher.Fetcher, opts ...Option) *BreadthFirstCrawler {
-	bfc := &BreadthFirstCrawler{fetcher: fetcher}
+	bfc := &BreadthFirstCrawler{crawlerConfig: crawlerConfig{fetcher: fetcher}}
 
 	for _, opt := range opts {
-		opt(bfc)
+		opt(&bfc.crawlerConfig)
 	}
 
 	return bfc
 }
 
+// NewBreadthFirstCrawlerFromState creates a new breadth first crawler like NewBreadthFirstCrawler,
+// holding the crawl state taken with Snapshot, so the crawl can be continued with Resume. The state is
+// restored into the frontier store set with the WithFrontierStore option, replacing its content, or
+// into a new in-memory store if there is none.
+//
+// Errors:
+//   - If the state doesn't hold a crawl, the function returns frontier.NoCrawlInfo.
+//   - If restoring the state into the frontier store fails, the function returns its error.
+//
+// Example:
+//
+//	var state FrontierState
+//	_ = json.Unmarshal(savedState, &state)
+//	crawler, err := NewBreadthFirstCrawlerFromState(fetcher, state, WithLinkFoundCallback(myLinkFoundCallback))
+//	if err != nil {
+//	    return err
+//	}
+//	links, err := crawler.Resume(ctx)
+func NewBreadthFirstCrawlerFromState(fetcher fetcher.Fetcher, state FrontierState, opts ...Option) (*BreadthFirstCrawler, error) {
+	if state.CrawlInfo == nil {
+		return nil, frontier.NoCrawlInfo
+	}
+	bfc := NewBreadthFirstCrawler(fetcher, opts...)
+	if bfc.frontierStore == nil {
+		bfc.frontierStore = frontier.NewMemoryStore()
+	}
+	if err := frontier.Restore(bfc.frontierStore, state); err != nil {
+		return nil, err
+	}
+	bfc.setStore(bfc.frontierStore)
+	return bfc, nil
+}
+
+// Snapshot returns the state of the last crawl: its parameters, the depth level it reached, the links
+// found and visited, and the links pending to be crawled. It can be taken while crawling, or once the
+// crawl was interrupted, e.g. by canceling its context or with the WithMaxPages and WithMaxDuration
+// options, and persisted in the storage of the embedding application, as it's JSON serializable. The
+// crawl is continued from it with NewBreadthFirstCrawlerFromState and Resume.
+//
+// Errors:
+//   - If the crawler hasn't crawled yet and has no frontier store, the function returns frontier.NoCrawlInfo.
+//   - If the frontier store can't export its state, like a frontier.BloomStore, the function returns
+//     frontier.SnapshotNotSupported.
+//
+// Example usage:
+//
+//	links, err := crawler.Crawl(ctx, *urlToCrawl, 10, 20) // interrupted
+//	state, err := crawler.Snapshot()
+//	savedState, err := json.Marshal(state)
+func (bfc *BreadthFirstCrawler) Snapshot() (FrontierState, error) {
+	bfc.mu.Lock()
+	store := bfc.store
+	bfc.mu.Unlock()
+	if store == nil {
+		store = bfc.frontierStore
+	}
+	if store == nil {
+		return FrontierState{}, frontier.NoCrawlInfo
+	}
+	return frontier.Snapshot(store)
+}
+
+func (bfc *BreadthFirstCrawler) setStore(store frontier.Store) {
+	bfc.mu.Lock()
+	defer bfc.mu.Unlock()
+	bfc.store = store
+}
+
 // Crawl performs a breadth-first web crawling starting from the specified URL.
 // It explores the web pages up to the specified depth and concurrently crawls
 // multiple pages based on the given maxConcurrency. The linkCallback function
@@ -64,13 +132,17 @@ func NewBreadthFirstCrawler(fetcher fetcher.Fetcher, opts ...Option) *BreadthFir
 // Errors:
 //   - If the provided depth is zero or negative, the function returns an error of type InvalidDepth.
 //   - If the provided maxConcurrency is zero or negative, the function returns an error of type InvalidMaxConcurrency.
+//   - If a pattern of WithURLFilters is not a valid regular expression, the function returns an InvalidURLFilter error.
+//   - If the seed checked with WithSeedCheck doesn't respond with a page, the function returns a SeedError.
+//   - If the frontier store set with WithFrontierStore fails, the function returns its error.
 //
 // The function uses breadth-first crawling to explore web pages and ensures that
 // no duplicate URLs are visited. It also gracefully cancels the crawl if the provided
 // context is canceled, allowing for clean shutdown of the crawling process.
 //
 // The linkFoundCallback and crawlingErrorCallback functions are executed asynchronously
-// in separate goroutines to avoid hindering the main crawling process.
+// in separate goroutines to avoid hindering the main crawling process. Use the
+// WithWaitForCallbacks option to make Crawl wait for them before returning.
 //
 // Example usage:
 //
@@ -86,104 +158,503 @@ func NewBreadthFirstCrawler(fetcher fetcher.Fetcher, opts ...Option) *BreadthFir
 //	    fmt.Println("Crawled links:", crawledLinks)
 //	}
 func (bfc *BreadthFirstCrawler) Crawl(ctx context.Context, urlToCrawl url.URL, depth, maxConcurrency int) ([]string, error) {
+	return bfc.crawl(ctx, urlToCrawl, depth, maxConcurrency, nil)
+}
+
+// CrawlChan performs the same crawl as Crawl, but streams a CrawlResult for every crawled
+// page as soon as its batch is done, instead of returning the links at the end. The results
+// of a batch are sent in the order their pages were queued, and the crawl doesn't move on
+// until the consumer has received them, so a slow consumer slows the crawl down.
+//
+// The results channel is closed when the crawl ends. Then, the error channel receives the
+// error that stopped the crawl, if any, and is closed too. Consumers that stop reading the
+// results before the crawl ends must cancel the context to release the crawl.
+//
+// Example usage:
+//
+//	results, errs := crawler.CrawlChan(ctx, *urlToCrawl, 3, 10)
+//	for result := range results {
+//	    fmt.Println("Crawled:", result.URL.String(), "links:", len(result.Links))
+//	}
+//	if err := <-errs; err != nil {
+//	    fmt.Println("Error occurred during the crawl:", err)
+//	}
+func (bfc *BreadthFirstCrawler) CrawlChan(ctx context.Context, urlToCrawl url.URL, depth, maxConcurrency int) (<-chan CrawlResult, <-chan error) {
+	results := make(chan CrawlResult)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		_, err := bfc.crawl(ctx, urlToCrawl, depth, maxConcurrency, func(result CrawlResult) bool {
+			select {
+			case results <- result:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+		close(results)
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return results, errs
+}
+
+// CrawlWithGraph performs the same crawl as Crawl, but returns the link graph of the site
+// instead of just the links found: which pages link to which. Links leading out of the crawl,
+// like the ones disallowed by the robots policy, are kept apart in CrawlGraph.Excluded. When the seed
+// redirected, the links to it lead to the page it redirected to. As the graph is built from the links
+// kept by the frontier store, it's empty with a frontier.BloomStore.
+//
+// Example usage:
+//
+//	graph, err := crawler.CrawlWithGraph(ctx, *urlToCrawl, 3, 10)
+//	for _, edge := range graph.Edges {
+//	    fmt.Println(edge.From, "->", edge.To)
+//	}
+func (bfc *BreadthFirstCrawler) CrawlWithGraph(ctx context.Context, urlToCrawl url.URL, depth, maxConcurrency int) (*CrawlGraph, error) {
+	var edges []Edge
+	var seedRedirect *Edge
+	links, err := bfc.crawl(ctx, urlToCrawl, depth, maxConcurrency, func(result CrawlResult) bool {
+		from := bfc.linkKey(result.URL)
+		if result.Canonical != nil {
+			from = bfc.linkKey(*result.Canonical)
+		}
+		if result.RedirectedFrom != nil {
+			seedRedirect = &Edge{From: bfc.seedKey(*result.RedirectedFrom), To: from}
+		}
+		for _, link := range result.Links {
+			if to := bfc.linkKey(link); to != from {
+				edges = append(edges, Edge{From: from, To: to})
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]bool, len(links))
+	for _, link := range links {
+		nodes[link] = true
+	}
+	graph := &CrawlGraph{Seed: bfc.seedKey(urlToCrawl), Nodes: links, SeedRedirect: seedRedirect}
+	if seedRedirect != nil {
+		graph.Seed = seedRedirect.To
+	}
+	sort.Strings(graph.Nodes)
+	for _, edge := range edges {
+		// the links to the seed as given lead to the page it redirected to
+		if seedRedirect != nil && edge.To == seedRedirect.From {
+			if edge.From == seedRedirect.To {
+				continue
+			}
+			edge.To = seedRedirect.To
+		}
+		if nodes[edge.To] {
+			graph.Edges = append(graph.Edges, edge)
+		} else {
+			graph.Excluded = append(graph.Excluded, edge)
+		}
+	}
+	return graph, nil
+}
+
+// Pages performs the same crawl as Crawl, exposed as an iterator of the crawled pages, so
+// the crawl can be consumed with a range loop and stopped early with a break. Pages are
+// yielded in the same order as CrawlChan sends them. Every page is yielded with a nil error,
+// as the error of a page is in CrawlResult.Err. If the crawl fails, the error is yielded
+// last with an empty CrawlResult.
+//
+// Example usage:
+//
+//	for page, err := range crawler.Pages(ctx, *urlToCrawl, 3, 10) {
+//	    if err != nil {
+//	        fmt.Println("Error occurred during the crawl:", err)
+//	        break
+//	    }
+//	    fmt.Println("Crawled:", page.URL.String())
+//	}
+func (bfc *BreadthFirstCrawler) Pages(ctx context.Context, urlToCrawl url.URL, depth, maxConcurrency int) iter.Seq2[CrawlResult, error] {
+	return func(yield func(CrawlResult, error) bool) {
+		stopped := false
+		_, err := bfc.crawl(ctx, urlToCrawl, depth, maxConcurrency, func(result CrawlResult) bool {
+			stopped = !yield(result, nil)
+			return !stopped
+		})
+		if err != nil && !stopped {
+			yield(CrawlResult{}, err)
+		}
+	}
+}
+
+func (bfc *BreadthFirstCrawler) crawl(ctx context.Context, urlToCrawl url.URL, depth, maxConcurrency int, emit resultEmitter) ([]string, error) {
+	defer bfc.waitPendingCallbacks()
+
 	if depth <= 0 {
 		return nil, InvalidDepth
 	}
 	if maxConcurrency <= 0 {
 		return nil, InvalidMaxConcurrency
 	}
+	if bfc.urlFilters.err != nil {
+		return nil, bfc.urlFilters.err
+	}
+	redirectedFrom := ""
+	if bfc.seedResolver != nil {
+		resolvedURL, err := bfc.checkSeed(urlToCrawl)
+		if err != nil {
+			return nil, err
+		}
+		if bfc.seedKey(resolvedURL) != bfc.seedKey(urlToCrawl) {
+			redirectedFrom = bfc.seedKey(urlToCrawl)
+			emit = withRedirectedSeed(emit, bfc.linkKey, bfc.seedKey(resolvedURL), urlToCrawl)
+		}
+		urlToCrawl = resolvedURL
+	}
+	crawlCtx, cancel := bfc.withMaxDuration(ctx)
+	defer cancel()
+
+	store := bfc.frontierStore
+	if store == nil {
+		store = frontier.NewMemoryStore()
+	} else if err := store.Clear(); err != nil {
+		return nil, err
+	}
+	bfc.setStore(store)
 
-	visitedLinks := make(map[string]bool) // map of links found while crawling + whether is visited or not
-	linksAtDepth := []url.URL{linkextractor.Normalize(urlToCrawl)}
+	info := frontier.CrawlInfo{URL: urlToCrawl.String(), RedirectedFrom: redirectedFrom, Depth: depth, MaxConcurrency: maxConcurrency}
+	if err := store.SaveCrawlInfo(info); err != nil {
+		return nil, err
+	}
+	// the links to the seed as given lead to the page it redirected to, so they aren't crawled
+	if redirectedFrom != "" {
+		if _, err := store.MarkFound(redirectedFrom); err != nil {
+			return nil, err
+		}
+	}
+	seeds := filterDisallowedLinks(bfc.robotsPolicy, bfc.seeds(urlToCrawl))
+	if err := bfc.pushLinks(store, 0, seeds); err != nil {
+		return nil, err
+	}
+
+	return bfc.crawlFrom(crawlCtx, store, info, emit)
+}
+
+// Resume continues the crawl saved in the frontier store set with the WithFrontierStore
+// option, or restored with NewBreadthFirstCrawlerFromState, from the depth level and the
+// pending links where it was interrupted. The crawl keeps the URL, depth and maxConcurrency
+// it was started with.
+//
+// Pages that were being crawled when the crawl got interrupted are considered visited,
+// so their links are not found again.
+//
+// Errors:
+//   - If the crawler has no frontier store, or the store doesn't hold a crawl, the function returns frontier.NoCrawlInfo.
+//   - If a pattern of WithURLFilters is not a valid regular expression, the function returns an InvalidURLFilter error.
+//   - If the frontier store fails, the function returns its error.
+func (bfc *BreadthFirstCrawler) Resume(ctx context.Context) ([]string, error) {
+	defer bfc.waitPendingCallbacks()
+
+	if bfc.frontierStore == nil {
+		return nil, frontier.NoCrawlInfo
+	}
+	if bfc.urlFilters.err != nil {
+		return nil, bfc.urlFilters.err
+	}
+	crawlCtx, cancel := bfc.withMaxDuration(ctx)
+	defer cancel()
+	bfc.setStore(bfc.frontierStore)
+	info, err := bfc.frontierStore.LoadCrawlInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	return bfc.crawlFrom(crawlCtx, bfc.frontierStore, *info, nil)
+}
+
+// resultEmitter receives the result of every crawled page. It returns false to stop the crawl.
+type resultEmitter func(result CrawlResult) bool
+
+// withRedirectedSeed returns an emitter that sets the URL the seed was given as to the result of the
+// page it redirected to, the one with the given key, before emitting it.
+func withRedirectedSeed(emit resultEmitter, linkKey func(url.URL) string, seedKey string, redirectedFrom url.URL) resultEmitter {
+	if emit == nil {
+		return nil
+	}
+	return func(result CrawlResult) bool {
+		if result.Depth == 0 && linkKey(result.URL) == seedKey {
+			result.RedirectedFrom = &redirectedFrom
+		}
+		return emit(result)
+	}
+}
+
+// crawlFrom crawls the links in the frontier store from the current depth of the crawl. If emit
+// is not nil, it's called for every crawled page, and the crawl stops after the current batch
+// once it returns false.
+func (bfc *BreadthFirstCrawler) crawlFrom(ctx context.Context, store frontier.Store, info frontier.CrawlInfo, emit resultEmitter) ([]string, error) {
+	stopProgress := bfc.startProgress()
+	defer stopProgress()
+
+	startedBatches := 0
+	crawledPages := 0
+	stopped := false
+	// conditionMet tells whether a crawled page met the condition of WithStopWhen
+	conditionMet := false
+	// excluded are the crawled pages left out of the returned links: the ones that declared another
+	// canonical URL, the noindex ones, and the seed as given when it redirected
+	excluded := make(map[string]bool)
+	// notFollowed are the nofollow links recorded but not crawled, crawled if found later in a followed link
+	notFollowed := make(map[string]bool)
+	if info.RedirectedFrom != "" {
+		excluded[info.RedirectedFrom] = true
+	}
+	for currentDepth := info.CurrentDepth; currentDepth < info.Depth; currentDepth++ {
+		for {
+			if startedBatches > 0 {
+				bfc.waitCrawlDelay(ctx)
+			}
 
-	for currentDepth := 0; currentDepth < depth; currentDepth++ {
-		batches := buildBatches(linksAtDepth, maxConcurrency)
-		linksAtDepth = nil
-		for _, batch := range batches {
 			// graceful cancel before starting a new batch
-			if errors.Is(ctx.Err(), context.Canceled) {
+			if stopped || conditionMet || interrupted(ctx) {
+				return linksWithoutExcluded(store, excluded)
+			}
+			// the pending links are kept in the frontier store once the maximum number of pages is crawled
+			batchSize := bfc.batchSize(info.MaxConcurrency, crawledPages)
+			if batchSize == 0 {
+				return linksWithoutExcluded(store, excluded)
+			}
+
+			batch, err := bfc.nextBatch(ctx, store, currentDepth, batchSize)
+			if err != nil {
+				return nil, err
+			}
+			// canceled while waiting for the schedule to open
+			if bfc.schedule != nil && ctx.Err() != nil && len(batch) == 0 {
+				return linksWithoutExcluded(store, excluded)
+			}
+			if len(batch) == 0 {
 				break
 			}
+			startedBatches++
+			crawledPages += len(batch)
 
-			linksAtDepth = append(linksAtDepth, crawlBatchConcurrently(batch, visitedLinks, bfc.fetcher, bfc.onError)...)
-		}
-		for _, link := range linksAtDepth {
-			if _, ok := visitedLinks[link.String()]; !ok {
-				visitedLinks[link.String()] = false
-				safeLinkFoundCallback(bfc.linkFound, link)
+			var linksFound []url.URL
+			for _, page := range bfc.crawlBatchConcurrently(ctx, batch) {
+				result := page.result(currentDepth, bfc.extraSeeds)
+				if emit != nil && !stopped {
+					stopped = !emit(result)
+				}
+				if !conditionMet {
+					conditionMet = bfc.conditionMet(result)
+				}
+				if page.noindex {
+					excluded[bfc.linkKey(page.link)] = true
+				}
+				pageLinks := page.links
+				if page.canonical != nil {
+					excluded[bfc.linkKey(page.link)] = true
+					if pageLinks, err = bfc.followCanonical(store, page); err != nil {
+						return nil, err
+					}
+				}
+				nofollow := page.nofollowKeys(bfc.linkKey)
+				for _, link := range bfc.crawlableLinks(pageLinks) {
+					key := bfc.linkKey(link)
+					isNew, err := store.MarkFound(key)
+					if err != nil {
+						return nil, err
+					}
+					if isNew {
+						safeLinkFoundCallback(&bfc.pendingCallbacks, bfc.linkFound, link)
+						bfc.progress.linkFound()
+					}
+					switch {
+					case isNew && nofollow[key]:
+						notFollowed[key] = true
+					case isNew:
+						linksFound = append(linksFound, link)
+					case notFollowed[key] && !nofollow[key]:
+						delete(notFollowed, key)
+						linksFound = append(linksFound, link)
+					}
+				}
+			}
+			// links found at the last depth level are reported but not crawled
+			if currentDepth+1 < info.Depth {
+				if err := bfc.pushLinks(store, currentDepth+1, linksFound); err != nil {
+					return nil, err
+				}
 			}
 		}
-	}
 
-	var i int
-	crawledLinks := make([]string, len(visitedLinks))
-	for link := range visitedLinks {
-		crawledLinks[i] = link
-		i++
+		info.CurrentDepth = currentDepth + 1
+		if err := store.SaveCrawlInfo(info); err != nil {
+			return nil, err
+		}
 	}
 
-	return crawledLinks, nil
+	return linksWithoutExcluded(store, excluded)
+}
+
+// followCanonical makes the canonical URL of a crawled page the identity of the page: it's reported
+// as found and marked as visited, so it's not crawled again. It returns the links of the page to
+// follow, which are none if the canonical URL was already visited, as the page is a duplicate.
+func (bfc *BreadthFirstCrawler) followCanonical(store frontier.Store, page crawledPage) ([]url.URL, error) {
+	isNew, err := store.MarkFound(bfc.linkKey(*page.canonical))
+	if err != nil {
+		return nil, err
+	}
+	if isNew {
+		safeLinkFoundCallback(&bfc.pendingCallbacks, bfc.linkFound, *page.canonical)
+		bfc.progress.linkFound()
+	}
+	firstVisit, err := store.MarkVisited(bfc.linkKey(*page.canonical))
+	if err != nil || !firstVisit {
+		return nil, err
+	}
+	return page.links, nil
 }
 
-func crawlBatchConcurrently(batch []url.URL, visitedLinks map[string]bool, fetcher fetcher.Fetcher, errorCallback crawlingErrorCallback) []url.URL {
-	var result []url.URL
-	wg := sync.WaitGroup{}
-	for _, linkInBatch := range batch {
-		if visitedLinks[linkInBatch.String()] {
-			continue
+func linksWithoutExcluded(store frontier.Store, excluded map[string]bool) ([]string, error) {
+	links, err := store.Links()
+	if err != nil || len(excluded) == 0 {
+		return links, err
+	}
+	var canonicalLinks []string
+	for _, link := range links {
+		if !excluded[link] {
+			canonicalLinks = append(canonicalLinks, link)
 		}
-		visitedLinks[linkInBatch.String()] = true
+	}
+	return canonicalLinks, nil
+}
 
-		wg.Add(1)
+func (bfc *BreadthFirstCrawler) pushLinks(store frontier.Store, depth int, links []url.URL) error {
+	prefetchHosts(bfc.hostPrefetcher, links)
+	linksToPush := make([]string, len(links))
+	for i, link := range links {
+		linksToPush[i] = link.String()
+	}
+	return store.Push(depth, linksToPush...)
+}
 
-		go func(link url.URL) {
-			defer wg.Done()
-			links, err := crawlWebpage(fetcher, link)
+// nextBatch pops the next links to crawl from the frontier of the given depth, skipping
+// the ones that were already visited. It returns an empty batch when the frontier is empty. If the
+// crawler has a schedule, it waits for it to open before marking the links as visited.
+func (bfc *BreadthFirstCrawler) nextBatch(ctx context.Context, store frontier.Store, depth, batchSize int) ([]url.URL, error) {
+	for {
+		links, err := store.Pop(depth, batchSize)
+		if err != nil || len(links) == 0 {
+			return nil, err
+		}
+		if bfc.schedule != nil {
+			bfc.waitSchedule(ctx)
+			if ctx.Err() != nil {
+				// the links go back to the frontier, so they're crawled if the crawl is resumed
+				return nil, store.Push(depth, links...)
+			}
+		}
+
+		var batch []url.URL
+		for _, link := range links {
+			parsedLink, err := url.Parse(link)
+			if err != nil {
+				continue
+			}
+			firstVisit, err := store.MarkVisited(bfc.linkKey(*parsedLink))
 			if err != nil {
-				safeCrawlingErrorCallback(errorCallback, link, err)
-				return
+				return nil, err
+			}
+			if firstVisit {
+				batch = append(batch, *parsedLink)
 			}
-			result = append(result, links...)
-		}(linkInBatch)
+		}
+		if len(batch) > 0 {
+			return batch, nil
+		}
 	}
-	wg.Wait()
-	return result
 }
 
-func buildBatches(urlsToCrawl []url.URL, batchSize int) [][]url.URL {
-	var result [][]url.URL
-	for i := 0; i < len(urlsToCrawl); i += batchSize {
-		j := i + batchSize
-		if j > len(urlsToCrawl) {
-			j = len(urlsToCrawl)
-		}
-		result = append(result, urlsToCrawl[i:j])
+// crawledPage holds the links found in a crawled page, or the error that prevented crawling it.
+type crawledPage struct {
+	link      url.URL
+	links     []url.URL
+	canonical *url.URL
+	// nofollow are the links recorded but not followed, with the RecordNofollow policy
+	nofollow []url.URL
+	// noindex tells whether the page asks not to be indexed, with the WithMetaRobots option
+	noindex bool
+	// statusCode, header and finalURL are the ones of the response of the page
+	statusCode int
+	header     http.Header
+	finalURL   url.URL
+	err        error
+}
+
+// result returns the CrawlResult of the page crawled at the given depth.
+func (p crawledPage) result(depth int, seeds []Seed) CrawlResult {
+	return CrawlResult{
+		URL:        p.link,
+		Depth:      depth,
+		Links:      p.links,
+		Canonical:  p.canonical,
+		NoIndex:    p.noindex,
+		StatusCode: p.statusCode,
+		Header:     p.header,
+		FinalURL:   p.finalURL,
+		Metadata:   SeedMetadata(seeds, p.link),
+		Err:        p.err,
 	}
-	return result
 }
 
-func crawlWebpage(httpFetcher fetcher.Fetcher, webpageURL url.URL) ([]url.URL, error) {
-	webpageReader, err := httpFetcher.FetchWebpageContent(webpageURL)
-	if err != nil {
-		return nil, err
+// nofollowKeys returns the keys of the links of the page that must not be followed.
+func (p crawledPage) nofollowKeys(linkKey func(url.URL) string) map[string]bool {
+	keys := make(map[string]bool, len(p.nofollow))
+	for _, link := range p.nofollow {
+		keys[linkKey(link)] = true
 	}
-	defer func(webpageReader io.ReadCloser) {
-		_ = webpageReader.Close()
-	}(webpageReader)
+	return keys
+}
 
-	links, err := linkextractor.Extract(webpageURL, webpageReader)
-	if err != nil {
-		return nil, err
+func filterDisallowedLinks(policy robotsPolicy, links []url.URL) []url.URL {
+	if policy == nil {
+		return links
 	}
+	var allowedLinks []url.URL
+	for _, link := range links {
+		if policy.Allowed(link) {
+			allowedLinks = append(allowedLinks, link)
+		}
+	}
+	return allowedLinks
+}
 
-	return links, nil
+func prefetchHosts(prefetcher hostPrefetcher, links []url.URL) {
+	if prefetcher == nil {
+		return
+	}
+	uniqueHosts := make(map[string]bool)
+	var hosts []string
+	for _, link := range links {
+		if !uniqueHosts[link.Hostname()] {
+			uniqueHosts[link.Hostname()] = true
+			hosts = append(hosts, link.Hostname())
+		}
+	}
+	prefetcher.Prefetch(hosts...)
 }
 
-func safeLinkFoundCallback(linkFound linkFoundCallback, link url.URL) {
+func safeLinkFoundCallback(pending *sync.WaitGroup, linkFound linkFoundCallback, link url.URL) {
 	if linkFound == nil {
 		return
 	}
+	pending.Add(1)
 	go func(l url.URL) {
+		defer pending.Done()
 		defer func() {
 			if err := recover(); err != nil {
 				fmt.Println("[RECOVERED] recovered from linkFoundCallback")
@@ -193,11 +664,13 @@ func safeLinkFoundCallback(linkFound linkFoundCallback, link url.URL) {
 	}(link)
 }
 
-func safeCrawlingErrorCallback(errorCallback crawlingErrorCallback, link url.URL, err error) {
+func safeCrawlingErrorCallback(pending *sync.WaitGroup, errorCallback crawlingErrorCallback, link url.URL, err error) {
 	if errorCallback == nil {
 		return
 	}
+	pending.Add(1)
 	go func(l url.URL, e error) {
+		defer pending.Done()
 		defer func() {
 			if err := recover(); err != nil {
 				fmt.Println("[RECOVERED] recovered from errorCallback")
@@ -1,17 +1,25 @@
 package crawler
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/url"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"golang.org/x/net/context"
 
 	"github.com/andiblas/website-crawler/pkg/fetcher"
+	"github.com/andiblas/website-crawler/pkg/frontier"
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
 )
 
 type errorCallbackArgs struct {
@@ -302,3 +310,893 @@ func TestBreadthFirstCrawler_Crawl(t *testing.T) {
 		})
 	}
 }
+
+// contentTypeFetcher skips the pages of the given URLs as if they weren't HTML.
+type contentTypeFetcher struct {
+	*mockFetcher
+	skipped map[string]bool
+}
+
+func (c contentTypeFetcher) FetchWebpageContent(urlToCrawl url.URL) (io.ReadCloser, error) {
+	if c.skipped[urlToCrawl.String()] {
+		return nil, &fetcher.UnsupportedContentTypeError{URL: urlToCrawl, ContentType: "application/pdf"}
+	}
+	return c.mockFetcher.FetchWebpageContent(urlToCrawl)
+}
+
+func TestBreadthFirstCrawler_CrawlWithSkippedContentTypes(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	pageFetcher := contentTypeFetcher{mockFetcher: newMockFetcher(nil), skipped: map[string]bool{"https://test.com/contact": true}}
+	var errorsCount atomic.Int32
+	bfCrawler := NewBreadthFirstCrawler(pageFetcher, WithOnErrorCallback(func(link url.URL, err error) {
+		errorsCount.Add(1)
+	}), WithWaitForCallbacks())
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 100)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	// the skipped page is recorded, but the links only found in it are not
+	sort.Strings(got)
+	want := []string{"https://test.com", "https://test.com/about-us", "https://test.com/contact"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl() links got %v, want %v", got, want)
+	}
+	if errorsCount.Load() != 0 {
+		t.Errorf("Crawl() reported %v errors, want skipped pages not to be errors", errorsCount.Load())
+	}
+}
+
+type mockRobotsPolicy struct {
+	disallowedLinks map[string]bool
+}
+
+func (m mockRobotsPolicy) Allowed(link url.URL) bool {
+	return !m.disallowedLinks[link.String()]
+}
+
+func TestBreadthFirstCrawler_CrawlWithRobotsPolicy(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	policy := mockRobotsPolicy{disallowedLinks: map[string]bool{"https://test.com/contact": true}}
+	bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil), WithRobotsPolicy(policy))
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	want := map[string]bool{
+		"https://test.com":          true,
+		"https://test.com/about-us": true,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Crawl() links len got %v want len %v\ngot\t\t%v\nwant\t%v", len(got), len(want), got, want)
+	}
+	for _, link := range got {
+		if _, ok := want[link]; !ok {
+			t.Errorf("Crawl() link %v not found in %v", link, want)
+		}
+	}
+}
+
+type mockLinkSampler struct {
+	unsampledLinks map[string]bool
+}
+
+func (m mockLinkSampler) Sample(link url.URL) bool {
+	return !m.unsampledLinks[link.String()]
+}
+
+func TestBreadthFirstCrawler_CrawlWithLinkSampler(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	sampler := mockLinkSampler{unsampledLinks: map[string]bool{"https://test.com/about-us": true}}
+	var linksFound sync.Map
+	bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil), WithLinkSampler(sampler), WithLinkFoundCallback(func(link url.URL) {
+		linksFound.Store(link.String(), true)
+	}))
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	if len(got) != 4 {
+		t.Errorf("Crawl() links got %v, want 4 links without the unsampled one", got)
+	}
+	for _, link := range got {
+		if link == "https://test.com/about-us" {
+			t.Errorf("Crawl() should not return the unsampled link %v", link)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if _, ok := linksFound.Load("https://test.com/about-us"); ok {
+		t.Errorf("linkFoundCallback should not be called with the unsampled link")
+	}
+}
+
+type mockSitemapSeeder struct {
+	seeds []string
+}
+
+func (m mockSitemapSeeder) Seeds(_ url.URL) ([]url.URL, error) {
+	var seeds []url.URL
+	for _, seed := range m.seeds {
+		seedUrl, _ := url.Parse(seed)
+		seeds = append(seeds, *seedUrl)
+	}
+	return seeds, nil
+}
+
+func TestBreadthFirstCrawler_CrawlWithSitemapSeeding(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	seeder := mockSitemapSeeder{seeds: []string{"https://www.test.com/depth3/", "https://other.com/page"}}
+	bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil), WithSitemapSeeding(seeder))
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 1, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	want := map[string]bool{
+		"https://test.com":          true,
+		"https://test.com/contact":  true,
+		"https://test.com/about-us": true,
+		"https://test.com/depth3":   true,
+		"https://test.com/depth4":   true,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Crawl() links len got %v want len %v\ngot\t\t%v\nwant\t%v", len(got), len(want), got, want)
+	}
+	for _, link := range got {
+		if _, ok := want[link]; !ok {
+			t.Errorf("Crawl() link %v not found in %v", link, want)
+		}
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithCrawlDelay(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil), WithCrawlDelay(50*time.Millisecond))
+
+	start := time.Now()
+	if _, err := bfCrawler.Crawl(context.Background(), *testUrl, 2, 2); err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	// one batch at the first depth level and one batch at the second depth level
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Crawl() took %v, want at least the crawl delay between batches", elapsed)
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithMaxPages(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	pageFetcher := &recordingFetcher{mockFetcher: newMockFetcher(nil)}
+	bfCrawler := NewBreadthFirstCrawler(pageFetcher, WithMaxPages(2))
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 100)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	// the start page and one of the pages of the second depth level, whose links are still returned
+	if len(pageFetcher.fetchedLinks) != 2 {
+		t.Errorf("Crawl() fetched %v pages, want 2: %v", len(pageFetcher.fetchedLinks), pageFetcher.fetchedLinks)
+	}
+	if len(got) < 3 {
+		t.Errorf("Crawl() links got %v, want the links found in the crawled pages", got)
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithStopWhen(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	target, _ := url.Parse("https://test.com/depth3")
+	pageFetcher := &recordingFetcher{mockFetcher: newMockFetcher(nil)}
+	bfCrawler := NewBreadthFirstCrawler(pageFetcher, WithStopWhen(func(result CrawlResult) bool {
+		return slices.Contains(result.Links, *target)
+	}))
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 100)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	// the batch of the page linking to the target is finished, and the next depth level isn't crawled
+	if len(pageFetcher.fetchedLinks) != 3 {
+		t.Errorf("Crawl() fetched %v pages, want 3: %v", len(pageFetcher.fetchedLinks), pageFetcher.fetchedLinks)
+	}
+	if !slices.Contains(got, target.String()) || slices.Contains(got, "https://test.com/depth4") {
+		t.Errorf("Crawl() links got %v, want the links found until the target was found", got)
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithMaxDuration(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	pageFetcher := &recordingFetcher{mockFetcher: newMockFetcher(nil)}
+	// the crawl delay would block the second batch for an hour if the time budget didn't end the crawl
+	bfCrawler := NewBreadthFirstCrawler(pageFetcher, WithCrawlDelay(time.Hour), WithMaxDuration(50*time.Millisecond))
+
+	start := time.Now()
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 100)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Crawl() took %v, want it to end once the time budget is exhausted", elapsed)
+	}
+	if len(pageFetcher.fetchedLinks) != 1 {
+		t.Errorf("Crawl() fetched links got %v, want only the start page", pageFetcher.fetchedLinks)
+	}
+	// the links found in the start page are returned
+	if len(got) != 3 {
+		t.Errorf("Crawl() links got %v, want the partial results of the crawl", got)
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlCanceledDuringRetries(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	unavailable := &fetcher.UnexpectedStatusError{URL: *testUrl, StatusCode: 503, Status: "503 Service Unavailable"}
+	// the back off between the retries would block the crawl for an hour if canceling didn't end it
+	pageFetcher := fetcher.NewExpBackoffRetryFetcher(newMockFetcher(unavailable), 3, time.Hour, 0)
+	var crawlErrs []error
+	var mu sync.Mutex
+	bfCrawler := NewBreadthFirstCrawler(pageFetcher, WithOnErrorCallback(func(_ url.URL, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		crawlErrs = append(crawlErrs, err)
+	}), WithWaitForCallbacks())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, _ = bfCrawler.Crawl(ctx, *testUrl, 100, 100)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Crawl() took %v, want it to end once the context is canceled", elapsed)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(crawlErrs) != 1 || !errors.Is(crawlErrs[0], context.Canceled) {
+		t.Errorf("Crawl() errors got %v, want the fetch of the start page canceled", crawlErrs)
+	}
+}
+
+type mockSchedule struct {
+	waits atomic.Int32
+	// closeAt cancels the crawl at the given wait, as if it was interrupted while the schedule is closed
+	closeAt int32
+	cancel  context.CancelFunc
+}
+
+func (m *mockSchedule) Wait(_ context.Context) {
+	if m.waits.Add(1) == m.closeAt {
+		m.cancel()
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithSchedule(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	crawlSchedule := &mockSchedule{}
+	bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil), WithSchedule(crawlSchedule))
+
+	if _, err := bfCrawler.Crawl(context.Background(), *testUrl, 2, 100); err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	// the schedule is waited for before every batch, one batch at every depth level
+	if waits := crawlSchedule.waits.Load(); waits < 2 {
+		t.Errorf("Crawl() waited for the schedule %v times, want once before every batch", waits)
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithScheduleInterrupted(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	store := frontier.NewMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	crawlSchedule := &mockSchedule{closeAt: 2, cancel: cancel}
+	bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil), WithSchedule(crawlSchedule), WithFrontierStore(store))
+
+	if _, err := bfCrawler.Crawl(ctx, *testUrl, 100, 100); err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	resumingFetcher := &recordingFetcher{mockFetcher: newMockFetcher(nil)}
+	resumingCrawler := NewBreadthFirstCrawler(resumingFetcher, WithFrontierStore(store))
+	if _, err := resumingCrawler.Resume(context.Background()); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	// the links popped while the schedule was closed are crawled once resumed
+	sort.Strings(resumingFetcher.fetchedLinks)
+	want := []string{"https://test.com/about-us", "https://test.com/contact", "https://test.com/depth3", "https://test.com/depth4"}
+	if !reflect.DeepEqual(resumingFetcher.fetchedLinks, want) {
+		t.Errorf("Resume() fetched links got %v, want %v", resumingFetcher.fetchedLinks, want)
+	}
+}
+
+func TestBreadthFirstCrawler_Resume(t *testing.T) {
+	t.Run("continues an interrupted crawl from the saved frontier", func(t *testing.T) {
+		store := frontier.NewMemoryStore()
+		_ = store.SaveCrawlInfo(frontier.CrawlInfo{URL: "https://test.com", Depth: 100, MaxConcurrency: 1, CurrentDepth: 1})
+		_, _ = store.MarkVisited("https://test.com")
+		_, _ = store.MarkFound("https://test.com/contact")
+		_, _ = store.MarkFound("https://test.com/about-us")
+		_ = store.Push(1, "https://test.com/contact", "https://test.com/about-us")
+		bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil), WithFrontierStore(store))
+
+		got, err := bfCrawler.Resume(context.Background())
+		if err != nil {
+			t.Fatalf("Resume() error = %v", err)
+		}
+		want := map[string]bool{
+			"https://test.com":          true,
+			"https://test.com/contact":  true,
+			"https://test.com/about-us": true,
+			"https://test.com/depth3":   true,
+			"https://test.com/depth4":   true,
+		}
+		if len(got) != len(want) {
+			t.Errorf("Resume() links len got %v want len %v\ngot\t\t%v\nwant\t%v", len(got), len(want), got, want)
+		}
+		for _, link := range got {
+			if _, ok := want[link]; !ok {
+				t.Errorf("Resume() link %v not found in %v", link, want)
+			}
+		}
+	})
+
+	t.Run("fails without a crawl to resume", func(t *testing.T) {
+		bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil))
+		if _, err := bfCrawler.Resume(context.Background()); !errors.Is(err, frontier.NoCrawlInfo) {
+			t.Errorf("Resume() error = %v, want %v", err, frontier.NoCrawlInfo)
+		}
+	})
+}
+
+func TestBreadthFirstCrawler_Snapshot(t *testing.T) {
+	t.Run("resumes an interrupted crawl from its snapshot", func(t *testing.T) {
+		testUrl, _ := url.Parse("https://test.com")
+		bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil), WithMaxPages(2))
+		if _, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1); err != nil {
+			t.Fatalf("Crawl() error = %v", err)
+		}
+		state, err := bfCrawler.Snapshot()
+		if err != nil {
+			t.Fatalf("Snapshot() error = %v", err)
+		}
+		// the state is persisted by the embedding application
+		savedState, err := json.Marshal(state)
+		if err != nil {
+			t.Fatalf("should not throw error at Marshal. err: %v", err)
+		}
+		var loadedState FrontierState
+		if err := json.Unmarshal(savedState, &loadedState); err != nil {
+			t.Fatalf("should not throw error at Unmarshal. err: %v", err)
+		}
+
+		resumingFetcher := &recordingFetcher{mockFetcher: newMockFetcher(nil)}
+		resumingCrawler, err := NewBreadthFirstCrawlerFromState(resumingFetcher, loadedState)
+		if err != nil {
+			t.Fatalf("NewBreadthFirstCrawlerFromState() error = %v", err)
+		}
+		got, err := resumingCrawler.Resume(context.Background())
+		if err != nil {
+			t.Fatalf("Resume() error = %v", err)
+		}
+		// the start page and the first page of the second depth level were crawled before the snapshot
+		sort.Strings(resumingFetcher.fetchedLinks)
+		want := []string{"https://test.com/about-us", "https://test.com/depth3", "https://test.com/depth4"}
+		if !reflect.DeepEqual(resumingFetcher.fetchedLinks, want) {
+			t.Errorf("Resume() fetched links got %v, want %v", resumingFetcher.fetchedLinks, want)
+		}
+		if len(got) != 5 {
+			t.Errorf("Resume() links got %v, want the links of the whole crawl", got)
+		}
+	})
+
+	t.Run("fails without a crawl", func(t *testing.T) {
+		if _, err := NewBreadthFirstCrawler(newMockFetcher(nil)).Snapshot(); !errors.Is(err, frontier.NoCrawlInfo) {
+			t.Errorf("Snapshot() error = %v, want %v", err, frontier.NoCrawlInfo)
+		}
+		if _, err := NewBreadthFirstCrawlerFromState(newMockFetcher(nil), FrontierState{}); !errors.Is(err, frontier.NoCrawlInfo) {
+			t.Errorf("NewBreadthFirstCrawlerFromState() error = %v, want %v", err, frontier.NoCrawlInfo)
+		}
+	})
+}
+
+func TestBreadthFirstCrawler_CrawlChan(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+
+	t.Run("streams every crawled page in breadth first order", func(t *testing.T) {
+		bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil))
+
+		results, errs := bfCrawler.CrawlChan(context.Background(), *testUrl, 3, 1)
+
+		var got []string
+		for result := range results {
+			got = append(got, fmt.Sprintf("%d %s %d", result.Depth, result.URL.String(), len(result.Links)))
+		}
+		if err := <-errs; err != nil {
+			t.Fatalf("CrawlChan() error = %v", err)
+		}
+		want := []string{
+			"0 https://test.com 3",
+			"1 https://test.com/contact 2",
+			"1 https://test.com/about-us 3",
+			"2 https://test.com/depth3 2",
+		}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("CrawlChan() results got\n%v\nwant\n%v", strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	})
+
+	t.Run("streams the pages that failed with their error", func(t *testing.T) {
+		bfCrawler := NewBreadthFirstCrawler(newMockFetcher(errors.New("fetch error")))
+
+		results, errs := bfCrawler.CrawlChan(context.Background(), *testUrl, 2, 1)
+
+		result := <-results
+		if result.Err == nil {
+			t.Errorf("CrawlChan() result error should be the fetch error")
+		}
+		if _, ok := <-results; ok {
+			t.Errorf("CrawlChan() results should be closed after the failed seed")
+		}
+		if err := <-errs; err != nil {
+			t.Errorf("CrawlChan() error = %v", err)
+		}
+	})
+
+	t.Run("streams the status codes of the pages", func(t *testing.T) {
+		results, _ := NewBreadthFirstCrawler(newMockFetcher(nil)).CrawlChan(context.Background(), *testUrl, 1, 1)
+		if result := <-results; result.StatusCode != 200 || result.FinalURL != *testUrl {
+			t.Errorf("CrawlChan() result got status %d and final URL %v, want 200 and %v", result.StatusCode, result.FinalURL.String(), testUrl.String())
+		}
+
+		results, _ = NewBreadthFirstCrawler(&statusFetcher{statusCodes: map[string]int{"https://test.com": 404}}).CrawlChan(context.Background(), *testUrl, 1, 1)
+		if result := <-results; result.StatusCode != 404 || result.Err == nil {
+			t.Errorf("CrawlChan() result got status %d and error %v, want the 404 error", result.StatusCode, result.Err)
+		}
+	})
+
+	t.Run("sends validation errors to the error channel", func(t *testing.T) {
+		bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil))
+
+		results, errs := bfCrawler.CrawlChan(context.Background(), *testUrl, 0, 1)
+
+		if _, ok := <-results; ok {
+			t.Errorf("CrawlChan() results should be closed")
+		}
+		if err := <-errs; !errors.Is(err, InvalidDepth) {
+			t.Errorf("CrawlChan() error = %v, want %v", err, InvalidDepth)
+		}
+	})
+
+	t.Run("stops when the consumer cancels the context", func(t *testing.T) {
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil))
+
+		results, errs := bfCrawler.CrawlChan(ctx, *testUrl, 100, 1)
+		<-results
+		cancelFunc()
+
+		for range results {
+		}
+		if err := <-errs; err != nil {
+			t.Errorf("CrawlChan() error = %v", err)
+		}
+	})
+}
+
+func TestBreadthFirstCrawler_Pages(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+
+	t.Run("iterates every crawled page", func(t *testing.T) {
+		bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil))
+
+		var got []string
+		for page, err := range bfCrawler.Pages(context.Background(), *testUrl, 3, 2) {
+			if err != nil {
+				t.Fatalf("Pages() error = %v", err)
+			}
+			got = append(got, page.URL.String())
+		}
+		want := []string{"https://test.com", "https://test.com/contact", "https://test.com/about-us", "https://test.com/depth3"}
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("Pages() got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("stops the crawl on break", func(t *testing.T) {
+		fetcher := &recordingFetcher{mockFetcher: newMockFetcher(nil)}
+		bfCrawler := NewBreadthFirstCrawler(fetcher)
+
+		for range bfCrawler.Pages(context.Background(), *testUrl, 100, 1) {
+			break
+		}
+		if len(fetcher.fetchedLinks) != 1 {
+			t.Errorf("Pages() fetched %v, want only the seed", fetcher.fetchedLinks)
+		}
+	})
+
+	t.Run("yields the crawl error", func(t *testing.T) {
+		bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil))
+
+		var errs []error
+		for _, err := range bfCrawler.Pages(context.Background(), *testUrl, 1, 0) {
+			errs = append(errs, err)
+		}
+		if len(errs) != 1 || !errors.Is(errs[0], InvalidMaxConcurrency) {
+			t.Errorf("Pages() errors = %v, want %v", errs, InvalidMaxConcurrency)
+		}
+	})
+}
+
+func TestBreadthFirstCrawler_CrawlWithGraph(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	policy := mockRobotsPolicy{disallowedLinks: map[string]bool{"https://test.com/depth3": true}}
+	bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil), WithRobotsPolicy(policy))
+
+	got, err := bfCrawler.CrawlWithGraph(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("CrawlWithGraph() error = %v", err)
+	}
+	wantNodes := []string{"https://test.com", "https://test.com/about-us", "https://test.com/contact"}
+	if !reflect.DeepEqual(got.Nodes, wantNodes) {
+		t.Errorf("CrawlWithGraph() nodes got = %v, want %v", got.Nodes, wantNodes)
+	}
+	wantEdges := []Edge{
+		{From: "https://test.com", To: "https://test.com/contact"},
+		{From: "https://test.com", To: "https://test.com/about-us"},
+		{From: "https://test.com/contact", To: "https://test.com"},
+		{From: "https://test.com/about-us", To: "https://test.com"},
+		{From: "https://test.com/about-us", To: "https://test.com/contact"},
+	}
+	if !reflect.DeepEqual(got.Edges, wantEdges) {
+		t.Errorf("CrawlWithGraph() edges got = %v, want %v", got.Edges, wantEdges)
+	}
+	wantExcluded := []Edge{
+		{From: "https://test.com/contact", To: "https://test.com/depth3"},
+	}
+	if !reflect.DeepEqual(got.Excluded, wantExcluded) {
+		t.Errorf("CrawlWithGraph() excluded got = %v, want %v", got.Excluded, wantExcluded)
+	}
+}
+
+func newCanonicalMockFetcher() *mockFetcher {
+	return &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com":                       `<a href="/products?utm_source=a"/><a href="/products?utm_source=b"/><a href="/products"/>`,
+		"https://test.com/products?utm_source=a": `<link rel="canonical" href="/products"><a href="/products/1"/>`,
+		"https://test.com/products?utm_source=b": `<link rel="canonical" href="/products"><a href="/products/2"/>`,
+		"https://test.com/products":              `<link rel="canonical" href="/products"><a href="/products/1"/>`,
+	}}
+}
+
+func TestBreadthFirstCrawler_CrawlWithCanonicalURLs(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	bfCrawler := NewBreadthFirstCrawler(newCanonicalMockFetcher(), WithCanonicalURLs())
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	sort.Strings(got)
+	want := []string{"https://test.com", "https://test.com/products", "https://test.com/products/1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl() links got = %v, want %v", got, want)
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithTrailingSlashPolicy(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/")
+	siteFetcher := &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com":       `<a href="/docs/"/><a href="/docs"/>`,
+		"https://test.com/docs/": `<a href="/docs/intro/"/>`,
+		"https://test.com/docs":  `<a href="/docs/broken"/>`,
+	}}
+	tests := []struct {
+		name   string
+		policy linkextractor.TrailingSlashPolicy
+		want   []string
+	}{
+		{name: "strip", policy: linkextractor.StripTrailingSlash, want: []string{"https://test.com", "https://test.com/docs", "https://test.com/docs/broken"}},
+		{name: "keep", policy: linkextractor.KeepTrailingSlash, want: []string{"https://test.com", "https://test.com/docs", "https://test.com/docs/intro"}},
+		{name: "distinct", policy: linkextractor.DistinctTrailingSlash, want: []string{"https://test.com", "https://test.com/docs", "https://test.com/docs/", "https://test.com/docs/broken", "https://test.com/docs/intro/"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bfCrawler := NewBreadthFirstCrawler(siteFetcher, WithTrailingSlashPolicy(tt.policy))
+
+			got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+			if err != nil {
+				t.Fatalf("Crawl() error = %v", err)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Crawl() links got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithCaseInsensitivePaths(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	iisFetcher := &recordingFetcher{mockFetcher: &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com":       `<a href="/About"/><a href="/about"/><a href="/ABOUT/"/>`,
+		"https://test.com/About": `<a href="/about/Team"/>`,
+	}}}
+	bfCrawler := NewBreadthFirstCrawler(iisFetcher, WithCaseInsensitivePaths())
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	sort.Strings(got)
+	want := []string{"https://test.com", "https://test.com/about", "https://test.com/about/team"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl() links got = %v, want %v", got, want)
+	}
+	wantFetched := []string{"https://test.com", "https://test.com/About", "https://test.com/about/Team"}
+	if !reflect.DeepEqual(iisFetcher.fetchedLinks, wantFetched) {
+		t.Errorf("Crawl() fetched links got = %v, want %v", iisFetcher.fetchedLinks, wantFetched)
+	}
+}
+
+func newNofollowMockFetcher() *mockFetcher {
+	return &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com":         `<a href="/login" rel="nofollow"/><a href="/partner" rel="sponsored"/><a href="/about"/>`,
+		"https://test.com/about":   `<a href="/login"/>`,
+		"https://test.com/login":   `<a href="/account"/>`,
+		"https://test.com/partner": `<a href="/partner/deals"/>`,
+	}}
+}
+
+func TestBreadthFirstCrawler_CrawlWithNofollowPolicy(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	tests := []struct {
+		name   string
+		policy NofollowPolicy
+		want   []string
+	}{
+		{name: "follow", policy: FollowNofollow, want: []string{"https://test.com", "https://test.com/about", "https://test.com/account", "https://test.com/login", "https://test.com/partner", "https://test.com/partner/deals"}},
+		{name: "skip", policy: SkipNofollow, want: []string{"https://test.com", "https://test.com/about", "https://test.com/account", "https://test.com/login"}},
+		{name: "record", policy: RecordNofollow, want: []string{"https://test.com", "https://test.com/about", "https://test.com/account", "https://test.com/login", "https://test.com/partner"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bfCrawler := NewBreadthFirstCrawler(newNofollowMockFetcher(), WithNofollowPolicy(tt.policy))
+
+			got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+			if err != nil {
+				t.Fatalf("Crawl() error = %v", err)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Crawl() links got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithMetaRobots(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	siteFetcher := &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com":         `<a href="/thanks"/><a href="/archive"/>`,
+		"https://test.com/thanks":  `<meta name="robots" content="noindex"><a href="/offer"/>`,
+		"https://test.com/archive": `<meta name="robots" content="index, nofollow"><a href="/archive/2010"/>`,
+	}}
+	bfCrawler := NewBreadthFirstCrawler(siteFetcher, WithMetaRobots())
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	sort.Strings(got)
+	want := []string{"https://test.com", "https://test.com/archive", "https://test.com/offer"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl() links got = %v, want %v", got, want)
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithDirectoryListingPolicy(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	tests := []struct {
+		name   string
+		policy DirectoryListingPolicy
+		want   []string
+	}{
+		{name: "follow", policy: FollowDirectoryListings, want: []string{"https://test.com", "https://test.com/files", "https://test.com/files/old", "https://test.com/files/report.pdf", "https://test.com/files?C=N;O=D"}},
+		{name: "entries", policy: FollowDirectoryEntries, want: []string{"https://test.com", "https://test.com/files", "https://test.com/files/old", "https://test.com/files/report.pdf"}},
+		{name: "skip", policy: SkipDirectoryListings, want: []string{"https://test.com", "https://test.com/files"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			siteFetcher := &mockFetcher{webpageWithLinks: map[string]string{
+				"https://test.com":       `<a href="/files/"/>`,
+				"https://test.com/files": `<title>Index of /files</title><a href="?C=N;O=D"/><a href="/"/><a href="/files/report.pdf"/><a href="/files/old/"/>`,
+			}}
+			bfCrawler := NewBreadthFirstCrawler(siteFetcher, WithQueryStrings(), WithDirectoryListingPolicy(tt.policy))
+
+			got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+			if err != nil {
+				t.Fatalf("Crawl() error = %v", err)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Crawl() links got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithQueryStrings(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	siteFetcher := &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com": `<a href="/products?page=2"/><a href="/products?page=3"/>`,
+	}}
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{name: "removes the query strings by default", want: []string{"https://test.com", "https://test.com/products"}},
+		{name: "keeps the query strings", opts: []Option{WithQueryStrings()}, want: []string{"https://test.com", "https://test.com/products?page=2", "https://test.com/products?page=3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewBreadthFirstCrawler(siteFetcher, tt.opts...).Crawl(context.Background(), *testUrl, 100, 1)
+			if err != nil {
+				t.Fatalf("Crawl() error = %v", err)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Crawl() links got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithStrippedQueryParameters(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	siteFetcher := &recordingFetcher{mockFetcher: &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com":         `<a href="/pricing?utm_source=home"/><a href="/pricing?utm_source=footer&gclid=1"/><a href="/search?q=go&fbclid=2"/>`,
+		"https://test.com/pricing": `<a href="/?utm_campaign=back"/>`,
+	}}}
+	bfCrawler := NewBreadthFirstCrawler(siteFetcher, WithQueryStrings(), WithStrippedQueryParameters(linkextractor.TrackingParameters...))
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	sort.Strings(got)
+	want := []string{"https://test.com", "https://test.com/pricing", "https://test.com/search?q=go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl() links got = %v, want %v", got, want)
+	}
+	if len(siteFetcher.fetchedLinks) != 3 {
+		t.Errorf("Crawl() fetched links got = %v, want every page fetched once", siteFetcher.fetchedLinks)
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithDefaultDocuments(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/index.html")
+	siteFetcher := &recordingFetcher{mockFetcher: &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com":      `<a href="/index.html"/><a href="/docs/"/><a href="/docs/index.php"/>`,
+		"https://test.com/docs": `<a href="/"/>`,
+	}}}
+	bfCrawler := NewBreadthFirstCrawler(siteFetcher, WithDefaultDocuments("index.html", "index.php"))
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	sort.Strings(got)
+	want := []string{"https://test.com", "https://test.com/docs"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl() links got = %v, want %v", got, want)
+	}
+	if len(siteFetcher.fetchedLinks) != 2 {
+		t.Errorf("Crawl() fetched links got = %v, want every page fetched once", siteFetcher.fetchedLinks)
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlChanWithSeeds(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	docsUrl, _ := url.Parse("https://test.com/docs")
+	otherUrl, _ := url.Parse("https://other.com/docs")
+	siteFetcher := &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com":          `<a href="/about"/>`,
+		"https://test.com/docs":     `<a href="/docs/api"/>`,
+		"https://test.com/docs/api": `<a href="/about"/>`,
+	}}
+	bfCrawler := NewBreadthFirstCrawler(siteFetcher, WithSeeds(
+		Seed{URL: *docsUrl, Metadata: map[string]string{"owner": "docs-team"}},
+		Seed{URL: *otherUrl, Metadata: map[string]string{"owner": "other-team"}},
+	))
+
+	results, errs := bfCrawler.CrawlChan(context.Background(), *testUrl, 3, 1)
+
+	var got []string
+	for result := range results {
+		got = append(got, fmt.Sprintf("%d %s %s", result.Depth, result.URL.String(), result.Metadata["owner"]))
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("CrawlChan() error = %v", err)
+	}
+	want := []string{
+		"0 https://test.com ",
+		"0 https://test.com/docs docs-team",
+		"1 https://test.com/about ",
+		"1 https://test.com/docs/api docs-team",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("CrawlChan() results got\n%v\nwant\n%v", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithScope(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	siteFetcher := &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com":           `<a href="https://blog.test.com"/><a href="https://other.com"/>`,
+		"https://blog.test.com":      `<a href="/post"/>`,
+		"https://blog.test.com/post": `<a href="https://test.com"/>`,
+	}}
+	bfCrawler := NewBreadthFirstCrawler(siteFetcher, WithScope(linkextractor.SameRegistrableDomain))
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	sort.Strings(got)
+	want := []string{"https://blog.test.com", "https://blog.test.com/post", "https://test.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl() links got = %v, want %v", got, want)
+	}
+}
+
+type mockSchemeUpgrader struct {
+	secureHosts map[string]bool
+}
+
+func (m mockSchemeUpgrader) Upgrade(_, link url.URL) (url.URL, bool) {
+	if link.Scheme != "http" || !m.secureHosts[link.Host] {
+		return link, false
+	}
+	link.Scheme = "https"
+	return link, true
+}
+
+func TestBreadthFirstCrawler_CrawlWithSchemeUpgrade(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	siteFetcher := &recordingFetcher{mockFetcher: &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com":       `<a href="http://test.com/about"/><a href="/about"/><a href="http://test.com/contact"/>`,
+		"https://test.com/about": `<a href="/"/>`,
+	}}}
+	bfCrawler := NewBreadthFirstCrawler(siteFetcher, WithSchemeUpgrade(mockSchemeUpgrader{secureHosts: map[string]bool{"test.com": true}}))
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	sort.Strings(got)
+	want := []string{"https://test.com", "https://test.com/about", "https://test.com/contact"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl() links got = %v, want %v", got, want)
+	}
+	for _, link := range siteFetcher.fetchedLinks {
+		if strings.HasPrefix(link, "http://") {
+			t.Errorf("Crawl() fetched links got = %v, want no http:// links", siteFetcher.fetchedLinks)
+		}
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithHashRoutes(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	spaFetcher := &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com":           `<a href="#/settings"/><a href="#/profile"/>`,
+		"https://test.com#/settings": `<a href="#/settings/billing"/>`,
+	}}
+	bfCrawler := NewBreadthFirstCrawler(spaFetcher, WithHashRoutes())
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	sort.Strings(got)
+	want := []string{"https://test.com", "https://test.com#/profile", "https://test.com#/settings", "https://test.com#/settings/billing"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl() links got = %v, want %v", got, want)
+	}
+}
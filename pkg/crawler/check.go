@@ -0,0 +1,149 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+// NotChecked indicates that a URL was left unchecked because the check was canceled, ran out of
+// time, or reached the maximum number of pages.
+var NotChecked = errors.New("url not checked")
+
+// PageStatus is the outcome of checking a URL with CheckURLs.
+type PageStatus struct {
+	// URL is the checked URL, as given.
+	URL url.URL
+	// StatusCode is the status code of the response when the fetcher rejected it, like 404, or 0 when
+	// the URL responded with a page or failed before getting a response.
+	StatusCode int
+	// Disallowed tells whether the URL was left unchecked because the robots policy or the URL filters
+	// don't allow it.
+	Disallowed bool
+	// Err is the error fetching the URL, nil if it responded with a page or with a document skipped for
+	// its content type, like a PDF.
+	Err error
+}
+
+// OK reports whether the URL was checked and responded with a page.
+func (s PageStatus) OK() bool {
+	return !s.Disallowed && s.Err == nil
+}
+
+// CheckURLs fetches every URL of a list, without extracting their links, and returns their status in
+// the same order, for the applications that only need to validate a list of links. The URLs are
+// fetched with the fetcher in batches of up to maxConcurrency, honoring the same options as a crawl:
+// the robots policy and the URL filters, the crawl delay between batches, the schedule, the maximum
+// number of pages and the maximum duration, so the checks are as polite as the crawls configured alike.
+// Retries and rate limits are the ones of the fetcher. Repeated URLs are fetched once.
+//
+// Errors:
+//   - InvalidMaxConcurrency: If maxConcurrency is less than or equal to 0.
+//
+// Example usage:
+//
+//	statuses, err := crawler.CheckURLs(ctx, fetcher.NewHTTPFetcher(http.DefaultClient), links, 10,
+//		crawler.WithRobotsPolicy(robotsPolicy), crawler.WithCrawlDelay(time.Second))
+//	for _, status := range statuses {
+//	    if !status.OK() {
+//	        fmt.Println(status.URL.String(), status.StatusCode, status.Err)
+//	    }
+//	}
+func CheckURLs(ctx context.Context, webpageFetcher fetcher.Fetcher, urls []url.URL, maxConcurrency int, opts ...Option) ([]PageStatus, error) {
+	if maxConcurrency <= 0 {
+		return nil, InvalidMaxConcurrency
+	}
+	config := &crawlerConfig{fetcher: webpageFetcher}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.urlFilters.err != nil {
+		return nil, config.urlFilters.err
+	}
+	ctx, cancel := config.withMaxDuration(ctx)
+	defer cancel()
+
+	statuses := make([]PageStatus, len(urls))
+	// checked maps the key of every URL to check to the index of its first status
+	checked := make(map[string]int, len(urls))
+	var pending []int
+	for i, link := range urls {
+		statuses[i] = PageStatus{URL: link, Err: NotChecked}
+		if (config.robotsPolicy != nil && !config.robotsPolicy.Allowed(link)) || !config.urlFilters.Allowed(link) {
+			statuses[i] = PageStatus{URL: link, Disallowed: true}
+			continue
+		}
+		if _, ok := checked[config.linkKey(link)]; ok {
+			continue
+		}
+		checked[config.linkKey(link)] = i
+		pending = append(pending, i)
+	}
+
+	checkedPages := 0
+	for startedBatches := 0; len(pending) > 0; startedBatches++ {
+		if startedBatches > 0 {
+			config.waitCrawlDelay(ctx)
+		}
+		config.waitSchedule(ctx)
+
+		// graceful cancel before starting a new batch
+		if interrupted(ctx) {
+			break
+		}
+		batchSize := min(config.batchSize(maxConcurrency, checkedPages), len(pending))
+		if batchSize == 0 {
+			break
+		}
+		config.checkBatchConcurrently(ctx, statuses, pending[:batchSize])
+		pending = pending[batchSize:]
+		checkedPages += batchSize
+	}
+
+	for i, link := range urls {
+		if first, ok := checked[config.linkKey(link)]; ok && first != i {
+			statuses[i] = statuses[first]
+			statuses[i].URL = link
+		}
+	}
+	config.waitPendingCallbacks()
+	return statuses, nil
+}
+
+// checkBatchConcurrently fetches the URLs of the statuses at the given indexes at the same time,
+// setting their outcome. The fetches end once the context is done.
+func (c *crawlerConfig) checkBatchConcurrently(ctx context.Context, statuses []PageStatus, batch []int) {
+	wg := sync.WaitGroup{}
+	for _, i := range batch {
+		wg.Add(1)
+
+		go func(status *PageStatus) {
+			defer wg.Done()
+			c.checkURL(ctx, status)
+		}(&statuses[i])
+	}
+	wg.Wait()
+}
+
+// checkURL fetches the URL of the status without reading its content, setting its outcome.
+func (c *crawlerConfig) checkURL(ctx context.Context, status *PageStatus) {
+	result, err := fetcher.FetchContext(ctx, c.fetcher, status.URL)
+	var contentTypeErr *fetcher.UnsupportedContentTypeError
+	if errors.As(err, &contentTypeErr) {
+		status.Err = nil
+		return
+	}
+	var statusErr *fetcher.UnexpectedStatusError
+	if errors.As(err, &statusErr) {
+		status.StatusCode = statusErr.StatusCode
+	}
+	status.Err = err
+	if err != nil {
+		safeCrawlingErrorCallback(&c.pendingCallbacks, c.onError, status.URL, err)
+		return
+	}
+	_ = result.Body.Close()
+}
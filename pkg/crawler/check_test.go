@@ -0,0 +1,82 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+// statusFetcher responds with the given status code to the URLs of its map, with a PDF to the ones
+// ending in .pdf, and with an empty page to the rest, counting the fetches.
+type statusFetcher struct {
+	statusCodes map[string]int
+	fetches     atomic.Int32
+}
+
+func (s *statusFetcher) FetchWebpageContent(link url.URL) (io.ReadCloser, error) {
+	s.fetches.Add(1)
+	if statusCode, ok := s.statusCodes[link.String()]; ok {
+		return nil, &fetcher.UnexpectedStatusError{URL: link, StatusCode: statusCode}
+	}
+	if strings.HasSuffix(link.Path, ".pdf") {
+		return nil, &fetcher.UnsupportedContentTypeError{URL: link, ContentType: "application/pdf"}
+	}
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func TestCheckURLs(t *testing.T) {
+	var links []url.URL
+	for _, link := range []string{"https://test.com", "https://test.com/missing", "https://test.com/private", "https://test.com/report.pdf", "https://test.com", "https://test.com/gone"} {
+		parsedURL, _ := url.Parse(link)
+		links = append(links, *parsedURL)
+	}
+	pageFetcher := &statusFetcher{statusCodes: map[string]int{"https://test.com/missing": 404, "https://test.com/gone": 410}}
+	policy := mockRobotsPolicy{disallowedLinks: map[string]bool{"https://test.com/private": true}}
+
+	got, err := CheckURLs(context.Background(), pageFetcher, links, 2, WithRobotsPolicy(policy))
+	if err != nil {
+		t.Fatalf("CheckURLs() error = %v", err)
+	}
+	want := []struct {
+		ok         bool
+		statusCode int
+		disallowed bool
+	}{{ok: true}, {statusCode: 404}, {disallowed: true}, {ok: true}, {ok: true}, {statusCode: 410}}
+	if len(got) != len(want) {
+		t.Fatalf("CheckURLs() got %d statuses, want %d", len(got), len(want))
+	}
+	for i, status := range got {
+		if status.URL != links[i] || status.OK() != want[i].ok || status.StatusCode != want[i].statusCode || status.Disallowed != want[i].disallowed {
+			t.Errorf("CheckURLs() status %d got = %+v, want %+v", i, status, want[i])
+		}
+	}
+	// the repeated URL is fetched once, and the disallowed one isn't fetched
+	if pageFetcher.fetches.Load() != 4 {
+		t.Errorf("CheckURLs() fetched %d URLs, want 4", pageFetcher.fetches.Load())
+	}
+}
+
+func TestCheckURLs_MaxPages(t *testing.T) {
+	var links []url.URL
+	for _, link := range []string{"https://test.com", "https://test.com/about", "https://test.com/contact"} {
+		parsedURL, _ := url.Parse(link)
+		links = append(links, *parsedURL)
+	}
+
+	got, err := CheckURLs(context.Background(), &statusFetcher{}, links, 1, WithMaxPages(2))
+	if err != nil {
+		t.Fatalf("CheckURLs() error = %v", err)
+	}
+	if !got[0].OK() || !got[1].OK() || !errors.Is(got[2].Err, NotChecked) {
+		t.Errorf("CheckURLs() got = %+v, want the last URL not checked", got)
+	}
+	if _, err := CheckURLs(context.Background(), &statusFetcher{}, links, 0); !errors.Is(err, InvalidMaxConcurrency) {
+		t.Errorf("CheckURLs() error = %v, want %v", err, InvalidMaxConcurrency)
+	}
+}
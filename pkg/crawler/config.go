@@ -0,0 +1,332 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+	"github.com/andiblas/website-crawler/pkg/frontier"
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+type linkFoundCallback func(link url.URL)
+type crawlingErrorCallback func(link url.URL, err error)
+type stopCondition func(result CrawlResult) bool
+
+type hostPrefetcher interface {
+	Prefetch(hosts ...string)
+}
+
+type robotsPolicy interface {
+	Allowed(link url.URL) bool
+}
+
+type sitemapSeeder interface {
+	Seeds(siteURL url.URL) ([]url.URL, error)
+}
+
+type linkSampler interface {
+	Sample(link url.URL) bool
+}
+
+type crawlSchedule interface {
+	Wait(ctx context.Context)
+}
+
+type seedResolver interface {
+	Resolve(url url.URL) (url.URL, error)
+}
+
+type schemeUpgrader interface {
+	Upgrade(page, link url.URL) (url.URL, bool)
+}
+
+// crawlerConfig holds the configuration shared by all the crawler implementations,
+// so the same options can be used to build any of them.
+type crawlerConfig struct {
+	fetcher        fetcher.Fetcher
+	linkFound      linkFoundCallback
+	onError        crawlingErrorCallback
+	hostPrefetcher hostPrefetcher
+	robotsPolicy   robotsPolicy
+	sitemapSeeder  sitemapSeeder
+	linkSampler    linkSampler
+	extractOptions []linkextractor.Option
+	crawlDelay     time.Duration
+	maxPages       int
+	maxDuration    time.Duration
+	schedule       crawlSchedule
+	frontierStore  frontier.Store
+	canonicalURLs  bool
+	nofollowPolicy NofollowPolicy
+	metaRobots     bool
+	schemeUpgrader schemeUpgrader
+	extraSeeds     []Seed
+	urlFilters     urlFilters
+
+	directoryListingPolicy DirectoryListingPolicy
+	seedResolver           seedResolver
+	probeSeedSchemes       bool
+	hreflangSeeds          bool
+
+	progressWriter   io.Writer
+	progressInterval time.Duration
+	// progress counts the pages and the links of the running crawl, nil without a progress writer
+	progress *crawlProgress
+
+	stopWhen stopCondition
+
+	waitForCallbacks bool
+	// pendingCallbacks tracks the callbacks still running, so a crawl can wait for them before returning.
+	pendingCallbacks sync.WaitGroup
+}
+
+// crawlableLinks filters the links found in a page down to the ones allowed by the robots
+// policy, the URL filters and the link sampler.
+func (c *crawlerConfig) crawlableLinks(links []url.URL) []url.URL {
+	links = c.filteredLinks(filterDisallowedLinks(c.robotsPolicy, links))
+	if c.linkSampler == nil {
+		return links
+	}
+	var sampledLinks []url.URL
+	for _, link := range links {
+		if c.linkSampler.Sample(link) {
+			sampledLinks = append(sampledLinks, link)
+		}
+	}
+	return sampledLinks
+}
+
+func (c *crawlerConfig) seeds(urlToCrawl url.URL) []url.URL {
+	startURL := linkextractor.Normalize(urlToCrawl, c.extractOptions...)
+	seeds := []url.URL{startURL}
+	if c.hreflangSeeds {
+		seeds = append(seeds, c.hreflangRoots(startURL)...)
+	}
+	for _, seed := range c.extraSeeds {
+		normalizedURL := linkextractor.Normalize(seed.URL, c.extractOptions...)
+		if linkextractor.InScope(startURL, normalizedURL, c.extractOptions...) && c.urlFilters.Allowed(normalizedURL) {
+			seeds = append(seeds, normalizedURL)
+		}
+	}
+	if c.sitemapSeeder == nil {
+		return seeds
+	}
+
+	sitemapURLs, err := c.sitemapSeeder.Seeds(startURL)
+	if err != nil {
+		safeCrawlingErrorCallback(&c.pendingCallbacks, c.onError, startURL, err)
+	}
+	for _, sitemapURL := range sitemapURLs {
+		normalizedURL := linkextractor.Normalize(sitemapURL, c.extractOptions...)
+		// sitemaps can list pages of other hosts, which are out of the scope of the crawl
+		if linkextractor.InScope(startURL, normalizedURL, c.extractOptions...) && c.urlFilters.Allowed(normalizedURL) {
+			seeds = append(seeds, normalizedURL)
+		}
+	}
+	return seeds
+}
+
+// filteredLinks returns the links allowed by the URL filters.
+func (c *crawlerConfig) filteredLinks(links []url.URL) []url.URL {
+	var filteredLinks []url.URL
+	for _, link := range links {
+		if c.urlFilters.Allowed(link) {
+			filteredLinks = append(filteredLinks, link)
+		}
+	}
+	return filteredLinks
+}
+
+// seedKey returns the identity of a seed as given, once normalized like the links found.
+func (c *crawlerConfig) seedKey(seed url.URL) string {
+	return c.linkKey(linkextractor.Normalize(seed, c.extractOptions...))
+}
+
+// linkKey returns the identity of a link, used to dedup the links found.
+func (c *crawlerConfig) linkKey(link url.URL) string {
+	return linkextractor.Key(link, c.extractOptions...)
+}
+
+// waitPendingCallbacks waits for the callbacks still running if the crawler is configured to.
+func (c *crawlerConfig) waitPendingCallbacks() {
+	if c.waitForCallbacks {
+		c.pendingCallbacks.Wait()
+	}
+}
+
+// maxDurationExceeded is the cause of the context of a crawl whose time budget is exhausted.
+var maxDurationExceeded = errors.New("max duration of the crawl exceeded")
+
+// withMaxDuration returns a context that is done once the time budget set with WithMaxDuration is
+// exhausted, so the crawl stops like when it's canceled.
+func (c *crawlerConfig) withMaxDuration(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.maxDuration <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, c.maxDuration, maxDurationExceeded)
+}
+
+// interrupted reports whether the crawl must stop before starting a new batch: its context was
+// canceled, or its time budget is exhausted.
+func interrupted(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled) || errors.Is(context.Cause(ctx), maxDurationExceeded)
+}
+
+// conditionMet reports whether the crawled page meets the condition set with WithStopWhen.
+func (c *crawlerConfig) conditionMet(result CrawlResult) bool {
+	return c.stopWhen != nil && c.stopWhen(result)
+}
+
+// batchSize returns the number of pages to crawl in the next batch: maxConcurrency, or the pages left
+// to reach the maximum set with WithMaxPages, which is zero once it's reached.
+func (c *crawlerConfig) batchSize(maxConcurrency, crawledPages int) int {
+	if c.maxPages > 0 && c.maxPages-crawledPages < maxConcurrency {
+		return max(c.maxPages-crawledPages, 0)
+	}
+	return maxConcurrency
+}
+
+// waitCrawlDelay waits for the configured crawl delay, returning early if the context is done.
+func (c *crawlerConfig) waitCrawlDelay(ctx context.Context) {
+	if c.crawlDelay <= 0 {
+		return
+	}
+	select {
+	case <-time.After(c.crawlDelay):
+	case <-ctx.Done():
+	}
+}
+
+// waitSchedule waits for the schedule of the crawl to open, returning early if the context is done.
+func (c *crawlerConfig) waitSchedule(ctx context.Context) {
+	if c.schedule != nil {
+		c.schedule.Wait(ctx)
+	}
+}
+
+// crawlBatchConcurrently crawls all the links of the batch at the same time and returns the crawled
+// pages in the same order as the batch. The fetches end once the context is done, failing the pages
+// still being fetched.
+func (c *crawlerConfig) crawlBatchConcurrently(ctx context.Context, batch []url.URL) []crawledPage {
+	result := make([]crawledPage, len(batch))
+	wg := sync.WaitGroup{}
+	for i, linkInBatch := range batch {
+		wg.Add(1)
+
+		go func(i int, link url.URL) {
+			defer wg.Done()
+			page := c.crawlWebpage(ctx, link)
+			c.progress.pageCrawled(page.err)
+			if page.err != nil {
+				safeCrawlingErrorCallback(&c.pendingCallbacks, c.onError, link, page.err)
+			}
+			result[i] = page
+		}(i, linkInBatch)
+	}
+	wg.Wait()
+	return result
+}
+
+// crawlWebpage fetches the webpage and extracts its links, keeping the status code, the headers and the
+// final URL of its response. The context is passed to the fetcher, see fetcher.FetchContext, and
+// extractWebpage.
+func (c *crawlerConfig) crawlWebpage(ctx context.Context, webpageURL url.URL) crawledPage {
+	result, err := fetcher.FetchContext(ctx, c.fetcher, webpageURL)
+	page := c.extractWebpage(webpageURL, result.Body, err)
+	page.statusCode, page.header, page.finalURL = result.StatusCode, result.Header, result.FinalURL
+	return page
+}
+
+// extractWebpage extracts the links of the fetched webpage. Responses skipped for their content type,
+// like PDFs, are crawled pages without links rather than errors. If the crawler uses canonical URLs,
+// the page includes its canonical URL when it's a different page in the scope of the crawl. The links
+// that ask crawlers not to follow them are handled according to the nofollow policy, the ones of the
+// directory listings according to the directory listing policy, and the meta robots directives of
+// the page are applied if the crawler respects them. The http:// links are upgraded to https if the
+// crawler is configured with a scheme upgrader.
+func (c *crawlerConfig) extractWebpage(webpageURL url.URL, webpageReader io.ReadCloser, err error) crawledPage {
+	var contentTypeErr *fetcher.UnsupportedContentTypeError
+	if errors.As(err, &contentTypeErr) {
+		return crawledPage{link: webpageURL}
+	}
+	if err != nil {
+		return crawledPage{link: webpageURL, err: err}
+	}
+	defer func(webpageReader io.ReadCloser) {
+		_ = webpageReader.Close()
+	}(webpageReader)
+
+	if !c.canonicalURLs && c.nofollowPolicy == FollowNofollow && !c.metaRobots && c.directoryListingPolicy == FollowDirectoryListings {
+		links, err := linkextractor.Extract(webpageURL, webpageReader, c.extractOptions...)
+		return c.withUpgradedSchemes(crawledPage{link: webpageURL, links: links, err: err})
+	}
+
+	extractedPage, err := linkextractor.ExtractPage(webpageURL, webpageReader, c.extractOptions...)
+	if err != nil {
+		return crawledPage{link: webpageURL, err: err}
+	}
+	page := crawledPage{link: webpageURL, links: extractedPage.Links}
+	switch c.nofollowPolicy {
+	case SkipNofollow:
+		page.links = c.withoutLinks(page.links, extractedPage.NofollowLinks)
+	case RecordNofollow:
+		page.nofollow = extractedPage.NofollowLinks
+	}
+	if c.metaRobots {
+		page.noindex = extractedPage.Meta.NoIndex()
+		if extractedPage.Meta.NoFollow() {
+			page.links = nil
+			page.nofollow = nil
+		}
+	}
+	if extractedPage.DirectoryListing {
+		switch c.directoryListingPolicy {
+		case FollowDirectoryEntries:
+			page.links = linkextractor.DirectoryEntries(webpageURL, page.links)
+			page.nofollow = nil
+		case SkipDirectoryListings:
+			page.links = nil
+			page.nofollow = nil
+		}
+	}
+	canonical := extractedPage.Meta.Canonical
+	if c.canonicalURLs && canonical != nil && linkextractor.InScope(webpageURL, *canonical, c.extractOptions...) && c.linkKey(*canonical) != c.linkKey(webpageURL) {
+		page.canonical = canonical
+	}
+	return c.withUpgradedSchemes(page)
+}
+
+// withUpgradedSchemes upgrades the http:// links of the page to https if the crawler is configured
+// with a scheme upgrader that knows their host is served over https.
+func (c *crawlerConfig) withUpgradedSchemes(page crawledPage) crawledPage {
+	if c.schemeUpgrader == nil {
+		return page
+	}
+	for i, link := range page.links {
+		page.links[i], _ = c.schemeUpgrader.Upgrade(page.link, link)
+	}
+	for i, link := range page.nofollow {
+		page.nofollow[i], _ = c.schemeUpgrader.Upgrade(page.link, link)
+	}
+	return page
+}
+
+// withoutLinks returns the links that are not in the excluded ones.
+func (c *crawlerConfig) withoutLinks(links, excluded []url.URL) []url.URL {
+	excludedKeys := make(map[string]bool, len(excluded))
+	for _, link := range excluded {
+		excludedKeys[c.linkKey(link)] = true
+	}
+	var remaining []url.URL
+	for _, link := range links {
+		if !excludedKeys[c.linkKey(link)] {
+			remaining = append(remaining, link)
+		}
+	}
+	return remaining
+}
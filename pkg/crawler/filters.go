@@ -0,0 +1,84 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// InvalidURLFilter indicates that a pattern of the WithURLFilters option is not a valid regular expression.
+var InvalidURLFilter = errors.New("invalid URL filter")
+
+// globPrefix marks the URL filter patterns that are globs instead of regular expressions.
+const globPrefix = "glob:"
+
+// urlFilters restricts the crawl to the links whose path and query match any of the include
+// patterns, if any, and none of the exclude patterns.
+type urlFilters struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+	err     error
+}
+
+func newURLFilters(include, exclude []string) urlFilters {
+	var filters urlFilters
+	filters.include, filters.err = compileURLFilters(include)
+	if filters.err == nil {
+		filters.exclude, filters.err = compileURLFilters(exclude)
+	}
+	return filters
+}
+
+// Allowed reports whether the link passes the filters.
+func (f urlFilters) Allowed(link url.URL) bool {
+	pathAndQuery := link.EscapedPath()
+	if pathAndQuery == "" {
+		pathAndQuery = "/"
+	}
+	if link.RawQuery != "" {
+		pathAndQuery += "?" + link.RawQuery
+	}
+
+	for _, pattern := range f.exclude {
+		if pattern.MatchString(pathAndQuery) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, pattern := range f.include {
+		if pattern.MatchString(pathAndQuery) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileURLFilters(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		expression := pattern
+		if glob, ok := strings.CutPrefix(pattern, globPrefix); ok {
+			expression = globExpression(glob)
+		}
+		compiledPattern, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %v", InvalidURLFilter, pattern, err)
+		}
+		compiled = append(compiled, compiledPattern)
+	}
+	return compiled, nil
+}
+
+// globExpression returns the regular expression matching the whole path and query with the glob,
+// where "*" matches any sequence of characters and the rest of the glob matches literally.
+func globExpression(glob string) string {
+	parts := strings.Split(glob, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return "^" + strings.Join(parts, ".*") + "$"
+}
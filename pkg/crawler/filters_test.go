@@ -0,0 +1,72 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func Test_urlFilters_Allowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		link    string
+		want    bool
+	}{
+		{name: "no filters", link: "https://test.com/blog", want: true},
+		{name: "included by regex", include: []string{"^/docs/"}, link: "https://test.com/docs/api", want: true},
+		{name: "not included by regex", include: []string{"^/docs/"}, link: "https://test.com/blog/docs/", want: false},
+		{name: "included by any pattern", include: []string{"^/docs/", "^/api/"}, link: "https://test.com/api/v1", want: true},
+		{name: "root path", include: []string{"^/$"}, link: "https://test.com", want: true},
+		{name: "excluded by regex on query", exclude: []string{`^/search\?`}, link: "https://test.com/search?q=go", want: false},
+		{name: "excluded by glob", exclude: []string{"glob:/blog/*/comments"}, link: "https://test.com/blog/2024/post/comments", want: false},
+		{name: "glob matches the whole path", exclude: []string{"glob:/blog/*/comments"}, link: "https://test.com/blog/post/comments/1", want: true},
+		{name: "glob is literal", include: []string{"glob:/search?q=*"}, link: "https://test.com/search?q=go", want: true},
+		{name: "exclude wins over include", include: []string{"^/docs/"}, exclude: []string{"/print$"}, link: "https://test.com/docs/api/print", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := newURLFilters(tt.include, tt.exclude)
+			if filters.err != nil {
+				t.Fatalf("should not throw error at newURLFilters. err: %v", filters.err)
+			}
+			link, _ := url.Parse(tt.link)
+			if got := filters.Allowed(*link); got != tt.want {
+				t.Errorf("Allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithURLFilters(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	siteFetcher := &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com":            `<a href="/docs"/><a href="/docs/api"/><a href="/blog"/>`,
+		"https://test.com/docs/api":   `<a href="/docs/guide"/><a href="/docs/api/print"/>`,
+		"https://test.com/docs/guide": `<a href="/blog/post"/>`,
+	}}
+	bfCrawler := NewBreadthFirstCrawler(siteFetcher, WithURLFilters([]string{"^/docs/"}, []string{"glob:*/print"}))
+
+	got, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	sort.Strings(got)
+	want := []string{"https://test.com", "https://test.com/docs/api", "https://test.com/docs/guide"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl() got = %v, want %v", got, want)
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithInvalidURLFilter(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil), WithURLFilters(nil, []string{"^/search("}))
+
+	if _, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 1); !errors.Is(err, InvalidURLFilter) {
+		t.Errorf("Crawl() error = %v, want %v", err, InvalidURLFilter)
+	}
+}
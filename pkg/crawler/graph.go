@@ -0,0 +1,45 @@
+package crawler
+
+// ClickPaths returns the click path of every page reachable from the seed of the crawl through the edges
+// of the graph: the shortest path of links from the seed to the page, keyed by page. Every path starts
+// with the seed and ends with the page, so the number of clicks to reach the page is its length minus
+// one. When many paths are the shortest, the one through the pages crawled first is returned. The pages
+// only linked from other seeds, like the ones of WithSeeds, are left out.
+//
+// Example usage:
+//
+//	graph, err := crawler.CrawlWithGraph(context.Background(), *urlToCrawl, 10, 20)
+//	for page, path := range graph.ClickPaths() {
+//	    fmt.Printf("%s is %d clicks away: %s\n", page, len(path)-1, strings.Join(path, " > "))
+//	}
+func (g *CrawlGraph) ClickPaths() map[string][]string {
+	if g.Seed == "" {
+		return map[string][]string{}
+	}
+	linksFrom := make(map[string][]string)
+	for _, edge := range g.Edges {
+		linksFrom[edge.From] = append(linksFrom[edge.From], edge.To)
+	}
+
+	paths := map[string][]string{g.Seed: {g.Seed}}
+	pending := []string{g.Seed}
+	for len(pending) > 0 {
+		page := pending[0]
+		pending = pending[1:]
+		for _, link := range linksFrom[page] {
+			if _, ok := paths[link]; ok {
+				continue
+			}
+			path := make([]string, len(paths[page]), len(paths[page])+1)
+			copy(path, paths[page])
+			paths[link] = append(path, link)
+			pending = append(pending, link)
+		}
+	}
+	return paths
+}
+
+// ClickPath returns the click path of the page, nil if it's not reachable from the seed. See ClickPaths.
+func (g *CrawlGraph) ClickPath(page string) []string {
+	return g.ClickPaths()[page]
+}
@@ -0,0 +1,30 @@
+package crawler
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestCrawlGraph_ClickPaths(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	graph, err := NewBreadthFirstCrawler(newMockFetcher(nil)).CrawlWithGraph(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("CrawlWithGraph() error = %v", err)
+	}
+
+	want := map[string][]string{
+		"https://test.com":          {"https://test.com"},
+		"https://test.com/contact":  {"https://test.com", "https://test.com/contact"},
+		"https://test.com/about-us": {"https://test.com", "https://test.com/about-us"},
+		"https://test.com/depth3":   {"https://test.com", "https://test.com/contact", "https://test.com/depth3"},
+		"https://test.com/depth4":   {"https://test.com", "https://test.com/contact", "https://test.com/depth3", "https://test.com/depth4"},
+	}
+	if got := graph.ClickPaths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ClickPaths() got = %v, want %v", got, want)
+	}
+	if got := graph.ClickPath("https://test.com/missing"); got != nil {
+		t.Errorf("ClickPath() got = %v, want nil for a page not in the graph", got)
+	}
+}
@@ -1,6 +1,15 @@
 package crawler
 
-type Option func(crawler *BreadthFirstCrawler)
+import (
+	"io"
+	"time"
+
+	"github.com/andiblas/website-crawler/pkg/frontier"
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+// Option configures a crawler. The same options can be used with every crawler implementation.
+type Option func(crawler *crawlerConfig)
 
 // WithLinkFoundCallback is an option to set the callback function that
 // will be executed when a new link is discovered during crawling.
@@ -18,7 +27,7 @@ type Option func(crawler *BreadthFirstCrawler)
 //	}
 //	crawler := NewBreadthFirstCrawler(fetcher, WithLinkFoundCallback(linkCallback))
 func WithLinkFoundCallback(linkFound linkFoundCallback) Option {
-	return func(crawler *BreadthFirstCrawler) {
+	return func(crawler *crawlerConfig) {
 		crawler.linkFound = linkFound
 	}
 }
@@ -39,7 +48,642 @@ func WithLinkFoundCallback(linkFound linkFoundCallback) Option {
 //	}
 //	crawler := NewBreadthFirstCrawler(fetcher, WithOnErrorCallback(errorCallback))
 func WithOnErrorCallback(onErrorCallback crawlingErrorCallback) Option {
-	return func(crawler *BreadthFirstCrawler) {
+	return func(crawler *crawlerConfig) {
 		crawler.onError = onErrorCallback
 	}
 }
+
+// WithWaitForCallbacks is an option to make the crawl wait for the link found
+// and error callbacks still running before returning, so the callbacks don't
+// outlive the crawl. They are still executed asynchronously while crawling.
+//
+// Returns:
+//   - An Option function that makes the BreadthFirstCrawler wait for its callbacks.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithLinkFoundCallback(writeLink), WithWaitForCallbacks())
+//	links, err := crawler.Crawl(context.Background(), *urlToCrawl, 3, 10)
+//	flushOutput() // every link was written
+func WithWaitForCallbacks() Option {
+	return func(crawler *crawlerConfig) {
+		crawler.waitForCallbacks = true
+	}
+}
+
+// WithHostPrefetcher is an option to set a prefetcher that will be notified with
+// the hosts of the links about to be crawled at each depth level, so it can
+// resolve them in the background before they are fetched.
+//
+// Parameters:
+//   - prefetcher: The hostPrefetcher that will receive the hosts to prefetch.
+//
+// Returns:
+//   - An Option function that sets the provided hostPrefetcher to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	dnsResolver := resolver.NewCachingResolver(net.DefaultResolver, 10)
+//	crawler := NewBreadthFirstCrawler(fetcher, WithHostPrefetcher(dnsResolver))
+func WithHostPrefetcher(prefetcher hostPrefetcher) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.hostPrefetcher = prefetcher
+	}
+}
+
+// WithSeedCheck is an option to verify the URL to crawl responds with a page before expanding it,
+// following its redirects, so a typo'd or broken seed fails fast with a SeedError instead of yielding
+// an empty crawl. The crawl starts from the URL the redirects of the seed end at, so the depth is
+// counted from that page, reported with the seed as given in CrawlResult.RedirectedFrom, and the links
+// to the seed as given aren't crawled again. With probeSchemes, both the https and the http variants
+// of the seed are tried, in that order, and the first that responds is crawled.
+//
+// Parameters:
+//   - resolver: The seedResolver that fetches the seed, like a fetcher.HTTPFetcher.
+//   - probeSchemes: Whether to try both the https and the http variants of the seed.
+//
+// Returns:
+//   - An Option function that makes the BreadthFirstCrawler check the seed before crawling it.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithSeedCheck(fetcher.NewHTTPFetcher(http.DefaultClient), true))
+func WithSeedCheck(resolver seedResolver, probeSchemes bool) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.seedResolver = resolver
+		crawler.probeSeedSchemes = probeSchemes
+	}
+}
+
+// WithHreflangSeeds is an option to also start the crawl from every localized version of the URL to
+// crawl declared by its <link rel="alternate" hreflang="..."> tags, like /de-de/ and /fr-fr/ for a
+// seed that redirects into /en-us/, so every locale is crawled to the same depth instead of being
+// found through the links of the seed. Along with WithSeedCheck, the crawl starts from the locale root
+// the seed redirects to.
+//
+// Returns:
+//   - An Option function that makes the BreadthFirstCrawler start from the hreflang roots of the seed.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithSeedCheck(httpFetcher, false), WithHreflangSeeds())
+func WithHreflangSeeds() Option {
+	return func(crawler *crawlerConfig) {
+		crawler.hreflangSeeds = true
+	}
+}
+
+// WithRobotsPolicy is an option to set the policy consulted before enqueuing
+// every link, so links disallowed by the robots.txt file of their host are
+// neither crawled nor reported. Passing nil disables the robots.txt checks,
+// which is the default.
+//
+// Parameters:
+//   - policy: The robotsPolicy used to decide whether a link can be crawled.
+//
+// Returns:
+//   - An Option function that sets the provided robotsPolicy to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	policy := robots.NewPolicy(fetcher, "website-crawler")
+//	crawler := NewBreadthFirstCrawler(fetcher, WithRobotsPolicy(policy))
+func WithRobotsPolicy(policy robotsPolicy) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.robotsPolicy = policy
+	}
+}
+
+// WithSitemapSeeding is an option to set a seeder that provides additional
+// seed URLs, usually taken from the sitemap.xml of the crawled site. Seeds
+// are crawled at the first depth level along with the provided URL, so pages
+// that are not reachable through internal links are found too.
+//
+// Parameters:
+//   - seeder: The sitemapSeeder that provides the additional seeds.
+//
+// Returns:
+//   - An Option function that sets the provided sitemapSeeder to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	seeder := sitemap.NewSeeder(fetcher)
+//	crawler := NewBreadthFirstCrawler(fetcher, WithSitemapSeeding(seeder))
+func WithSitemapSeeding(seeder sitemapSeeder) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.sitemapSeeder = seeder
+	}
+}
+
+// WithLinkSampler is an option to limit the crawl of parameterized pages, like
+// internal search results or tag listings, to a small sample of every URL
+// pattern. The links the sampler leaves out are neither crawled nor reported
+// as found, reducing the noise and the load on the site. The query strings of
+// the links are kept, as the patterns need them to tell the variants apart.
+//
+// Parameters:
+//   - sampler: The sampler that decides which found links are crawled, e.g. a sampling.Sampler.
+//
+// Returns:
+//   - An Option function that sets the provided sampler to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	sampler, _ := sampling.NewSampler(5, "/search?q=*", "/tag/*")
+//	crawler := NewBreadthFirstCrawler(fetcher, WithLinkSampler(sampler))
+//	links, err := crawler.Crawl(context.Background(), *urlToCrawl, 3, 10)
+//	fmt.Println(sampler.Counts())
+func WithLinkSampler(sampler linkSampler) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.linkSampler = sampler
+		crawler.extractOptions = append(crawler.extractOptions, linkextractor.WithQueryStrings())
+	}
+}
+
+// WithQueryStrings is an option to keep the query strings of the links, so /products?page=2 is
+// crawled as a distinct page. By default they're removed, so only /products is crawled, as following
+// every variant of the parameterized pages, like internal search results, may never end. See
+// WithLinkSampler to crawl only a sample of them.
+//
+// Returns:
+//   - An Option function that makes the BreadthFirstCrawler keep the query strings of the links.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithQueryStrings())
+func WithQueryStrings() Option {
+	return func(crawler *crawlerConfig) {
+		crawler.extractOptions = append(crawler.extractOptions, linkextractor.WithQueryStrings())
+	}
+}
+
+// WithHashRoutes is an option to crawl the hash routes of single page
+// applications, like /#/settings, as distinct pages instead of removing the
+// fragments of the links. As HTTP requests don't include the fragment, it's
+// only useful with a fetcher that renders the routes, like a headless browser.
+//
+// Returns:
+//   - An Option function that makes the BreadthFirstCrawler keep the hash routes of the links.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(renderingFetcher, WithHashRoutes())
+func WithHashRoutes() Option {
+	return func(crawler *crawlerConfig) {
+		crawler.extractOptions = append(crawler.extractOptions, linkextractor.WithHashRoutes())
+	}
+}
+
+// WithFragmentPolicy is an option to set how the #fragments of the links are handled. By default
+// they're removed, so /page and /page#section are the same page, as servers never see the fragment.
+// With linkextractor.KeepFragments, every fragment is kept and /page#section is crawled as a distinct
+// page, which is only useful with a fetcher that renders the fragments, like a headless browser.
+//
+// Parameters:
+//   - policy: The linkextractor.FragmentPolicy used to normalize the links.
+//
+// Returns:
+//   - An Option function that sets the provided fragment policy to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(renderingFetcher, WithFragmentPolicy(linkextractor.KeepFragments))
+func WithFragmentPolicy(policy linkextractor.FragmentPolicy) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.extractOptions = append(crawler.extractOptions, linkextractor.WithFragmentPolicy(policy))
+	}
+}
+
+// WithTrailingSlashPolicy is an option to set how the trailing slashes of the links are handled.
+// By default they're removed, so /docs/ and /docs are the same page, which can hide broken URLs on
+// servers that respond differently to them. With linkextractor.KeepTrailingSlash, the links are
+// fetched as written but still deduped as one page, and with linkextractor.DistinctTrailingSlash
+// both links are crawled as distinct pages.
+//
+// Parameters:
+//   - policy: The linkextractor.TrailingSlashPolicy used to normalize the links.
+//
+// Returns:
+//   - An Option function that sets the provided trailing slash policy to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithTrailingSlashPolicy(linkextractor.DistinctTrailingSlash))
+func WithTrailingSlashPolicy(policy linkextractor.TrailingSlashPolicy) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.extractOptions = append(crawler.extractOptions, linkextractor.WithTrailingSlashPolicy(policy))
+	}
+}
+
+// WithCaseInsensitivePaths is an option to treat the paths of the links case-insensitively
+// when deduping them, so /About and /about don't double the crawl of sites hosted on IIS or
+// other Windows servers. Links are fetched as written the first time they're found, and the
+// returned links have lower-cased paths. Paths are case-sensitive by default.
+//
+// Returns:
+//   - An Option function that makes the BreadthFirstCrawler dedup the links case-insensitively.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithCaseInsensitivePaths())
+func WithCaseInsensitivePaths() Option {
+	return func(crawler *crawlerConfig) {
+		crawler.extractOptions = append(crawler.extractOptions, linkextractor.WithCaseInsensitivePaths())
+	}
+}
+
+// NofollowPolicy decides what the crawler does with the links that ask crawlers not to follow
+// them, with a rel="nofollow", rel="ugc" or rel="sponsored" attribute.
+type NofollowPolicy int
+
+const (
+	// FollowNofollow crawls the nofollow links like any other link. It's the default.
+	FollowNofollow NofollowPolicy = iota
+	// SkipNofollow neither reports nor crawls the nofollow links.
+	SkipNofollow
+	// RecordNofollow reports the nofollow links as found, but doesn't crawl them unless they're
+	// found in a followed link too.
+	RecordNofollow
+)
+
+// WithNofollowPolicy is an option to set what the crawler does with the links whose anchors carry
+// a rel="nofollow", rel="ugc" or rel="sponsored" attribute. A link is only considered nofollow if
+// every anchor to it in the page is nofollow.
+//
+// Parameters:
+//   - policy: The NofollowPolicy applied to the nofollow links.
+//
+// Returns:
+//   - An Option function that sets the provided NofollowPolicy to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithNofollowPolicy(RecordNofollow))
+func WithNofollowPolicy(policy NofollowPolicy) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.nofollowPolicy = policy
+	}
+}
+
+// DirectoryListingPolicy decides what the crawler does with the links of the directory indexes
+// generated by the web server, like the "Index of /" pages of Apache and Nginx.
+type DirectoryListingPolicy int
+
+const (
+	// FollowDirectoryListings crawls the links of the directory listings like the ones of any other
+	// page, including the links that only sort the listing. It's the default.
+	FollowDirectoryListings DirectoryListingPolicy = iota
+	// FollowDirectoryEntries only crawls the entries of the directory listings, their files and
+	// subdirectories, for a complete crawl of the exposed directories without the sort links.
+	FollowDirectoryEntries
+	// SkipDirectoryListings doesn't crawl the links of the directory listings.
+	SkipDirectoryListings
+)
+
+// WithDirectoryListingPolicy is an option to set what the crawler does with the links of the directory
+// listings it finds. See linkextractor.DirectoryEntries.
+//
+// Parameters:
+//   - policy: The DirectoryListingPolicy applied to the directory listings.
+//
+// Returns:
+//   - An Option function that sets the provided DirectoryListingPolicy to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithDirectoryListingPolicy(FollowDirectoryEntries))
+func WithDirectoryListingPolicy(policy DirectoryListingPolicy) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.directoryListingPolicy = policy
+	}
+}
+
+// WithMetaRobots is an option to respect the directives of the <meta name="robots"> tag of every
+// crawled page, for SEO-accurate crawls: the links of nofollow pages are not followed, and noindex
+// pages are left out of the returned links. The pages are still reported as found when discovered.
+//
+// Returns:
+//   - An Option function that makes the BreadthFirstCrawler respect the meta robots directives.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithMetaRobots())
+func WithMetaRobots() Option {
+	return func(crawler *crawlerConfig) {
+		crawler.metaRobots = true
+	}
+}
+
+// WithDefaultDocuments is an option to fold the links to default documents, like /docs/index.html,
+// into their directory URL, /docs, so the same page is not crawled and counted twice.
+//
+// Parameters:
+//   - fileNames: The file names of the default documents, matched case-insensitively. linkextractor.DefaultDocuments has the usual ones.
+//
+// Returns:
+//   - An Option function that makes the BreadthFirstCrawler fold the provided default documents.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithDefaultDocuments("index.html", "default.aspx"))
+func WithDefaultDocuments(fileNames ...string) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.extractOptions = append(crawler.extractOptions, linkextractor.WithDefaultDocuments(fileNames...))
+	}
+}
+
+// WithStrippedQueryParameters is an option to remove the marketing and analytics query parameters
+// of the links, like ?utm_source=newsletter, so the same page linked with different parameters is
+// not crawled and counted many times. The parameters are removed from the query strings kept with
+// WithQueryStrings or WithLinkSampler, this option doesn't keep them by itself.
+//
+// Parameters:
+//   - names: The names of the parameters, matched case-insensitively. A name ending with "*" matches
+//     every parameter with that prefix. linkextractor.TrackingParameters has the usual ones.
+//
+// Returns:
+//   - An Option function that makes the BreadthFirstCrawler strip the provided query parameters.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithStrippedQueryParameters(linkextractor.TrackingParameters...))
+func WithStrippedQueryParameters(names ...string) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.extractOptions = append(crawler.extractOptions, linkextractor.WithStrippedQueryParameters(names...))
+	}
+}
+
+// WithCanonicalURLs is an option to treat the canonical URL declared by a page with a
+// <link rel="canonical"> tag as the identity of the page. The canonical URL is reported as
+// found, the crawled URLs that declared another canonical URL are left out of the returned
+// links, and pages whose canonical URL was already crawled are considered duplicates and
+// their links are not followed. It prevents URL permutations, like
+// the ones with tracking parameters, from bloating the results. Canonical URLs out of the scope
+// of the crawl, by default the ones of other hosts, are ignored.
+//
+// Returns:
+//   - An Option function that makes the BreadthFirstCrawler dedup the pages by their canonical URLs.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithCanonicalURLs())
+//	links, err := crawler.Crawl(context.Background(), *urlToCrawl, 3, 10) // no ?utm_source= permutations
+func WithCanonicalURLs() Option {
+	return func(crawler *crawlerConfig) {
+		crawler.canonicalURLs = true
+	}
+}
+
+// WithSchemeUpgrade is an option to upgrade the http:// links found in the pages to https before
+// fetching them, when their host is known to be served over https, so a site that serves HSTS or
+// redirects every http:// URL to https:// is not crawled twice. fetcher.SchemeUpgrader learns the
+// secure hosts from the responses, and records every upgraded link so they can still be reported as
+// insecure internal links.
+//
+// Parameters:
+//   - upgrader: The scheme upgrader that decides whether a link is upgraded, e.g. a fetcher.SchemeUpgrader.
+//
+// Returns:
+//   - An Option function that makes the BreadthFirstCrawler upgrade the links with the provided upgrader.
+//
+// Example usage:
+//
+//	upgrader := fetcher.NewSchemeUpgrader(http.DefaultTransport)
+//	httpFetcher := fetcher.NewHTTPFetcher(&http.Client{Transport: upgrader})
+//	crawler := NewBreadthFirstCrawler(httpFetcher, WithSchemeUpgrade(upgrader))
+func WithSchemeUpgrade(upgrader schemeUpgrader) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.schemeUpgrader = upgrader
+	}
+}
+
+// WithSeeds is an option to start the crawl from more URLs besides the one given to Crawl, every one
+// with its own metadata, like the team owning a section of the site. The metadata of every crawled
+// page is the one of the seed it belongs to, as told by SeedMetadata, and it's carried through to
+// CrawlResult.Metadata, so the results of a multi-section crawl can be attributed to the right team.
+// Seeds out of the scope of the crawl, by default the ones of other hosts, are ignored.
+//
+// Parameters:
+//   - seeds: The seeds to start the crawl from, along with the crawled URL, at depth 0.
+//
+// Returns:
+//   - An Option function that adds the provided seeds to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	docsURL, _ := url.Parse("https://example.com/docs")
+//	crawler := NewBreadthFirstCrawler(fetcher, WithSeeds(Seed{URL: *docsURL, Metadata: map[string]string{"owner": "docs-team"}}))
+func WithSeeds(seeds ...Seed) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.extraSeeds = append(crawler.extraSeeds, seeds...)
+	}
+}
+
+// WithScope is an option to set which links found in the pages are crawled. By default, only the
+// links of the same host as the page are, so blog.example.com is left out when crawling example.com.
+// linkextractor.SameRegistrableDomain includes the subdomains of the same registrable domain, and any
+// function with the signature of linkextractor.Scope can be used as a custom scope. The seeds, like
+// the ones of the sitemap, and the canonical URLs must be in the scope too.
+//
+// Parameters:
+//   - scope: The scope of the crawl, e.g. linkextractor.SameRegistrableDomain.
+//
+// Returns:
+//   - An Option function that sets the provided scope to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithScope(linkextractor.SameRegistrableDomain))
+func WithScope(scope linkextractor.Scope) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.extractOptions = append(crawler.extractOptions, linkextractor.WithScope(scope))
+	}
+}
+
+// WithURLFilters is an option to restrict the crawl to some sections of a site, or to skip some of
+// them. The patterns are regular expressions matched against the path and query of the links, like
+// ^/docs/ or ^/search\?, or globs matching the whole path and query when prefixed with "glob:", where
+// "*" matches any sequence of characters, like glob:/blog/*/comments. A link is crawled if it matches
+// any of the include patterns, or there are none, and none of the exclude patterns. The URL the crawl
+// starts from is always crawled, while the other seeds, like the ones of the sitemap, must pass the
+// filters too.
+//
+// Parameters:
+//   - include: The patterns of the links to crawl. All the links are crawled if empty.
+//   - exclude: The patterns of the links to skip.
+//
+// Returns:
+//   - An Option function that sets the provided URL filters to the BreadthFirstCrawler. The crawl
+//     fails with an InvalidURLFilter error if a pattern is not a valid regular expression.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithURLFilters([]string{"^/docs/"}, []string{"glob:/docs/*/print"}))
+//	links, err := crawler.Crawl(context.Background(), *docsURL, 3, 10)
+func WithURLFilters(include, exclude []string) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.urlFilters = newURLFilters(include, exclude)
+	}
+}
+
+// WithCrawlDelay is an option to set how long every worker waits between
+// consecutive fetches, for polite crawling of small sites. The delay is
+// interrupted if the context of the crawl is canceled.
+//
+// Parameters:
+//   - delay: The time to wait between fetches. Zero or negative values disable the delay.
+//
+// Returns:
+//   - An Option function that sets the provided delay to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithCrawlDelay(2*time.Second))
+func WithCrawlDelay(delay time.Duration) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.crawlDelay = delay
+	}
+}
+
+// WithMaxPages is an option to stop the crawl once the given number of pages has been crawled,
+// whatever the depth it reached. Depth alone is a poor budget for sites with a wide fan-out, where a
+// single level can hold thousands of pages. The links found in the crawled pages are still returned,
+// and with a persistent frontier store the pages left are kept pending, so a resumed crawl gets a new
+// budget and continues from them. Zero or a negative number doesn't limit the crawl, the default.
+//
+// Parameters:
+//   - maxPages: The maximum number of pages to crawl.
+//
+// Returns:
+//   - An Option function that sets the provided maximum number of pages to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithMaxPages(1000))
+//	links, err := crawler.Crawl(context.Background(), *urlToCrawl, 10, 20)
+func WithMaxPages(maxPages int) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.maxPages = maxPages
+	}
+}
+
+// WithMaxDuration is an option to set the time budget of the crawl, for scheduled jobs with hard time
+// slots. Once it's exhausted, the crawl ends cleanly: the pages being crawled are finished, no new batch
+// is started, and the links found so far are returned without an error, like when the context of the
+// crawl is canceled. The budget is separate from the context, which can still cancel the crawl earlier.
+// With a persistent frontier store, the pending links are kept, so the crawl can be resumed in the next
+// slot with a new budget. Zero or a negative duration doesn't limit the crawl, the default.
+//
+// Parameters:
+//   - maxDuration: The maximum duration of the crawl.
+//
+// Returns:
+//   - An Option function that sets the provided time budget to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithMaxDuration(30*time.Minute), WithFrontierStore(store))
+//	links, err := crawler.Crawl(context.Background(), *urlToCrawl, 10, 20)
+func WithMaxDuration(maxDuration time.Duration) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.maxDuration = maxDuration
+	}
+}
+
+// WithSchedule is an option to confine the crawl to some time-of-day windows, like the low-traffic
+// hours of a production site. The crawler fetches at full rate while the schedule is open, and pauses
+// before starting a new batch while it's closed. The pause is interrupted if the context of the crawl
+// is canceled, so with a persistent frontier store the crawl can also be stopped and resumed later.
+//
+// Parameters:
+//   - schedule: The schedule of the crawl, e.g. a schedule.Schedule.
+//
+// Returns:
+//   - An Option function that sets the provided schedule to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	nightly, _ := schedule.Parse("01:00-05:00")
+//	crawler := NewBreadthFirstCrawler(fetcher, WithSchedule(nightly), WithFrontierStore(store))
+func WithSchedule(schedule crawlSchedule) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.schedule = schedule
+	}
+}
+
+// WithFrontierStore is an option to set the store that holds the state of the
+// crawl: the links found, the visited ones, and the pending links per depth
+// level. Using a persistent store allows resuming an interrupted crawl with
+// BreadthFirstCrawler.Resume. By default, the state is kept in memory and
+// discarded once the crawl ends. For crawls of millions of URLs, a
+// frontier.BloomStore bounds the memory used to remember the found links, in
+// which case Crawl returns no links and they should be collected with
+// WithLinkFoundCallback. The PriorityCrawler keeps its frontier in memory and
+// ignores this option.
+//
+// Parameters:
+//   - store: The frontier.Store used to keep the state of the crawl.
+//
+// Returns:
+//   - An Option function that sets the provided frontier.Store to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	store, _ := frontier.NewBoltStore("crawl.state")
+//	defer store.Close()
+//	crawler := NewBreadthFirstCrawler(fetcher, WithFrontierStore(store))
+//	links, err := crawler.Resume(context.Background())
+func WithFrontierStore(store frontier.Store) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.frontierStore = store
+	}
+}
+
+// WithProgressWriter is an option to write a snapshot of the progress of every crawl to the given
+// writer periodically, as a single line of JSON, like
+// {"elapsed_seconds":10.5,"pages_crawled":120,"links_found":480,"errors":2,"pages_per_second":11.4,"done":false},
+// so the services embedding the crawler can pipe it to their logs or UIs. A last snapshot with done
+// set to true is written when the crawl ends. The errors writing the snapshots are ignored.
+//
+// Parameters:
+//   - writer: The io.Writer the snapshots are written to, like os.Stderr.
+//   - interval: The time between snapshots. The progress isn't written if it's not greater than 0.
+//
+// Returns:
+//   - An Option function that makes the BreadthFirstCrawler write the progress of its crawls.
+//
+// Example usage:
+//
+//	crawler := NewBreadthFirstCrawler(fetcher, WithProgressWriter(os.Stderr, 5*time.Second))
+func WithProgressWriter(writer io.Writer, interval time.Duration) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.progressWriter = writer
+		crawler.progressInterval = interval
+	}
+}
+
+// WithStopWhen is an option to end the crawl as soon as a crawled page meets the given condition, like
+// linking to a given URL, containing a text, or being the tenth broken link, to answer questions like
+// "does the site still link to X?" without crawling all of it. The pages of the batch being crawled are
+// finished, no new batch is started, and the links found so far are returned without an error, like when
+// the time budget of WithMaxDuration is exhausted. The condition is called with the result of every
+// crawled page, one at a time, so it can keep a count without synchronization.
+//
+// Parameters:
+//   - stopWhen: The condition that ends the crawl when it returns true.
+//
+// Returns:
+//   - An Option function that sets the provided stop condition to the BreadthFirstCrawler.
+//
+// Example usage:
+//
+//	target, _ := url.Parse("https://old-partner.com")
+//	crawler := NewBreadthFirstCrawler(fetcher, WithStopWhen(func(result CrawlResult) bool {
+//	    return slices.Contains(result.Links, *target)
+//	}))
+//	links, err := crawler.Crawl(context.Background(), *urlToCrawl, 10, 20)
+func WithStopWhen(stopWhen func(result CrawlResult) bool) Option {
+	return func(crawler *crawlerConfig) {
+		crawler.stopWhen = stopWhen
+	}
+}
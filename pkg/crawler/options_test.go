@@ -1,9 +1,12 @@
 package crawler
 
 import (
+	"context"
 	"io"
 	"net/url"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type MockFetcher struct{}
@@ -32,3 +35,44 @@ func TestNewBreadthFirstCrawler(t *testing.T) {
 		t.Errorf("Expected onError callback to be set")
 	}
 }
+
+type mockHostPrefetcher struct {
+	prefetchedHosts []string
+}
+
+func (m *mockHostPrefetcher) Prefetch(hosts ...string) {
+	m.prefetchedHosts = append(m.prefetchedHosts, hosts...)
+}
+
+func TestWithHostPrefetcher(t *testing.T) {
+	prefetcher := &mockHostPrefetcher{}
+	crawler := NewBreadthFirstCrawler(&MockFetcher{}, WithHostPrefetcher(prefetcher))
+
+	if crawler.hostPrefetcher != prefetcher {
+		t.Errorf("Expected hostPrefetcher to be set to prefetcher")
+	}
+
+	testUrl, _ := url.Parse("https://test.com/contact")
+	prefetchHosts(crawler.hostPrefetcher, []url.URL{*testUrl, *testUrl})
+	if len(prefetcher.prefetchedHosts) != 1 || prefetcher.prefetchedHosts[0] != "test.com" {
+		t.Errorf("Expected hosts to be prefetched once, got %v", prefetcher.prefetchedHosts)
+	}
+}
+
+func TestWithWaitForCallbacks(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	var linksFound atomic.Int32
+	crawler := NewBreadthFirstCrawler(newMockFetcher(nil), WithWaitForCallbacks(), WithLinkFoundCallback(func(link url.URL) {
+		time.Sleep(10 * time.Millisecond)
+		linksFound.Add(1)
+	}))
+
+	links, err := crawler.Crawl(context.Background(), *testUrl, 100, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	// every link but the start URL is reported through the callback
+	if got := int(linksFound.Load()); got != len(links)-1 {
+		t.Errorf("Crawl() returned with %d callbacks done, want %d", got, len(links)-1)
+	}
+}
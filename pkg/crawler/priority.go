@@ -0,0 +1,231 @@
+package crawler
+
+import (
+	"container/heap"
+	"context"
+	"net/url"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+type scoreFunc func(link url.URL, depth int) float64
+
+type PriorityCrawler struct {
+	crawlerConfig
+	score scoreFunc
+}
+
+// NewPriorityCrawler creates a new best-first crawler with the given fetcher, scoring function and options.
+//
+// Parameters:
+//   - fetcher: The fetcher implementation used to retrieve webpage content.
+//   - score: The function used to score every link found. Links with higher scores are crawled first.
+//   - opts: Optional variadic list of functional options to configure the crawler.
+//
+// Returns:
+//   - A new instance of PriorityCrawler initialized with the provided fetcher, scoring function and options.
+//
+// Example:
+//
+//	productsFirst := func(link url.URL, depth int) float64 {
+//	    if strings.HasPrefix(link.Path, "/product/") {
+//	        return 1
+//	    }
+//	    return 0
+//	}
+//	crawler := NewPriorityCrawler(fetcher, productsFirst, WithLinkFoundCallback(myLinkFoundCallback))
+func NewPriorityCrawler(fetcher fetcher.Fetcher, score scoreFunc, opts ...Option) *PriorityCrawler {
+	pc := &PriorityCrawler{crawlerConfig: crawlerConfig{fetcher: fetcher}, score: score}
+
+	for _, opt := range opts {
+		opt(&pc.crawlerConfig)
+	}
+
+	return pc
+}
+
+// Crawl performs a best-first web crawling starting from the specified URL.
+// Every link found is scored with the scoring function of the crawler, and the
+// pending links with the highest scores are always crawled first, up to
+// maxConcurrency pages at a time. Links with the same score are crawled in the
+// order they were found.
+//
+// The depth limit, the returned links, the errors and the callbacks behave
+// exactly like in BreadthFirstCrawler.Crawl, so both crawlers can be swapped
+// through the Crawler interface.
+func (pc *PriorityCrawler) Crawl(ctx context.Context, urlToCrawl url.URL, depth, maxConcurrency int) ([]string, error) {
+	defer pc.waitPendingCallbacks()
+
+	if depth <= 0 {
+		return nil, InvalidDepth
+	}
+	if maxConcurrency <= 0 {
+		return nil, InvalidMaxConcurrency
+	}
+	if pc.urlFilters.err != nil {
+		return nil, pc.urlFilters.err
+	}
+	var redirectedSeed *url.URL
+	if pc.seedResolver != nil {
+		resolvedURL, err := pc.checkSeed(urlToCrawl)
+		if err != nil {
+			return nil, err
+		}
+		if pc.seedKey(resolvedURL) != pc.seedKey(urlToCrawl) {
+			givenSeed := urlToCrawl
+			redirectedSeed = &givenSeed
+		}
+		urlToCrawl = resolvedURL
+	}
+	ctx, cancel := pc.withMaxDuration(ctx)
+	defer cancel()
+	stopProgress := pc.startProgress()
+	defer stopProgress()
+
+	visitedLinks := make(map[string]bool) // map of links found while crawling + whether is visited or not
+	linkDepths := make(map[string]int)
+	frontier := &priorityFrontier{}
+	excluded := make(map[string]bool)
+	notFollowed := make(map[string]bool)
+	// the links to the seed as given lead to the page it redirected to, so they aren't crawled
+	if redirectedSeed != nil {
+		visitedLinks[pc.seedKey(*redirectedSeed)] = true
+		excluded[pc.seedKey(*redirectedSeed)] = true
+	}
+	for _, seed := range filterDisallowedLinks(pc.robotsPolicy, pc.seeds(urlToCrawl)) {
+		pc.push(frontier, seed, 0)
+		linkDepths[pc.linkKey(seed)] = 0
+	}
+
+	crawledPages := 0
+	conditionMet := false
+	for startedBatches := 0; frontier.Len() > 0 && !conditionMet; startedBatches++ {
+		if startedBatches > 0 {
+			pc.waitCrawlDelay(ctx)
+		}
+		pc.waitSchedule(ctx)
+
+		// graceful cancel before starting a new batch
+		if interrupted(ctx) {
+			break
+		}
+		batchSize := pc.batchSize(maxConcurrency, crawledPages)
+		if batchSize == 0 {
+			break
+		}
+
+		var batch []url.URL
+		for frontier.Len() > 0 && len(batch) < batchSize {
+			item := heap.Pop(frontier).(frontierItem)
+			if !visitedLinks[pc.linkKey(item.link)] {
+				visitedLinks[pc.linkKey(item.link)] = true
+				batch = append(batch, item.link)
+			}
+		}
+		prefetchHosts(pc.hostPrefetcher, batch)
+		crawledPages += len(batch)
+
+		for _, page := range pc.crawlBatchConcurrently(ctx, batch) {
+			linkDepth := linkDepths[pc.linkKey(page.link)] + 1
+			if !conditionMet {
+				conditionMet = pc.conditionMet(page.result(linkDepth-1, pc.extraSeeds))
+			}
+			if page.noindex {
+				excluded[pc.linkKey(page.link)] = true
+			}
+			pageLinks := page.links
+			if page.canonical != nil {
+				excluded[pc.linkKey(page.link)] = true
+				pageLinks = pc.followCanonical(visitedLinks, page)
+			}
+			nofollow := page.nofollowKeys(pc.linkKey)
+			for _, link := range pc.crawlableLinks(pageLinks) {
+				key := pc.linkKey(link)
+				if _, ok := visitedLinks[key]; ok {
+					if !notFollowed[key] || nofollow[key] {
+						continue
+					}
+					delete(notFollowed, key)
+				} else {
+					visitedLinks[key] = false
+					safeLinkFoundCallback(&pc.pendingCallbacks, pc.linkFound, link)
+					pc.progress.linkFound()
+				}
+				if nofollow[key] {
+					notFollowed[key] = true
+					continue
+				}
+				if linkDepth < depth {
+					linkDepths[key] = linkDepth
+					pc.push(frontier, link, linkDepth)
+				}
+			}
+		}
+	}
+
+	crawledLinks := make([]string, 0, len(visitedLinks))
+	for link := range visitedLinks {
+		if !excluded[link] {
+			crawledLinks = append(crawledLinks, link)
+		}
+	}
+
+	return crawledLinks, nil
+}
+
+// followCanonical makes the canonical URL of a crawled page the identity of the page, like
+// BreadthFirstCrawler does, and returns the links of the page to follow.
+func (pc *PriorityCrawler) followCanonical(visitedLinks map[string]bool, page crawledPage) []url.URL {
+	visited, found := visitedLinks[pc.linkKey(*page.canonical)]
+	if visited {
+		return nil
+	}
+	if !found {
+		safeLinkFoundCallback(&pc.pendingCallbacks, pc.linkFound, *page.canonical)
+		pc.progress.linkFound()
+	}
+	visitedLinks[pc.linkKey(*page.canonical)] = true
+	return page.links
+}
+
+func (pc *PriorityCrawler) push(frontier *priorityFrontier, link url.URL, depth int) {
+	heap.Push(frontier, frontierItem{link: link, score: pc.score(link, depth), order: frontier.pushed})
+}
+
+type frontierItem struct {
+	link  url.URL
+	score float64
+	order int
+}
+
+// priorityFrontier is a max-heap of links by score, implementing heap.Interface.
+type priorityFrontier struct {
+	items  []frontierItem
+	pushed int
+}
+
+func (f *priorityFrontier) Len() int {
+	return len(f.items)
+}
+
+func (f *priorityFrontier) Less(i, j int) bool {
+	if f.items[i].score == f.items[j].score {
+		return f.items[i].order < f.items[j].order
+	}
+	return f.items[i].score > f.items[j].score
+}
+
+func (f *priorityFrontier) Swap(i, j int) {
+	f.items[i], f.items[j] = f.items[j], f.items[i]
+}
+
+func (f *priorityFrontier) Push(x any) {
+	f.items = append(f.items, x.(frontierItem))
+	f.pushed++
+}
+
+func (f *priorityFrontier) Pop() any {
+	last := f.items[len(f.items)-1]
+	f.items = f.items[:len(f.items)-1]
+	return last
+}
@@ -0,0 +1,135 @@
+package crawler
+
+import (
+	"io"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+type recordingFetcher struct {
+	*mockFetcher
+	mu           sync.Mutex
+	fetchedLinks []string
+}
+
+func (r *recordingFetcher) FetchWebpageContent(urlToCrawl url.URL) (io.ReadCloser, error) {
+	r.mu.Lock()
+	r.fetchedLinks = append(r.fetchedLinks, urlToCrawl.String())
+	r.mu.Unlock()
+	return r.mockFetcher.FetchWebpageContent(urlToCrawl)
+}
+
+func TestPriorityCrawler_Crawl(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+
+	t.Run("crawls links with higher scores first", func(t *testing.T) {
+		pageFetcher := &recordingFetcher{mockFetcher: newMockFetcher(nil)}
+		aboutUsFirst := func(link url.URL, depth int) float64 {
+			if strings.HasPrefix(link.Path, "/about-us") {
+				return 1
+			}
+			return 0
+		}
+		pc := NewPriorityCrawler(pageFetcher, aboutUsFirst)
+
+		got, err := pc.Crawl(context.Background(), *testUrl, 100, 1)
+		if err != nil {
+			t.Fatalf("Crawl() error = %v", err)
+		}
+		if len(got) != 5 {
+			t.Errorf("Crawl() links len got %v want len 5\ngot\t\t%v", len(got), got)
+		}
+		want := []string{"https://test.com", "https://test.com/about-us", "https://test.com/contact", "https://test.com/depth3", "https://test.com/depth4"}
+		if strings.Join(pageFetcher.fetchedLinks, " ") != strings.Join(want, " ") {
+			t.Errorf("Crawl() fetch order got %v, want %v", pageFetcher.fetchedLinks, want)
+		}
+	})
+
+	t.Run("stops after the maximum number of pages", func(t *testing.T) {
+		pageFetcher := &recordingFetcher{mockFetcher: newMockFetcher(nil)}
+		pc := NewPriorityCrawler(pageFetcher, func(link url.URL, depth int) float64 { return 0 }, WithMaxPages(3))
+
+		if _, err := pc.Crawl(context.Background(), *testUrl, 100, 2); err != nil {
+			t.Fatalf("Crawl() error = %v", err)
+		}
+		if len(pageFetcher.fetchedLinks) != 3 {
+			t.Errorf("Crawl() fetched %v pages, want 3: %v", len(pageFetcher.fetchedLinks), pageFetcher.fetchedLinks)
+		}
+	})
+
+	t.Run("stops once the time budget is exhausted", func(t *testing.T) {
+		pageFetcher := &recordingFetcher{mockFetcher: newMockFetcher(nil)}
+		pc := NewPriorityCrawler(pageFetcher, func(link url.URL, depth int) float64 { return 0 }, WithCrawlDelay(time.Hour), WithMaxDuration(50*time.Millisecond))
+
+		got, err := pc.Crawl(context.Background(), *testUrl, 100, 100)
+		if err != nil {
+			t.Fatalf("Crawl() error = %v", err)
+		}
+		if len(pageFetcher.fetchedLinks) != 1 || len(got) != 3 {
+			t.Errorf("Crawl() fetched %v and returned %v, want only the start page and its links", pageFetcher.fetchedLinks, got)
+		}
+	})
+
+	t.Run("respects the depth limit", func(t *testing.T) {
+		pc := NewPriorityCrawler(newMockFetcher(nil), func(link url.URL, depth int) float64 { return 0 })
+
+		got, err := pc.Crawl(context.Background(), *testUrl, 2, 2)
+		if err != nil {
+			t.Fatalf("Crawl() error = %v", err)
+		}
+		want := map[string]bool{
+			"https://test.com":          true,
+			"https://test.com/contact":  true,
+			"https://test.com/about-us": true,
+			"https://test.com/depth3":   true,
+		}
+		if len(got) != len(want) {
+			t.Errorf("Crawl() links len got %v want len %v\ngot\t\t%v\nwant\t%v", len(got), len(want), got, want)
+		}
+		for _, link := range got {
+			if _, ok := want[link]; !ok {
+				t.Errorf("Crawl() link %v not found in %v", link, want)
+			}
+		}
+	})
+
+	t.Run("invalid depth", func(t *testing.T) {
+		pc := NewPriorityCrawler(newMockFetcher(nil), func(link url.URL, depth int) float64 { return 0 })
+		if _, err := pc.Crawl(context.Background(), *testUrl, 0, 1); err != InvalidDepth {
+			t.Errorf("Crawl() error = %v, want %v", err, InvalidDepth)
+		}
+	})
+	t.Run("dedups pages by their canonical URLs", func(t *testing.T) {
+		pc := NewPriorityCrawler(newCanonicalMockFetcher(), func(link url.URL, depth int) float64 { return 0 }, WithCanonicalURLs())
+
+		got, err := pc.Crawl(context.Background(), *testUrl, 100, 1)
+		if err != nil {
+			t.Fatalf("Crawl() error = %v", err)
+		}
+		sort.Strings(got)
+		want := []string{"https://test.com", "https://test.com/products", "https://test.com/products/1"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Crawl() links got = %v, want %v", got, want)
+		}
+	})
+	t.Run("records nofollow links without crawling them", func(t *testing.T) {
+		pc := NewPriorityCrawler(newNofollowMockFetcher(), func(link url.URL, depth int) float64 { return 0 }, WithNofollowPolicy(RecordNofollow))
+
+		got, err := pc.Crawl(context.Background(), *testUrl, 100, 1)
+		if err != nil {
+			t.Fatalf("Crawl() error = %v", err)
+		}
+		sort.Strings(got)
+		want := []string{"https://test.com", "https://test.com/about", "https://test.com/account", "https://test.com/login", "https://test.com/partner"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Crawl() links got = %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package crawler
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Progress is a snapshot of how far a crawl got, written as a single line of JSON by the crawlers
+// configured with WithProgressWriter.
+type Progress struct {
+	// ElapsedSeconds is the time since the crawl started.
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	// PagesCrawled is the number of pages fetched so far, including the ones that failed.
+	PagesCrawled int64 `json:"pages_crawled"`
+	// LinksFound is the number of new links found so far in the crawled pages, the ones reported to
+	// the callback of WithLinkFoundCallback.
+	LinksFound int64 `json:"links_found"`
+	// Errors is the number of pages that failed.
+	Errors int64 `json:"errors"`
+	// PagesPerSecond is the average crawl rate since the crawl started.
+	PagesPerSecond float64 `json:"pages_per_second"`
+	// Done tells whether the crawl ended. It's only true in the last snapshot.
+	Done bool `json:"done"`
+}
+
+// crawlProgress counts the pages and the links of a crawl, and writes its snapshots periodically. Its
+// methods are no-ops on a nil crawlProgress, so the crawlers count unconditionally.
+type crawlProgress struct {
+	writer       io.Writer
+	started      time.Time
+	pagesCrawled atomic.Int64
+	linksFound   atomic.Int64
+	errors       atomic.Int64
+	stop         chan struct{}
+	stopped      sync.WaitGroup
+}
+
+// startProgress starts writing the progress snapshots of a crawl if the crawler is configured with
+// WithProgressWriter, and returns the function that ends it, writing the last snapshot.
+func (c *crawlerConfig) startProgress() func() {
+	if c.progressWriter == nil || c.progressInterval <= 0 {
+		c.progress = nil
+		return func() {}
+	}
+	progress := &crawlProgress{writer: c.progressWriter, started: time.Now(), stop: make(chan struct{})}
+	c.progress = progress
+	progress.stopped.Add(1)
+	go func() {
+		defer progress.stopped.Done()
+		ticker := time.NewTicker(c.progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				progress.write(false)
+			case <-progress.stop:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(progress.stop)
+		progress.stopped.Wait()
+		progress.write(true)
+	}
+}
+
+func (p *crawlProgress) pageCrawled(err error) {
+	if p == nil {
+		return
+	}
+	p.pagesCrawled.Add(1)
+	if err != nil {
+		p.errors.Add(1)
+	}
+}
+
+func (p *crawlProgress) linkFound() {
+	if p == nil {
+		return
+	}
+	p.linksFound.Add(1)
+}
+
+func (p *crawlProgress) snapshot(done bool) Progress {
+	elapsed := time.Since(p.started).Seconds()
+	progress := Progress{
+		ElapsedSeconds: elapsed,
+		PagesCrawled:   p.pagesCrawled.Load(),
+		LinksFound:     p.linksFound.Load(),
+		Errors:         p.errors.Load(),
+		Done:           done,
+	}
+	if elapsed > 0 {
+		progress.PagesPerSecond = float64(progress.PagesCrawled) / elapsed
+	}
+	return progress
+}
+
+// write writes a snapshot of the progress as a line of JSON. Write errors are ignored, so a broken
+// progress writer doesn't stop the crawl.
+func (p *crawlProgress) write(done bool) {
+	_ = json.NewEncoder(p.writer).Encode(p.snapshot(done))
+}
@@ -0,0 +1,52 @@
+package crawler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestBreadthFirstCrawler_CrawlWithProgressWriter(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	var output bytes.Buffer
+	bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil), WithProgressWriter(&output, time.Hour))
+
+	if _, err := bfCrawler.Crawl(context.Background(), *testUrl, 100, 2); err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+
+	var snapshots []Progress
+	scanner := bufio.NewScanner(&output)
+	for scanner.Scan() {
+		var snapshot Progress
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			t.Fatalf("progress line %q is not JSON. err: %v", scanner.Text(), err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	// the interval is longer than the crawl, so only the last snapshot is written
+	if len(snapshots) != 1 {
+		t.Fatalf("got %d progress snapshots, want 1", len(snapshots))
+	}
+	got := snapshots[0]
+	if !got.Done || got.PagesCrawled != 5 || got.LinksFound != 4 || got.Errors != 0 {
+		t.Errorf("last progress snapshot got = %+v, want done with 5 pages crawled and 4 links found", got)
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithProgressWriterInterval(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	var output bytes.Buffer
+	bfCrawler := NewBreadthFirstCrawler(newMockFetcher(nil), WithProgressWriter(&output, 10*time.Millisecond), WithCrawlDelay(30*time.Millisecond))
+
+	if _, err := bfCrawler.Crawl(context.Background(), *testUrl, 3, 1); err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	if lines := bytes.Count(output.Bytes(), []byte("\n")); lines < 2 {
+		t.Errorf("got %d progress snapshots, want periodic snapshots before the last one", lines)
+	}
+}
@@ -0,0 +1,77 @@
+package crawler
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+// SeedError is returned when the seed of a crawl checked with the WithSeedCheck option doesn't respond
+// with a page, like a typo'd domain or a URL that is not found, instead of an empty crawl.
+type SeedError struct {
+	URL url.URL
+	Err error
+}
+
+func (e *SeedError) Error() string {
+	return fmt.Sprintf("seed %s is not crawlable: %v", e.URL.String(), e.Err)
+}
+
+func (e *SeedError) Unwrap() error {
+	return e.Err
+}
+
+// checkSeed verifies the seed responds with a page before the crawl expands it, and returns the URL its
+// redirects end at, so the crawl starts from the canonical seed. If the crawler probes the schemes of
+// the seed, its https variant is tried before its http one, and the first that responds is used.
+func (c *crawlerConfig) checkSeed(seed url.URL) (url.URL, error) {
+	candidates := []url.URL{seed}
+	if c.probeSeedSchemes && (seed.Scheme == "http" || seed.Scheme == "https") {
+		httpsSeed, httpSeed := seed, seed
+		httpsSeed.Scheme, httpSeed.Scheme = "https", "http"
+		candidates = []url.URL{httpsSeed, httpSeed}
+	}
+
+	var seedErr error
+	for _, candidate := range candidates {
+		resolvedSeed, err := c.seedResolver.Resolve(candidate)
+		if err == nil {
+			return resolvedSeed, nil
+		}
+		// the error of the seed as given is the one worth reporting
+		if seedErr == nil || candidate.Scheme == seed.Scheme {
+			seedErr = err
+		}
+	}
+	return seed, &SeedError{URL: seed, Err: seedErr}
+}
+
+// hreflangRoots returns the localized versions of the seed declared by its hreflang alternates, like the
+// x-default and the other locale roots of a seed that redirects into one of them, that are in the scope
+// of the crawl. The errors fetching the seed are reported to the error callback.
+func (c *crawlerConfig) hreflangRoots(seed url.URL) []url.URL {
+	webpageReader, err := c.fetcher.FetchWebpageContent(seed)
+	if err != nil {
+		safeCrawlingErrorCallback(&c.pendingCallbacks, c.onError, seed, err)
+		return nil
+	}
+	defer func(webpageReader io.ReadCloser) {
+		_ = webpageReader.Close()
+	}(webpageReader)
+	meta, err := linkextractor.ExtractMeta(seed, webpageReader)
+	if err != nil {
+		safeCrawlingErrorCallback(&c.pendingCallbacks, c.onError, seed, err)
+		return nil
+	}
+
+	var roots []url.URL
+	for _, alternate := range meta.Alternates {
+		root := linkextractor.Normalize(alternate.URL, c.extractOptions...)
+		if c.linkKey(root) != c.linkKey(seed) && linkextractor.InScope(seed, root, c.extractOptions...) && c.urlFilters.Allowed(root) {
+			roots = append(roots, root)
+		}
+	}
+	return roots
+}
@@ -0,0 +1,146 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// mockSeedResolver resolves the URLs of its map to their value, and fails for the rest.
+type mockSeedResolver struct {
+	resolved map[string]string
+}
+
+var seedNotFound = errors.New("unexpected status 404 Not Found")
+
+func (m mockSeedResolver) Resolve(link url.URL) (url.URL, error) {
+	resolved, ok := m.resolved[link.String()]
+	if !ok {
+		return link, seedNotFound
+	}
+	resolvedURL, _ := url.Parse(resolved)
+	return *resolvedURL, nil
+}
+
+func TestBreadthFirstCrawler_CrawlWithSeedCheck(t *testing.T) {
+	siteFetcher := &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com":      `<a href="/about"/>`,
+		"https://test.com/docs": `<a href="/docs/setup"/>`,
+	}}
+	resolver := mockSeedResolver{resolved: map[string]string{
+		"https://test.com":      "https://test.com",
+		"https://test.com/help": "https://test.com/docs",
+	}}
+	tests := []struct {
+		name         string
+		seed         string
+		probeSchemes bool
+		want         []string
+		wantErr      bool
+	}{
+		{name: "healthy seed", seed: "https://test.com", want: []string{"https://test.com", "https://test.com/about"}},
+		{name: "starts from the end of the redirects", seed: "https://test.com/help", want: []string{"https://test.com/docs", "https://test.com/docs/setup"}},
+		{name: "broken seed", seed: "https://test.com/typo", wantErr: true},
+		{name: "http seed without probing the schemes", seed: "http://test.com", wantErr: true},
+		{name: "http seed probing the schemes", seed: "http://test.com", probeSchemes: true, want: []string{"https://test.com", "https://test.com/about"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bfCrawler := NewBreadthFirstCrawler(siteFetcher, WithSeedCheck(resolver, tt.probeSchemes))
+			seed, _ := url.Parse(tt.seed)
+
+			got, err := bfCrawler.Crawl(context.Background(), *seed, 100, 1)
+			if tt.wantErr {
+				var seedErr *SeedError
+				if !errors.As(err, &seedErr) || !errors.Is(err, seedNotFound) || seedErr.URL.String() != tt.seed {
+					t.Errorf("Crawl() error = %v, want a SeedError of %s", err, tt.seed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Crawl() error = %v", err)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Crawl() links got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithHreflangSeeds(t *testing.T) {
+	siteFetcher := &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com/en-us": `<link rel="alternate" hreflang="en-us" href="/en-us/"><link rel="alternate" hreflang="de-de" href="/de-de/"><link rel="alternate" hreflang="x-default" href="/"><link rel="alternate" hreflang="fr" href="https://other.com/fr/"><a href="/en-us/pricing"/>`,
+		"https://test.com/de-de": `<a href="/de-de/preise"/>`,
+		"https://test.com":       `<a href="/en-us/"/>`,
+	}}
+	resolver := mockSeedResolver{resolved: map[string]string{"https://test.com": "https://test.com/en-us/"}}
+	bfCrawler := NewBreadthFirstCrawler(siteFetcher, WithSeedCheck(resolver, false), WithHreflangSeeds())
+	seed, _ := url.Parse("https://test.com")
+
+	got, err := bfCrawler.Crawl(context.Background(), *seed, 1, 1)
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	sort.Strings(got)
+	// the hreflang roots are crawled at depth 0, so their links are found with a depth of 1. The
+	// x-default root is the seed, which redirected to /en-us, so it's not crawled again
+	want := []string{"https://test.com/de-de", "https://test.com/de-de/preise", "https://test.com/en-us", "https://test.com/en-us/pricing"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl() links got = %v, want %v", got, want)
+	}
+}
+
+func TestBreadthFirstCrawler_PagesWithRedirectedSeed(t *testing.T) {
+	siteFetcher := &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com/en-us":         `<a href="/"/><a href="/en-us/pricing"/>`,
+		"https://test.com/en-us/pricing": `<a href="/en-us/pricing/teams"/>`,
+	}}
+	resolver := mockSeedResolver{resolved: map[string]string{"https://test.com": "https://test.com/en-us"}}
+	bfCrawler := NewBreadthFirstCrawler(siteFetcher, WithSeedCheck(resolver, false))
+	seed, _ := url.Parse("https://test.com")
+
+	got := make(map[string]CrawlResult)
+	for result, err := range bfCrawler.Pages(context.Background(), *seed, 2, 1) {
+		if err != nil {
+			t.Fatalf("Pages() error = %v", err)
+		}
+		got[result.URL.String()] = result
+	}
+	if len(got) != 2 {
+		t.Fatalf("Pages() crawled %d pages, want the landing page and its link, without the seed as given. got = %v", len(got), got)
+	}
+	landing := got["https://test.com/en-us"]
+	if landing.Depth != 0 || landing.RedirectedFrom == nil || landing.RedirectedFrom.String() != "https://test.com" {
+		t.Errorf("landing page got depth %d redirected from %v, want depth 0 redirected from https://test.com", landing.Depth, landing.RedirectedFrom)
+	}
+	if pricing := got["https://test.com/en-us/pricing"]; pricing.Depth != 1 || pricing.RedirectedFrom != nil {
+		t.Errorf("linked page got depth %d redirected from %v, want depth 1 and no redirect", pricing.Depth, pricing.RedirectedFrom)
+	}
+}
+
+func TestBreadthFirstCrawler_CrawlWithGraphWithRedirectedSeed(t *testing.T) {
+	siteFetcher := &mockFetcher{webpageWithLinks: map[string]string{
+		"https://test.com/en-us":         `<a href="/"/><a href="/en-us/pricing"/>`,
+		"https://test.com/en-us/pricing": `<a href="/"/>`,
+	}}
+	resolver := mockSeedResolver{resolved: map[string]string{"https://test.com": "https://test.com/en-us"}}
+	bfCrawler := NewBreadthFirstCrawler(siteFetcher, WithSeedCheck(resolver, false))
+	seed, _ := url.Parse("https://test.com")
+
+	graph, err := bfCrawler.CrawlWithGraph(context.Background(), *seed, 2, 1)
+	if err != nil {
+		t.Fatalf("CrawlWithGraph() error = %v", err)
+	}
+	wantRedirect := Edge{From: "https://test.com", To: "https://test.com/en-us"}
+	if graph.SeedRedirect == nil || *graph.SeedRedirect != wantRedirect {
+		t.Errorf("SeedRedirect got = %v, want %v", graph.SeedRedirect, wantRedirect)
+	}
+	wantEdges := []Edge{{From: "https://test.com/en-us", To: "https://test.com/en-us/pricing"}, {From: "https://test.com/en-us/pricing", To: "https://test.com/en-us"}}
+	if !reflect.DeepEqual(graph.Edges, wantEdges) || len(graph.Excluded) != 0 {
+		t.Errorf("Edges got = %v, excluded %v, want %v", graph.Edges, graph.Excluded, wantEdges)
+	}
+}
@@ -0,0 +1,74 @@
+package crawler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+// InvalidSeed indicates that a line of a seeds file is not a URL followed by key=value pairs.
+var InvalidSeed = errors.New("invalid seed")
+
+// Seed is a URL the crawl starts from, with arbitrary metadata, like labels, owners or sections,
+// attributed to the pages under it.
+type Seed struct {
+	URL      url.URL
+	Metadata map[string]string
+}
+
+// SeedMetadata returns the metadata of the seed the link belongs to: the seed of the same host
+// whose path is the longest prefix of the path of the link, so /docs/api/auth belongs to the
+// /docs/api seed rather than to the /docs one. It returns nil if the link belongs to no seed.
+func SeedMetadata(seeds []Seed, link url.URL) map[string]string {
+	link = linkextractor.Normalize(link)
+	var metadata map[string]string
+	longestPrefix := -1
+	for _, seed := range seeds {
+		seedURL := linkextractor.Normalize(seed.URL)
+		if seedURL.Host != link.Host || !hasPathPrefix(link.Path, seedURL.Path) {
+			continue
+		}
+		if len(seedURL.Path) > longestPrefix {
+			metadata, longestPrefix = seed.Metadata, len(seedURL.Path)
+		}
+	}
+	return metadata
+}
+
+// hasPathPrefix reports whether the prefix is the path or one of its parent directories.
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// ParseSeeds reads a seeds file with a seed per line: an absolute URL followed by its metadata as
+// whitespace separated key=value pairs, e.g. "https://example.com/docs owner=docs-team section=docs".
+// Empty lines and lines starting with # are skipped.
+func ParseSeeds(r io.Reader) ([]Seed, error) {
+	var seeds []Seed
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		seedURL, err := url.Parse(fields[0])
+		if err != nil || !seedURL.IsAbs() {
+			return nil, fmt.Errorf("%w at line %d: invalid URL %q", InvalidSeed, line, fields[0])
+		}
+		seed := Seed{URL: *seedURL, Metadata: make(map[string]string)}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok || key == "" {
+				return nil, fmt.Errorf("%w at line %d: want key=value, got %q", InvalidSeed, line, field)
+			}
+			seed.Metadata[key] = value
+		}
+		seeds = append(seeds, seed)
+	}
+	return seeds, scanner.Err()
+}
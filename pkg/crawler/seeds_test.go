@@ -0,0 +1,67 @@
+package crawler
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSeedMetadata(t *testing.T) {
+	seed := func(link, owner string) Seed {
+		seedURL, _ := url.Parse(link)
+		return Seed{URL: *seedURL, Metadata: map[string]string{"owner": owner}}
+	}
+	seeds := []Seed{
+		seed("https://test.com/", "web-team"),
+		seed("https://www.test.com/docs/", "docs-team"),
+		seed("https://test.com/docs/api", "api-team"),
+	}
+	tests := []struct {
+		link string
+		want map[string]string
+	}{
+		{link: "https://test.com/pricing", want: map[string]string{"owner": "web-team"}},
+		{link: "https://test.com/docs", want: map[string]string{"owner": "docs-team"}},
+		{link: "https://test.com/docs/guides/start", want: map[string]string{"owner": "docs-team"}},
+		{link: "https://test.com/docs/api/auth?v=2", want: map[string]string{"owner": "api-team"}},
+		{link: "https://test.com/docs/apis", want: map[string]string{"owner": "docs-team"}},
+		{link: "https://other.com/docs", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			link, _ := url.Parse(tt.link)
+			if got := SeedMetadata(seeds, *link); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SeedMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSeeds(t *testing.T) {
+	seedsFile := `# seeds of the audit
+https://test.com/docs owner=docs-team section=docs
+
+https://test.com/blog owner=marketing
+https://test.com/pricing`
+
+	got, err := ParseSeeds(strings.NewReader(seedsFile))
+	if err != nil {
+		t.Fatalf("should not throw error at ParseSeeds. err: %v", err)
+	}
+	want := []Seed{
+		{URL: url.URL{Scheme: "https", Host: "test.com", Path: "/docs"}, Metadata: map[string]string{"owner": "docs-team", "section": "docs"}},
+		{URL: url.URL{Scheme: "https", Host: "test.com", Path: "/blog"}, Metadata: map[string]string{"owner": "marketing"}},
+		{URL: url.URL{Scheme: "https", Host: "test.com", Path: "/pricing"}, Metadata: map[string]string{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSeeds() got = %v, want %v", got, want)
+	}
+
+	for _, invalidFile := range []string{"/docs owner=docs-team", "https://test.com/docs owner"} {
+		if _, err := ParseSeeds(strings.NewReader(invalidFile)); !errors.Is(err, InvalidSeed) {
+			t.Errorf("ParseSeeds(%q) error = %v, want %v", invalidFile, err, InvalidSeed)
+		}
+	}
+}
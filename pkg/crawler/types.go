@@ -3,7 +3,10 @@ package crawler
 import (
 	"context"
 	"errors"
+	"net/http"
 	"net/url"
+
+	"github.com/andiblas/website-crawler/pkg/frontier"
 )
 
 // InvalidDepth indicates that the provided depth for the crawl operation is invalid.
@@ -15,6 +18,63 @@ var InvalidDepth = errors.New("invalid depth. must be greater than 0")
 // to allow concurrent crawling of multiple pages.
 var InvalidMaxConcurrency = errors.New("invalid maximum concurrency. must be greater than 0")
 
+// FrontierState is the state of a breadth first crawl, returned by BreadthFirstCrawler.Snapshot, to
+// persist it and continue the crawl later with NewBreadthFirstCrawlerFromState.
+type FrontierState = frontier.State
+
+// CrawlResult is the outcome of crawling a page, streamed by BreadthFirstCrawler.CrawlChan.
+type CrawlResult struct {
+	// URL is the crawled page.
+	URL url.URL
+	// Depth is the depth level the page was crawled at, starting from 0 for the seeds.
+	Depth int
+	// Links are all the links found in the page, including the ones found before in other pages.
+	Links []url.URL
+	// Canonical is the canonical URL declared by the page when it's another page, if the crawler
+	// was configured with WithCanonicalURLs.
+	Canonical *url.URL
+	// NoIndex tells whether the page asks search engines not to index it with a <meta name="robots">
+	// tag, if the crawler was configured with WithMetaRobots.
+	NoIndex bool
+	// RedirectedFrom is the URL the seed was given as, if the page is the one the seed redirected to and
+	// the crawler was configured with WithSeedCheck. The depth is counted from this page.
+	RedirectedFrom *url.URL
+	// StatusCode is the status code of the response of the page, like 200 or 404, 0 if none was received,
+	// and Header holds its headers. They're only known if the fetcher is a fetcher.ResultFetcher, like
+	// the HTTPFetcher and its decorators; otherwise, the pages fetched successfully have a 200 status code.
+	StatusCode int
+	Header     http.Header
+	// FinalURL is the URL the redirects of the page ended at, the URL of the page if there were none.
+	FinalURL url.URL
+	// Metadata is the metadata of the seed the page belongs to, if the crawler was configured with
+	// WithSeeds. See SeedMetadata.
+	Metadata map[string]string
+	// Err is the error that prevented crawling the page, if any.
+	Err error
+}
+
+// Edge is a link from a crawled page to another page of the site.
+type Edge struct {
+	From string
+	To   string
+}
+
+// CrawlGraph is the link graph of a crawled site, returned by BreadthFirstCrawler.CrawlWithGraph.
+type CrawlGraph struct {
+	// Seed is the page the crawl started from, the one the seed redirected to if it did.
+	Seed string
+	// Nodes are the links found during the crawl, sorted.
+	Nodes []string
+	// Edges are the links between the nodes, in the order they were crawled. Links from a page to itself are left out.
+	Edges []Edge
+	// Excluded are the links from the nodes to pages left out of the crawl, like the ones disallowed by the
+	// robots policy or left out by the link sampler, in the order they were crawled.
+	Excluded []Edge
+	// SeedRedirect is the hop from the URL the crawl was started with to the page it redirected to, if the
+	// crawler was configured with WithSeedCheck and the seed redirected.
+	SeedRedirect *Edge
+}
+
 type Crawler interface {
 	Crawl(ctx context.Context, urlToCrawl url.URL, depth, maxConcurrency int) ([]string, error)
 }
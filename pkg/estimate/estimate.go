@@ -0,0 +1,172 @@
+package estimate
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+// defaultSampleDepth is the depth of the sample crawl, unless set with WithSampleDepth.
+const defaultSampleDepth = 2
+
+type statsSource interface {
+	Stats() []fetcher.HostStats
+}
+
+type sitemapSeeder interface {
+	Seeds(siteURL url.URL) ([]url.URL, error)
+}
+
+type robotsPolicy interface {
+	Allowed(link url.URL) bool
+}
+
+// Estimate is the predicted cost of a crawl.
+type Estimate struct {
+	// SitemapURLs is the number of URLs of the sitemap of the same host as the site, allowed by robots.txt.
+	SitemapURLs int
+	// SampledPages is the number of pages fetched by the sample crawl.
+	SampledPages int
+	// SampledLinks is the number of distinct links found by the sample crawl.
+	SampledLinks int
+	// Complete tells whether the sample crawl fetched every page found, so it found the whole site.
+	Complete bool
+	// LowerBound tells whether Pages is likely to be exceeded, as the sample crawl didn't find the whole
+	// site and the sitemap lists fewer URLs than the sample found.
+	LowerBound bool
+	// Pages is the predicted number of pages of the crawl.
+	Pages int
+	// Requests is the predicted number of requests of the crawl, including the robots.txt and sitemap.xml files.
+	Requests int
+	// Bytes is the predicted number of bytes downloaded by the crawl.
+	Bytes int64
+	// Duration is the predicted time the crawl takes.
+	Duration time.Duration
+}
+
+// Estimator predicts the pages, requests, bandwidth and time of a crawl before committing to it, using
+// the number of URLs of the sitemap allowed by robots.txt and a shallow sample crawl of the site.
+type Estimator struct {
+	sampleCrawler crawler.Crawler
+	stats         statsSource
+	sitemapSeeder sitemapSeeder
+	robotsPolicy  robotsPolicy
+	sampleDepth   int
+	crawlDelay    time.Duration
+	rateLimit     float64
+}
+
+// Option configures an Estimator.
+type Option func(estimator *Estimator)
+
+// WithSitemap is an option to count the URLs listed in the sitemap of the site.
+func WithSitemap(seeder sitemapSeeder) Option {
+	return func(estimator *Estimator) {
+		estimator.sitemapSeeder = seeder
+	}
+}
+
+// WithRobotsPolicy is an option to count only the URLs of the sitemap allowed by robots.txt, and the
+// request of the robots.txt file of every host.
+func WithRobotsPolicy(policy robotsPolicy) Option {
+	return func(estimator *Estimator) {
+		estimator.robotsPolicy = policy
+	}
+}
+
+// WithSampleDepth is an option to set the depth of the sample crawl. It's 2 by default.
+func WithSampleDepth(depth int) Option {
+	return func(estimator *Estimator) {
+		estimator.sampleDepth = depth
+	}
+}
+
+// WithCrawlDelay is an option to account for the delay every worker of the crawl waits between fetches.
+func WithCrawlDelay(delay time.Duration) Option {
+	return func(estimator *Estimator) {
+		estimator.crawlDelay = delay
+	}
+}
+
+// WithRateLimit is an option to account for the maximum number of requests per second sent to the site.
+func WithRateLimit(requestsPerSecond float64) Option {
+	return func(estimator *Estimator) {
+		estimator.rateLimit = requestsPerSecond
+	}
+}
+
+// NewEstimator creates a new Estimator that runs the sample crawl with the given crawler, which must
+// fetch the pages through the given source of stats, like a fetcher.StatsFetcher, to measure them.
+func NewEstimator(sampleCrawler crawler.Crawler, stats statsSource, opts ...Option) *Estimator {
+	estimator := &Estimator{sampleCrawler: sampleCrawler, stats: stats, sampleDepth: defaultSampleDepth}
+	for _, opt := range opts {
+		opt(estimator)
+	}
+	return estimator
+}
+
+// Estimate predicts the cost of crawling the site with the given depth and maxConcurrency. The sample
+// crawl goes as deep as the sample depth, or the depth of the crawl if it's shallower.
+//
+// Errors:
+//   - If the sample crawl fails, the function returns its error.
+func (e *Estimator) Estimate(ctx context.Context, siteURL url.URL, depth, maxConcurrency int) (Estimate, error) {
+	var estimate Estimate
+	requests := 0
+	if e.sitemapSeeder != nil {
+		// the sitemap can't be fetched, but it's still requested
+		requests++
+		sitemapURLs, _ := e.sitemapSeeder.Seeds(siteURL)
+		for _, sitemapURL := range sitemapURLs {
+			if sameSite(siteURL, sitemapURL) && (e.robotsPolicy == nil || e.robotsPolicy.Allowed(sitemapURL)) {
+				estimate.SitemapURLs++
+			}
+		}
+	}
+
+	links, err := e.sampleCrawler.Crawl(ctx, siteURL, min(e.sampleDepth, depth), maxConcurrency)
+	if err != nil {
+		return Estimate{}, err
+	}
+	var bytes int64
+	var latency time.Duration
+	stats := e.stats.Stats()
+	for _, hostStats := range stats {
+		estimate.SampledPages += hostStats.Pages
+		bytes += hostStats.Bytes
+		latency += hostStats.TotalLatency
+	}
+	if e.robotsPolicy != nil {
+		requests += len(stats)
+	}
+	estimate.SampledLinks = len(links)
+	estimate.Complete = e.sampleDepth >= depth || estimate.SampledPages >= len(links)
+
+	estimate.Pages = estimate.SampledLinks
+	if !estimate.Complete {
+		estimate.Pages = max(estimate.SampledLinks, estimate.SitemapURLs)
+		estimate.LowerBound = estimate.SitemapURLs <= estimate.SampledLinks
+	}
+	estimate.Requests = estimate.Pages + requests
+	if estimate.SampledPages == 0 {
+		return estimate, nil
+	}
+	estimate.Bytes = bytes / int64(estimate.SampledPages) * int64(estimate.Pages)
+
+	averageLatency := latency / time.Duration(estimate.SampledPages)
+	batches := (estimate.Pages + maxConcurrency - 1) / maxConcurrency
+	estimate.Duration = time.Duration(batches) * (averageLatency + e.crawlDelay)
+	if e.rateLimit > 0 {
+		estimate.Duration = max(estimate.Duration, time.Duration(float64(estimate.Pages)/e.rateLimit*float64(time.Second)))
+	}
+	return estimate, nil
+}
+
+// sameSite reports whether the link is of the host of the site, as the sitemap can list other hosts.
+func sameSite(siteURL, link url.URL) bool {
+	return linkextractor.InScope(linkextractor.Normalize(siteURL), linkextractor.Normalize(link))
+}
@@ -0,0 +1,128 @@
+package estimate
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+type mockCrawler struct {
+	links []string
+	err   error
+	depth int
+}
+
+func (m *mockCrawler) Crawl(_ context.Context, _ url.URL, depth, _ int) ([]string, error) {
+	m.depth = depth
+	return m.links, m.err
+}
+
+type mockStats []fetcher.HostStats
+
+func (m mockStats) Stats() []fetcher.HostStats {
+	return m
+}
+
+type mockSitemapSeeder []string
+
+func (m mockSitemapSeeder) Seeds(_ url.URL) ([]url.URL, error) {
+	var seeds []url.URL
+	for _, seed := range m {
+		seedURL, _ := url.Parse(seed)
+		seeds = append(seeds, *seedURL)
+	}
+	return seeds, nil
+}
+
+type mockRobotsPolicy struct{}
+
+func (mockRobotsPolicy) Allowed(link url.URL) bool {
+	return link.Path != "/private"
+}
+
+func TestEstimator_Estimate(t *testing.T) {
+	siteURL, _ := url.Parse("https://test.com")
+	// 2 pages fetched out of 4 links found, 100 KB and 200ms per page
+	sampleLinks := []string{"https://test.com", "https://test.com/a", "https://test.com/b", "https://test.com/c"}
+	sampleStats := mockStats{{Host: "test.com", Pages: 2, TotalLatency: 400 * time.Millisecond, Bytes: 200_000}}
+	sitemap := make(mockSitemapSeeder, 0)
+	for i := 0; i < 10; i++ {
+		sitemap = append(sitemap, "https://test.com/page"+string(rune('a'+i)))
+	}
+	sitemap = append(sitemap, "https://test.com/private", "https://other.com/page")
+
+	tests := []struct {
+		name  string
+		depth int
+		opts  []Option
+		want  Estimate
+	}{
+		{
+			name:  "uses the sitemap when the sample crawl is not complete",
+			depth: 5,
+			opts:  []Option{WithSitemap(sitemap), WithRobotsPolicy(mockRobotsPolicy{})},
+			want: Estimate{SitemapURLs: 10, SampledPages: 2, SampledLinks: 4, Pages: 10, Requests: 12,
+				Bytes: 1_000_000, Duration: 5 * 200 * time.Millisecond},
+		},
+		{
+			name:  "is a lower bound without a sitemap",
+			depth: 5,
+			want: Estimate{SampledPages: 2, SampledLinks: 4, LowerBound: true, Pages: 4, Requests: 4,
+				Bytes: 400_000, Duration: 2 * 200 * time.Millisecond},
+		},
+		{
+			name:  "is the sample when the crawl is as deep as the sample",
+			depth: 2,
+			opts:  []Option{WithSitemap(sitemap)},
+			want: Estimate{SitemapURLs: 11, SampledPages: 2, SampledLinks: 4, Complete: true, Pages: 4, Requests: 5,
+				Bytes: 400_000, Duration: 2 * 200 * time.Millisecond},
+		},
+		{
+			name:  "accounts for the crawl delay and the rate limit",
+			depth: 5,
+			opts:  []Option{WithCrawlDelay(time.Second), WithRateLimit(0.5)},
+			want: Estimate{SampledPages: 2, SampledLinks: 4, LowerBound: true, Pages: 4, Requests: 4,
+				Bytes: 400_000, Duration: 8 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimator := NewEstimator(&mockCrawler{links: sampleLinks}, sampleStats, tt.opts...)
+
+			got, err := estimator.Estimate(context.Background(), *siteURL, tt.depth, 2)
+			if err != nil {
+				t.Fatalf("should not throw error at Estimate. err: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Estimate() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimator_EstimateSampleDepth(t *testing.T) {
+	siteURL, _ := url.Parse("https://test.com")
+	sampleCrawler := &mockCrawler{}
+	estimator := NewEstimator(sampleCrawler, mockStats{}, WithSampleDepth(3))
+
+	if _, err := estimator.Estimate(context.Background(), *siteURL, 10, 2); err != nil {
+		t.Fatalf("should not throw error at Estimate. err: %v", err)
+	}
+	if sampleCrawler.depth != 3 {
+		t.Errorf("Estimate() sample crawl depth got = %v, want 3", sampleCrawler.depth)
+	}
+}
+
+func TestEstimator_EstimateWithCrawlError(t *testing.T) {
+	siteURL, _ := url.Parse("https://test.com")
+	crawlErr := errors.New("crawl failed")
+	estimator := NewEstimator(&mockCrawler{err: crawlErr}, mockStats{})
+
+	if _, err := estimator.Estimate(context.Background(), *siteURL, 10, 2); !errors.Is(err, crawlErr) {
+		t.Errorf("Estimate() error = %v, want %v", err, crawlErr)
+	}
+}
@@ -0,0 +1,22 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// WriteAnchorIndex writes the element ids of every page as a JSON object mapping the page URLs to their
+// sorted ids, e.g. {"https://example.com/docs/guide": ["install", "usage"]}, so other tools and the docs
+// builds of other sites can validate their deep links into the crawled site without crawling it again.
+func WriteAnchorIndex(w io.Writer, ids map[string][]string) error {
+	index := make(map[string][]string, len(ids))
+	for page, pageIDs := range ids {
+		sortedIDs := append([]string{}, pageIDs...)
+		sort.Strings(sortedIDs)
+		index[page] = sortedIDs
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(index)
+}
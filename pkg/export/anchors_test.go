@@ -0,0 +1,29 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteAnchorIndex(t *testing.T) {
+	var buffer bytes.Buffer
+	ids := map[string][]string{
+		"https://test.com/guide": {"usage", "install"},
+		"https://test.com":       nil,
+	}
+	if err := WriteAnchorIndex(&buffer, ids); err != nil {
+		t.Fatalf("should not throw error at WriteAnchorIndex. err: %v", err)
+	}
+
+	want := `{
+  "https://test.com": [],
+  "https://test.com/guide": [
+    "install",
+    "usage"
+  ]
+}
+`
+	if got := buffer.String(); got != want {
+		t.Errorf("WriteAnchorIndex() got\n%v\nwant\n%v", got, want)
+	}
+}
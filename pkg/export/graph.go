@@ -0,0 +1,103 @@
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+)
+
+// WriteDOT renders the crawl graph in the Graphviz DOT language, e.g. to visualize it with
+// `dot -Tsvg site.dot -o site.svg`.
+func WriteDOT(w io.Writer, graph *crawler.CrawlGraph) error {
+	writer := bufio.NewWriter(w)
+	_, _ = writer.WriteString("digraph crawl {\n")
+	for _, node := range graph.Nodes {
+		_, _ = fmt.Fprintf(writer, "\t%s;\n", dotID(node))
+	}
+	for _, edge := range graph.Edges {
+		_, _ = fmt.Fprintf(writer, "\t%s -> %s;\n", dotID(edge.From), dotID(edge.To))
+	}
+	_, _ = writer.WriteString("}\n")
+	return writer.Flush()
+}
+
+// dotID quotes the value as a DOT identifier.
+func dotID(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML renders the crawl graph in the GraphML format, e.g. to analyze it with Gephi.
+// Every node has a "url" attribute with the link it represents.
+func WriteGraphML(w io.Writer, graph *crawler.CrawlGraph) error {
+	document := graphML{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys:  []graphMLKey{{ID: "url", For: "node", AttrName: "url", AttrType: "string"}},
+		Graph: graphMLGraph{ID: "crawl", EdgeDefault: "directed"},
+	}
+	nodeIDs := make(map[string]string, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		nodeIDs[node] = fmt.Sprintf("n%d", i)
+		document.Graph.Nodes = append(document.Graph.Nodes, graphMLNode{
+			ID:   nodeIDs[node],
+			Data: []graphMLData{{Key: "url", Value: node}},
+		})
+	}
+	for _, edge := range graph.Edges {
+		source, sourceOk := nodeIDs[edge.From]
+		target, targetOk := nodeIDs[edge.To]
+		if sourceOk && targetOk {
+			document.Graph.Edges = append(document.Graph.Edges, graphMLEdge{Source: source, Target: target})
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
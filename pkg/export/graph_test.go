@@ -0,0 +1,57 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/crawler"
+)
+
+var testGraph = &crawler.CrawlGraph{
+	Nodes: []string{"https://test.com", "https://test.com/about", `https://test.com/search?q="go"`},
+	Edges: []crawler.Edge{
+		{From: "https://test.com", To: "https://test.com/about"},
+		{From: "https://test.com/about", To: `https://test.com/search?q="go"`},
+	},
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteDOT(&buffer, testGraph); err != nil {
+		t.Fatalf("should not throw error at WriteDOT. err: %v", err)
+	}
+
+	want := `digraph crawl {
+	"https://test.com";
+	"https://test.com/about";
+	"https://test.com/search?q=\"go\"";
+	"https://test.com" -> "https://test.com/about";
+	"https://test.com/about" -> "https://test.com/search?q=\"go\"";
+}
+`
+	if got := buffer.String(); got != want {
+		t.Errorf("WriteDOT() got\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteGraphML(&buffer, testGraph); err != nil {
+		t.Fatalf("should not throw error at WriteGraphML. err: %v", err)
+	}
+
+	var document graphML
+	if err := xml.Unmarshal(buffer.Bytes(), &document); err != nil {
+		t.Fatalf("WriteGraphML() should write valid XML. err: %v", err)
+	}
+	if len(document.Graph.Nodes) != 3 || len(document.Graph.Edges) != 2 {
+		t.Fatalf("WriteGraphML() got %d nodes and %d edges, want 3 and 2", len(document.Graph.Nodes), len(document.Graph.Edges))
+	}
+	if got := document.Graph.Nodes[2].Data[0].Value; got != `https://test.com/search?q="go"` {
+		t.Errorf("WriteGraphML() node url got = %v", got)
+	}
+	if edge := document.Graph.Edges[1]; edge.Source != "n1" || edge.Target != "n2" {
+		t.Errorf("WriteGraphML() edge got = %+v, want n1 -> n2", edge)
+	}
+}
@@ -0,0 +1,159 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// NotModified is returned by a ConditionalFetcher when the server responds with 304 Not Modified, as the
+// page didn't change since the version identified by the validators of the request.
+var NotModified = errors.New("not modified")
+
+// Validators identify a version of a page, so a conditional request only downloads it again if it changed.
+type Validators struct {
+	// ETag is the value of the ETag header, sent back in the If-None-Match header.
+	ETag string `json:"etag,omitempty"`
+	// LastModified is the value of the Last-Modified header, sent back in the If-Modified-Since header.
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func (v Validators) empty() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// ConditionalFetcher is a fetcher that can send conditional requests, like the HTTPFetcher.
+type ConditionalFetcher interface {
+	Fetcher
+	// FetchIfModified fetches the webpage along with its response, with the given context, unless it
+	// didn't change since the version identified by the validators, in which case it returns NotModified.
+	// It also returns the validators of the fetched version, which are empty if the server doesn't send them.
+	FetchIfModified(ctx context.Context, url url.URL, validators Validators) (FetchResult, Validators, error)
+}
+
+// CachedPage is a version of a page saved in a CacheStore, along with the response it came in.
+type CachedPage struct {
+	Validators Validators  `json:"validators"`
+	StatusCode int         `json:"status_code,omitempty"`
+	Header     http.Header `json:"header,omitempty"`
+	// FinalURL is the URL the redirects ended at, empty if there were none.
+	FinalURL string `json:"final_url,omitempty"`
+	Body     []byte `json:"body"`
+}
+
+// result returns the cached page as the result of a fetch of the given URL. The pages cached without
+// their response are returned with a 200 status code.
+func (p CachedPage) result(link url.URL) FetchResult {
+	result := FetchResult{Body: io.NopCloser(bytes.NewReader(p.Body)), StatusCode: p.StatusCode, Header: p.Header.Clone(), FinalURL: link}
+	if result.StatusCode == 0 {
+		result.StatusCode = http.StatusOK
+	}
+	if finalURL, err := url.Parse(p.FinalURL); p.FinalURL != "" && err == nil {
+		result.FinalURL = *finalURL
+	}
+	return result
+}
+
+// CacheStore holds the pages cached by the CachingFetcher, keyed by URL.
+// Implementations must be safe for concurrent use.
+type CacheStore interface {
+	// Load returns the page cached for the URL. It reports whether the page was found.
+	Load(url string) (CachedPage, bool, error)
+	// Save caches the page for the URL, replacing the previous version.
+	Save(url string, page CachedPage) error
+}
+
+// MemoryCacheStore is a CacheStore that keeps the pages in memory, so they're cached for the lifetime of
+// the process, like across the re-crawls of a long-running service.
+type MemoryCacheStore struct {
+	mu    sync.Mutex
+	pages map[string]CachedPage
+}
+
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return &MemoryCacheStore{pages: make(map[string]CachedPage)}
+}
+
+func (s *MemoryCacheStore) Load(url string) (CachedPage, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	page, ok := s.pages[url]
+	return page, ok, nil
+}
+
+func (s *MemoryCacheStore) Save(url string, page CachedPage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pages[url] = page
+	return nil
+}
+
+// CachingFetcher is a fetcher decorator that makes the re-crawls of a site efficient. It records the ETag
+// and Last-Modified validators of the fetched pages, along with their bodies, and sends them back in the
+// If-None-Match and If-Modified-Since headers when the pages are fetched again. When the server responds
+// with 304 Not Modified, the cached body is returned without downloading it again.
+type CachingFetcher struct {
+	innerFetcher ConditionalFetcher
+	store        CacheStore
+}
+
+func NewCachingFetcher(innerFetcher ConditionalFetcher, store CacheStore) *CachingFetcher {
+	return &CachingFetcher{innerFetcher: innerFetcher, store: store}
+}
+
+// FetchWebpageContent fetches the webpage with a conditional request if a version of it is cached, and
+// returns the cached body if it didn't change. Otherwise, the fetched body is cached if the server sent
+// its validators. The pages without validators are neither cached nor read ahead of the caller.
+func (f *CachingFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := f.Fetch(url)
+	return result.Body, err
+}
+
+// Fetch fetches the webpage like FetchWebpageContent, along with its response. The response of a cached
+// page that didn't change is the cached one, not the 304 Not Modified one.
+func (f *CachingFetcher) Fetch(url url.URL) (FetchResult, error) {
+	return f.FetchContext(context.Background(), url)
+}
+
+// FetchContext fetches the webpage like Fetch, passing the context to the inner fetcher.
+func (f *CachingFetcher) FetchContext(ctx context.Context, url url.URL) (FetchResult, error) {
+	key := url.String()
+	cached, ok, err := f.store.Load(key)
+	if err != nil {
+		return FetchResult{FinalURL: url}, err
+	}
+
+	var validators Validators
+	if ok {
+		validators = cached.Validators
+	}
+	result, fetchedValidators, err := f.innerFetcher.FetchIfModified(ctx, url, validators)
+	if errors.Is(err, NotModified) && ok {
+		return cached.result(url), nil
+	}
+	if err != nil || fetchedValidators.empty() {
+		return result, err
+	}
+
+	body, err := io.ReadAll(result.Body)
+	_ = result.Body.Close()
+	result.Body = nil
+	if err != nil {
+		return result, err
+	}
+	cached = CachedPage{Validators: fetchedValidators, StatusCode: result.StatusCode, Header: result.Header, Body: body}
+	if result.FinalURL != url {
+		cached.FinalURL = result.FinalURL.String()
+	}
+	if err := f.store.Save(key, cached); err != nil {
+		return result, err
+	}
+	result.Body = io.NopCloser(bytes.NewReader(body))
+	return result, nil
+}
@@ -0,0 +1,116 @@
+package fetcher
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestCachingFetcher_FetchWebpageContent(t *testing.T) {
+	page := "<html><body><a href=\"/contact\">Contact</a></body></html>"
+	var requests, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/etag":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+		case "/last-modified":
+			if r.Header.Get("If-Modified-Since") == "Wed, 21 Oct 2015 07:28:00 GMT" {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		}
+		_, _ = w.Write([]byte(page))
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	tests := []struct {
+		name            string
+		path            string
+		wantNotModified int
+	}{
+		{name: "revalidates with the ETag", path: "/etag", wantNotModified: 2},
+		{name: "revalidates with the Last-Modified date", path: "/last-modified", wantNotModified: 2},
+		{name: "fetches the pages without validators again", path: "/no-validators", wantNotModified: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests, notModified = 0, 0
+			cachingFetcher := NewCachingFetcher(NewHTTPFetcher(server.Client()), NewMemoryCacheStore())
+			link := *serverURL
+			link.Path = tt.path
+			for i := 0; i < 3; i++ {
+				webpageContent, err := cachingFetcher.FetchWebpageContent(link)
+				if err != nil {
+					t.Fatalf("should not throw error at FetchWebpageContent. err: %v", err)
+				}
+				body, _ := io.ReadAll(webpageContent)
+				_ = webpageContent.Close()
+				if string(body) != page {
+					t.Errorf("FetchWebpageContent() body = %q, want %q", body, page)
+				}
+			}
+			if requests != 3 || notModified != tt.wantNotModified {
+				t.Errorf("FetchWebpageContent() sent %d requests, %d not modified, want 3 requests, %d not modified", requests, notModified, tt.wantNotModified)
+			}
+		})
+	}
+}
+
+func TestCachingFetcher_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+	oldURL, _ := url.Parse(server.URL + "/old")
+	cachingFetcher := NewCachingFetcher(NewHTTPFetcher(server.Client()), NewMemoryCacheStore())
+
+	for i := 0; i < 2; i++ {
+		result, err := cachingFetcher.Fetch(*oldURL)
+		if err != nil {
+			t.Fatalf("should not throw error at Fetch. err: %v", err)
+		}
+		_ = result.Body.Close()
+		// the page not modified keeps the response it was cached with
+		if result.StatusCode != http.StatusOK || result.Header.Get("Content-Type") != "text/html" || result.FinalURL.String() != server.URL+"/new" {
+			t.Errorf("Fetch() got status %d, headers %v and final URL %v, want the response of the cached page", result.StatusCode, result.Header, result.FinalURL.String())
+		}
+	}
+}
+
+func TestHTTPFetcher_FetchIfModified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	_, _, err := NewHTTPFetcher(server.Client()).FetchIfModified(context.Background(), *serverURL, Validators{ETag: `"v1"`})
+	if err != NotModified {
+		t.Errorf("FetchIfModified() error = %v, want NotModified", err)
+	}
+	if isRetryable(err) {
+		t.Errorf("isRetryable() should not retry a NotModified error")
+	}
+}
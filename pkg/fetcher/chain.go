@@ -0,0 +1,206 @@
+package fetcher
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// The layers of a chain of decorators, from the innermost to the outermost one.
+const (
+	// the cache decorates the base fetcher, as it sends the conditional requests with it
+	cacheLayer = iota
+	// rate limits are the closest to the network, so every attempt of a request takes its slot, even a
+	// conditional one
+	rateLimitLayer
+	// retries are outside the rate limit, so they're rate limited too
+	retryLayer
+	// the circuit breaker is outside the retries, so the requests to a failing host skip them all
+	circuitBreakerLayer
+	// re-authentications are outside the retries, so transient errors don't trigger them
+	reauthLayer
+	// variants of broken links are tried once their retries failed
+	variantsLayer
+	// the pages saved to disk are read again without fetching them
+	diskCacheLayer
+	// the pages kept in memory skip every decorator but the metrics
+	lruLayer
+	// metrics measure the pages as the crawler sees them
+	metricsLayer
+)
+
+// ChainOption adds a decorator to the fetcher built by Chain.
+type ChainOption func(chain *chain)
+
+type decorator func(innerFetcher Fetcher, pacer *Pacer) Fetcher
+
+type chain struct {
+	ctx    context.Context
+	pacer  *Pacer
+	layers map[int]decorator
+}
+
+// Chain decorates the base fetcher, usually an HTTPFetcher, with the decorators of the given options.
+// The decorators are composed in a fixed order whatever the order of the options, so every request
+// attempt is rate limited, retries happen before the variants of a broken link are tried, and the
+// metrics measure the pages as the crawler sees them. The retries and the rate limit share a Pacer, so
+// their delays don't compound. If an option is given more than once, the last one is used.
+//
+// Example usage:
+//
+//	stats := fetcher.NewStatsFetcher(nil)
+//	pageFetcher := fetcher.Chain(fetcher.NewHTTPFetcher(http.DefaultClient),
+//	    fetcher.WithRetry(3, 4*time.Second, 0.2),
+//	    fetcher.WithRateLimit(2, 1),
+//	    fetcher.WithMetrics(stats),
+//	)
+func Chain(base Fetcher, opts ...ChainOption) Fetcher {
+	c := &chain{ctx: context.Background(), pacer: NewPacer(), layers: make(map[int]decorator)}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	layers := make([]int, 0, len(c.layers))
+	for layer := range c.layers {
+		layers = append(layers, layer)
+	}
+	sort.Ints(layers)
+	decorated := base
+	for _, layer := range layers {
+		decorated = c.layers[layer](decorated, c.pacer)
+	}
+	return decorated
+}
+
+// WithRetry is an option to retry the failed requests with an ExpBackoffRetryFetcher, randomizing its
+// back offs by up to the given jitter. The back offs end once the context of WithContext is done.
+func WithRetry(numberOfRetries int, delayBetweenRetries time.Duration, jitter float64) ChainOption {
+	return func(chain *chain) {
+		chain.layers[retryLayer] = func(innerFetcher Fetcher, pacer *Pacer) Fetcher {
+			return contextFetcher{ctx: chain.ctx, innerFetcher: NewExpBackoffRetryFetcher(innerFetcher, numberOfRetries, delayBetweenRetries, jitter, WithPacer(pacer))}
+		}
+	}
+}
+
+// WithCircuitBreaker is an option to stop sending requests to the hosts that fail threshold times in a
+// row for the cool-down period with a CircuitBreakerFetcher.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ChainOption {
+	return func(chain *chain) {
+		chain.layers[circuitBreakerLayer] = func(innerFetcher Fetcher, _ *Pacer) Fetcher {
+			return NewCircuitBreakerFetcher(innerFetcher, threshold, cooldown)
+		}
+	}
+}
+
+// WithRateLimit is an option to limit the requests per second sent to each host with a RateLimitedFetcher.
+// The waits for the rate limit end once the context of WithContext is done.
+func WithRateLimit(requestsPerSecond float64, burst int) ChainOption {
+	return func(chain *chain) {
+		chain.layers[rateLimitLayer] = func(innerFetcher Fetcher, pacer *Pacer) Fetcher {
+			return contextFetcher{ctx: chain.ctx, innerFetcher: NewRateLimitedFetcher(innerFetcher, requestsPerSecond, burst, WithPacer(pacer))}
+		}
+	}
+}
+
+// WithCache is an option to cache the pages and fetch them again with conditional requests with a
+// CachingFetcher, saving the pages in the given store. The base fetcher must be a ConditionalFetcher,
+// like the HTTPFetcher, otherwise the pages are not cached.
+func WithCache(store CacheStore) ChainOption {
+	return func(chain *chain) {
+		chain.layers[cacheLayer] = func(innerFetcher Fetcher, _ *Pacer) Fetcher {
+			conditionalFetcher, ok := innerFetcher.(ConditionalFetcher)
+			if !ok {
+				return innerFetcher
+			}
+			return NewCachingFetcher(conditionalFetcher, store)
+		}
+	}
+}
+
+// WithReauth is an option to re-authenticate when the session expires with a ReauthFetcher.
+func WithReauth(authenticate authenticateFunc) ChainOption {
+	return func(chain *chain) {
+		chain.layers[reauthLayer] = func(innerFetcher Fetcher, _ *Pacer) Fetcher {
+			return NewReauthFetcher(innerFetcher, authenticate)
+		}
+	}
+}
+
+// WithVariants is an option to try the trivial variants of the links that respond with 404 Not Found
+// with a VariantFetcher.
+func WithVariants() ChainOption {
+	return func(chain *chain) {
+		chain.layers[variantsLayer] = func(innerFetcher Fetcher, _ *Pacer) Fetcher {
+			return NewVariantFetcher(innerFetcher)
+		}
+	}
+}
+
+// WithDiskCache is an option to save the fetched pages to the given directory with a DiskCacheFetcher,
+// and read them from there until they're older than the ttl.
+func WithDiskCache(dir string, ttl time.Duration) ChainOption {
+	return func(chain *chain) {
+		chain.layers[diskCacheLayer] = func(innerFetcher Fetcher, _ *Pacer) Fetcher {
+			return NewDiskCacheFetcher(innerFetcher, dir, ttl)
+		}
+	}
+}
+
+// WithLRU is an option to keep up to maxPages pages in memory with an LRUFetcher, so the pages fetched
+// more than once hit the network only once.
+func WithLRU(maxPages int) ChainOption {
+	return func(chain *chain) {
+		chain.layers[lruLayer] = func(innerFetcher Fetcher, _ *Pacer) Fetcher {
+			return NewLRUFetcher(innerFetcher, maxPages)
+		}
+	}
+}
+
+// WithMetrics is an option to gather the statistics of the fetched pages with the given StatsFetcher,
+// created with NewStatsFetcher(nil), as Chain sets the fetcher it decorates.
+func WithMetrics(stats *StatsFetcher) ChainOption {
+	return func(chain *chain) {
+		chain.layers[metricsLayer] = func(innerFetcher Fetcher, _ *Pacer) Fetcher {
+			stats.innerFetcher = innerFetcher
+			return stats
+		}
+	}
+}
+
+// WithPacing is an option to share the given Pacer with the decorators of other chains, like the ones
+// of another fetcher sending requests to the same hosts. By default, every chain has its own Pacer.
+func WithPacing(pacer *Pacer) ChainOption {
+	return func(chain *chain) {
+		chain.pacer = pacer
+	}
+}
+
+// WithContext is an option to stop the waits of the decorators, like the back offs of the retries, once
+// the given context is done, so they don't delay the shutdown of a canceled crawl.
+func WithContext(ctx context.Context) ChainOption {
+	return func(chain *chain) {
+		chain.ctx = ctx
+	}
+}
+
+// contextFetcher fetches with the context of WithContext through the decorators that take it per call,
+// unless a context is passed to it.
+type contextFetcher struct {
+	ctx          context.Context
+	innerFetcher ContextFetcher
+}
+
+func (f contextFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := f.Fetch(url)
+	return result.Body, err
+}
+
+func (f contextFetcher) Fetch(url url.URL) (FetchResult, error) {
+	return f.innerFetcher.FetchContext(f.ctx, url)
+}
+
+func (f contextFetcher) FetchContext(ctx context.Context, url url.URL) (FetchResult, error) {
+	return f.innerFetcher.FetchContext(ctx, url)
+}
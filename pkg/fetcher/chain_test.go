@@ -0,0 +1,85 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestChain(t *testing.T) {
+	t.Run("composes the decorators in a fixed order", func(t *testing.T) {
+		base := mockFetcher{}
+		stats := NewStatsFetcher(nil)
+		chained := Chain(base, WithMetrics(stats), WithRateLimit(2, 1), WithVariants(), WithRetry(3, time.Second, 0))
+
+		if chained != Fetcher(stats) {
+			t.Fatalf("Chain() got = %T, want the metrics outermost", chained)
+		}
+		variantFetcher, ok := stats.innerFetcher.(*VariantFetcher)
+		if !ok {
+			t.Fatalf("Chain() decorated the metrics with %T, want a *VariantFetcher", stats.innerFetcher)
+		}
+		retryFetcher, ok := withoutContext(variantFetcher.innerFetcher).(*ExpBackoffRetryFetcher)
+		if !ok {
+			t.Fatalf("Chain() decorated the variants with %T, want an *ExpBackoffRetryFetcher", variantFetcher.innerFetcher)
+		}
+		rateLimitedFetcher, ok := withoutContext(retryFetcher.innerFetcher).(*RateLimitedFetcher)
+		if !ok {
+			t.Fatalf("Chain() decorated the retries with %T, want a *RateLimitedFetcher", retryFetcher.innerFetcher)
+		}
+		if rateLimitedFetcher.innerFetcher != Fetcher(base) {
+			t.Errorf("Chain() decorated the rate limit with %T, want the base fetcher", rateLimitedFetcher.innerFetcher)
+		}
+		if retryFetcher.pacer == nil || retryFetcher.pacer != rateLimitedFetcher.pacer {
+			t.Errorf("Chain() should share a pacer between the retries and the rate limit")
+		}
+	})
+
+	t.Run("passes the context to the decorators that wait", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		chained := Chain(mockFetcher{}, WithContext(ctx), WithRateLimit(1, 1), WithRetry(3, time.Hour, 0))
+
+		if _, err := chained.FetchWebpageContent(url.URL{Host: "test.com"}); !errors.Is(err, context.Canceled) {
+			t.Errorf("FetchWebpageContent() error = %v, want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("returns the base fetcher without options", func(t *testing.T) {
+		if chained := Chain(mockFetcher{}); chained != Fetcher(mockFetcher{}) {
+			t.Errorf("Chain() got = %T, want the base fetcher", chained)
+		}
+	})
+}
+
+func TestWithCache(t *testing.T) {
+	t.Run("decorates a conditional base fetcher", func(t *testing.T) {
+		base := NewHTTPFetcher(mockHttpGetter{})
+		chained := Chain(base, WithRateLimit(2, 1), WithCache(NewMemoryCacheStore()))
+
+		rateLimitedFetcher, ok := withoutContext(chained).(*RateLimitedFetcher)
+		if !ok {
+			t.Fatalf("Chain() got = %T, want a *RateLimitedFetcher outside the cache", chained)
+		}
+		cachingFetcher, ok := rateLimitedFetcher.innerFetcher.(*CachingFetcher)
+		if !ok || cachingFetcher.innerFetcher != ConditionalFetcher(base) {
+			t.Errorf("Chain() decorated the rate limit with %T, want a *CachingFetcher of the base fetcher", rateLimitedFetcher.innerFetcher)
+		}
+	})
+
+	t.Run("ignores a base fetcher without conditional requests", func(t *testing.T) {
+		if chained := Chain(mockFetcher{}, WithCache(NewMemoryCacheStore())); chained != Fetcher(mockFetcher{}) {
+			t.Errorf("Chain() got = %T, want the base fetcher", chained)
+		}
+	})
+}
+
+// withoutContext returns the decorator the chain passes its context to.
+func withoutContext(chained Fetcher) Fetcher {
+	if contextFetcher, ok := chained.(contextFetcher); ok {
+		return contextFetcher.innerFetcher.(Fetcher)
+	}
+	return chained
+}
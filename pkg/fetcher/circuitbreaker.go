@@ -0,0 +1,112 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// CircuitOpenError indicates that a request was not sent because its host failed too many times in a
+// row, and its circuit is open until the cool-down period ends.
+type CircuitOpenError struct {
+	URL   url.URL
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for %s until %s after consecutive failures", e.URL.Host, e.Until.Format(time.RFC3339))
+}
+
+// hostCircuit is the state of the circuit of a host.
+type hostCircuit struct {
+	failures int
+	// openUntil is the end of the cool-down period of an open circuit, zero if it's closed
+	openUntil time.Time
+	// probing tells whether a request is testing the host once the cool-down period ended
+	probing bool
+}
+
+// CircuitBreakerFetcher is a fetcher decorator that stops sending requests to a host after a number of
+// consecutive failures, so a dying host doesn't waste the time of the crawl with requests and retries
+// bound to fail. The requests to the host fail right away with a CircuitOpenError during a cool-down
+// period. Then, a single request tests the host: the circuit closes if it succeeds, and opens again
+// otherwise. Only the failures that retrying could fix count, like the network errors and the 5xx
+// statuses; a 404 Not Found means the host is up.
+type CircuitBreakerFetcher struct {
+	innerFetcher Fetcher
+	threshold    int
+	cooldown     time.Duration
+	mu           sync.Mutex
+	circuits     map[string]*hostCircuit
+}
+
+// NewCircuitBreakerFetcher creates a new CircuitBreakerFetcher that opens the circuit of a host after
+// threshold consecutive failures, for the given cool-down period. threshold is set to 1 if it's lower than 1.
+func NewCircuitBreakerFetcher(innerFetcher Fetcher, threshold int, cooldown time.Duration) *CircuitBreakerFetcher {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreakerFetcher{innerFetcher: innerFetcher, threshold: threshold, cooldown: cooldown, circuits: make(map[string]*hostCircuit)}
+}
+
+// FetchWebpageContent fetches the webpage using the inner fetcher, unless the circuit of its host is open.
+func (f *CircuitBreakerFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := f.Fetch(url)
+	return result.Body, err
+}
+
+// Fetch fetches the webpage like FetchWebpageContent, along with its response.
+func (f *CircuitBreakerFetcher) Fetch(url url.URL) (FetchResult, error) {
+	return f.FetchContext(context.Background(), url)
+}
+
+// FetchContext fetches the webpage like Fetch, passing the context to the inner fetcher.
+func (f *CircuitBreakerFetcher) FetchContext(ctx context.Context, url url.URL) (FetchResult, error) {
+	if until, open := f.allow(url.Host, time.Now()); open {
+		return FetchResult{FinalURL: url}, &CircuitOpenError{URL: url, Until: until}
+	}
+	result, err := FetchContext(ctx, f.innerFetcher, url)
+	f.record(url.Host, err, time.Now())
+	return result, err
+}
+
+// allow reports whether the circuit of the host is open, and until when. Once the cool-down period
+// ended, it lets a single request through to test the host.
+func (f *CircuitBreakerFetcher) allow(host string, now time.Time) (time.Time, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	circuit, ok := f.circuits[host]
+	if !ok || circuit.openUntil.IsZero() {
+		return time.Time{}, false
+	}
+	if now.Before(circuit.openUntil) || circuit.probing {
+		return circuit.openUntil, true
+	}
+	circuit.probing = true
+	return time.Time{}, false
+}
+
+// record updates the circuit of the host with the outcome of a request.
+func (f *CircuitBreakerFetcher) record(host string, err error, now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err == nil || !isRetryable(err) {
+		delete(f.circuits, host)
+		return
+	}
+	circuit, ok := f.circuits[host]
+	if !ok {
+		circuit = &hostCircuit{}
+		f.circuits[host] = circuit
+	}
+	circuit.failures++
+	if circuit.probing || circuit.failures >= f.threshold {
+		circuit.openUntil = now.Add(f.cooldown)
+		circuit.probing = false
+	}
+}
@@ -0,0 +1,90 @@
+package fetcher
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hostStatusFetcher responds with the status code of the host of the URL, or with a page if it has none.
+type hostStatusFetcher struct {
+	statusCodes map[string]int
+	fetchCalls  int
+}
+
+func (m *hostStatusFetcher) FetchWebpageContent(link url.URL) (io.ReadCloser, error) {
+	m.fetchCalls++
+	if statusCode, ok := m.statusCodes[link.Host]; ok {
+		return nil, &UnexpectedStatusError{URL: link, StatusCode: statusCode, Status: http.StatusText(statusCode)}
+	}
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func TestCircuitBreakerFetcher_FetchWebpageContent(t *testing.T) {
+	deadURL := url.URL{Scheme: "https", Host: "dead.com", Path: "/page"}
+	aliveURL := url.URL{Scheme: "https", Host: "alive.com", Path: "/page"}
+
+	t.Run("opens the circuit of the host after consecutive failures", func(t *testing.T) {
+		innerFetcher := &hostStatusFetcher{statusCodes: map[string]int{"dead.com": http.StatusBadGateway}}
+		circuitBreaker := NewCircuitBreakerFetcher(innerFetcher, 3, time.Hour)
+
+		for i := 0; i < 5; i++ {
+			_, _ = circuitBreaker.FetchWebpageContent(deadURL)
+		}
+		if innerFetcher.fetchCalls != 3 {
+			t.Errorf("inner fetcher called %v times, want 3", innerFetcher.fetchCalls)
+		}
+		var circuitErr *CircuitOpenError
+		if _, err := circuitBreaker.FetchWebpageContent(deadURL); !errors.As(err, &circuitErr) {
+			t.Errorf("FetchWebpageContent() error = %v, want a CircuitOpenError", err)
+		}
+		if _, err := circuitBreaker.FetchWebpageContent(aliveURL); err != nil {
+			t.Errorf("should not throw error at FetchWebpageContent of another host. err: %v", err)
+		}
+	})
+
+	t.Run("doesn't count the errors of the pages of a host that is up", func(t *testing.T) {
+		innerFetcher := &hostStatusFetcher{statusCodes: map[string]int{"dead.com": http.StatusNotFound}}
+		circuitBreaker := NewCircuitBreakerFetcher(innerFetcher, 2, time.Hour)
+
+		for i := 0; i < 5; i++ {
+			_, _ = circuitBreaker.FetchWebpageContent(deadURL)
+		}
+		if innerFetcher.fetchCalls != 5 {
+			t.Errorf("inner fetcher called %v times, want 5", innerFetcher.fetchCalls)
+		}
+	})
+
+	t.Run("tests the host once the cool-down period ends", func(t *testing.T) {
+		innerFetcher := &hostStatusFetcher{statusCodes: map[string]int{"dead.com": http.StatusServiceUnavailable}}
+		circuitBreaker := NewCircuitBreakerFetcher(innerFetcher, 1, time.Hour)
+		now := time.Now()
+
+		circuitBreaker.record("dead.com", &UnexpectedStatusError{StatusCode: http.StatusServiceUnavailable}, now)
+		if _, open := circuitBreaker.allow("dead.com", now.Add(time.Minute)); !open {
+			t.Fatalf("circuit should be open during the cool-down period")
+		}
+		if _, open := circuitBreaker.allow("dead.com", now.Add(2*time.Hour)); open {
+			t.Fatalf("circuit should let a request test the host after the cool-down period")
+		}
+		if _, open := circuitBreaker.allow("dead.com", now.Add(2*time.Hour)); !open {
+			t.Errorf("circuit should let a single request test the host")
+		}
+
+		// the failed test opens the circuit again
+		circuitBreaker.record("dead.com", &UnexpectedStatusError{StatusCode: http.StatusServiceUnavailable}, now.Add(2*time.Hour))
+		if _, open := circuitBreaker.allow("dead.com", now.Add(2*time.Hour+time.Minute)); !open {
+			t.Errorf("circuit should open again after the test failed")
+		}
+
+		// the successful test closes it
+		circuitBreaker.record("dead.com", nil, now.Add(4*time.Hour))
+		if _, open := circuitBreaker.allow("dead.com", now.Add(4*time.Hour)); open {
+			t.Errorf("circuit should be closed after the test succeeded")
+		}
+	})
+}
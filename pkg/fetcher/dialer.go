@@ -0,0 +1,41 @@
+package fetcher
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+)
+
+// LocalAddrRotator dials outbound connections binding each one to the next local IP address
+// of a configured set, in a round-robin fashion. It's meant to be used as the DialContext of
+// an http.Transport on hosts that have multiple IP addresses assigned.
+type LocalAddrRotator struct {
+	dialers []*net.Dialer
+	next    uint64
+}
+
+// NewLocalAddrRotator creates a new LocalAddrRotator that copies the settings of baseDialer
+// for each one of the given local IP addresses.
+func NewLocalAddrRotator(baseDialer net.Dialer, localIPs []net.IP) *LocalAddrRotator {
+	dialers := make([]*net.Dialer, len(localIPs))
+	for i, ip := range localIPs {
+		dialer := baseDialer
+		dialer.LocalAddr = &net.TCPAddr{IP: ip}
+		dialers[i] = &dialer
+	}
+	return &LocalAddrRotator{dialers: dialers}
+}
+
+// DialContext connects to the address on the named network using the next local IP address.
+// If the rotator has no local IP addresses, the connection is dialed with the default local address.
+func (r *LocalAddrRotator) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return r.nextDialer().DialContext(ctx, network, addr)
+}
+
+func (r *LocalAddrRotator) nextDialer() *net.Dialer {
+	if len(r.dialers) == 0 {
+		return &net.Dialer{}
+	}
+	i := atomic.AddUint64(&r.next, 1) - 1
+	return r.dialers[i%uint64(len(r.dialers))]
+}
@@ -0,0 +1,40 @@
+package fetcher
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestLocalAddrRotator_DialContext(t *testing.T) {
+	t.Run("rotates local addresses round-robin", func(t *testing.T) {
+		localIPs := []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")}
+		rotator := NewLocalAddrRotator(net.Dialer{}, localIPs)
+
+		want := []string{"10.0.0.1:0", "10.0.0.2:0", "10.0.0.1:0"}
+		for _, wantAddr := range want {
+			if got := rotator.nextDialer().LocalAddr.String(); got != wantAddr {
+				t.Errorf("nextDialer() local address got = %v, want %v", got, wantAddr)
+			}
+		}
+	})
+
+	t.Run("dials from the configured local address", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("could not start listener. err: %v", err)
+		}
+		defer func() { _ = listener.Close() }()
+
+		rotator := NewLocalAddrRotator(net.Dialer{}, []net.IP{net.ParseIP("127.0.0.1")})
+		conn, err := rotator.DialContext(context.Background(), "tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatalf("should not throw error at DialContext. err: %v", err)
+		}
+		defer func() { _ = conn.Close() }()
+
+		if got := conn.LocalAddr().(*net.TCPAddr).IP.String(); got != "127.0.0.1" {
+			t.Errorf("DialContext() local address got = %v, want 127.0.0.1", got)
+		}
+	})
+}
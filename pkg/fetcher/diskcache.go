@@ -0,0 +1,145 @@
+package fetcher
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DiskCacheFetcher is a fetcher decorator that saves the fetched bodies to a local directory, in a file
+// named after the hash of their URL, and reads them from there instead of fetching them again while
+// they're fresh. It lets the extraction and the analysis of a site run again without downloading it
+// again. The status code, the headers and the final URL of the responses are saved in the first line of
+// the files, in JSON. The failed fetches are not cached.
+type DiskCacheFetcher struct {
+	innerFetcher Fetcher
+	dir          string
+	ttl          time.Duration
+}
+
+// diskCachedResponse is the response of a body saved by the DiskCacheFetcher.
+type diskCachedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	FinalURL   string      `json:"final_url"`
+}
+
+// NewDiskCacheFetcher creates a new DiskCacheFetcher that saves the bodies in the given directory,
+// created if it doesn't exist, and fetches them again once they're older than the ttl. A ttl of 0 keeps
+// them fresh forever.
+func NewDiskCacheFetcher(innerFetcher Fetcher, dir string, ttl time.Duration) *DiskCacheFetcher {
+	return &DiskCacheFetcher{innerFetcher: innerFetcher, dir: dir, ttl: ttl}
+}
+
+// FetchWebpageContent returns the saved body of the webpage if it's fresh, or fetches it with the inner
+// fetcher and saves it. The errors saving the body are returned, as the caller expects it cached.
+func (f *DiskCacheFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := f.Fetch(url)
+	return result.Body, err
+}
+
+// Fetch fetches the webpage like FetchWebpageContent, along with its response, saved with the body.
+func (f *DiskCacheFetcher) Fetch(url url.URL) (FetchResult, error) {
+	return f.FetchContext(context.Background(), url)
+}
+
+// FetchContext fetches the webpage like Fetch, passing the context to the inner fetcher.
+func (f *DiskCacheFetcher) FetchContext(ctx context.Context, url url.URL) (FetchResult, error) {
+	path := f.path(url)
+	if result, ok := f.load(path); ok {
+		return result, nil
+	}
+
+	result, err := FetchContext(ctx, f.innerFetcher, url)
+	if err != nil {
+		return result, err
+	}
+	webpageContent := result.Body
+	defer func(webpageContent io.ReadCloser) {
+		_ = webpageContent.Close()
+	}(webpageContent)
+	result.Body = nil
+	body, err := io.ReadAll(webpageContent)
+	if err != nil {
+		return result, err
+	}
+	response := diskCachedResponse{StatusCode: result.StatusCode, Header: result.Header, FinalURL: result.FinalURL.String()}
+	if err := f.save(path, response, body); err != nil {
+		return result, err
+	}
+	result.Body = io.NopCloser(bytes.NewReader(body))
+	return result, nil
+}
+
+// load returns the saved response and body of the file at the given path if it's fresh. The files
+// without a valid response line, like the ones saved before the responses were, are not fresh.
+func (f *DiskCacheFetcher) load(path string) (FetchResult, bool) {
+	info, err := os.Stat(path)
+	if err != nil || (f.ttl != 0 && time.Since(info.ModTime()) >= f.ttl) {
+		return FetchResult{}, false
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return FetchResult{}, false
+	}
+	reader := bufio.NewReader(file)
+	var response diskCachedResponse
+	line, err := reader.ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &response)
+	}
+	var finalURL *url.URL
+	if err == nil {
+		finalURL, err = url.Parse(response.FinalURL)
+	}
+	if err != nil {
+		_ = file.Close()
+		return FetchResult{}, false
+	}
+	body := struct {
+		io.Reader
+		io.Closer
+	}{reader, file}
+	return FetchResult{Body: body, StatusCode: response.StatusCode, Header: response.Header, FinalURL: *finalURL}, true
+}
+
+// path returns the path of the file where the body of the URL is saved.
+func (f *DiskCacheFetcher) path(url url.URL) string {
+	hash := sha256.Sum256([]byte(url.String()))
+	return filepath.Join(f.dir, hex.EncodeToString(hash[:]))
+}
+
+// save writes the response line and the body to a temporary file renamed to the given path, so the
+// concurrent fetches of the page never read a partially written file.
+func (f *DiskCacheFetcher) save(path string, response diskCachedResponse, body []byte) error {
+	line, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(append(line, '\n'), body...)); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,81 @@
+package fetcher
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDiskCacheFetcher_FetchWebpageContent(t *testing.T) {
+	link := url.URL{Scheme: "https", Host: "test.com", Path: "/about"}
+	fetch := func(diskCacheFetcher *DiskCacheFetcher) {
+		webpageContent, err := diskCacheFetcher.FetchWebpageContent(link)
+		if err != nil {
+			t.Fatalf("should not throw error at FetchWebpageContent. err: %v", err)
+		}
+		body, _ := io.ReadAll(webpageContent)
+		_ = webpageContent.Close()
+		if string(body) != link.Path {
+			t.Errorf("FetchWebpageContent() body = %q, want %q", body, link.Path)
+		}
+	}
+
+	t.Run("reads the fresh pages from disk", func(t *testing.T) {
+		dir := t.TempDir()
+		inner := &countingFetcher{fetches: make(map[string]int)}
+		fetch(NewDiskCacheFetcher(inner, dir, time.Hour))
+		// another process reads the pages saved by the first one
+		fetch(NewDiskCacheFetcher(inner, dir, time.Hour))
+
+		if inner.fetches[link.Path] != 1 {
+			t.Errorf("FetchWebpageContent() fetched %s %d times, want 1", link.Path, inner.fetches[link.Path])
+		}
+	})
+
+	t.Run("fetches the expired pages again", func(t *testing.T) {
+		dir := t.TempDir()
+		inner := &countingFetcher{fetches: make(map[string]int)}
+		diskCacheFetcher := NewDiskCacheFetcher(inner, dir, time.Hour)
+		fetch(diskCacheFetcher)
+		expired := time.Now().Add(-2 * time.Hour)
+		if err := os.Chtimes(diskCacheFetcher.path(link), expired, expired); err != nil {
+			t.Fatalf("should not throw error at Chtimes. err: %v", err)
+		}
+		fetch(diskCacheFetcher)
+
+		if inner.fetches[link.Path] != 2 {
+			t.Errorf("FetchWebpageContent() fetched %s %d times, want 2", link.Path, inner.fetches[link.Path])
+		}
+	})
+	t.Run("keeps the responses", func(t *testing.T) {
+		dir := t.TempDir()
+		inner := &countingFetcher{fetches: make(map[string]int)}
+		for i := 0; i < 2; i++ {
+			result, err := NewDiskCacheFetcher(inner, dir, time.Hour).Fetch(link)
+			if err != nil {
+				t.Fatalf("should not throw error at Fetch. err: %v", err)
+			}
+			_ = result.Body.Close()
+			wantCountingResponse(t, result, link)
+		}
+		if inner.fetches[link.Path] != 1 {
+			t.Errorf("Fetch() fetched %s %d times, want 1", link.Path, inner.fetches[link.Path])
+		}
+	})
+
+	t.Run("fetches the pages saved without their response again", func(t *testing.T) {
+		dir := t.TempDir()
+		inner := &countingFetcher{fetches: make(map[string]int)}
+		diskCacheFetcher := NewDiskCacheFetcher(inner, dir, time.Hour)
+		if err := os.WriteFile(diskCacheFetcher.path(link), []byte("<html></html>"), 0o644); err != nil {
+			t.Fatalf("should not throw error at WriteFile. err: %v", err)
+		}
+		fetch(diskCacheFetcher)
+
+		if inner.fetches[link.Path] != 1 {
+			t.Errorf("FetchWebpageContent() fetched %s %d times, want 1", link.Path, inner.fetches[link.Path])
+		}
+	})
+}
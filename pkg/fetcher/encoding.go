@@ -0,0 +1,128 @@
+package fetcher
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// ContentDecoder decodes a response body compressed with a content encoding.
+type ContentDecoder func(body io.Reader) (io.Reader, error)
+
+// defaultContentDecoders are the content encodings the HTTPFetcher decodes out of the box.
+var defaultContentDecoders = map[string]ContentDecoder{
+	"gzip":    decodeGzip,
+	"x-gzip":  decodeGzip,
+	"deflate": decodeDeflate,
+	"br":      decodeBrotli,
+}
+
+// UnsupportedContentEncodingError is returned when the server responds with a body compressed with a
+// content encoding the HTTPFetcher can't decode, like zstd without a decoder set with the
+// WithContentDecoder option.
+type UnsupportedContentEncodingError struct {
+	URL             url.URL
+	ContentEncoding string
+}
+
+func (e *UnsupportedContentEncodingError) Error() string {
+	return fmt.Sprintf("unsupported content encoding %s", e.ContentEncoding)
+}
+
+// WithContentDecoder is an option to decode the response bodies compressed with the given content
+// encoding, advertised in the Accept-Encoding header of the requests. gzip, deflate and brotli ("br")
+// are decoded out of the box, and other encodings, like zstd, can be added with a third-party decoder.
+//
+// Example usage:
+//
+//	// with github.com/klauspost/compress/zstd
+//	decodeZstd := func(body io.Reader) (io.Reader, error) { return zstd.NewReader(body) }
+//	httpFetcher := fetcher.NewHTTPFetcher(http.DefaultClient, fetcher.WithContentDecoder("zstd", decodeZstd))
+func WithContentDecoder(contentEncoding string, decoder ContentDecoder) HTTPFetcherOption {
+	return func(fetcher *HTTPFetcher) {
+		if fetcher.contentDecoders == nil {
+			fetcher.contentDecoders = make(map[string]ContentDecoder)
+		}
+		fetcher.contentDecoders[strings.ToLower(contentEncoding)] = decoder
+	}
+}
+
+// acceptEncoding returns the value of the Accept-Encoding header of the requests: the content
+// encodings the fetcher can decode.
+func (f *HTTPFetcher) acceptEncoding() string {
+	var extraEncodings []string
+	for encoding := range f.contentDecoders {
+		if _, ok := defaultContentDecoders[encoding]; !ok {
+			extraEncodings = append(extraEncodings, encoding)
+		}
+	}
+	sort.Strings(extraEncodings)
+	return strings.Join(append([]string{"gzip", "deflate", "br"}, extraEncodings...), ", ")
+}
+
+// decodedBody decodes the body of the response according to its Content-Encoding header. Some servers
+// send gzip compressed pages without the header, so the text bodies are detected by their magic number
+// too, while compressed files, like .gz archives, are left alone. The bodies already decoded by the
+// transport of the client are returned as they are.
+func (f *HTTPFetcher) decodedBody(url url.URL, res *http.Response) (io.ReadCloser, error) {
+	if res.Uncompressed {
+		return res.Body, nil
+	}
+	bufferedBody := bufio.NewReader(res.Body)
+	contentEncoding := strings.ToLower(strings.TrimSpace(res.Header.Get("Content-Encoding")))
+	if contentEncoding == "" || contentEncoding == "identity" {
+		contentType := strings.ToLower(res.Header.Get("Content-Type"))
+		if contentType != "" && !strings.HasPrefix(contentType, "text/") {
+			return readCloser{Reader: bufferedBody, Closer: res.Body}, nil
+		}
+		if magic, _ := bufferedBody.Peek(2); len(magic) < 2 || magic[0] != 0x1f || magic[1] != 0x8b {
+			return readCloser{Reader: bufferedBody, Closer: res.Body}, nil
+		}
+		contentEncoding = "gzip"
+	}
+
+	decoder, ok := f.contentDecoders[contentEncoding]
+	if !ok {
+		decoder, ok = defaultContentDecoders[contentEncoding]
+	}
+	if !ok {
+		return nil, &UnsupportedContentEncodingError{URL: url, ContentEncoding: contentEncoding}
+	}
+	decoded, err := decoder(bufferedBody)
+	if err != nil {
+		return nil, err
+	}
+	return readCloser{Reader: decoded, Closer: res.Body}, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func decodeGzip(body io.Reader) (io.Reader, error) {
+	return gzip.NewReader(body)
+}
+
+// decodeDeflate decodes a deflate body, which should be zlib wrapped, but some servers send it raw.
+func decodeDeflate(body io.Reader) (io.Reader, error) {
+	bufferedBody := bufio.NewReader(body)
+	header, _ := bufferedBody.Peek(2)
+	if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(bufferedBody)
+	}
+	return flate.NewReader(bufferedBody), nil
+}
+
+func decodeBrotli(body io.Reader) (io.Reader, error) {
+	return brotli.NewReader(body), nil
+}
@@ -0,0 +1,111 @@
+package fetcher
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+// encodingDoer responds with the given body and headers, recording the headers of the request.
+type encodingDoer struct {
+	body          []byte
+	header        http.Header
+	requestHeader http.Header
+}
+
+func (d *encodingDoer) Get(_ string) (*http.Response, error) {
+	return d.Do(&http.Request{Header: http.Header{}})
+}
+
+func (d *encodingDoer) Do(req *http.Request) (*http.Response, error) {
+	d.requestHeader = req.Header
+	return &http.Response{StatusCode: http.StatusOK, Header: d.header, Body: io.NopCloser(bytes.NewReader(d.body))}, nil
+}
+
+func compress(t *testing.T, newWriter func(w io.Writer) io.WriteCloser, content string) []byte {
+	var compressed bytes.Buffer
+	writer := newWriter(&compressed)
+	if _, err := writer.Write([]byte(content)); err != nil {
+		t.Fatalf("should not throw error at Write. err: %v", err)
+	}
+	_ = writer.Close()
+	return compressed.Bytes()
+}
+
+func TestHTTPFetcher_decodedBody(t *testing.T) {
+	page := "<html><body><a href=\"/contact\">Contact</a></body></html>"
+	gzipped := compress(t, func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }, page)
+	zlibbed := compress(t, func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }, page)
+	deflated := compress(t, func(w io.Writer) io.WriteCloser {
+		writer, _ := flate.NewWriter(w, flate.DefaultCompression)
+		return writer
+	}, page)
+	brotlied := compress(t, func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) }, page)
+
+	tests := []struct {
+		name   string
+		body   []byte
+		header http.Header
+		want   string
+	}{
+		{name: "gzip", body: gzipped, header: http.Header{"Content-Encoding": {"gzip"}}, want: page},
+		{name: "zlib wrapped deflate", body: zlibbed, header: http.Header{"Content-Encoding": {"deflate"}}, want: page},
+		{name: "raw deflate", body: deflated, header: http.Header{"Content-Encoding": {"deflate"}}, want: page},
+		{name: "brotli", body: brotlied, header: http.Header{"Content-Encoding": {"br"}}, want: page},
+		{name: "gzip without the header", body: gzipped, header: http.Header{"Content-Type": {"text/html"}}, want: page},
+		{name: "gzip archive", body: gzipped, header: http.Header{"Content-Type": {"application/gzip"}}, want: string(gzipped)},
+		{name: "uncompressed", body: []byte(page), header: http.Header{}, want: page},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doer := &encodingDoer{body: tt.body, header: tt.header}
+			reader, err := NewHTTPFetcher(doer).FetchWebpageContent(url.URL{Scheme: "https", Host: "test.com"})
+			if err != nil {
+				t.Fatalf("should not throw error at FetchWebpageContent. err: %v", err)
+			}
+			got, err := io.ReadAll(reader)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("FetchWebpageContent() body = %q, err = %v, want %q", got, err, tt.want)
+			}
+			if acceptEncoding := doer.requestHeader.Get("Accept-Encoding"); acceptEncoding != "gzip, deflate, br" {
+				t.Errorf("FetchWebpageContent() sent Accept-Encoding %q, want \"gzip, deflate, br\"", acceptEncoding)
+			}
+		})
+	}
+
+	t.Run("decodes the encodings of the content decoders", func(t *testing.T) {
+		doer := &encodingDoer{body: []byte(strings.ToUpper(page)), header: http.Header{"Content-Encoding": {"zstd"}}}
+		lowerCase := func(body io.Reader) (io.Reader, error) {
+			content, err := io.ReadAll(body)
+			return strings.NewReader(strings.ToLower(string(content))), err
+		}
+		reader, err := NewHTTPFetcher(doer, WithContentDecoder("zstd", lowerCase)).FetchWebpageContent(url.URL{Scheme: "https", Host: "test.com"})
+		if err != nil {
+			t.Fatalf("should not throw error at FetchWebpageContent. err: %v", err)
+		}
+		if got, _ := io.ReadAll(reader); string(got) != strings.ToLower(page) {
+			t.Errorf("FetchWebpageContent() body = %q, want the decoded body", got)
+		}
+		if acceptEncoding := doer.requestHeader.Get("Accept-Encoding"); acceptEncoding != "gzip, deflate, br, zstd" {
+			t.Errorf("FetchWebpageContent() sent Accept-Encoding %q, want \"gzip, deflate, br, zstd\"", acceptEncoding)
+		}
+	})
+
+	t.Run("fails with encodings without a decoder", func(t *testing.T) {
+		doer := &encodingDoer{body: []byte("compressed"), header: http.Header{"Content-Encoding": {"zstd"}}}
+		_, err := NewHTTPFetcher(doer).FetchWebpageContent(url.URL{Scheme: "https", Host: "test.com"})
+		var encodingErr *UnsupportedContentEncodingError
+		if !errors.As(err, &encodingErr) || encodingErr.ContentEncoding != "zstd" {
+			t.Errorf("FetchWebpageContent() error = %v, want an UnsupportedContentEncodingError", err)
+		}
+	})
+}
@@ -1,12 +1,24 @@
 package fetcher
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"io"
+	"math/rand/v2"
+	"mime"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// defaultErrorBodySampleSize is the number of bytes of the response body captured by default
+// when a request fails with an unexpected status.
+const defaultErrorBodySampleSize = 1024
+
 type Fetcher interface {
 	FetchWebpageContent(url url.URL) (io.ReadCloser, error)
 }
@@ -15,50 +27,560 @@ type httpGetter interface {
 	Get(url string) (resp *http.Response, err error)
 }
 
+// httpDoer is implemented by the clients that can send requests with headers, like *http.Client.
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
 type HTTPFetcher struct {
-	httpClient httpGetter
+	httpClient          httpGetter
+	errorBodySampleSize int
+	contentTypes        []string
+	maxBodySize         int64
+	contentDecoders     map[string]ContentDecoder
+	header              http.Header
+	cookieJar           http.CookieJar
+	headRequests        bool
+}
+
+type HTTPFetcherOption func(fetcher *HTTPFetcher)
+
+// UnexpectedStatusError is returned when the server responds with an error status code (4xx or 5xx).
+// It holds the beginning of the response body, which usually explains the error.
+type UnexpectedStatusError struct {
+	URL        url.URL
+	StatusCode int
+	Status     string
+	Header     http.Header
+	BodySample []byte
+}
+
+func (e *UnexpectedStatusError) Error() string {
+	return fmt.Sprintf("unexpected status %s", e.Status)
+}
+
+// DefaultContentTypes are the media types of HTML pages.
+var DefaultContentTypes = []string{"text/html", "application/xhtml+xml"}
+
+// UnsupportedContentTypeError is returned when the server responds with a media type that is not
+// allowed by the WithContentTypes option, like a PDF or an image. The body is not downloaded.
+type UnsupportedContentTypeError struct {
+	URL         url.URL
+	ContentType string
+}
+
+func (e *UnsupportedContentTypeError) Error() string {
+	return fmt.Sprintf("unsupported content type %s", e.ContentType)
+}
+
+// BodyTooLargeError is returned when the body of a response is larger than the maximum set with the
+// WithMaxBodySize option. It's returned by FetchWebpageContent if the Content-Length of the response
+// already exceeds it, or by the Read method of the body once it's exceeded.
+type BodyTooLargeError struct {
+	URL         url.URL
+	MaxBodySize int64
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("response body larger than %d bytes", e.MaxBodySize)
 }
 
 type ExpBackoffRetryFetcher struct {
 	innerFetcher        Fetcher
 	numberOfRetries     int
 	delayBetweenRetries time.Duration
+	jitter              float64
+	pacing
+}
+
+// NewExpBackoffRetryFetcher creates a new ExpBackoffRetryFetcher that backs off for delayBetweenRetries
+// before the first retry, doubling it before every other one. Every back off is randomized by up to the
+// jitter, a fraction of the delay between 0 and 1, so the retries of the requests that failed together
+// don't hit the host together again. With the WithPacer option, the back off before a retry holds
+// every request to the host sharing the Pacer, not only the retried one.
+func NewExpBackoffRetryFetcher(innerFetcher Fetcher, numberOfRetries int, delayBetweenRetries time.Duration, jitter float64, opts ...PacingOption) *ExpBackoffRetryFetcher {
+	return &ExpBackoffRetryFetcher{
+		innerFetcher:        innerFetcher,
+		numberOfRetries:     numberOfRetries,
+		delayBetweenRetries: delayBetweenRetries,
+		jitter:              min(max(jitter, 0), 1),
+		pacing:              newPacing(opts),
+	}
+}
+
+func NewHTTPFetcher(httpClient httpGetter, opts ...HTTPFetcherOption) *HTTPFetcher {
+	f := &HTTPFetcher{httpClient: httpClient, errorBodySampleSize: defaultErrorBodySampleSize}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
 }
 
-func NewExpBackoffRetryFetcher(innerFetcher Fetcher, numberOfRetries int, delayBetweenRetries time.Duration) *ExpBackoffRetryFetcher {
-	return &ExpBackoffRetryFetcher{innerFetcher: innerFetcher, numberOfRetries: numberOfRetries, delayBetweenRetries: delayBetweenRetries}
+// WithErrorBodySampleSize is an option to set how many bytes of the response body are captured
+// into the UnexpectedStatusError when a request fails with an error status. 0 disables the sampling.
+func WithErrorBodySampleSize(size int) HTTPFetcherOption {
+	return func(fetcher *HTTPFetcher) {
+		fetcher.errorBodySampleSize = size
+	}
 }
 
-func NewHTTPFetcher(httpClient httpGetter) *HTTPFetcher {
-	return &HTTPFetcher{httpClient: httpClient}
+// WithContentTypes is an option to only accept the responses with one of the given media types, like
+// "text/html", or with a wildcard subtype, like "text/*", or "*/*" for any, so the bodies of PDFs, images and archives are
+// neither downloaded nor parsed. Other responses fail with an UnsupportedContentTypeError. Responses
+// without a Content-Type header are accepted. See DefaultContentTypes for the media types of HTML pages.
+func WithContentTypes(contentTypes ...string) HTTPFetcherOption {
+	return func(fetcher *HTTPFetcher) {
+		fetcher.contentTypes = append(fetcher.contentTypes, contentTypes...)
+	}
+}
+
+// WithMaxBodySize is an option to abort the downloads of the response bodies larger than the given
+// number of bytes, so a huge file doesn't blow up the memory of the process. 0 doesn't limit them.
+func WithMaxBodySize(maxBodySize int64) HTTPFetcherOption {
+	return func(fetcher *HTTPFetcher) {
+		fetcher.maxBodySize = maxBodySize
+	}
+}
+
+// WithUserAgent is an option to send the given User-Agent header instead of the default one of Go, as
+// some sites block it or serve different content to it.
+func WithUserAgent(userAgent string) HTTPFetcherOption {
+	return WithHeader("User-Agent", userAgent)
+}
+
+// WithHeader is an option to send the given header with every request, like Accept-Language or an API
+// key. It can be given many times, and the values of the same header are added up. The headers are only
+// sent if the client can send requests with headers, like *http.Client.
+//
+// Example usage:
+//
+//	httpFetcher := fetcher.NewHTTPFetcher(http.DefaultClient,
+//	    fetcher.WithHeader("Accept-Language", "en-US,en;q=0.9"),
+//	    fetcher.WithHeader("X-API-Key", apiKey),
+//	)
+func WithHeader(name, value string) HTTPFetcherOption {
+	return func(fetcher *HTTPFetcher) {
+		if fetcher.header == nil {
+			fetcher.header = make(http.Header)
+		}
+		if strings.EqualFold(name, "User-Agent") {
+			fetcher.header.Set(name, value)
+			return
+		}
+		fetcher.header.Add(name, value)
+	}
+}
+
+// WithBasicAuth is an option to authenticate every request with the given credentials with the HTTP
+// Basic scheme, to crawl the intranet and staging sites behind it. The credentials are sent like the
+// other headers, only to the hosts of the crawled URLs, as the HTTP client drops them when redirected
+// to other hosts.
+func WithBasicAuth(username, password string) HTTPFetcherOption {
+	credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return withAuthorization("Basic " + credentials)
+}
+
+// WithBearerToken is an option to authenticate every request with the given token with the Bearer
+// scheme, like an OAuth access token or an API token. See WithBasicAuth.
+func WithBearerToken(token string) HTTPFetcherOption {
+	return withAuthorization("Bearer " + token)
+}
+
+func withAuthorization(authorization string) HTTPFetcherOption {
+	return func(fetcher *HTTPFetcher) {
+		if fetcher.header == nil {
+			fetcher.header = make(http.Header)
+		}
+		fetcher.header.Set("Authorization", authorization)
+	}
+}
+
+// WithCookieJar is an option to keep the cookies set by the site, like the session ones, in the given
+// jar and send them back with the next requests, so the pages behind a login don't redirect to it
+// forever. If the client is an *http.Client, the requests are sent with a copy of it using the jar, so
+// the cookies set by the redirects are kept too. Otherwise, the cookies are only sent if the client can
+// send requests with headers, like the other headers.
+//
+// Example usage:
+//
+//	jar, _ := cookiejar.New(nil)
+//	httpFetcher := fetcher.NewHTTPFetcher(http.DefaultClient, fetcher.WithCookieJar(jar))
+func WithCookieJar(jar http.CookieJar) HTTPFetcherOption {
+	return func(fetcher *HTTPFetcher) {
+		if client, ok := fetcher.httpClient.(*http.Client); ok {
+			clientWithJar := *client
+			clientWithJar.Jar = jar
+			fetcher.httpClient = &clientWithJar
+			return
+		}
+		fetcher.cookieJar = jar
+	}
+}
+
+// WithHeadRequests is an option to send HEAD requests instead of GET ones, for the fetchers of the URLs
+// that are only validated, like the links to binary files or the assets of the pages, so their bodies
+// are not downloaded. The responses are checked like the ones of GET requests, with the status code,
+// the media type and the Content-Length, and the returned content is empty. If the server doesn't
+// support HEAD requests, responding with 405 Method Not Allowed or 501 Not Implemented, the URL is
+// fetched again with a GET request. HEAD requests are only sent if the client can send requests with
+// headers, like *http.Client, otherwise GET requests are sent.
+func WithHeadRequests() HTTPFetcherOption {
+	return func(fetcher *HTTPFetcher) {
+		fetcher.headRequests = true
+	}
 }
 
 // FetchWebpageContent fetches the content of a webpage specified by the given URL using an HTTP GET request.
 // It uses the HTTP client provided in the HTTPFetcher and returns the content as a string.
 // The method returns an error if the HTTP request fails or if there is an error reading the response body.
+// If the server responds with an error status, an UnexpectedStatusError is returned with a sample of the body.
+// If it responds with a media type not allowed by the WithContentTypes option, an UnsupportedContentTypeError
+// is returned without downloading the body. Bodies larger than the maximum set with the WithMaxBodySize option
+// fail with a BodyTooLargeError. Compressed bodies are decoded, see WithContentDecoder. With the
+// WithHeadRequests option, the webpage is validated with a HEAD request instead.
 func (f *HTTPFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
-	res, err := f.httpClient.Get(url.String())
+	result, err := f.Fetch(url)
+	return result.Body, err
+}
+
+// Fetch fetches the webpage like FetchWebpageContent, along with the status code, the headers and the
+// final URL of its response. They're also returned when the fetch fails once the response is received,
+// like with an UnexpectedStatusError.
+func (f *HTTPFetcher) Fetch(url url.URL) (FetchResult, error) {
+	return f.FetchContext(context.Background(), url)
+}
+
+// FetchContext fetches the webpage like Fetch, sending the request with the given context, so it's
+// aborted once the context is done, even while the response is being received. The context is only
+// passed to the clients that can send requests with headers, like *http.Client.
+func (f *HTTPFetcher) FetchContext(ctx context.Context, url url.URL) (FetchResult, error) {
+	res, err := f.send(ctx, url)
 	if err != nil {
-		return nil, err
+		return FetchResult{FinalURL: url}, err
+	}
+	result := FetchResult{StatusCode: res.StatusCode, Header: res.Header, FinalURL: finalURL(url, res)}
+	if res.StatusCode == http.StatusNotModified {
+		_ = res.Body.Close()
+		return result, NotModified
+	}
+	result.Body, err = f.readResponse(url, res)
+	return result, err
+}
+
+// FetchIfModified fetches the webpage like FetchContext, but with a conditional request that sends the
+// given validators in the If-None-Match and If-Modified-Since headers. It returns NotModified if the
+// server responds with 304 Not Modified, or the validators of the fetched version otherwise. The
+// validators are only sent if the client can send requests with headers, like *http.Client.
+func (f *HTTPFetcher) FetchIfModified(ctx context.Context, url url.URL, validators Validators) (FetchResult, Validators, error) {
+	res, err := f.get(ctx, url, validators)
+	if err != nil {
+		return FetchResult{FinalURL: url}, Validators{}, err
+	}
+	result := FetchResult{StatusCode: res.StatusCode, Header: res.Header, FinalURL: finalURL(url, res)}
+	if res.StatusCode == http.StatusNotModified {
+		_ = res.Body.Close()
+		return result, Validators{}, NotModified
+	}
+	result.Body, err = f.readResponse(url, res)
+	if err != nil {
+		return result, Validators{}, err
+	}
+	return result, Validators{ETag: res.Header.Get("ETag"), LastModified: res.Header.Get("Last-Modified")}, nil
+}
+
+// Resolve fetches the webpage like FetchWebpageContent, following the redirects, and returns the URL
+// the redirects ended at, without reading its body. It fails with the same errors, so it verifies the
+// URL responds with a page the fetcher accepts, like the seed of a crawl before expanding it.
+func (f *HTTPFetcher) Resolve(url url.URL) (url.URL, error) {
+	res, err := f.get(context.Background(), url, Validators{})
+	if err != nil {
+		return url, err
+	}
+	webpageContent, err := f.readResponse(url, res)
+	if err != nil {
+		return url, err
+	}
+	_ = webpageContent.Close()
+	return finalURL(url, res), nil
+}
+
+// finalURL returns the URL the redirects of the request of the response ended at.
+func finalURL(url url.URL, res *http.Response) url.URL {
+	if res.Request == nil || res.Request.URL == nil {
+		return url
+	}
+	return *res.Request.URL
+}
+
+// send sends the request of the webpage: a HEAD request with the WithHeadRequests option, sent again
+// as a GET request if the server doesn't support HEAD, or a GET request otherwise.
+func (f *HTTPFetcher) send(ctx context.Context, url url.URL) (*http.Response, error) {
+	if _, ok := f.httpClient.(httpDoer); !ok || !f.headRequests {
+		return f.get(ctx, url, Validators{})
+	}
+	res, err := f.request(ctx, http.MethodHead, url, Validators{})
+	if err != nil || (res.StatusCode != http.StatusMethodNotAllowed && res.StatusCode != http.StatusNotImplemented) {
+		return res, err
+	}
+	_ = res.Body.Close()
+	return f.get(ctx, url, Validators{})
+}
+
+// readResponse checks the status, the media type and the size of the response, and returns its decoded body.
+func (f *HTTPFetcher) readResponse(url url.URL, res *http.Response) (io.ReadCloser, error) {
+	// the responses to HEAD requests have the Content-Encoding of the body they don't have
+	if res.Request == nil || res.Request.Method != http.MethodHead {
+		body, err := f.decodedBody(url, res)
+		if err != nil {
+			_ = res.Body.Close()
+			return nil, err
+		}
+		res.Body = body
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		defer func(body io.ReadCloser) {
+			_ = body.Close()
+		}(res.Body)
+		statusErr := &UnexpectedStatusError{URL: url, StatusCode: res.StatusCode, Status: res.Status, Header: res.Header}
+		if f.errorBodySampleSize > 0 {
+			statusErr.BodySample, _ = io.ReadAll(io.LimitReader(res.Body, int64(f.errorBodySampleSize)))
+		}
+		return nil, statusErr
+	}
+
+	if contentType := res.Header.Get("Content-Type"); !f.allowedContentType(contentType) {
+		_ = res.Body.Close()
+		return nil, &UnsupportedContentTypeError{URL: url, ContentType: contentType}
+	}
+
+	if f.maxBodySize > 0 {
+		if res.ContentLength > f.maxBodySize {
+			_ = res.Body.Close()
+			return nil, &BodyTooLargeError{URL: url, MaxBodySize: f.maxBodySize}
+		}
+		return &limitedReadCloser{Reader: io.LimitReader(res.Body, f.maxBodySize+1), body: res.Body, url: url, maxBodySize: f.maxBodySize}, nil
 	}
 
 	return res.Body, nil
 }
 
+// get sends the GET request of the webpage. If the client can send requests with headers, the request
+// sends the headers set with the WithHeader option, and advertises the content encodings the fetcher
+// decodes, as some servers compress the responses anyway, the validators of a conditional request, and
+// the cookies of the jar set with the WithCookieJar option.
+func (f *HTTPFetcher) get(ctx context.Context, url url.URL, validators Validators) (*http.Response, error) {
+	if _, ok := f.httpClient.(httpDoer); !ok {
+		return f.httpClient.Get(url.String())
+	}
+	return f.request(ctx, http.MethodGet, url, validators)
+}
+
+// request sends a request of the webpage with the given method through a client that can send requests
+// with headers. See get.
+func (f *HTTPFetcher) request(ctx context.Context, method string, url url.URL, validators Validators) (*http.Response, error) {
+	doer := f.httpClient.(httpDoer)
+	req, err := http.NewRequestWithContext(ctx, method, url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range f.header {
+		req.Header[name] = append([]string(nil), values...)
+	}
+	req.Header.Set("Accept-Encoding", f.acceptEncoding())
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+	if f.cookieJar != nil {
+		for _, cookie := range f.cookieJar.Cookies(&url) {
+			req.AddCookie(cookie)
+		}
+	}
+	res, err := doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.Request == nil {
+		res.Request = req
+	}
+	if f.cookieJar != nil {
+		f.cookieJar.SetCookies(&url, res.Cookies())
+	}
+	return res, nil
+}
+
+// limitedReadCloser reads a body through an io.LimitReader of one byte more than the maximum size, and
+// fails with a BodyTooLargeError once that byte is read, so oversized bodies are not read until the end.
+type limitedReadCloser struct {
+	io.Reader
+	body        io.Closer
+	url         url.URL
+	maxBodySize int64
+	read        int64
+}
+
+func (l *limitedReadCloser) Read(p []byte) (int, error) {
+	n, err := l.Reader.Read(p)
+	l.read += int64(n)
+	if l.read > l.maxBodySize {
+		return n - int(l.read-l.maxBodySize), &BodyTooLargeError{URL: l.url, MaxBodySize: l.maxBodySize}
+	}
+	return n, err
+}
+
+func (l *limitedReadCloser) Close() error {
+	return l.body.Close()
+}
+
+// allowedContentType reports whether the media type of the Content-Type header is allowed by the
+// WithContentTypes option.
+func (f *HTTPFetcher) allowedContentType(contentType string) bool {
+	if len(f.contentTypes) == 0 || contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range f.contentTypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == mediaType || allowed == "*/*" || (strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
 // FetchWebpageContent fetches the content of a webpage specified by the given URL using an exponential backoff retry strategy.
 // It uses the innerFetcher to perform the actual fetch operation and retries fetching up to the specified number of times.
 // The method returns the webpage content as a string and nil for the error if the fetch is successful.
 // If the fetch encounters errors on all retries, the last encountered error is returned.
+// Client errors that won't change by retrying, like a 404 status, are returned right away. When a 429
+// or a 503 response tells how long to wait with a Retry-After header, that delay is waited instead.
 func (r *ExpBackoffRetryFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := r.Fetch(url)
+	return result.Body, err
+}
+
+// Fetch fetches the webpage like FetchWebpageContent, along with the response of the last attempt.
+func (r *ExpBackoffRetryFetcher) Fetch(url url.URL) (FetchResult, error) {
+	return r.FetchContext(context.Background(), url)
+}
+
+// FetchContext fetches the webpage like Fetch, passing the context to the inner fetcher. Once the context
+// is done, the failed attempts are not retried anymore and a back off ends right away, failing with the
+// error of the context.
+func (r *ExpBackoffRetryFetcher) FetchContext(ctx context.Context, url url.URL) (FetchResult, error) {
+	var lastResult FetchResult
 	var lastError error
 	for i := 1; i <= r.numberOfRetries; i++ {
-		webpageContent, err := r.innerFetcher.FetchWebpageContent(url)
+		result, err := FetchContext(ctx, r.innerFetcher, url)
 		if err != nil {
-			lastError = err
-			time.Sleep((time.Duration(i) ^ 2) * r.delayBetweenRetries)
+			result.Body = nil
+			if !isRetryable(err) {
+				return result, err
+			}
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			lastResult, lastError = result, err
+			if i == r.numberOfRetries {
+				break
+			}
+			delay := r.backoffDelay(i)
+			if retryAfter, ok := retryAfterDelay(err, time.Now()); ok {
+				delay = retryAfter
+			}
+			if err := r.backoff(ctx, url.Host, delay); err != nil {
+				return lastResult, err
+			}
 			continue
 		}
-		return webpageContent, nil
+		return result, nil
+	}
+	return lastResult, lastError
+}
+
+// backoffDelay returns the delay before the retry that follows the given attempt, counted from 1.
+func (r *ExpBackoffRetryFetcher) backoffDelay(attempt int) time.Duration {
+	// the shift is capped so the delay doesn't overflow
+	delay := r.delayBetweenRetries << min(attempt-1, 30)
+	if r.jitter == 0 {
+		return delay
+	}
+	return time.Duration(float64(delay) * (1 + r.jitter*(2*rand.Float64()-1)))
+}
+
+// backoff waits for the given delay before retrying a request to the host, unless the context is done
+// first, returning its error. With a Pacer, the other requests to the host wait for it too.
+func (r *ExpBackoffRetryFetcher) backoff(ctx context.Context, host string, delay time.Duration) error {
+	if r.pacer == nil {
+		return sleep(ctx, delay)
+	}
+	r.pacer.Backoff(host, delay)
+	return r.pacer.WaitContext(ctx, host)
+}
+
+// sleep waits for the given delay, unless the context is done first, returning its error.
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryAfterDelay returns the delay asked by the Retry-After header of a 429 or a 503 response, given
+// in seconds or as an HTTP date, reporting whether there was any.
+func retryAfterDelay(err error, now time.Time) (time.Duration, bool) {
+	var statusErr *UnexpectedStatusError
+	if !errors.As(err, &statusErr) || (statusErr.StatusCode != http.StatusTooManyRequests && statusErr.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+	retryAfter := strings.TrimSpace(statusErr.Header.Get("Retry-After"))
+	if retryAfter == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	date, err := http.ParseTime(retryAfter)
+	if err != nil {
+		return 0, false
+	}
+	return max(date.Sub(now), 0), true
+}
+
+func isRetryable(err error) bool {
+	var contentTypeErr *UnsupportedContentTypeError
+	var bodyErr *BodyTooLargeError
+	var encodingErr *UnsupportedContentEncodingError
+	var circuitErr *CircuitOpenError
+	// the deadline of a request, like the timeout of the client, comes wrapped in a *url.Error and is
+	// retried, unlike the one of the context of the fetch
+	var urlErr *url.Error
+	if errors.Is(err, context.DeadlineExceeded) && !errors.As(err, &urlErr) {
+		return false
+	}
+	if errors.Is(err, NotModified) || errors.Is(err, context.Canceled) || errors.Is(err, SessionExpired) || errors.As(err, &contentTypeErr) || errors.As(err, &bodyErr) || errors.As(err, &encodingErr) || errors.As(err, &circuitErr) {
+		return false
+	}
+	var statusErr *UnexpectedStatusError
+	if !errors.As(err, &statusErr) {
+		return true
 	}
-	return nil, lastError
+	return statusErr.StatusCode >= http.StatusInternalServerError ||
+		statusErr.StatusCode == http.StatusRequestTimeout ||
+		statusErr.StatusCode == http.StatusTooManyRequests
 }
@@ -1,9 +1,12 @@
 package fetcher
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
 	"net/url"
 	"reflect"
 	"strings"
@@ -13,12 +16,17 @@ import (
 
 type mockHttpGetter struct {
 	webpageContent string
+	statusCode     int
+	contentType    string
 	throwError     error
 }
 
 func (m mockHttpGetter) Get(_ string) (resp *http.Response, err error) {
 	return &http.Response{
-		Body: io.NopCloser(strings.NewReader(m.webpageContent)),
+		StatusCode: m.statusCode,
+		Status:     http.StatusText(m.statusCode),
+		Header:     http.Header{"Content-Type": {m.contentType}},
+		Body:       io.NopCloser(strings.NewReader(m.webpageContent)),
 	}, m.throwError
 }
 
@@ -52,6 +60,87 @@ func TestHTTPFetcher_FetchWebpageContent(t *testing.T) {
 			t.Errorf("should throw error at httpFetcher.FetchWebpageContent for mocked httpgetter")
 		}
 	})
+
+	t.Run("returns an error with a sample of the body for error statuses", func(t *testing.T) {
+		httpFetcherError := NewHTTPFetcher(mockHttpGetter{
+			webpageContent: "database connection failed",
+			statusCode:     http.StatusInternalServerError,
+		}, WithErrorBodySampleSize(8))
+		_, err := httpFetcherError.FetchWebpageContent(url.URL{})
+
+		var statusErr *UnexpectedStatusError
+		if !errors.As(err, &statusErr) {
+			t.Fatalf("FetchWebpageContent() error = %v, want an UnexpectedStatusError", err)
+		}
+		if statusErr.StatusCode != http.StatusInternalServerError || string(statusErr.BodySample) != "database" {
+			t.Errorf("FetchWebpageContent() got status %v and body sample %q, want 500 and \"database\"", statusErr.StatusCode, statusErr.BodySample)
+		}
+	})
+
+	t.Run("aborts the downloads of bodies larger than the maximum size", func(t *testing.T) {
+		httpFetcher := NewHTTPFetcher(mockHttpGetter{webpageContent: "<body>0123456789</body>"}, WithMaxBodySize(10))
+		reader, err := httpFetcher.FetchWebpageContent(url.URL{})
+		if err != nil {
+			t.Fatalf("should not throw error at FetchWebpageContent without a Content-Length. err: %v", err)
+		}
+		content, err := io.ReadAll(reader)
+		var bodyErr *BodyTooLargeError
+		if !errors.As(err, &bodyErr) || string(content) != "<body>0123" {
+			t.Errorf("ReadAll() got = %q, error = %v, want the first 10 bytes and a BodyTooLargeError", content, err)
+		}
+
+		httpFetcher = NewHTTPFetcher(mockHttpGetter{webpageContent: "<body></body>"}, WithMaxBodySize(13))
+		reader, _ = httpFetcher.FetchWebpageContent(url.URL{})
+		if content, err := io.ReadAll(reader); err != nil || string(content) != "<body></body>" {
+			t.Errorf("ReadAll() got = %q, error = %v, want the whole body", content, err)
+		}
+	})
+
+	t.Run("rejects the responses whose Content-Length exceeds the maximum size", func(t *testing.T) {
+		httpFetcher := NewHTTPFetcher(contentLengthGetter{contentLength: 4 << 30}, WithMaxBodySize(10<<20))
+		_, err := httpFetcher.FetchWebpageContent(url.URL{})
+		var bodyErr *BodyTooLargeError
+		if !errors.As(err, &bodyErr) {
+			t.Errorf("FetchWebpageContent() error = %v, want a BodyTooLargeError", err)
+		}
+	})
+
+	t.Run("skips the responses with content types not allowed", func(t *testing.T) {
+		tests := []struct {
+			contentType string
+			wantErr     bool
+		}{
+			{contentType: "text/html; charset=utf-8"},
+			{contentType: "TEXT/HTML"},
+			{contentType: "application/xhtml+xml"},
+			{contentType: ""},
+			{contentType: "application/pdf", wantErr: true},
+			{contentType: "image/png", wantErr: true},
+			{contentType: "not a media type", wantErr: true},
+		}
+		for _, tt := range tests {
+			httpFetcher := NewHTTPFetcher(mockHttpGetter{contentType: tt.contentType}, WithContentTypes(DefaultContentTypes...))
+			_, err := httpFetcher.FetchWebpageContent(url.URL{})
+
+			var contentTypeErr *UnsupportedContentTypeError
+			if errors.As(err, &contentTypeErr) != tt.wantErr {
+				t.Errorf("FetchWebpageContent() with content type %q error = %v, wantErr %v", tt.contentType, err, tt.wantErr)
+			}
+		}
+
+		httpFetcher := NewHTTPFetcher(mockHttpGetter{contentType: "text/plain"}, WithContentTypes("text/*"))
+		if _, err := httpFetcher.FetchWebpageContent(url.URL{}); err != nil {
+			t.Errorf("should not throw error at FetchWebpageContent for a wildcard content type. err: %v", err)
+		}
+	})
+}
+
+type contentLengthGetter struct {
+	contentLength int64
+}
+
+func (c contentLengthGetter) Get(_ string) (resp *http.Response, err error) {
+	return &http.Response{StatusCode: http.StatusOK, ContentLength: c.contentLength, Body: io.NopCloser(strings.NewReader(""))}, nil
 }
 
 type mockRetryFetcher struct {
@@ -67,11 +156,66 @@ func (m *mockRetryFetcher) FetchWebpageContent(_ url.URL) (io.ReadCloser, error)
 	return nil, errors.New("error")
 }
 
+type mockStatusFetcher struct {
+	statusCode int
+	fetchCalls int
+}
+
+func (m *mockStatusFetcher) FetchWebpageContent(_ url.URL) (io.ReadCloser, error) {
+	m.fetchCalls++
+	return nil, &UnexpectedStatusError{StatusCode: m.statusCode, Status: http.StatusText(m.statusCode)}
+}
+
+// retryAfterFetcher fails the first fetch with a 503 status and the given Retry-After header.
+type retryAfterFetcher struct {
+	retryAfter string
+	fetchCalls int
+}
+
+func (m *retryAfterFetcher) FetchWebpageContent(_ url.URL) (io.ReadCloser, error) {
+	m.fetchCalls++
+	if m.fetchCalls == 1 {
+		header := http.Header{"Retry-After": {m.retryAfter}}
+		return nil, &UnexpectedStatusError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable", Header: header}
+	}
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func TestRetryAfterDelay(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		statusCode int
+		retryAfter string
+		want       time.Duration
+		wantOk     bool
+	}{
+		{name: "seconds", statusCode: http.StatusTooManyRequests, retryAfter: "120", want: 2 * time.Minute, wantOk: true},
+		{name: "http date", statusCode: http.StatusServiceUnavailable, retryAfter: "Wed, 01 May 2024 12:00:30 GMT", want: 30 * time.Second, wantOk: true},
+		{name: "http date in the past", statusCode: http.StatusServiceUnavailable, retryAfter: "Wed, 01 May 2024 11:00:00 GMT", want: 0, wantOk: true},
+		{name: "invalid value", statusCode: http.StatusTooManyRequests, retryAfter: "soon"},
+		{name: "no header", statusCode: http.StatusTooManyRequests},
+		{name: "other status", statusCode: http.StatusInternalServerError, retryAfter: "120"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.retryAfter != "" {
+				header.Set("Retry-After", tt.retryAfter)
+			}
+			got, ok := retryAfterDelay(&UnexpectedStatusError{StatusCode: tt.statusCode, Header: header}, now)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("retryAfterDelay() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
 func TestExpBackoffRetryFetcher_FetchWebpageContent(t *testing.T) {
 	t.Run("should retry until it gets the result from the inner fetcher", func(t *testing.T) {
 		backoffRetryFetcher := NewExpBackoffRetryFetcher(&mockRetryFetcher{
 			numberOfRetriesToWork: 2,
-		}, 3, time.Second)
+		}, 3, time.Second, 0)
 
 		_, err := backoffRetryFetcher.FetchWebpageContent(url.URL{})
 		if err != nil {
@@ -79,14 +223,268 @@ func TestExpBackoffRetryFetcher_FetchWebpageContent(t *testing.T) {
 		}
 	})
 
+	t.Run("doesn't retry client errors", func(t *testing.T) {
+		innerFetcher := &mockStatusFetcher{statusCode: http.StatusNotFound}
+		backoffRetryFetcher := NewExpBackoffRetryFetcher(innerFetcher, 3, time.Second, 0)
+
+		_, err := backoffRetryFetcher.FetchWebpageContent(url.URL{})
+		if err == nil {
+			t.Errorf("should throw error at backoffRetryFetcher.FetchWebpageContent")
+		}
+		if innerFetcher.fetchCalls != 1 {
+			t.Errorf("inner fetcher called %v times, want 1", innerFetcher.fetchCalls)
+		}
+	})
+
+	t.Run("waits the delay of the retry-after header instead of backing off", func(t *testing.T) {
+		innerFetcher := &retryAfterFetcher{retryAfter: "0"}
+		backoffRetryFetcher := NewExpBackoffRetryFetcher(innerFetcher, 3, time.Hour, 0)
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := backoffRetryFetcher.FetchWebpageContent(url.URL{})
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if err != nil || innerFetcher.fetchCalls != 2 {
+				t.Errorf("FetchWebpageContent() error = %v after %v calls, want no error after 2 calls", err, innerFetcher.fetchCalls)
+			}
+		case <-time.After(2 * time.Second):
+			t.Error("FetchWebpageContent() backed off instead of waiting the delay of the retry-after header")
+		}
+	})
+
 	t.Run("gets error after retrying", func(t *testing.T) {
 		backoffRetryFetcher := NewExpBackoffRetryFetcher(&mockRetryFetcher{
 			numberOfRetriesToWork: 100,
-		}, 2, time.Second)
+		}, 2, time.Second, 0)
 
 		_, err := backoffRetryFetcher.FetchWebpageContent(url.URL{})
 		if err == nil {
 			t.Errorf("should throw error at backoffRetryFetcher.FetchWebpageContent")
 		}
 	})
+
+	t.Run("stops backing off once the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		backoffRetryFetcher := NewExpBackoffRetryFetcher(&mockRetryFetcher{
+			numberOfRetriesToWork: 100,
+		}, 3, time.Hour, 0)
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := backoffRetryFetcher.FetchContext(ctx, url.URL{})
+			done <- err
+		}()
+		cancel()
+		select {
+		case err := <-done:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("FetchWebpageContent() error = %v, want %v", err, context.Canceled)
+			}
+		case <-time.After(2 * time.Second):
+			t.Error("FetchWebpageContent() kept backing off after the context was canceled")
+		}
+	})
+
+	t.Run("doesn't retry once the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		innerFetcher := &mockStatusFetcher{statusCode: http.StatusServiceUnavailable}
+		backoffRetryFetcher := NewExpBackoffRetryFetcher(innerFetcher, 3, time.Hour, 0)
+
+		if _, err := backoffRetryFetcher.FetchContext(ctx, url.URL{}); !errors.Is(err, context.Canceled) || innerFetcher.fetchCalls != 1 {
+			t.Errorf("FetchContext() error = %v after %v calls, want %v after 1 call", err, innerFetcher.fetchCalls, context.Canceled)
+		}
+	})
+}
+
+func TestIsRetryable_deadlines(t *testing.T) {
+	requestTimeout := &url.Error{Op: "Get", URL: "https://test.com", Err: context.DeadlineExceeded}
+	if !isRetryable(requestTimeout) {
+		t.Errorf("isRetryable() should retry the timeout of a request")
+	}
+	if isRetryable(context.DeadlineExceeded) {
+		t.Errorf("isRetryable() should not retry once the deadline of the context is exceeded")
+	}
+	if isRetryable(context.Canceled) {
+		t.Errorf("isRetryable() should not retry once the context is canceled")
+	}
+}
+
+func TestExpBackoffRetryFetcher_backoffDelay(t *testing.T) {
+	backoffRetryFetcher := NewExpBackoffRetryFetcher(nil, 4, time.Second, 0)
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second} {
+		if got := backoffRetryFetcher.backoffDelay(attempt + 1); got != want {
+			t.Errorf("backoffDelay(%d) = %v, want %v", attempt+1, got, want)
+		}
+	}
+
+	jitteredFetcher := NewExpBackoffRetryFetcher(nil, 4, time.Second, 0.5)
+	for i := 0; i < 100; i++ {
+		if got := jitteredFetcher.backoffDelay(2); got < time.Second || got > 3*time.Second {
+			t.Fatalf("backoffDelay(2) = %v, want between 1s and 3s", got)
+		}
+	}
+}
+
+func TestHTTPFetcher_headers(t *testing.T) {
+	doer := &encodingDoer{body: []byte("<html></html>"), header: http.Header{}}
+	httpFetcher := NewHTTPFetcher(doer,
+		WithUserAgent("website-crawler/1.0"),
+		WithHeader("Accept-Language", "en-US,en;q=0.9"),
+		WithHeader("X-API-Key", "secret"),
+		WithHeader("Accept-Encoding", "br"),
+	)
+	if _, err := httpFetcher.FetchWebpageContent(url.URL{Scheme: "https", Host: "test.com"}); err != nil {
+		t.Fatalf("should not throw error at FetchWebpageContent. err: %v", err)
+	}
+
+	want := map[string]string{
+		"User-Agent":      "website-crawler/1.0",
+		"Accept-Language": "en-US,en;q=0.9",
+		"X-Api-Key":       "secret",
+		// the fetcher only advertises the encodings it decodes
+		"Accept-Encoding": "gzip, deflate, br",
+	}
+	for name, value := range want {
+		if got := doer.requestHeader.Get(name); got != value {
+			t.Errorf("FetchWebpageContent() sent %s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestHTTPFetcher_Resolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/new":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html></html>"))
+		case "/report.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	httpFetcher := NewHTTPFetcher(server.Client(), WithContentTypes(DefaultContentTypes...))
+
+	oldURL, _ := url.Parse(server.URL + "/old")
+	got, err := httpFetcher.Resolve(*oldURL)
+	if err != nil {
+		t.Fatalf("should not throw error at Resolve. err: %v", err)
+	}
+	if got.String() != server.URL+"/new" {
+		t.Errorf("Resolve() got = %v, want %v", got.String(), server.URL+"/new")
+	}
+
+	var statusErr *UnexpectedStatusError
+	missingURL, _ := url.Parse(server.URL + "/missing")
+	if _, err := httpFetcher.Resolve(*missingURL); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Resolve() error = %v, want an UnexpectedStatusError with status 404", err)
+	}
+	var contentTypeErr *UnsupportedContentTypeError
+	pdfURL, _ := url.Parse(server.URL + "/report.pdf")
+	if _, err := httpFetcher.Resolve(*pdfURL); !errors.As(err, &contentTypeErr) {
+		t.Errorf("Resolve() error = %v, want an UnsupportedContentTypeError", err)
+	}
+}
+
+func TestHTTPFetcher_FetchContext(t *testing.T) {
+	released := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the response never comes unless the request is aborted
+		select {
+		case <-r.Context().Done():
+		case <-released:
+		}
+	}))
+	defer server.Close()
+	defer close(released)
+	httpFetcher := NewHTTPFetcher(server.Client())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	link, _ := url.Parse(server.URL)
+	start := time.Now()
+	_, err := httpFetcher.FetchContext(ctx, *link)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("FetchContext() error = %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("FetchContext() took %v, want the request aborted once the context is canceled", elapsed)
+	}
+}
+
+// headerClient is a client that can send requests with headers, but is not an *http.Client.
+type headerClient struct {
+	*http.Client
+}
+
+func TestHTTPFetcher_WithCookieJar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", Path: "/"})
+			_, _ = w.Write([]byte("logged in"))
+		case "/account":
+			if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "abc123" {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte("account"))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		client httpGetter
+	}{
+		{name: "http client", client: server.Client()},
+		{name: "client sending headers", client: headerClient{Client: server.Client()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jar, _ := cookiejar.New(nil)
+			httpFetcher := NewHTTPFetcher(tt.client, WithCookieJar(jar))
+			for _, path := range []string{"/login", "/account"} {
+				link, _ := url.Parse(server.URL + path)
+				content, err := httpFetcher.FetchWebpageContent(*link)
+				if err != nil {
+					t.Fatalf("should not throw error at FetchWebpageContent of %s. err: %v", path, err)
+				}
+				_ = content.Close()
+			}
+		})
+	}
+	if server.Client().Jar != nil {
+		t.Errorf("WithCookieJar() should not set the jar of the given client")
+	}
+}
+
+func TestHTTPFetcher_auth(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  HTTPFetcherOption
+		want string
+	}{
+		{name: "basic auth", opt: WithBasicAuth("admin", "s3cret"), want: "Basic YWRtaW46czNjcmV0"},
+		{name: "bearer token", opt: WithBearerToken("eyJhbGciOiJIUzI1NiJ9"), want: "Bearer eyJhbGciOiJIUzI1NiJ9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doer := &encodingDoer{body: []byte("<html></html>"), header: http.Header{}}
+			httpFetcher := NewHTTPFetcher(doer, tt.opt)
+			if _, err := httpFetcher.FetchWebpageContent(url.URL{Scheme: "https", Host: "test.com"}); err != nil {
+				t.Fatalf("should not throw error at FetchWebpageContent. err: %v", err)
+			}
+			if got := doer.requestHeader.Get("Authorization"); got != tt.want {
+				t.Errorf("FetchWebpageContent() sent Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
 }
@@ -0,0 +1,116 @@
+package fetcher
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// InsecureLink is an http:// link found in a page of a host that is served over https.
+type InsecureLink struct {
+	// Page is the page the link was found in.
+	Page url.URL
+	// Link is the link as written in the page.
+	Link url.URL
+}
+
+// SchemeUpgrader is an http.RoundTripper decorator that learns which hosts are served over https,
+// either because they send a Strict-Transport-Security header or because they consistently redirect
+// their http:// URLs to https://, so the http:// links to them can be upgraded before fetching them.
+// Set it as the Transport of the client of the fetcher. It's safe for concurrent use.
+type SchemeUpgrader struct {
+	next          http.RoundTripper
+	mu            sync.Mutex
+	hstsHosts     map[string]bool
+	upgradedHosts map[string]bool
+	plainHosts    map[string]bool
+	insecureLinks []InsecureLink
+	// recordedLinks are the insecure links recorded, by page and link, so links found many times in
+	// the same page, or in a page fetched again, are recorded once
+	recordedLinks map[string]bool
+}
+
+// NewSchemeUpgrader creates a new SchemeUpgrader that sends the requests through the given round tripper.
+func NewSchemeUpgrader(next http.RoundTripper) *SchemeUpgrader {
+	return &SchemeUpgrader{
+		next:          next,
+		hstsHosts:     make(map[string]bool),
+		upgradedHosts: make(map[string]bool),
+		plainHosts:    make(map[string]bool),
+		recordedLinks: make(map[string]bool),
+	}
+}
+
+// RoundTrip sends the request and learns from the response whether its host is served over https.
+func (u *SchemeUpgrader) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := u.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	host := req.URL.Host
+	switch req.URL.Scheme {
+	case "https":
+		if maxAge, ok := hstsMaxAge(res.Header.Get("Strict-Transport-Security")); ok {
+			u.hstsHosts[host] = maxAge > 0
+		}
+	case "http":
+		if location, err := res.Location(); err == nil && location.Scheme == "https" && location.Host == host {
+			u.upgradedHosts[host] = true
+		} else {
+			u.plainHosts[host] = true
+		}
+	}
+	return res, nil
+}
+
+// Secure reports whether the host is known to be served over https: it sent a Strict-Transport-Security
+// header, or every http:// URL of it fetched so far redirected to https://.
+func (u *SchemeUpgrader) Secure(host string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.hstsHosts[host] || (u.upgradedHosts[host] && !u.plainHosts[host])
+}
+
+// Upgrade returns the link with the https scheme if it's an http:// link to a host served over https,
+// recording it as an insecure link of the page the first time it's found in it.
+func (u *SchemeUpgrader) Upgrade(page, link url.URL) (url.URL, bool) {
+	if link.Scheme != "http" || !u.Secure(link.Host) {
+		return link, false
+	}
+
+	u.mu.Lock()
+	if key := page.String() + " " + link.String(); !u.recordedLinks[key] {
+		u.recordedLinks[key] = true
+		u.insecureLinks = append(u.insecureLinks, InsecureLink{Page: page, Link: link})
+	}
+	u.mu.Unlock()
+
+	link.Scheme = "https"
+	return link, true
+}
+
+// InsecureLinks returns the links upgraded so far, once per page they were found in, in the order they were found.
+func (u *SchemeUpgrader) InsecureLinks() []InsecureLink {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return append([]InsecureLink(nil), u.insecureLinks...)
+}
+
+// hstsMaxAge returns the max-age directive of a Strict-Transport-Security header.
+func hstsMaxAge(header string) (int, bool) {
+	for _, directive := range strings.Split(header, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		maxAge, err := strconv.Atoi(strings.Trim(value, `"`))
+		return maxAge, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,84 @@
+package fetcher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestSchemeUpgrader(t *testing.T) {
+	hstsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer hstsServer.Close()
+	redirectingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://"+r.Host+r.URL.Path, http.StatusMovedPermanently)
+	}))
+	defer redirectingServer.Close()
+	plainServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/secure" {
+			http.Redirect(w, r, "https://"+r.Host+r.URL.Path, http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer plainServer.Close()
+
+	upgrader := NewSchemeUpgrader(hstsServer.Client().Transport)
+	client := &http.Client{Transport: upgrader, CheckRedirect: noFollow}
+	for _, link := range []string{hstsServer.URL, redirectingServer.URL + "/old", plainServer.URL + "/secure", plainServer.URL} {
+		res, err := client.Get(link)
+		if err != nil {
+			t.Fatalf("should not throw error at client.Get. err: %v", err)
+		}
+		_ = res.Body.Close()
+	}
+
+	hstsURL, _ := url.Parse(hstsServer.URL)
+	redirectingURL, _ := url.Parse(redirectingServer.URL)
+	plainURL, _ := url.Parse(plainServer.URL)
+	tests := []struct {
+		name string
+		host string
+		want bool
+	}{
+		{name: "host with HSTS", host: hstsURL.Host, want: true},
+		{name: "host redirecting to https", host: redirectingURL.Host, want: true},
+		{name: "host serving some pages over http", host: plainURL.Host, want: false},
+		{name: "unknown host", host: "other.com", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := upgrader.Secure(tt.host); got != tt.want {
+				t.Errorf("Secure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	page := url.URL{Scheme: "https", Host: redirectingURL.Host}
+	link := url.URL{Scheme: "http", Host: redirectingURL.Host, Path: "/pricing"}
+	if got, upgraded := upgrader.Upgrade(page, link); !upgraded || got.Scheme != "https" {
+		t.Errorf("Upgrade() got = %v, %v, want the https link", got.String(), upgraded)
+	}
+	if _, upgraded := upgrader.Upgrade(page, link); !upgraded {
+		t.Errorf("Upgrade() should upgrade the link found again in the same page")
+	}
+	if got, upgraded := upgrader.Upgrade(page, url.URL{Scheme: "http", Host: plainURL.Host}); upgraded {
+		t.Errorf("Upgrade() got = %v, want the link not upgraded", got.String())
+	}
+	if got := upgrader.InsecureLinks(); len(got) != 1 || got[0].Link != link || got[0].Page != page {
+		t.Errorf("InsecureLinks() got = %v, want only the upgraded link once", got)
+	}
+
+	otherPage := url.URL{Scheme: "https", Host: redirectingURL.Host, Path: "/about"}
+	_, _ = upgrader.Upgrade(otherPage, link)
+	if got := upgrader.InsecureLinks(); len(got) != 2 || got[1].Page != otherPage {
+		t.Errorf("InsecureLinks() got = %v, want the link of every page", got)
+	}
+}
+
+func noFollow(_ *http.Request, _ []*http.Request) error {
+	return http.ErrUseLastResponse
+}
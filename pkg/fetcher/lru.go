@@ -0,0 +1,145 @@
+package fetcher
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"io"
+	"net/url"
+	"sync"
+)
+
+// LRUFetcher is a fetcher decorator that keeps the most recently fetched pages in memory, along with
+// their responses, so the pages fetched more than once in the same process, like by several crawls of
+// the same site in the tests, hit the network only once. When the cache is full, the least recently
+// used page is evicted. The failed fetches are not cached. The pages are keyed by the requested URL, so
+// the URLs redirected to the same page are cached separately.
+type LRUFetcher struct {
+	innerFetcher Fetcher
+	maxPages     int
+
+	mu       sync.Mutex
+	pages    map[string]*list.Element
+	order    *list.List
+	inFlight map[string]*inFlightFetch
+}
+
+type lruPage struct {
+	url    string
+	result FetchResult
+	body   []byte
+}
+
+// inFlightFetch is a fetch of a page that other fetches of the same page wait for instead of sending
+// their own request.
+type inFlightFetch struct {
+	done   chan struct{}
+	result FetchResult
+	body   []byte
+	err    error
+}
+
+// NewLRUFetcher creates a new LRUFetcher that keeps up to maxPages pages in memory.
+func NewLRUFetcher(innerFetcher Fetcher, maxPages int) *LRUFetcher {
+	return &LRUFetcher{
+		innerFetcher: innerFetcher,
+		maxPages:     maxPages,
+		pages:        make(map[string]*list.Element),
+		order:        list.New(),
+		inFlight:     make(map[string]*inFlightFetch),
+	}
+}
+
+// FetchWebpageContent returns the cached body of the webpage, or fetches it with the inner fetcher and
+// caches it. The concurrent fetches of a page that is not cached yet wait for the first one.
+func (f *LRUFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := f.Fetch(url)
+	return result.Body, err
+}
+
+// Fetch fetches the webpage like FetchWebpageContent, along with its response, cached with the body.
+func (f *LRUFetcher) Fetch(url url.URL) (FetchResult, error) {
+	return f.FetchContext(context.Background(), url)
+}
+
+// FetchContext fetches the webpage like Fetch, passing the context to the inner fetcher. The wait for
+// the fetch of the same page by another call ends once the context is done.
+func (f *LRUFetcher) FetchContext(ctx context.Context, url url.URL) (FetchResult, error) {
+	key := url.String()
+	f.mu.Lock()
+	if element, ok := f.pages[key]; ok {
+		f.order.MoveToFront(element)
+		page := element.Value.(*lruPage)
+		f.mu.Unlock()
+		return cachedResult(page.result, page.body), nil
+	}
+	if fetch, ok := f.inFlight[key]; ok {
+		f.mu.Unlock()
+		select {
+		case <-fetch.done:
+		case <-ctx.Done():
+			return FetchResult{FinalURL: url}, ctx.Err()
+		}
+		if fetch.err != nil {
+			return cachedResult(fetch.result, nil), fetch.err
+		}
+		return cachedResult(fetch.result, fetch.body), nil
+	}
+	fetch := &inFlightFetch{done: make(chan struct{})}
+	f.inFlight[key] = fetch
+	f.mu.Unlock()
+
+	fetch.result, fetch.body, fetch.err = f.fetch(ctx, url)
+
+	f.mu.Lock()
+	delete(f.inFlight, key)
+	if fetch.err == nil {
+		f.add(key, fetch.result, fetch.body)
+	}
+	f.mu.Unlock()
+	close(fetch.done)
+
+	if fetch.err != nil {
+		return cachedResult(fetch.result, nil), fetch.err
+	}
+	return cachedResult(fetch.result, fetch.body), nil
+}
+
+// fetch fetches the webpage with the inner fetcher, returning its response without the body, and the body.
+func (f *LRUFetcher) fetch(ctx context.Context, url url.URL) (FetchResult, []byte, error) {
+	result, err := FetchContext(ctx, f.innerFetcher, url)
+	webpageContent := result.Body
+	result.Body = nil
+	if err != nil {
+		return result, nil, err
+	}
+	defer func(webpageContent io.ReadCloser) {
+		_ = webpageContent.Close()
+	}(webpageContent)
+	body, err := io.ReadAll(webpageContent)
+	return result, body, err
+}
+
+// cachedResult returns a copy of the cached response with the given body, so the callers don't share
+// its headers. A nil body is kept nil, like in the result of a failed fetch.
+func cachedResult(result FetchResult, body []byte) FetchResult {
+	result.Header = result.Header.Clone()
+	if body != nil {
+		result.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return result
+}
+
+// add caches the page, evicting the least recently used page if the cache is full.
+// It must be called with the lock held.
+func (f *LRUFetcher) add(key string, result FetchResult, body []byte) {
+	if f.maxPages <= 0 {
+		return
+	}
+	f.pages[key] = f.order.PushFront(&lruPage{url: key, result: result, body: body})
+	if f.order.Len() > f.maxPages {
+		oldest := f.order.Back()
+		f.order.Remove(oldest)
+		delete(f.pages, oldest.Value.(*lruPage).url)
+	}
+}
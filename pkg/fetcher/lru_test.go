@@ -0,0 +1,136 @@
+package fetcher
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingFetcher returns the path of the URL as the body, counting the fetches of every URL. The
+// responses are redirected to the URL with a trailing slash.
+type countingFetcher struct {
+	mu      sync.Mutex
+	fetches map[string]int
+	delay   time.Duration
+	err     error
+}
+
+func (m *countingFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := m.Fetch(url)
+	return result.Body, err
+}
+
+func (m *countingFetcher) Fetch(url url.URL) (FetchResult, error) {
+	m.mu.Lock()
+	m.fetches[url.Path]++
+	m.mu.Unlock()
+	time.Sleep(m.delay)
+	if m.err != nil {
+		return FetchResult{FinalURL: url}, m.err
+	}
+	finalURL := url
+	finalURL.Path += "/"
+	return FetchResult{
+		Body:       io.NopCloser(strings.NewReader(url.Path)),
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/html"}},
+		FinalURL:   finalURL,
+	}, nil
+}
+
+// wantCountingResponse checks the result has the response of the countingFetcher.
+func wantCountingResponse(t *testing.T, result FetchResult, link url.URL) {
+	t.Helper()
+	if result.StatusCode != http.StatusOK || result.Header.Get("Content-Type") != "text/html" || result.FinalURL.Path != link.Path+"/" {
+		t.Errorf("Fetch() got status %d, headers %v and final URL %v, want the response of the inner fetcher", result.StatusCode, result.Header, result.FinalURL.String())
+	}
+}
+
+func TestLRUFetcher_FetchWebpageContent(t *testing.T) {
+	fetchAll := func(lruFetcher *LRUFetcher, paths ...string) {
+		for _, path := range paths {
+			webpageContent, err := lruFetcher.FetchWebpageContent(url.URL{Scheme: "https", Host: "test.com", Path: path})
+			if err != nil {
+				t.Fatalf("should not throw error at FetchWebpageContent. err: %v", err)
+			}
+			if body, _ := io.ReadAll(webpageContent); string(body) != path {
+				t.Errorf("FetchWebpageContent() body = %q, want %q", body, path)
+			}
+		}
+	}
+
+	t.Run("evicts the least recently used pages", func(t *testing.T) {
+		inner := &countingFetcher{fetches: make(map[string]int)}
+		lruFetcher := NewLRUFetcher(inner, 2)
+		fetchAll(lruFetcher, "/a", "/b", "/a", "/c", "/a", "/b")
+
+		want := map[string]int{"/a": 1, "/b": 2, "/c": 1}
+		for path, wantFetches := range want {
+			if inner.fetches[path] != wantFetches {
+				t.Errorf("FetchWebpageContent() fetched %s %d times, want %d", path, inner.fetches[path], wantFetches)
+			}
+		}
+	})
+
+	t.Run("keeps the responses", func(t *testing.T) {
+		inner := &countingFetcher{fetches: make(map[string]int)}
+		lruFetcher := NewLRUFetcher(inner, 2)
+		link := url.URL{Scheme: "https", Host: "test.com", Path: "/a"}
+		for i := 0; i < 2; i++ {
+			result, err := lruFetcher.Fetch(link)
+			if err != nil {
+				t.Fatalf("should not throw error at Fetch. err: %v", err)
+			}
+			wantCountingResponse(t, result, link)
+			// the headers of the cached response are not shared with the callers
+			result.Header.Set("Content-Type", "text/plain")
+		}
+		if inner.fetches["/a"] != 1 {
+			t.Errorf("Fetch() fetched /a %d times, want 1", inner.fetches["/a"])
+		}
+	})
+
+	t.Run("does not cache the errors", func(t *testing.T) {
+		inner := &countingFetcher{fetches: make(map[string]int), err: errors.New("connection reset")}
+		lruFetcher := NewLRUFetcher(inner, 2)
+		for i := 0; i < 2; i++ {
+			if _, err := lruFetcher.FetchWebpageContent(url.URL{Scheme: "https", Host: "test.com", Path: "/a"}); err == nil {
+				t.Errorf("FetchWebpageContent() should return the error of the inner fetcher")
+			}
+		}
+		if inner.fetches["/a"] != 2 {
+			t.Errorf("FetchWebpageContent() fetched /a %d times, want 2", inner.fetches["/a"])
+		}
+	})
+
+	t.Run("fetches a page once for concurrent fetches", func(t *testing.T) {
+		inner := &countingFetcher{fetches: make(map[string]int), delay: 50 * time.Millisecond}
+		lruFetcher := NewLRUFetcher(inner, 2)
+		var wg sync.WaitGroup
+		var failed atomic.Int32
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				webpageContent, err := lruFetcher.FetchWebpageContent(url.URL{Scheme: "https", Host: "test.com", Path: "/a"})
+				if err != nil {
+					failed.Add(1)
+					return
+				}
+				if body, _ := io.ReadAll(webpageContent); string(body) != "/a" {
+					failed.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		if failed.Load() > 0 || inner.fetches["/a"] != 1 {
+			t.Errorf("FetchWebpageContent() fetched /a %d times with %d failures, want once without failures", inner.fetches["/a"], failed.Load())
+		}
+	})
+}
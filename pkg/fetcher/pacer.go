@@ -0,0 +1,85 @@
+package fetcher
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Pacer coordinates the delays of the decorators that pace the requests sent to every host, the
+// ExpBackoffRetryFetcher and the RateLimitedFetcher, when they're composed. Without it, every decorator
+// waits on its own: a retry backs off only the failing request while the other requests keep hitting
+// the host at the rate limit, and the slots the rate limit hands out during the back off add up to it.
+// With a shared Pacer, a back off holds every request to the host, and the rate limit resumes from its
+// end, so the aggregate timing of the requests is the longer of both delays instead of their sum.
+type Pacer struct {
+	mu sync.Mutex
+	// backoffs are the times until which the hosts are backed off
+	backoffs map[string]time.Time
+}
+
+// NewPacer creates a new Pacer, to be shared by the decorators of a fetcher with the WithPacer option.
+func NewPacer() *Pacer {
+	return &Pacer{backoffs: make(map[string]time.Time)}
+}
+
+// PacingOption configures how a fetcher decorator paces the requests it sends.
+type PacingOption func(pacing *pacing)
+
+type pacing struct {
+	pacer *Pacer
+}
+
+func newPacing(opts []PacingOption) pacing {
+	p := pacing{}
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return p
+}
+
+// WithPacer is an option to coordinate the delays of the decorator with the other decorators sharing
+// the given Pacer.
+func WithPacer(pacer *Pacer) PacingOption {
+	return func(pacing *pacing) {
+		pacing.pacer = pacer
+	}
+}
+
+// Backoff holds the requests to the host until the given delay has passed, unless it's already held
+// for longer.
+func (p *Pacer) Backoff(host string, delay time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if until := time.Now().Add(delay); until.After(p.backoffs[host]) {
+		p.backoffs[host] = until
+	}
+}
+
+// Wait waits until the back off of the host is over, if it's backed off.
+func (p *Pacer) Wait(host string) {
+	_ = p.WaitContext(context.Background(), host)
+}
+
+// WaitContext waits until the back off of the host is over, if it's backed off, unless the context is
+// done first, returning its error.
+func (p *Pacer) WaitContext(ctx context.Context, host string) error {
+	return sleep(ctx, p.delay(host, time.Now()))
+}
+
+// delay returns how long the host is still backed off.
+func (p *Pacer) delay(host string, now time.Time) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	until, ok := p.backoffs[host]
+	if !ok {
+		return 0
+	}
+	if !until.After(now) {
+		delete(p.backoffs, host)
+		return 0
+	}
+	return until.Sub(now)
+}
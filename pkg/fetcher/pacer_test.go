@@ -0,0 +1,71 @@
+package fetcher
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPacer(t *testing.T) {
+	t.Run("holds the requests to a backed off host", func(t *testing.T) {
+		pacer := NewPacer()
+		now := time.Now()
+		pacer.Backoff("test.com", time.Minute)
+		pacer.Backoff("test.com", time.Second)
+
+		if got := pacer.delay("test.com", now); got <= time.Second {
+			t.Errorf("delay() got = %v, want the longest back off", got)
+		}
+		if got := pacer.delay("other.com", now); got != 0 {
+			t.Errorf("delay() got = %v, want 0 for a host not backed off", got)
+		}
+		if got := pacer.delay("test.com", now.Add(2*time.Minute)); got != 0 {
+			t.Errorf("delay() got = %v, want 0 once the back off is over", got)
+		}
+	})
+
+	t.Run("rate limited requests wait for the back off of a retry", func(t *testing.T) {
+		pacer := NewPacer()
+		rateLimitedFetcher := NewRateLimitedFetcher(mockFetcher{}, 1000, 1, WithPacer(pacer))
+		var wg sync.WaitGroup
+		var otherRequestDone time.Time
+		// the first request fails, and another request to the host is sent while it's backed off
+		failingFetcher := &onceFailingFetcher{onFailure: func() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				time.Sleep(10 * time.Millisecond)
+				_, _ = rateLimitedFetcher.FetchWebpageContent(url.URL{Host: "test.com"})
+				otherRequestDone = time.Now()
+			}()
+		}}
+		retryFetcher := NewExpBackoffRetryFetcher(failingFetcher, 2, 150*time.Millisecond, 0, WithPacer(pacer))
+
+		start := time.Now()
+		if _, err := retryFetcher.FetchWebpageContent(url.URL{Host: "test.com"}); err != nil {
+			t.Fatalf("should not throw error at retryFetcher.FetchWebpageContent. err: %v", err)
+		}
+		wg.Wait()
+		// the back off of the first retry is 150ms
+		if elapsed := otherRequestDone.Sub(start); elapsed < 150*time.Millisecond {
+			t.Errorf("the other request was sent after %v, want it to wait for the back off", elapsed)
+		}
+	})
+}
+
+type onceFailingFetcher struct {
+	failed    bool
+	onFailure func()
+}
+
+func (f *onceFailingFetcher) FetchWebpageContent(_ url.URL) (io.ReadCloser, error) {
+	if f.failed {
+		return nil, nil
+	}
+	f.failed = true
+	f.onFailure()
+	return nil, errors.New("connection reset")
+}
@@ -0,0 +1,50 @@
+package fetcher
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestProxyRotator_Proxy(t *testing.T) {
+	t.Run("rotates proxies round-robin", func(t *testing.T) {
+		var proxies []url.URL
+		for _, name := range []string{"proxy-a", "proxy-b"} {
+			proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// a forward proxy receives the absolute URL of the target
+				_, _ = w.Write([]byte(name + " " + r.URL.String()))
+			}))
+			defer proxy.Close()
+			proxyURL, _ := url.Parse(proxy.URL)
+			proxies = append(proxies, *proxyURL)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = NewProxyRotator(proxies...).Proxy
+		client := &http.Client{Transport: transport}
+
+		var got []string
+		for i := 0; i < 3; i++ {
+			res, err := client.Get("http://test.com/page")
+			if err != nil {
+				t.Fatalf("should not throw error at client.Get. err: %v", err)
+			}
+			body, _ := io.ReadAll(res.Body)
+			_ = res.Body.Close()
+			got = append(got, string(body))
+		}
+		want := []string{"proxy-a http://test.com/page", "proxy-b http://test.com/page", "proxy-a http://test.com/page"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("responses got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("sends the requests directly without proxies", func(t *testing.T) {
+		proxy, err := NewProxyRotator().Proxy(&http.Request{})
+		if proxy != nil || err != nil {
+			t.Errorf("Proxy() got = %v, %v, want no proxy", proxy, err)
+		}
+	})
+}
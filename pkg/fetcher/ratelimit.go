@@ -0,0 +1,99 @@
+package fetcher
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a token bucket that hands out reservations, so concurrent callers are served
+// in the order they asked and never exceed the configured rate.
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// RateLimitedFetcher is a fetcher decorator that limits the number of requests per second sent to
+// each host. The limit is shared by every goroutine using the same RateLimitedFetcher.
+type RateLimitedFetcher struct {
+	innerFetcher      Fetcher
+	requestsPerSecond float64
+	burst             int
+	mu                sync.Mutex
+	buckets           map[string]*tokenBucket
+	pacing
+}
+
+// NewRateLimitedFetcher creates a new RateLimitedFetcher that allows up to requestsPerSecond requests
+// per second to each host, with bursts of up to burst requests. burst is set to 1 if it's lower than 1.
+// With the WithPacer option, the requests to a backed off host wait for the back off to end before
+// taking their slot, so the rate limit resumes from there.
+func NewRateLimitedFetcher(innerFetcher Fetcher, requestsPerSecond float64, burst int, opts ...PacingOption) *RateLimitedFetcher {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimitedFetcher{
+		innerFetcher:      innerFetcher,
+		requestsPerSecond: requestsPerSecond,
+		burst:             burst,
+		buckets:           make(map[string]*tokenBucket),
+		pacing:            newPacing(opts),
+	}
+}
+
+// FetchWebpageContent waits until the host of the given URL is under its rate limit, and no longer
+// backed off if the fetcher has a Pacer, and then fetches the webpage using the inner fetcher.
+func (f *RateLimitedFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := f.Fetch(url)
+	return result.Body, err
+}
+
+// Fetch fetches the webpage like FetchWebpageContent, along with its response.
+func (f *RateLimitedFetcher) Fetch(url url.URL) (FetchResult, error) {
+	return f.FetchContext(context.Background(), url)
+}
+
+// FetchContext fetches the webpage like Fetch, unless the context is done before the host is under its
+// rate limit, returning the error of the context.
+func (f *RateLimitedFetcher) FetchContext(ctx context.Context, url url.URL) (FetchResult, error) {
+	if f.pacer != nil {
+		if err := f.pacer.WaitContext(ctx, url.Host); err != nil {
+			return FetchResult{}, err
+		}
+	}
+	if err := sleep(ctx, f.reserve(url.Host, time.Now())); err != nil {
+		return FetchResult{}, err
+	}
+	return FetchContext(ctx, f.innerFetcher, url)
+}
+
+// reserve takes a token from the bucket of the host and returns how long the caller must wait
+// before using it.
+func (f *RateLimitedFetcher) reserve(host string, now time.Time) time.Duration {
+	if f.requestsPerSecond <= 0 {
+		return 0
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, ok := f.buckets[host]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(f.burst), lastRefill: now}
+		f.buckets[host] = bucket
+	}
+
+	bucket.tokens += now.Sub(bucket.lastRefill).Seconds() * f.requestsPerSecond
+	if bucket.tokens > float64(f.burst) {
+		bucket.tokens = float64(f.burst)
+	}
+	bucket.lastRefill = now
+	bucket.tokens--
+
+	if bucket.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-bucket.tokens / f.requestsPerSecond * float64(time.Second))
+}
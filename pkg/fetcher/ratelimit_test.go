@@ -0,0 +1,88 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockFetcher struct{}
+
+func (m mockFetcher) FetchWebpageContent(_ url.URL) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func TestRateLimitedFetcher_reserve(t *testing.T) {
+	now := time.Now()
+
+	t.Run("allows bursts and then spaces requests by the rate", func(t *testing.T) {
+		rateLimitedFetcher := NewRateLimitedFetcher(mockFetcher{}, 2, 2)
+
+		want := []time.Duration{0, 0, 500 * time.Millisecond, time.Second}
+		for _, wantWait := range want {
+			if got := rateLimitedFetcher.reserve("test.com", now); got != wantWait {
+				t.Errorf("reserve() got = %v, want %v", got, wantWait)
+			}
+		}
+	})
+
+	t.Run("limits every host independently", func(t *testing.T) {
+		rateLimitedFetcher := NewRateLimitedFetcher(mockFetcher{}, 1, 1)
+
+		if got := rateLimitedFetcher.reserve("test.com", now); got != 0 {
+			t.Errorf("reserve() got = %v, want 0", got)
+		}
+		if got := rateLimitedFetcher.reserve("other.com", now); got != 0 {
+			t.Errorf("reserve() got = %v, want 0", got)
+		}
+	})
+
+	t.Run("refills tokens over time", func(t *testing.T) {
+		rateLimitedFetcher := NewRateLimitedFetcher(mockFetcher{}, 1, 1)
+
+		rateLimitedFetcher.reserve("test.com", now)
+		if got := rateLimitedFetcher.reserve("test.com", now.Add(time.Second)); got != 0 {
+			t.Errorf("reserve() got = %v, want 0", got)
+		}
+	})
+}
+
+func TestRateLimitedFetcher_FetchWebpageContent(t *testing.T) {
+	rateLimitedFetcher := NewRateLimitedFetcher(mockFetcher{}, 20, 1)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := rateLimitedFetcher.FetchWebpageContent(url.URL{Host: "test.com"}); err != nil {
+			t.Errorf("should not throw error at rateLimitedFetcher.FetchWebpageContent. err: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("FetchWebpageContent() took %v for 3 requests at 20 rps, want at least 100ms", elapsed)
+	}
+}
+
+func TestRateLimitedFetcher_FetchContext(t *testing.T) {
+	pacer := NewPacer()
+	pacer.Backoff("test.com", time.Hour)
+	rateLimitedFetcher := NewRateLimitedFetcher(mockFetcher{}, 1, 1, WithPacer(pacer))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := rateLimitedFetcher.FetchContext(ctx, url.URL{Host: "test.com"})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("FetchContext() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("FetchContext() kept waiting for the backed off host after the context was done")
+	}
+}
@@ -0,0 +1,160 @@
+package fetcher
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HostRateLimit is the rate limit a host advertised in the headers of its responses.
+type HostRateLimit struct {
+	Host string
+	// Limit is the number of requests allowed per window, as last advertised, or 0 if unknown.
+	Limit int
+	// Window is the time window of the limit, or 0 if the host didn't advertise it.
+	Window time.Duration
+	// MinRemaining is the lowest number of remaining requests advertised during the crawl, or -1 if
+	// the host never advertised it. A value close to 0 means the crawl nearly hit the limit.
+	MinRemaining int
+	// Responses is the number of responses that advertised the rate limit.
+	Responses int
+}
+
+// RateLimitRecorder is an http.RoundTripper decorator that records the rate limits advertised by the
+// hosts in the X-RateLimit-* headers, the RateLimit-* headers of the IETF draft, or its combined
+// RateLimit and RateLimit-Policy headers, so the concurrency of later crawls can be tuned to what the
+// servers actually allow. Set it as the Transport of the client of the fetcher. It's safe for
+// concurrent use.
+type RateLimitRecorder struct {
+	next   http.RoundTripper
+	mu     sync.Mutex
+	limits map[string]*HostRateLimit
+}
+
+// NewRateLimitRecorder creates a new RateLimitRecorder that sends the requests through the given round tripper.
+func NewRateLimitRecorder(next http.RoundTripper) *RateLimitRecorder {
+	return &RateLimitRecorder{next: next, limits: make(map[string]*HostRateLimit)}
+}
+
+// RoundTrip sends the request and records the rate limit advertised by the response, if any.
+func (r *RateLimitRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	advertised, ok := parseRateLimitHeaders(res.Header)
+	if !ok {
+		return res, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	host := req.URL.Host
+	limit, ok := r.limits[host]
+	if !ok {
+		limit = &HostRateLimit{Host: host, MinRemaining: -1}
+		r.limits[host] = limit
+	}
+	limit.Responses++
+	if advertised.Limit > 0 {
+		limit.Limit = advertised.Limit
+	}
+	if advertised.Window > 0 {
+		limit.Window = advertised.Window
+	}
+	if advertised.MinRemaining >= 0 && (limit.MinRemaining < 0 || advertised.MinRemaining < limit.MinRemaining) {
+		limit.MinRemaining = advertised.MinRemaining
+	}
+	return res, nil
+}
+
+// RateLimits returns the rate limits advertised so far by every host, sorted by host.
+func (r *RateLimitRecorder) RateLimits() []HostRateLimit {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limits := make([]HostRateLimit, 0, len(r.limits))
+	for _, limit := range r.limits {
+		limits = append(limits, *limit)
+	}
+	sort.Slice(limits, func(i, j int) bool {
+		return limits[i].Host < limits[j].Host
+	})
+	return limits
+}
+
+// parseRateLimitHeaders parses the rate limit advertised in the headers of a response, reporting
+// whether there was any. The limit and the remaining requests are read from the X-RateLimit-Limit and
+// X-RateLimit-Remaining headers and their RateLimit-* and X-Rate-Limit-* variants, or from the
+// limit/q and remaining/r parameters of the combined RateLimit header, and the window from the w
+// parameter of the RateLimit-Policy header or of the limit itself, like "100, 100;w=60".
+func parseRateLimitHeaders(header http.Header) (HostRateLimit, bool) {
+	limit := HostRateLimit{MinRemaining: -1}
+	found := false
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-", "X-Rate-Limit-"} {
+		if value := header.Get(prefix + "Limit"); value != "" {
+			if n, ok := leadingInt(value); ok {
+				limit.Limit, found = n, true
+			}
+			if window, ok := rateLimitParameter(value, "w"); ok {
+				limit.Window = time.Duration(window) * time.Second
+			}
+		}
+		if value := header.Get(prefix + "Remaining"); value != "" {
+			if n, ok := leadingInt(value); ok {
+				limit.MinRemaining, found = n, true
+			}
+		}
+		if value := header.Get(prefix + "Policy"); value != "" {
+			if window, ok := rateLimitParameter(value, "w"); ok {
+				limit.Window = time.Duration(window) * time.Second
+			}
+			if quota, ok := rateLimitParameter(value, "q"); ok && limit.Limit == 0 {
+				limit.Limit, found = quota, true
+			}
+		}
+	}
+	if value := header.Get("RateLimit"); value != "" {
+		for _, name := range []string{"limit", "q"} {
+			if n, ok := rateLimitParameter(value, name); ok {
+				limit.Limit, found = n, true
+			}
+		}
+		for _, name := range []string{"remaining", "r"} {
+			if n, ok := rateLimitParameter(value, name); ok {
+				limit.MinRemaining, found = n, true
+			}
+		}
+	}
+	return limit, found
+}
+
+// leadingInt parses the integer a header value starts with, like the 100 of "100, 100;w=60".
+func leadingInt(value string) (int, bool) {
+	value = strings.TrimSpace(value)
+	end := strings.IndexFunc(value, func(r rune) bool { return r < '0' || r > '9' })
+	if end < 0 {
+		end = len(value)
+	}
+	n, err := strconv.Atoi(value[:end])
+	return n, err == nil
+}
+
+// rateLimitParameter returns the integer value of the name=value parameter of a header value whose
+// parameters are separated by commas or semicolons, like the w of "100;w=60".
+func rateLimitParameter(value, name string) (int, bool) {
+	for _, parameter := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		key, parameterValue, ok := strings.Cut(strings.TrimSpace(parameter), "=")
+		if !ok || !strings.EqualFold(key, name) {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.Trim(parameterValue, `"`)); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,95 @@
+package fetcher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRateLimitRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/plain" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", "10")
+		w.Header().Set("X-RateLimit-Remaining", "7")
+		if r.URL.Path == "/low" {
+			w.Header().Set("X-RateLimit-Remaining", "1")
+		}
+		w.Header().Set("RateLimit-Policy", "10;w=60")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	recorder := NewRateLimitRecorder(http.DefaultTransport)
+	client := &http.Client{Transport: recorder}
+	for _, path := range []string{"/", "/low", "/", "/plain"} {
+		res, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("should not throw error at client.Get. err: %v", err)
+		}
+		_ = res.Body.Close()
+	}
+
+	serverURL, _ := url.Parse(server.URL)
+	got := recorder.RateLimits()
+	want := []HostRateLimit{{Host: serverURL.Host, Limit: 10, Window: time.Minute, MinRemaining: 1, Responses: 3}}
+	if len(got) != 1 || got[0] != want[0] {
+		t.Errorf("RateLimits() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseRateLimitHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    HostRateLimit
+		wantOk  bool
+	}{
+		{
+			name:    "x-ratelimit headers",
+			headers: map[string]string{"X-RateLimit-Limit": "5000", "X-RateLimit-Remaining": "4987", "X-RateLimit-Reset": "1372700873"},
+			want:    HostRateLimit{Limit: 5000, MinRemaining: 4987},
+			wantOk:  true,
+		},
+		{
+			name:    "ietf draft headers with the window in the limit",
+			headers: map[string]string{"RateLimit-Limit": "100, 100;w=60", "RateLimit-Remaining": "50"},
+			want:    HostRateLimit{Limit: 100, Window: time.Minute, MinRemaining: 50},
+			wantOk:  true,
+		},
+		{
+			name:    "combined ratelimit header",
+			headers: map[string]string{"RateLimit": "limit=100, remaining=5, reset=30"},
+			want:    HostRateLimit{Limit: 100, MinRemaining: 5},
+			wantOk:  true,
+		},
+		{
+			name:    "structured ratelimit and policy headers",
+			headers: map[string]string{"RateLimit": `"default";r=20;t=30`, "RateLimit-Policy": `"default";q=100;w=10`},
+			want:    HostRateLimit{Limit: 100, Window: 10 * time.Second, MinRemaining: 20},
+			wantOk:  true,
+		},
+		{
+			name:    "no rate limit headers",
+			headers: map[string]string{"Content-Type": "text/html"},
+			want:    HostRateLimit{MinRemaining: -1},
+			wantOk:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for name, value := range tt.headers {
+				header.Set(name, value)
+			}
+			got, ok := parseRateLimitHeaders(header)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("parseRateLimitHeaders() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
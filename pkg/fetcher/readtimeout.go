@@ -0,0 +1,88 @@
+package fetcher
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ReadTimeoutError indicates that the server stopped sending the body of a response for longer than
+// the read timeout.
+type ReadTimeoutError struct {
+	URL     url.URL
+	Timeout time.Duration
+}
+
+func (e *ReadTimeoutError) Error() string {
+	return fmt.Sprintf("no data received from the body for %s", e.Timeout)
+}
+
+// ReadTimeoutTransport is an http.RoundTripper decorator that aborts the responses whose body stalls,
+// sending no data for longer than the read timeout, so a slow body doesn't hang a worker until the
+// timeout of the whole request. The wait for the response headers is bound by the ResponseHeaderTimeout
+// of the http.Transport. Set it as the Transport of the client of the fetcher.
+type ReadTimeoutTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration
+}
+
+// NewReadTimeoutTransport creates a new ReadTimeoutTransport that sends the requests through the given
+// round tripper. 0 doesn't limit the reads.
+func NewReadTimeoutTransport(next http.RoundTripper, timeout time.Duration) *ReadTimeoutTransport {
+	return &ReadTimeoutTransport{next: next, timeout: timeout}
+}
+
+// RoundTrip sends the request and returns its response, with a body that fails with a ReadTimeoutError
+// once it stalls.
+func (t *ReadTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err != nil || t.timeout <= 0 {
+		return res, err
+	}
+	body := &readTimeoutBody{ReadCloser: res.Body, url: *req.URL, timeout: t.timeout}
+	body.timer = time.AfterFunc(t.timeout, body.abort)
+	res.Body = body
+	return res, nil
+}
+
+// readTimeoutBody closes the body when the timer set with every read fires, which unblocks the read
+// waiting for data.
+type readTimeoutBody struct {
+	io.ReadCloser
+	url     url.URL
+	timeout time.Duration
+	timer   *time.Timer
+	mu      sync.Mutex
+	stalled bool
+}
+
+func (b *readTimeoutBody) abort() {
+	b.mu.Lock()
+	b.stalled = true
+	b.mu.Unlock()
+	_ = b.ReadCloser.Close()
+}
+
+func (b *readTimeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.mu.Lock()
+	stalled := b.stalled
+	b.mu.Unlock()
+	if stalled {
+		return n, &ReadTimeoutError{URL: b.url, Timeout: b.timeout}
+	}
+	if err != nil {
+		b.timer.Stop()
+		return n, err
+	}
+	b.timer.Reset(b.timeout)
+	return n, nil
+}
+
+func (b *readTimeoutBody) Close() error {
+	b.timer.Stop()
+	return b.ReadCloser.Close()
+}
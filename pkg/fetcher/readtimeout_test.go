@@ -0,0 +1,53 @@
+package fetcher
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReadTimeoutTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 3; i++ {
+			_, _ = w.Write([]byte("<p>chunk</p>"))
+			w.(http.Flusher).Flush()
+			if r.URL.Path == "/stalled" {
+				time.Sleep(300 * time.Millisecond)
+			} else {
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: NewReadTimeoutTransport(http.DefaultTransport, 100*time.Millisecond)}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "body sent steadily", path: "/steady"},
+		{name: "stalled body", path: "/stalled", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := client.Get(server.URL + tt.path)
+			if err != nil {
+				t.Fatalf("should not throw error at client.Get. err: %v", err)
+			}
+			defer func() { _ = res.Body.Close() }()
+
+			body, err := io.ReadAll(res.Body)
+			var timeoutErr *ReadTimeoutError
+			if errors.As(err, &timeoutErr) != tt.wantErr {
+				t.Errorf("ReadAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(body) != 3*len("<p>chunk</p>") {
+				t.Errorf("ReadAll() read %d bytes, want the whole body", len(body))
+			}
+		})
+	}
+}
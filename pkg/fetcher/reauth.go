@@ -0,0 +1,95 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// SessionExpired indicates that the authenticated session used to fetch a page is no longer valid,
+// usually detected because the site redirected the request to its login page.
+var SessionExpired = errors.New("session expired")
+
+type authenticateFunc func() error
+
+// ReauthFetcher is a fetcher decorator that re-authenticates when the inner fetcher reports an
+// expired session, and then fetches the page again instead of failing.
+type ReauthFetcher struct {
+	innerFetcher      Fetcher
+	authenticate      authenticateFunc
+	mu                sync.Mutex
+	sessionGeneration int
+}
+
+func NewReauthFetcher(innerFetcher Fetcher, authenticate authenticateFunc) *ReauthFetcher {
+	return &ReauthFetcher{innerFetcher: innerFetcher, authenticate: authenticate}
+}
+
+// DetectLoginRedirect returns a redirect policy for http.Client.CheckRedirect that aborts the request
+// with a SessionExpired error when it is redirected to a page that isLoginPage reports as the login page.
+//
+// Example usage:
+//
+//	client := &http.Client{CheckRedirect: DetectLoginRedirect(func(link url.URL) bool {
+//	    return strings.HasPrefix(link.Path, "/login")
+//	})}
+func DetectLoginRedirect(isLoginPage func(link url.URL) bool) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if isLoginPage(*req.URL) {
+			return SessionExpired
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+}
+
+// FetchWebpageContent fetches the content of a webpage using the inner fetcher. If the session expired,
+// it re-authenticates and fetches the page once more. When many pages detect the expired session at the
+// same time, only one of them re-authenticates.
+func (f *ReauthFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := f.Fetch(url)
+	return result.Body, err
+}
+
+// Fetch fetches the webpage like FetchWebpageContent, along with its response.
+func (f *ReauthFetcher) Fetch(url url.URL) (FetchResult, error) {
+	return f.FetchContext(context.Background(), url)
+}
+
+// FetchContext fetches the webpage like Fetch, passing the context to the inner fetcher.
+func (f *ReauthFetcher) FetchContext(ctx context.Context, url url.URL) (FetchResult, error) {
+	f.mu.Lock()
+	sessionGeneration := f.sessionGeneration
+	f.mu.Unlock()
+
+	result, err := FetchContext(ctx, f.innerFetcher, url)
+	if !errors.Is(err, SessionExpired) {
+		return result, err
+	}
+
+	if err := f.reauthenticate(sessionGeneration); err != nil {
+		return FetchResult{FinalURL: url}, err
+	}
+	return FetchContext(ctx, f.innerFetcher, url)
+}
+
+func (f *ReauthFetcher) reauthenticate(expiredSessionGeneration int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// another fetch already re-authenticated after this session expired
+	if f.sessionGeneration != expiredSessionGeneration {
+		return nil
+	}
+	if err := f.authenticate(); err != nil {
+		return fmt.Errorf("re-authenticating after session expired: %w", err)
+	}
+	f.sessionGeneration++
+	return nil
+}
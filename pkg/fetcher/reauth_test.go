@@ -0,0 +1,78 @@
+package fetcher
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type mockSessionFetcher struct {
+	sessionValid bool
+}
+
+func (m *mockSessionFetcher) FetchWebpageContent(_ url.URL) (io.ReadCloser, error) {
+	if !m.sessionValid {
+		return nil, SessionExpired
+	}
+	return io.NopCloser(strings.NewReader("<p>private</p>")), nil
+}
+
+func TestReauthFetcher_FetchWebpageContent(t *testing.T) {
+	t.Run("re-authenticates and fetches again when the session expired", func(t *testing.T) {
+		innerFetcher := &mockSessionFetcher{}
+		authenticateCalls := 0
+		reauthFetcher := NewReauthFetcher(innerFetcher, func() error {
+			authenticateCalls++
+			innerFetcher.sessionValid = true
+			return nil
+		})
+
+		_, err := reauthFetcher.FetchWebpageContent(url.URL{})
+		if err != nil {
+			t.Errorf("should not throw error at reauthFetcher.FetchWebpageContent. err: %v", err)
+		}
+		if authenticateCalls != 1 {
+			t.Errorf("authenticate called %v times, want 1", authenticateCalls)
+		}
+	})
+
+	t.Run("returns an error when re-authentication fails", func(t *testing.T) {
+		reauthFetcher := NewReauthFetcher(&mockSessionFetcher{}, func() error {
+			return errors.New("invalid credentials")
+		})
+
+		_, err := reauthFetcher.FetchWebpageContent(url.URL{})
+		if err == nil {
+			t.Errorf("should throw error at reauthFetcher.FetchWebpageContent")
+		}
+	})
+}
+
+func TestDetectLoginRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/private" {
+			http.Redirect(w, r, "/login?next=/private", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("<p>login</p>"))
+	}))
+	defer server.Close()
+
+	httpFetcher := NewHTTPFetcher(&http.Client{CheckRedirect: DetectLoginRedirect(func(link url.URL) bool {
+		return link.Path == "/login"
+	})})
+	privateUrl, _ := url.Parse(server.URL + "/private")
+
+	_, err := httpFetcher.FetchWebpageContent(*privateUrl)
+	if !errors.Is(err, SessionExpired) {
+		t.Errorf("FetchWebpageContent() error = %v, want %v", err, SessionExpired)
+	}
+	// the re-authentication is outside the retries, which would only delay it
+	if isRetryable(err) {
+		t.Errorf("isRetryable() should not retry a SessionExpired error")
+	}
+}
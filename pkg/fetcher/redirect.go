@@ -0,0 +1,78 @@
+package fetcher
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// maxRedirects is the number of redirects followed before giving up, the same as the default of http.Client.
+const maxRedirects = 10
+
+// TooManyRedirects is returned when a request is redirected more than maxRedirects times.
+var TooManyRedirects = errors.New("stopped after 10 redirects")
+
+// Redirect is a URL that was redirected while fetching it.
+type Redirect struct {
+	// From is the requested URL.
+	From url.URL
+	// To is the URL the redirects ended at.
+	To url.URL
+	// StatusCode is the status of the first redirect, e.g. 301.
+	StatusCode int
+	// Hops is the number of redirects followed.
+	Hops int
+}
+
+// RedirectTracker records the redirects followed by an http.Client. Set its CheckRedirect
+// method as the CheckRedirect function of the client.
+type RedirectTracker struct {
+	mu        sync.Mutex
+	redirects map[string]*Redirect
+	order     []string
+}
+
+func NewRedirectTracker() *RedirectTracker {
+	return &RedirectTracker{redirects: make(map[string]*Redirect)}
+}
+
+// CheckRedirect records the redirect and follows it, up to 10 redirects like the default policy
+// of http.Client.
+func (t *RedirectTracker) CheckRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return TooManyRedirects
+	}
+
+	from := *via[0].URL
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	redirect, ok := t.redirects[from.String()]
+	if !ok || len(via) == 1 {
+		redirect = &Redirect{From: from}
+		if req.Response != nil {
+			redirect.StatusCode = req.Response.StatusCode
+		}
+		if !ok {
+			t.order = append(t.order, from.String())
+		}
+		t.redirects[from.String()] = redirect
+	}
+	redirect.To = *req.URL
+	redirect.Hops = len(via)
+	return nil
+}
+
+// Redirects returns the recorded redirects, in the order they were first followed. If a URL was
+// fetched many times, only its last redirects are kept.
+func (t *RedirectTracker) Redirects() []Redirect {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	redirects := make([]Redirect, len(t.order))
+	for i, from := range t.order {
+		redirects[i] = *t.redirects[from]
+	}
+	return redirects
+}
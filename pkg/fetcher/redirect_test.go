@@ -0,0 +1,50 @@
+package fetcher
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirectTracker_CheckRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/older", http.StatusMovedPermanently)
+		case "/older":
+			http.Redirect(w, r, "/", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	tracker := NewRedirectTracker()
+	client := &http.Client{CheckRedirect: tracker.CheckRedirect}
+
+	for _, path := range []string{"/", "/old", "/old"} {
+		res, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("should not throw error at client.Get. err: %v", err)
+		}
+		_ = res.Body.Close()
+	}
+	if _, err := client.Get(server.URL + "/loop"); !errors.Is(err, TooManyRedirects) {
+		t.Errorf("client.Get() error = %v, want %v", err, TooManyRedirects)
+	}
+
+	redirects := tracker.Redirects()
+	if len(redirects) != 2 {
+		t.Fatalf("Redirects() got %d redirects, want 2", len(redirects))
+	}
+	got := redirects[0]
+	if got.From.String() != server.URL+"/old" || got.To.String() != server.URL+"/" || got.StatusCode != http.StatusMovedPermanently || got.Hops != 2 {
+		t.Errorf("Redirects() got = %+v, want /old -> / with status 301 in 2 hops", got)
+	}
+	if got := redirects[1]; got.From.String() != server.URL+"/loop" || got.Hops != 9 {
+		t.Errorf("Redirects() got = %+v, want /loop with 9 hops followed", got)
+	}
+}
@@ -0,0 +1,73 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// FetchResult is a fetched webpage along with the response it came in.
+type FetchResult struct {
+	// Body is the content of the webpage, nil if the fetch failed.
+	Body io.ReadCloser
+	// StatusCode is the status code of the response, 0 if no response was received.
+	StatusCode int
+	// Header holds the headers of the response.
+	Header http.Header
+	// FinalURL is the URL the redirects ended at, the requested one if there were none.
+	FinalURL url.URL
+}
+
+// ResultFetcher is implemented by the fetchers that return the status code, the headers and the final
+// URL of the responses along with the webpages, like the HTTPFetcher, the decorators that pass them
+// through and the caching decorators, which keep them along with the bodies.
+type ResultFetcher interface {
+	Fetcher
+	Fetch(url url.URL) (FetchResult, error)
+}
+
+// Fetch fetches the webpage with the given fetcher, along with the response it came in if the fetcher
+// is a ResultFetcher. Otherwise, the result of a webpage fetched successfully has a 200 status code and
+// the requested URL as its final URL, and the status code and the headers of an UnexpectedStatusError
+// are kept in the result of a failed fetch.
+//
+// Example usage:
+//
+//	result, err := fetcher.Fetch(pageFetcher, *link)
+//	if err == nil && result.FinalURL != *link {
+//	    fmt.Println("Redirected to:", result.FinalURL.String())
+//	}
+func Fetch(webpageFetcher Fetcher, url url.URL) (FetchResult, error) {
+	if resultFetcher, ok := webpageFetcher.(ResultFetcher); ok {
+		return resultFetcher.Fetch(url)
+	}
+	webpageContent, err := webpageFetcher.FetchWebpageContent(url)
+	result := FetchResult{Body: webpageContent, FinalURL: url}
+	var statusErr *UnexpectedStatusError
+	switch {
+	case errors.As(err, &statusErr):
+		result.StatusCode = statusErr.StatusCode
+		result.Header = statusErr.Header
+	case err == nil:
+		result.StatusCode = http.StatusOK
+	}
+	return result, err
+}
+
+// ContextFetcher is implemented by the fetchers that take the context of every fetch: the ones that wait
+// before fetching, like the back offs of the ExpBackoffRetryFetcher or the RateLimitedFetcher, so the waits
+// end once the context of the call is done, the HTTPFetcher, whose requests are aborted, and the decorators
+// that pass it through.
+type ContextFetcher interface {
+	FetchContext(ctx context.Context, url url.URL) (FetchResult, error)
+}
+
+// FetchContext fetches the webpage like Fetch, passing the context to the fetcher if it's a ContextFetcher.
+func FetchContext(ctx context.Context, webpageFetcher Fetcher, url url.URL) (FetchResult, error) {
+	if contextFetcher, ok := webpageFetcher.(ContextFetcher); ok {
+		return contextFetcher.FetchContext(ctx, url)
+	}
+	return Fetch(webpageFetcher, url)
+}
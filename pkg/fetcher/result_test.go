@@ -0,0 +1,58 @@
+package fetcher
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/new":
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("X-Served-By", "test")
+			_, _ = w.Write([]byte("<html></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	// the decorators pass the responses of the HTTPFetcher through
+	pageFetcher := Chain(NewHTTPFetcher(server.Client()), WithRetry(2, time.Millisecond, 0), WithCircuitBreaker(5, time.Minute), WithMetrics(NewStatsFetcher(nil)))
+
+	oldURL, _ := url.Parse(server.URL + "/old")
+	result, err := Fetch(pageFetcher, *oldURL)
+	if err != nil {
+		t.Fatalf("should not throw error at Fetch. err: %v", err)
+	}
+	_ = result.Body.Close()
+	if result.StatusCode != http.StatusOK || result.Header.Get("X-Served-By") != "test" || result.FinalURL.String() != server.URL+"/new" {
+		t.Errorf("Fetch() got = %+v, want the response of %s", result, server.URL+"/new")
+	}
+
+	missingURL, _ := url.Parse(server.URL + "/missing")
+	result, err = Fetch(pageFetcher, *missingURL)
+	var statusErr *UnexpectedStatusError
+	if !errors.As(err, &statusErr) || result.StatusCode != http.StatusNotFound || result.Body != nil {
+		t.Errorf("Fetch() got = %+v, %v, want the 404 response without a body", result, err)
+	}
+
+	t.Run("fetchers without responses", func(t *testing.T) {
+		link := url.URL{Scheme: "https", Host: "test.com"}
+		result, err := Fetch(mockFetcher{}, link)
+		if err != nil || result.Body == nil || result.StatusCode != http.StatusOK || result.FinalURL != link {
+			t.Errorf("Fetch() got = %+v, %v, want a 200 result for the requested URL", result, err)
+		}
+
+		result, err = Fetch(&mockStatusFetcher{statusCode: http.StatusGone}, link)
+		if err == nil || result.StatusCode != http.StatusGone {
+			t.Errorf("Fetch() got = %+v, %v, want the status code of the error", result, err)
+		}
+	})
+}
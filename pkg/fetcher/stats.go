@@ -0,0 +1,116 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HostStats are the statistics of the webpages fetched from a host.
+type HostStats struct {
+	Host string
+	// Pages is the number of webpages fetched, including the ones that failed.
+	Pages int
+	// Errors is the number of webpages that failed to be fetched. The responses skipped for their
+	// content type are not errors.
+	Errors int
+	// TotalLatency is the sum of the time every fetch took until the response was received.
+	TotalLatency time.Duration
+	// Bytes is the number of bytes read from the bodies of the webpages.
+	Bytes int64
+}
+
+// AverageLatency returns the average time a fetch took until the response was received.
+func (s HostStats) AverageLatency() time.Duration {
+	if s.Pages == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Pages)
+}
+
+// StatsFetcher is a fetcher decorator that gathers statistics of the fetched webpages per host,
+// useful for crawls spanning several subdomains or domains. It's safe for concurrent use.
+type StatsFetcher struct {
+	innerFetcher Fetcher
+	mu           sync.Mutex
+	hosts        map[string]*HostStats
+}
+
+// NewStatsFetcher creates a new StatsFetcher that fetches the webpages using the given fetcher.
+func NewStatsFetcher(innerFetcher Fetcher) *StatsFetcher {
+	return &StatsFetcher{innerFetcher: innerFetcher, hosts: make(map[string]*HostStats)}
+}
+
+// FetchWebpageContent fetches the webpage using the inner fetcher, recording the latency and the
+// outcome of the fetch. The bytes of the body are counted as they're read.
+func (f *StatsFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := f.Fetch(url)
+	return result.Body, err
+}
+
+// Fetch fetches the webpage like FetchWebpageContent, along with its response.
+func (f *StatsFetcher) Fetch(url url.URL) (FetchResult, error) {
+	return f.FetchContext(context.Background(), url)
+}
+
+// FetchContext fetches the webpage like Fetch, passing the context to the inner fetcher.
+func (f *StatsFetcher) FetchContext(ctx context.Context, url url.URL) (FetchResult, error) {
+	start := time.Now()
+	result, err := FetchContext(ctx, f.innerFetcher, url)
+	latency := time.Since(start)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stats, ok := f.hosts[url.Host]
+	if !ok {
+		stats = &HostStats{Host: url.Host}
+		f.hosts[url.Host] = stats
+	}
+	stats.Pages++
+	stats.TotalLatency += latency
+	var contentTypeErr *UnsupportedContentTypeError
+	if err != nil && !errors.As(err, &contentTypeErr) {
+		stats.Errors++
+	}
+	if err != nil {
+		result.Body = nil
+		return result, err
+	}
+	result.Body = &countingReadCloser{ReadCloser: result.Body, fetcher: f, stats: stats}
+	return result, nil
+}
+
+// Stats returns the statistics gathered so far of every host, sorted by host.
+func (f *StatsFetcher) Stats() []HostStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stats := make([]HostStats, 0, len(f.hosts))
+	for _, hostStats := range f.hosts {
+		stats = append(stats, *hostStats)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Host < stats[j].Host
+	})
+	return stats
+}
+
+// countingReadCloser adds the bytes read from a body to the statistics of its host.
+type countingReadCloser struct {
+	io.ReadCloser
+	fetcher *StatsFetcher
+	stats   *HostStats
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.fetcher.mu.Lock()
+	c.stats.Bytes += int64(n)
+	c.fetcher.mu.Unlock()
+	return n, err
+}
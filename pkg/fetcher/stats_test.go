@@ -0,0 +1,45 @@
+package fetcher
+
+import (
+	"io"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestStatsFetcher_FetchWebpageContent(t *testing.T) {
+	statsFetcher := NewStatsFetcher(&mockSiteFetcher{pages: map[string]string{
+		"https://test.com":      "<body>home</body>",
+		"https://test.com/blog": "<body>blog</body>",
+		"https://docs.test.com": "<body>docs</body>",
+	}})
+
+	for _, link := range []string{"https://test.com", "https://test.com/blog", "https://test.com/missing", "https://docs.test.com"} {
+		parsedLink, _ := url.Parse(link)
+		content, err := statsFetcher.FetchWebpageContent(*parsedLink)
+		if err != nil {
+			continue
+		}
+		if _, err := io.ReadAll(content); err != nil {
+			t.Fatalf("should not throw error at io.ReadAll. err: %v", err)
+		}
+		_ = content.Close()
+	}
+
+	got := statsFetcher.Stats()
+	if len(got) != 2 {
+		t.Fatalf("Stats() got %d hosts, want 2", len(got))
+	}
+	want := []HostStats{
+		{Host: "docs.test.com", Pages: 1, Errors: 0, Bytes: 17},
+		{Host: "test.com", Pages: 3, Errors: 1, Bytes: 34},
+	}
+	for i := range want {
+		if got[i].Host != want[i].Host || got[i].Pages != want[i].Pages || got[i].Errors != want[i].Errors || got[i].Bytes != want[i].Bytes {
+			t.Errorf("Stats() got = %+v, want %+v", got[i], want[i])
+		}
+		if got[i].AverageLatency() != got[i].TotalLatency/time.Duration(got[i].Pages) {
+			t.Errorf("AverageLatency() got = %v", got[i].AverageLatency())
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package fetcher
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// TransportOption tunes the http.Transport built by NewTransport.
+type TransportOption func(transport *http.Transport)
+
+// NewTransport creates a clone of http.DefaultTransport tuned with the given options, to use as the
+// Transport of the client of an HTTPFetcher. The default transport keeps 2 idle connections per host,
+// so a crawl with many concurrent workers opens and closes connections to the host all the time. Use
+// WithMaxIdleConnsPerHost to keep one for every worker.
+//
+// Example usage:
+//
+//	transport := fetcher.NewTransport(fetcher.WithMaxIdleConnsPerHost(50), fetcher.WithMaxConnsPerHost(50))
+//	httpFetcher := fetcher.NewHTTPFetcher(&http.Client{Timeout: 15 * time.Second, Transport: transport})
+func NewTransport(opts ...TransportOption) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	for _, opt := range opts {
+		opt(transport)
+	}
+	return transport
+}
+
+// WithMaxIdleConnsPerHost is an option to keep up to the given number of idle connections to every host
+// for the next requests, raising the limit of idle connections to all the hosts if it's lower. 0 keeps
+// the default of 2.
+func WithMaxIdleConnsPerHost(maxIdleConnsPerHost int) TransportOption {
+	return func(transport *http.Transport) {
+		transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+		if transport.MaxIdleConns > 0 && transport.MaxIdleConns < maxIdleConnsPerHost {
+			transport.MaxIdleConns = maxIdleConnsPerHost
+		}
+	}
+}
+
+// WithMaxConnsPerHost is an option to limit the connections to every host, counting the ones in use and
+// the idle ones, so the requests over the limit wait for a connection. 0 doesn't limit them.
+func WithMaxConnsPerHost(maxConnsPerHost int) TransportOption {
+	return func(transport *http.Transport) {
+		transport.MaxConnsPerHost = maxConnsPerHost
+	}
+}
+
+// WithTLSHandshakeTimeout is an option to limit the time the TLS handshakes take. 0 doesn't limit it.
+func WithTLSHandshakeTimeout(timeout time.Duration) TransportOption {
+	return func(transport *http.Transport) {
+		transport.TLSHandshakeTimeout = timeout
+	}
+}
+
+// WithHTTP2 is an option to enable or disable HTTP/2. It's enabled by default, multiplexing the requests
+// to a host over a single connection, which makes the connection limits per host moot. Disable it to
+// spread the requests over many HTTP/1.1 connections, or for the servers with a broken HTTP/2 support.
+func WithHTTP2(enabled bool) TransportOption {
+	return func(transport *http.Transport) {
+		transport.ForceAttemptHTTP2 = enabled
+		if enabled {
+			transport.TLSNextProto = nil
+			return
+		}
+		// a non-nil empty map disables HTTP/2
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+		if transport.TLSClientConfig != nil {
+			transport.TLSClientConfig.NextProtos = nil
+		}
+	}
+}
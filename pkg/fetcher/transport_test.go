@@ -0,0 +1,42 @@
+package fetcher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewTransport(t *testing.T) {
+	transport := NewTransport(WithMaxIdleConnsPerHost(200), WithMaxConnsPerHost(50), WithTLSHandshakeTimeout(time.Second))
+	if transport.MaxIdleConnsPerHost != 200 || transport.MaxIdleConns != 200 || transport.MaxConnsPerHost != 50 || transport.TLSHandshakeTimeout != time.Second {
+		t.Errorf("NewTransport() got = %+v, want the tuned limits and timeout", transport)
+	}
+	if transport == http.DefaultTransport {
+		t.Errorf("NewTransport() should not tune the default transport")
+	}
+
+	t.Run("HTTP/2", func(t *testing.T) {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.Proto))
+		}))
+		server.EnableHTTP2 = true
+		server.StartTLS()
+		defer server.Close()
+
+		trustServer := func(transport *http.Transport) {
+			transport.TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+		}
+		for _, enabled := range []bool{true, false} {
+			transport := NewTransport(trustServer, WithHTTP2(enabled))
+			res, err := (&http.Client{Transport: transport}).Get(server.URL)
+			if err != nil {
+				t.Fatalf("should not throw error at Get. err: %v", err)
+			}
+			_ = res.Body.Close()
+			if got := res.ProtoMajor == 2; got != enabled {
+				t.Errorf("WithHTTP2(%v) sent the request with %s", enabled, res.Proto)
+			}
+		}
+	})
+}
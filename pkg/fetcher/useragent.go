@@ -0,0 +1,70 @@
+package fetcher
+
+import (
+	"math/rand"
+	"net/http"
+	"sync"
+)
+
+// UserAgentRotation is the order a UserAgentRotator sends its user agents in.
+type UserAgentRotation int
+
+const (
+	// RoundRobinUserAgents sends the user agents one after the other, in the given order.
+	RoundRobinUserAgents UserAgentRotation = iota
+	// RandomUserAgents sends a user agent picked at random with every request.
+	RandomUserAgents
+)
+
+// UserAgentRotator is an http.RoundTripper decorator that sends every request with the next of a list
+// of User-Agent headers, replacing the one set by the fetcher, to crawl the sites that vary their
+// content or throttle by user agent. Set it as the Transport of the client of the fetcher. It's safe
+// for concurrent use.
+type UserAgentRotator struct {
+	next       http.RoundTripper
+	userAgents []string
+	rotation   UserAgentRotation
+	mu         sync.Mutex
+	sent       int
+	random     *rand.Rand
+}
+
+// NewUserAgentRotator creates a new UserAgentRotator that sends the requests through the given round
+// tripper with the given user agents, in the given rotation. With no user agents, the requests are
+// sent unchanged.
+//
+// Example usage:
+//
+//	rotator := fetcher.NewUserAgentRotator(http.DefaultTransport, fetcher.RoundRobinUserAgents,
+//		"Mozilla/5.0 (Windows NT 10.0; Win64; x64)", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)")
+//	client := &http.Client{Transport: rotator}
+func NewUserAgentRotator(next http.RoundTripper, rotation UserAgentRotation, userAgents ...string) *UserAgentRotator {
+	return &UserAgentRotator{
+		next:       next,
+		userAgents: userAgents,
+		rotation:   rotation,
+		random:     rand.New(rand.NewSource(rand.Int63())),
+	}
+}
+
+// RoundTrip sends a copy of the request with the next user agent.
+func (r *UserAgentRotator) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(r.userAgents) == 0 {
+		return r.next.RoundTrip(req)
+	}
+	rotatedReq := req.Clone(req.Context())
+	rotatedReq.Header.Set("User-Agent", r.nextUserAgent())
+	return r.next.RoundTrip(rotatedReq)
+}
+
+func (r *UserAgentRotator) nextUserAgent() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.rotation == RandomUserAgents {
+		return r.userAgents[r.random.Intn(len(r.userAgents))]
+	}
+	userAgent := r.userAgents[r.sent%len(r.userAgents)]
+	r.sent++
+	return userAgent
+}
@@ -0,0 +1,81 @@
+package fetcher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestUserAgentRotator(t *testing.T) {
+	userAgents := []string{"desktop-agent", "mobile-agent", "tablet-agent"}
+	tests := []struct {
+		name     string
+		rotation UserAgentRotation
+		agents   []string
+		check    func(t *testing.T, got []string)
+	}{
+		{
+			name:     "round robin",
+			rotation: RoundRobinUserAgents,
+			agents:   userAgents,
+			check: func(t *testing.T, got []string) {
+				want := []string{"desktop-agent", "mobile-agent", "tablet-agent", "desktop-agent", "mobile-agent", "tablet-agent"}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("user agents got = %v, want %v", got, want)
+				}
+			},
+		},
+		{
+			name:     "random",
+			rotation: RandomUserAgents,
+			agents:   userAgents,
+			check: func(t *testing.T, got []string) {
+				for _, userAgent := range got {
+					if userAgent != "desktop-agent" && userAgent != "mobile-agent" && userAgent != "tablet-agent" {
+						t.Errorf("user agent got = %v, want one of %v", userAgent, userAgents)
+					}
+				}
+			},
+		},
+		{
+			name:     "no user agents keeps the one of the fetcher",
+			rotation: RoundRobinUserAgents,
+			check: func(t *testing.T, got []string) {
+				for _, userAgent := range got {
+					if userAgent != "fetcher-agent" {
+						t.Errorf("user agent got = %v, want fetcher-agent", userAgent)
+					}
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var got []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				got = append(got, r.UserAgent())
+				mu.Unlock()
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: NewUserAgentRotator(http.DefaultTransport, tt.rotation, tt.agents...)}
+			for i := 0; i < 6; i++ {
+				req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+				req.Header.Set("User-Agent", "fetcher-agent")
+				res, err := client.Do(req)
+				if err != nil {
+					t.Fatalf("should not throw error at client.Do. err: %v", err)
+				}
+				_ = res.Body.Close()
+				if req.UserAgent() != "fetcher-agent" {
+					t.Fatalf("the request of the caller should not be modified. got = %v", req.UserAgent())
+				}
+			}
+			tt.check(t, got)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package fetcher
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// DefaultBinaryExtensions are the extensions of the files that are not webpages, like documents, images,
+// media and archives, whose links only need to be validated.
+var DefaultBinaryExtensions = []string{
+	".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx",
+	".jpg", ".jpeg", ".png", ".gif", ".webp", ".avif", ".svg", ".ico",
+	".mp3", ".mp4", ".webm", ".mov", ".avi",
+	".zip", ".gz", ".tgz", ".rar", ".7z", ".dmg", ".exe", ".iso",
+}
+
+// ValidationFetcher is a fetcher decorator that fetches the URLs that only need to be validated, the
+// ones whose path ends with one of its extensions, like the links to PDFs and images, with the
+// validation fetcher, usually an HTTPFetcher with the WithHeadRequests option, and the webpages to parse
+// with the page fetcher. It cuts the bandwidth of the crawls that check many links to binary files.
+type ValidationFetcher struct {
+	pageFetcher       Fetcher
+	validationFetcher Fetcher
+	extensions        map[string]bool
+}
+
+// NewValidationFetcher creates a new ValidationFetcher that validates the URLs with the given extensions,
+// like DefaultBinaryExtensions, with the validationFetcher. The extensions are compared case-insensitively.
+//
+// Example usage:
+//
+//	headFetcher := fetcher.NewHTTPFetcher(http.DefaultClient, fetcher.WithHeadRequests())
+//	pageFetcher := fetcher.NewValidationFetcher(fetcher.NewHTTPFetcher(http.DefaultClient), headFetcher,
+//	    fetcher.DefaultBinaryExtensions...)
+func NewValidationFetcher(pageFetcher, validationFetcher Fetcher, extensions ...string) *ValidationFetcher {
+	validatedExtensions := make(map[string]bool, len(extensions))
+	for _, extension := range extensions {
+		validatedExtensions[strings.ToLower(extension)] = true
+	}
+	return &ValidationFetcher{pageFetcher: pageFetcher, validationFetcher: validationFetcher, extensions: validatedExtensions}
+}
+
+// FetchWebpageContent fetches the webpage using the validation fetcher if it only needs to be validated,
+// or using the page fetcher otherwise.
+func (f *ValidationFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	return f.fetcherOf(url).FetchWebpageContent(url)
+}
+
+// Fetch fetches the webpage like FetchWebpageContent, along with its response.
+func (f *ValidationFetcher) Fetch(url url.URL) (FetchResult, error) {
+	return Fetch(f.fetcherOf(url), url)
+}
+
+// FetchContext fetches the webpage like Fetch, passing the context to the fetcher of the URL.
+func (f *ValidationFetcher) FetchContext(ctx context.Context, url url.URL) (FetchResult, error) {
+	return FetchContext(ctx, f.fetcherOf(url), url)
+}
+
+func (f *ValidationFetcher) fetcherOf(url url.URL) Fetcher {
+	if f.extensions[strings.ToLower(path.Ext(url.Path))] {
+		return f.validationFetcher
+	}
+	return f.pageFetcher
+}
@@ -0,0 +1,74 @@
+package fetcher
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestValidationFetcher_FetchWebpageContent(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/report.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write([]byte(strings.Repeat("%PDF", 100)))
+		case "/legacy.zip":
+			// a server that doesn't support HEAD requests
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			_, _ = w.Write([]byte("PK"))
+		case "/about":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html>about</html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	validationFetcher := NewValidationFetcher(NewHTTPFetcher(server.Client()), NewHTTPFetcher(server.Client(), WithHeadRequests()), ".pdf", ".ZIP")
+
+	link := func(path string) url.URL {
+		parsedLink, _ := url.Parse(server.URL + path)
+		return *parsedLink
+	}
+	content, err := validationFetcher.FetchWebpageContent(link("/report.pdf"))
+	if err != nil {
+		t.Fatalf("should not throw error at FetchWebpageContent. err: %v", err)
+	}
+	if body, _ := io.ReadAll(content); len(body) != 0 {
+		t.Errorf("FetchWebpageContent() content got = %q, want it empty", string(body))
+	}
+	if _, err := validationFetcher.FetchWebpageContent(link("/legacy.zip")); err != nil {
+		t.Errorf("should not throw error at FetchWebpageContent. err: %v", err)
+	}
+	var statusErr *UnexpectedStatusError
+	if _, err := validationFetcher.FetchWebpageContent(link("/missing.pdf")); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("FetchWebpageContent() error = %v, want a 404 UnexpectedStatusError", err)
+	}
+	content, err = validationFetcher.FetchWebpageContent(link("/about"))
+	if err != nil {
+		t.Fatalf("should not throw error at FetchWebpageContent. err: %v", err)
+	}
+	if body, _ := io.ReadAll(content); string(body) != "<html>about</html>" {
+		t.Errorf("FetchWebpageContent() content got = %q, want the page", string(body))
+	}
+
+	want := []string{"HEAD /report.pdf", "HEAD /legacy.zip", "GET /legacy.zip", "HEAD /missing.pdf", "GET /about"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests got = %v, want %v", requests, want)
+	}
+}
@@ -0,0 +1,103 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// VariantWorksError is returned by the VariantFetcher when a URL responds with 404 Not Found but
+// one of its trivial variants can be fetched, which usually points to a missing redirect.
+type VariantWorksError struct {
+	URL     url.URL
+	Variant url.URL
+	Err     error
+}
+
+func (e *VariantWorksError) Error() string {
+	return fmt.Sprintf("%v, works with variant %s", e.Err, e.Variant.String())
+}
+
+func (e *VariantWorksError) Unwrap() error {
+	return e.Err
+}
+
+// VariantFetcher is a fetcher decorator that, when a URL responds with 404 Not Found, tries
+// trivial variants of it: toggling the trailing slash, appending index.html and lower-casing
+// the path. It's useful to catch the redirects missed after a site migration.
+type VariantFetcher struct {
+	innerFetcher Fetcher
+}
+
+func NewVariantFetcher(innerFetcher Fetcher) *VariantFetcher {
+	return &VariantFetcher{innerFetcher: innerFetcher}
+}
+
+// FetchWebpageContent fetches the webpage using the inner fetcher. If it responds with 404 Not Found,
+// the variants of the URL are fetched in order, and the first one that works is reported in a
+// VariantWorksError wrapping the original error. The page is still reported as failed, as the
+// link itself is broken.
+func (f *VariantFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	result, err := f.Fetch(url)
+	return result.Body, err
+}
+
+// Fetch fetches the webpage like FetchWebpageContent, along with its response. The result of a broken
+// link is the one of the link, not of its variant.
+func (f *VariantFetcher) Fetch(url url.URL) (FetchResult, error) {
+	return f.FetchContext(context.Background(), url)
+}
+
+// FetchContext fetches the webpage like Fetch, passing the context to the inner fetcher.
+func (f *VariantFetcher) FetchContext(ctx context.Context, url url.URL) (FetchResult, error) {
+	result, err := FetchContext(ctx, f.innerFetcher, url)
+	var statusErr *UnexpectedStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		return result, err
+	}
+	result.Body = nil
+
+	for _, variant := range urlVariants(url) {
+		variantResult, variantErr := FetchContext(ctx, f.innerFetcher, variant)
+		if variantErr != nil {
+			continue
+		}
+		_ = variantResult.Body.Close()
+		return result, &VariantWorksError{URL: url, Variant: variant, Err: err}
+	}
+	return result, err
+}
+
+// urlVariants returns the trivial variants of the given URL, skipping the ones equal to it.
+func urlVariants(link url.URL) []url.URL {
+	path := link.Path
+	if path == "" {
+		path = "/"
+	}
+
+	var paths []string
+	if strings.HasSuffix(path, "/") {
+		if path != "/" {
+			paths = append(paths, strings.TrimSuffix(path, "/"))
+		}
+		paths = append(paths, path+"index.html")
+	} else {
+		paths = append(paths, path+"/", path+"/index.html")
+	}
+	if lowerPath := strings.ToLower(path); lowerPath != path {
+		paths = append(paths, lowerPath)
+	}
+
+	variants := make([]url.URL, 0, len(paths))
+	for _, variantPath := range paths {
+		variant := link
+		variant.Path = variantPath
+		variant.RawPath = ""
+		variants = append(variants, variant)
+	}
+	return variants
+}
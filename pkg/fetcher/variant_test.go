@@ -0,0 +1,100 @@
+package fetcher
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type mockSiteFetcher struct {
+	pages   map[string]string
+	fetched []string
+}
+
+func (m *mockSiteFetcher) FetchWebpageContent(url url.URL) (io.ReadCloser, error) {
+	m.fetched = append(m.fetched, url.String())
+	content, ok := m.pages[url.String()]
+	if !ok {
+		return nil, &UnexpectedStatusError{URL: url, StatusCode: http.StatusNotFound, Status: "404 Not Found"}
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+func TestVariantFetcher_FetchWebpageContent(t *testing.T) {
+	t.Run("reports the variant that works on 404", func(t *testing.T) {
+		site := &mockSiteFetcher{pages: map[string]string{"https://test.com/about/": "about"}}
+		variantFetcher := NewVariantFetcher(site)
+
+		_, err := variantFetcher.FetchWebpageContent(url.URL{Scheme: "https", Host: "test.com", Path: "/about"})
+
+		var variantErr *VariantWorksError
+		if !errors.As(err, &variantErr) {
+			t.Fatalf("FetchWebpageContent() error = %v, want a VariantWorksError", err)
+		}
+		if got := variantErr.Variant.String(); got != "https://test.com/about/" {
+			t.Errorf("VariantWorksError.Variant got = %v, want https://test.com/about/", got)
+		}
+		var statusErr *UnexpectedStatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+			t.Errorf("FetchWebpageContent() error should wrap the original 404 error")
+		}
+	})
+
+	t.Run("returns the original error when no variant works", func(t *testing.T) {
+		site := &mockSiteFetcher{pages: map[string]string{}}
+		variantFetcher := NewVariantFetcher(site)
+
+		_, err := variantFetcher.FetchWebpageContent(url.URL{Scheme: "https", Host: "test.com", Path: "/About"})
+
+		var variantErr *VariantWorksError
+		if errors.As(err, &variantErr) {
+			t.Errorf("FetchWebpageContent() error = %v, want the original error", err)
+		}
+		want := []string{"https://test.com/About", "https://test.com/About/", "https://test.com/About/index.html", "https://test.com/about"}
+		if !reflect.DeepEqual(site.fetched, want) {
+			t.Errorf("fetched got = %v, want %v", site.fetched, want)
+		}
+	})
+
+	t.Run("doesn't try variants of pages that work", func(t *testing.T) {
+		site := &mockSiteFetcher{pages: map[string]string{"https://test.com/": "home"}}
+		variantFetcher := NewVariantFetcher(site)
+
+		content, err := variantFetcher.FetchWebpageContent(url.URL{Scheme: "https", Host: "test.com", Path: "/"})
+		if err != nil {
+			t.Fatalf("should not throw error at variantFetcher.FetchWebpageContent. err: %v", err)
+		}
+		_ = content.Close()
+		if len(site.fetched) != 1 {
+			t.Errorf("fetched got = %v, want only the page", site.fetched)
+		}
+	})
+}
+
+func Test_urlVariants(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want []string
+	}{
+		{name: "without trailing slash", link: "https://test.com/Blog", want: []string{"https://test.com/Blog/", "https://test.com/Blog/index.html", "https://test.com/blog"}},
+		{name: "with trailing slash", link: "https://test.com/blog/", want: []string{"https://test.com/blog", "https://test.com/blog/index.html"}},
+		{name: "root", link: "https://test.com", want: []string{"https://test.com/index.html"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, _ := url.Parse(tt.link)
+			var got []string
+			for _, variant := range urlVariants(*link) {
+				got = append(got, variant.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("urlVariants() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package fingerprint
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+// Fingerprint identifies a well-known error page and the likely cause of the error.
+type Fingerprint struct {
+	Name  string
+	Cause string
+}
+
+// Detector recognizes the error pages of a hosting provider or CDN.
+type Detector struct {
+	Fingerprint Fingerprint
+	Matches     func(statusCode int, header http.Header, body string) bool
+}
+
+// Detectors is the list of detectors used by Identify, in order of precedence.
+// Custom detectors can be appended to it.
+var Detectors = []Detector{
+	{
+		Fingerprint: Fingerprint{Name: "s3-no-such-key", Cause: "S3 object missing"},
+		Matches: func(statusCode int, _ http.Header, body string) bool {
+			return strings.Contains(body, "<Code>NoSuchKey</Code>")
+		},
+	},
+	{
+		Fingerprint: Fingerprint{Name: "s3-no-such-bucket", Cause: "S3 bucket missing"},
+		Matches: func(statusCode int, _ http.Header, body string) bool {
+			return strings.Contains(body, "<Code>NoSuchBucket</Code>")
+		},
+	},
+	{
+		Fingerprint: Fingerprint{Name: "s3-access-denied", Cause: "S3 access denied, the object is missing or private"},
+		Matches: func(statusCode int, header http.Header, body string) bool {
+			return strings.Contains(body, "<Code>AccessDenied</Code>") && header.Get("X-Amz-Request-Id") != ""
+		},
+	},
+	{
+		Fingerprint: Fingerprint{Name: "github-pages-no-site", Cause: "GitHub Pages site not configured for this domain"},
+		Matches: func(statusCode int, header http.Header, body string) bool {
+			return strings.Contains(body, "There isn't a GitHub Pages site here")
+		},
+	},
+	{
+		Fingerprint: Fingerprint{Name: "github-pages-404", Cause: "GitHub Pages file missing"},
+		Matches: func(statusCode int, header http.Header, body string) bool {
+			return statusCode == http.StatusNotFound && strings.EqualFold(header.Get("Server"), "GitHub.com")
+		},
+	},
+	{
+		Fingerprint: Fingerprint{Name: "netlify-404", Cause: "Netlify page not found, the file is missing from the deploy"},
+		Matches: func(statusCode int, header http.Header, body string) bool {
+			return statusCode == http.StatusNotFound && strings.EqualFold(header.Get("Server"), "Netlify")
+		},
+	},
+	{
+		Fingerprint: Fingerprint{Name: "vercel-404", Cause: "Vercel deployment has no file for this path"},
+		Matches: func(statusCode int, header http.Header, body string) bool {
+			return statusCode == http.StatusNotFound && header.Get("X-Vercel-Error") == "NOT_FOUND"
+		},
+	},
+	{
+		Fingerprint: Fingerprint{Name: "heroku-no-such-app", Cause: "Heroku app doesn't exist"},
+		Matches: func(statusCode int, header http.Header, body string) bool {
+			return strings.Contains(body, "herokucdn.com/error-pages/no-such-app.html")
+		},
+	},
+	cloudflareDetector(520, "origin server returned an unknown error"),
+	cloudflareDetector(521, "origin web server is down"),
+	cloudflareDetector(522, "connection to the origin server timed out"),
+	cloudflareDetector(523, "origin server is unreachable"),
+	cloudflareDetector(524, "origin server timed out responding"),
+	cloudflareDetector(525, "SSL handshake with the origin server failed"),
+	cloudflareDetector(526, "origin server has an invalid SSL certificate"),
+	cloudflareDetector(http.StatusBadGateway, "origin server returned an invalid response"),
+	cloudflareDetector(http.StatusGatewayTimeout, "origin server timed out"),
+}
+
+// Identify returns the fingerprint of the first detector that recognizes the error page.
+func Identify(statusCode int, header http.Header, bodySample []byte) (Fingerprint, bool) {
+	if header == nil {
+		header = http.Header{}
+	}
+	body := string(bodySample)
+	for _, detector := range Detectors {
+		if detector.Matches(statusCode, header, body) {
+			return detector.Fingerprint, true
+		}
+	}
+	return Fingerprint{}, false
+}
+
+// IdentifyError identifies the error page carried by an fetcher.UnexpectedStatusError.
+func IdentifyError(err error) (Fingerprint, bool) {
+	var statusErr *fetcher.UnexpectedStatusError
+	if !errors.As(err, &statusErr) {
+		return Fingerprint{}, false
+	}
+	return Identify(statusErr.StatusCode, statusErr.Header, statusErr.BodySample)
+}
+
+func cloudflareDetector(statusCode int, cause string) Detector {
+	return Detector{
+		Fingerprint: Fingerprint{Name: "cloudflare-" + strconv.Itoa(statusCode), Cause: "Cloudflare: " + cause},
+		Matches: func(responseStatusCode int, header http.Header, _ string) bool {
+			return responseStatusCode == statusCode && strings.EqualFold(header.Get("Server"), "cloudflare")
+		},
+	}
+}
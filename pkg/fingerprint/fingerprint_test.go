@@ -0,0 +1,78 @@
+package fingerprint
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+func TestIdentify(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		header     http.Header
+		body       string
+		want       string
+		wantFound  bool
+	}{
+		{
+			name:       "identifies missing S3 objects",
+			statusCode: http.StatusNotFound,
+			body:       `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`,
+			want:       "s3-no-such-key",
+			wantFound:  true,
+		},
+		{
+			name:       "identifies GitHub Pages 404s by server header",
+			statusCode: http.StatusNotFound,
+			header:     http.Header{"Server": {"GitHub.com"}},
+			want:       "github-pages-404",
+			wantFound:  true,
+		},
+		{
+			name:       "identifies Netlify 404s",
+			statusCode: http.StatusNotFound,
+			header:     http.Header{"Server": {"Netlify"}},
+			want:       "netlify-404",
+			wantFound:  true,
+		},
+		{
+			name:       "identifies Cloudflare origin errors",
+			statusCode: 522,
+			header:     http.Header{"Server": {"cloudflare"}},
+			want:       "cloudflare-522",
+			wantFound:  true,
+		},
+		{
+			name:       "doesn't identify generic errors",
+			statusCode: http.StatusInternalServerError,
+			header:     http.Header{"Server": {"nginx"}},
+			body:       "<h1>Internal Server Error</h1>",
+			wantFound:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := Identify(tt.statusCode, tt.header, []byte(tt.body))
+			if found != tt.wantFound {
+				t.Fatalf("Identify() found = %v, want %v", found, tt.wantFound)
+			}
+			if got.Name != tt.want {
+				t.Errorf("Identify() got = %v, want %v", got.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentifyError(t *testing.T) {
+	statusErr := &fetcher.UnexpectedStatusError{
+		StatusCode: 521,
+		Header:     http.Header{"Server": {"cloudflare"}},
+	}
+	got, found := IdentifyError(fmt.Errorf("crawling page: %w", statusErr))
+	if !found || got.Cause != "Cloudflare: origin web server is down" {
+		t.Errorf("IdentifyError() got = %v, found = %v", got, found)
+	}
+}
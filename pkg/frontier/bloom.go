@@ -0,0 +1,158 @@
+package frontier
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// bloomFilter is a space-efficient probabilistic set. It can report false positives,
+// but never false negatives.
+type bloomFilter struct {
+	bits      []uint64
+	numBits   uint64
+	numHashes uint64
+}
+
+func newBloomFilter(expectedItems int, falsePositiveRate float64) *bloomFilter {
+	if expectedItems < 1 {
+		expectedItems = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.01
+	}
+	numBits := uint64(math.Ceil(-float64(expectedItems) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	numHashes := uint64(math.Max(1, math.Round(float64(numBits)/float64(expectedItems)*math.Ln2)))
+	return &bloomFilter{bits: make([]uint64, (numBits+63)/64), numBits: numBits, numHashes: numHashes}
+}
+
+// add adds the item to the set and reports whether it was not in the set before.
+func (b *bloomFilter) add(item string) bool {
+	h1, h2 := hashes(item)
+	added := false
+	for i := uint64(0); i < b.numHashes; i++ {
+		bit := (h1 + i*h2) % b.numBits
+		if b.bits[bit/64]&(1<<(bit%64)) == 0 {
+			b.bits[bit/64] |= 1 << (bit % 64)
+			added = true
+		}
+	}
+	return added
+}
+
+func (b *bloomFilter) contains(item string) bool {
+	h1, h2 := hashes(item)
+	for i := uint64(0); i < b.numHashes; i++ {
+		bit := (h1 + i*h2) % b.numBits
+		if b.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hashes returns two independent hashes of the item, combined to simulate k hash functions
+// as described by Kirsch and Mitzenmacher.
+func hashes(item string) (uint64, uint64) {
+	h := fnv.New128a()
+	_, _ = h.Write([]byte(item))
+	sum := h.Sum(nil)
+	return mix(binary.BigEndian.Uint64(sum[:8])), mix(binary.BigEndian.Uint64(sum[8:])) | 1
+}
+
+// mix is the MurmurHash3 finalizer, which spreads the bits FNV leaves correlated for similar items.
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// BloomStore is a Store that remembers the found and visited links using Bloom filters, so its memory
+// use is bounded no matter how many links are found. The price is that it can mistake a new link for
+// one found before, with the configured false positive rate, and skip it. It also doesn't keep the
+// links themselves, so Links always returns an empty list: use WithLinkFoundCallback to collect them.
+type BloomStore struct {
+	mu                sync.Mutex
+	expectedLinks     int
+	falsePositiveRate float64
+	found             *bloomFilter
+	visited           *bloomFilter
+	frontier          *MemoryStore
+	count             int
+}
+
+// NewBloomStore creates a new BloomStore sized for the expected number of links with the given
+// false positive rate (e.g. 0.001). Finding more links than expected increases the false positive rate.
+func NewBloomStore(expectedLinks int, falsePositiveRate float64) *BloomStore {
+	return &BloomStore{
+		expectedLinks:     expectedLinks,
+		falsePositiveRate: falsePositiveRate,
+		found:             newBloomFilter(expectedLinks, falsePositiveRate),
+		visited:           newBloomFilter(expectedLinks, falsePositiveRate),
+		frontier:          NewMemoryStore(),
+	}
+}
+
+func (s *BloomStore) MarkFound(link string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	isNew := s.found.add(link)
+	if isNew {
+		s.count++
+	}
+	return isNew, nil
+}
+
+func (s *BloomStore) MarkVisited(link string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.found.add(link) {
+		s.count++
+	}
+	return s.visited.add(link), nil
+}
+
+func (s *BloomStore) Push(depth int, links ...string) error {
+	return s.frontier.Push(depth, links...)
+}
+
+func (s *BloomStore) Pop(depth int, n int) ([]string, error) {
+	return s.frontier.Pop(depth, n)
+}
+
+// Links always returns an empty list, as the Bloom filters don't keep the links.
+func (s *BloomStore) Links() ([]string, error) {
+	return []string{}, nil
+}
+
+// Count returns the approximate number of links found or visited.
+func (s *BloomStore) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.count
+}
+
+func (s *BloomStore) SaveCrawlInfo(info CrawlInfo) error {
+	return s.frontier.SaveCrawlInfo(info)
+}
+
+func (s *BloomStore) LoadCrawlInfo() (*CrawlInfo, error) {
+	return s.frontier.LoadCrawlInfo()
+}
+
+func (s *BloomStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.found = newBloomFilter(s.expectedLinks, s.falsePositiveRate)
+	s.visited = newBloomFilter(s.expectedLinks, s.falsePositiveRate)
+	s.count = 0
+	return s.frontier.Clear()
+}
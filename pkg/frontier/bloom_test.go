@@ -0,0 +1,67 @@
+package frontier
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestBloomStore(t *testing.T) {
+	t.Run("found and visited links are only new once", func(t *testing.T) {
+		store := NewBloomStore(1000, 0.001)
+
+		if isNew, _ := store.MarkFound("https://test.com/contact"); !isNew {
+			t.Errorf("MarkFound() should report a new link")
+		}
+		if isNew, _ := store.MarkFound("https://test.com/contact"); isNew {
+			t.Errorf("MarkFound() should not report an already found link")
+		}
+		if firstVisit, _ := store.MarkVisited("https://test.com/contact"); !firstVisit {
+			t.Errorf("MarkVisited() should report the first visit of a found link")
+		}
+		if firstVisit, _ := store.MarkVisited("https://test.com/contact"); firstVisit {
+			t.Errorf("MarkVisited() should not report an already visited link")
+		}
+		_, _ = store.MarkVisited("https://test.com")
+		if isNew, _ := store.MarkFound("https://test.com"); isNew {
+			t.Errorf("MarkFound() should not report an already visited link")
+		}
+		if count := store.Count(); count != 2 {
+			t.Errorf("Count() got = %v, want 2", count)
+		}
+		if links, _ := store.Links(); len(links) != 0 {
+			t.Errorf("Links() got = %v, want no links", links)
+		}
+	})
+
+	t.Run("keeps the false positive rate for the expected links", func(t *testing.T) {
+		store := NewBloomStore(10000, 0.01)
+		for i := 0; i < 10000; i++ {
+			_, _ = store.MarkFound(fmt.Sprintf("https://test.com/page/%d", i))
+		}
+
+		falsePositives := 0
+		for i := 0; i < 10000; i++ {
+			if store.found.contains(fmt.Sprintf("https://test.com/other/%d", i)) {
+				falsePositives++
+			}
+		}
+		if falsePositives > 200 {
+			t.Errorf("got %d false positives out of 10000 links, want about 100", falsePositives)
+		}
+	})
+
+	t.Run("pops links in the order they were pushed and clears the state", func(t *testing.T) {
+		store := NewBloomStore(100, 0.01)
+		_ = store.Push(1, "a", "b")
+		_, _ = store.MarkFound("a")
+
+		if got, _ := store.Pop(1, 5); !reflect.DeepEqual(got, []string{"a", "b"}) {
+			t.Errorf("Pop() got = %v, want [a b]", got)
+		}
+		_ = store.Clear()
+		if isNew, _ := store.MarkFound("a"); !isNew {
+			t.Errorf("MarkFound() should report a new link after Clear()")
+		}
+	})
+}
@@ -0,0 +1,233 @@
+package frontier
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// linksBucket maps the links found to whether they were visited
+	linksBucket = []byte("links")
+	// frontiersBucket holds a bucket per depth level, mapping increasing sequence numbers to the links
+	// pending to be crawled, so they're popped in the order they were pushed
+	frontiersBucket = []byte("frontiers")
+	metaBucket      = []byte("meta")
+	crawlInfoKey    = []byte("crawl_info")
+)
+
+var (
+	linkFound   = []byte{0}
+	linkVisited = []byte{1}
+)
+
+// boltOpenTimeout is the time to wait for the lock of a state file used by another crawler process.
+const boltOpenTimeout = time.Second
+
+// BoltStore is a Store that persists the state of the crawl to a BoltDB file, an embedded key/value
+// database, so a crawl can be resumed after the process restarts. Every change is committed to the file
+// as it happens, without waiting for the disk to flush it, which survives the crawler process dying but
+// not the whole system crashing. Only one process can open the file at a time.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens the BoltDB file at path, creating it if it doesn't exist.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: boltOpenTimeout, NoSync: true})
+	if err != nil {
+		return nil, err
+	}
+	store := &BoltStore{db: db}
+	if err := db.Update(createBuckets); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func createBuckets(tx *bolt.Tx) error {
+	for _, name := range [][]byte{linksBucket, frontiersBucket, metaBucket} {
+		if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BoltStore) MarkFound(link string) (bool, error) {
+	isNew := false
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		links := tx.Bucket(linksBucket)
+		if links.Get([]byte(link)) != nil {
+			return nil
+		}
+		isNew = true
+		return links.Put([]byte(link), linkFound)
+	})
+	return isNew, err
+}
+
+func (s *BoltStore) MarkVisited(link string) (bool, error) {
+	firstVisit := false
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		links := tx.Bucket(linksBucket)
+		if visited := links.Get([]byte(link)); visited != nil && visited[0] == linkVisited[0] {
+			return nil
+		}
+		firstVisit = true
+		return links.Put([]byte(link), linkVisited)
+	})
+	return firstVisit, err
+}
+
+func (s *BoltStore) Push(depth int, links ...string) error {
+	if len(links) == 0 {
+		return nil
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		frontier, err := tx.Bucket(frontiersBucket).CreateBucketIfNotExists(depthKey(depth))
+		if err != nil {
+			return err
+		}
+		for _, link := range links {
+			sequence, err := frontier.NextSequence()
+			if err != nil {
+				return err
+			}
+			if err := frontier.Put(sequenceKey(sequence), []byte(link)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *BoltStore) Pop(depth int, n int) ([]string, error) {
+	popped := make([]string, 0)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		frontiers := tx.Bucket(frontiersBucket)
+		frontier := frontiers.Bucket(depthKey(depth))
+		if frontier == nil {
+			return nil
+		}
+		cursor := frontier.Cursor()
+		for key, link := cursor.First(); key != nil && len(popped) < n; key, link = cursor.First() {
+			popped = append(popped, string(link))
+			if err := cursor.Delete(); err != nil {
+				return err
+			}
+		}
+		if key, _ := cursor.First(); key == nil {
+			return frontiers.DeleteBucket(depthKey(depth))
+		}
+		return nil
+	})
+	return popped, err
+}
+
+func (s *BoltStore) Links() ([]string, error) {
+	var links []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(linksBucket).ForEach(func(link, _ []byte) error {
+			links = append(links, string(link))
+			return nil
+		})
+	})
+	return links, err
+}
+
+func (s *BoltStore) SaveCrawlInfo(info CrawlInfo) error {
+	encodedInfo, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(metaBucket).Put(crawlInfoKey, encodedInfo)
+	})
+}
+
+func (s *BoltStore) LoadCrawlInfo() (*CrawlInfo, error) {
+	var info *CrawlInfo
+	err := s.db.View(func(tx *bolt.Tx) error {
+		encodedInfo := tx.Bucket(metaBucket).Get(crawlInfoKey)
+		if encodedInfo == nil {
+			return NoCrawlInfo
+		}
+		info = &CrawlInfo{}
+		return json.Unmarshal(encodedInfo, info)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (s *BoltStore) Snapshot() (State, error) {
+	state := State{Frontiers: make(map[int][]string)}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if encodedInfo := tx.Bucket(metaBucket).Get(crawlInfoKey); encodedInfo != nil {
+			state.CrawlInfo = &CrawlInfo{}
+			if err := json.Unmarshal(encodedInfo, state.CrawlInfo); err != nil {
+				return err
+			}
+		}
+		err := tx.Bucket(linksBucket).ForEach(func(link, visited []byte) error {
+			if visited[0] == linkVisited[0] {
+				state.Visited = append(state.Visited, string(link))
+			} else {
+				state.Found = append(state.Found, string(link))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(frontiersBucket).ForEachBucket(func(key []byte) error {
+			depth, err := strconv.Atoi(string(key))
+			if err != nil {
+				return err
+			}
+			return tx.Bucket(frontiersBucket).Bucket(key).ForEach(func(_, link []byte) error {
+				state.Frontiers[depth] = append(state.Frontiers[depth], string(link))
+				return nil
+			})
+		})
+	})
+	return state, err
+}
+
+// Clear removes all the state of the store.
+func (s *BoltStore) Clear() error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{linksBucket, frontiersBucket, metaBucket} {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+		return createBuckets(tx)
+	})
+}
+
+// Close flushes the file to disk and closes it.
+func (s *BoltStore) Close() error {
+	if err := s.db.Sync(); err != nil {
+		_ = s.db.Close()
+		return err
+	}
+	return s.db.Close()
+}
+
+func depthKey(depth int) []byte {
+	return []byte(strconv.Itoa(depth))
+}
+
+// sequenceKey encodes the sequence number in big endian, so the keys are sorted by it.
+func sequenceKey(sequence uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, sequence)
+	return key
+}
@@ -0,0 +1,59 @@
+package frontier
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBoltStore(t *testing.T) {
+	t.Run("state survives reopening the store", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "crawl.state")
+		store, err := NewBoltStore(path)
+		if err != nil {
+			t.Fatalf("should not throw error at NewBoltStore. err: %v", err)
+		}
+		_ = store.SaveCrawlInfo(CrawlInfo{URL: "https://test.com", Depth: 3, MaxConcurrency: 2, CurrentDepth: 1})
+		_, _ = store.MarkVisited("https://test.com")
+		_, _ = store.MarkFound("https://test.com/contact")
+		_ = store.Push(1, "https://test.com/contact", "https://test.com/about-us")
+		_, _ = store.Pop(1, 1)
+		if err := store.Close(); err != nil {
+			t.Fatalf("should not throw error at Close. err: %v", err)
+		}
+
+		reopenedStore, err := NewBoltStore(path)
+		if err != nil {
+			t.Fatalf("should not throw error at NewBoltStore. err: %v", err)
+		}
+		defer func() { _ = reopenedStore.Close() }()
+
+		info, err := reopenedStore.LoadCrawlInfo()
+		if err != nil || info.CurrentDepth != 1 || info.Depth != 3 {
+			t.Errorf("LoadCrawlInfo() got = %+v, err = %v", info, err)
+		}
+		if links, _ := reopenedStore.Links(); len(links) != 2 {
+			t.Errorf("Links() got = %v, want 2 links", links)
+		}
+		if firstVisit, _ := reopenedStore.MarkVisited("https://test.com"); firstVisit {
+			t.Errorf("MarkVisited() should not report a link visited before reopening")
+		}
+		if got, _ := reopenedStore.Pop(1, 10); !reflect.DeepEqual(got, []string{"https://test.com/about-us"}) {
+			t.Errorf("Pop() got = %v, want [https://test.com/about-us]", got)
+		}
+	})
+
+	t.Run("clear removes the state", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "crawl.state")
+		store, _ := NewBoltStore(path)
+		_, _ = store.MarkFound("https://test.com")
+		_ = store.Clear()
+		_ = store.Close()
+
+		reopenedStore, _ := NewBoltStore(path)
+		defer func() { _ = reopenedStore.Close() }()
+		if links, _ := reopenedStore.Links(); len(links) != 0 {
+			t.Errorf("Links() got = %v, want no links", links)
+		}
+	})
+}
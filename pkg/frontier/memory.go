@@ -0,0 +1,125 @@
+package frontier
+
+import "sync"
+
+// MemoryStore is a Store that keeps the state of the crawl in memory.
+type MemoryStore struct {
+	mu        sync.Mutex
+	links     map[string]bool // map of links found while crawling + whether is visited or not
+	frontiers map[int][]string
+	crawlInfo *CrawlInfo
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{links: make(map[string]bool), frontiers: make(map[int][]string)}
+}
+
+func (s *MemoryStore) MarkFound(link string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.links[link]; ok {
+		return false, nil
+	}
+	s.links[link] = false
+	return true, nil
+}
+
+func (s *MemoryStore) MarkVisited(link string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.links[link] {
+		return false, nil
+	}
+	s.links[link] = true
+	return true, nil
+}
+
+func (s *MemoryStore) Push(depth int, links ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.frontiers[depth] = append(s.frontiers[depth], links...)
+	return nil
+}
+
+func (s *MemoryStore) Pop(depth int, n int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	frontier := s.frontiers[depth]
+	if n > len(frontier) {
+		n = len(frontier)
+	}
+	popped := make([]string, n)
+	copy(popped, frontier[:n])
+	if n == len(frontier) {
+		delete(s.frontiers, depth)
+	} else {
+		s.frontiers[depth] = frontier[n:]
+	}
+	return popped, nil
+}
+
+func (s *MemoryStore) Links() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links := make([]string, 0, len(s.links))
+	for link := range s.links {
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+func (s *MemoryStore) SaveCrawlInfo(info CrawlInfo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.crawlInfo = &info
+	return nil
+}
+
+func (s *MemoryStore) LoadCrawlInfo() (*CrawlInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.crawlInfo == nil {
+		return nil, NoCrawlInfo
+	}
+	info := *s.crawlInfo
+	return &info, nil
+}
+
+func (s *MemoryStore) Snapshot() (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := State{Frontiers: make(map[int][]string, len(s.frontiers))}
+	if s.crawlInfo != nil {
+		info := *s.crawlInfo
+		state.CrawlInfo = &info
+	}
+	for link, visited := range s.links {
+		if visited {
+			state.Visited = append(state.Visited, link)
+		} else {
+			state.Found = append(state.Found, link)
+		}
+	}
+	for depth, frontier := range s.frontiers {
+		state.Frontiers[depth] = append([]string(nil), frontier...)
+	}
+	return state, nil
+}
+
+func (s *MemoryStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.links = make(map[string]bool)
+	s.frontiers = make(map[int][]string)
+	s.crawlInfo = nil
+	return nil
+}
@@ -0,0 +1,104 @@
+package frontier
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Run("found and visited links are only new once", func(t *testing.T) {
+		store := NewMemoryStore()
+
+		if isNew, _ := store.MarkFound("https://test.com/contact"); !isNew {
+			t.Errorf("MarkFound() should report a new link")
+		}
+		if isNew, _ := store.MarkFound("https://test.com/contact"); isNew {
+			t.Errorf("MarkFound() should not report an already found link")
+		}
+		if firstVisit, _ := store.MarkVisited("https://test.com/contact"); !firstVisit {
+			t.Errorf("MarkVisited() should report the first visit of a found link")
+		}
+		if firstVisit, _ := store.MarkVisited("https://test.com/contact"); firstVisit {
+			t.Errorf("MarkVisited() should not report an already visited link")
+		}
+		_, _ = store.MarkVisited("https://test.com")
+		if isNew, _ := store.MarkFound("https://test.com"); isNew {
+			t.Errorf("MarkFound() should not report an already visited link")
+		}
+		if links, _ := store.Links(); len(links) != 2 {
+			t.Errorf("Links() got = %v, want 2 links", links)
+		}
+	})
+
+	t.Run("pops links in the order they were pushed per depth", func(t *testing.T) {
+		store := NewMemoryStore()
+		_ = store.Push(1, "a", "b", "c")
+		_ = store.Push(2, "d")
+
+		if got, _ := store.Pop(1, 2); !reflect.DeepEqual(got, []string{"a", "b"}) {
+			t.Errorf("Pop() got = %v, want [a b]", got)
+		}
+		if got, _ := store.Pop(1, 2); !reflect.DeepEqual(got, []string{"c"}) {
+			t.Errorf("Pop() got = %v, want [c]", got)
+		}
+		if got, _ := store.Pop(1, 2); len(got) != 0 {
+			t.Errorf("Pop() got = %v, want an empty frontier", got)
+		}
+		if got, _ := store.Pop(2, 2); !reflect.DeepEqual(got, []string{"d"}) {
+			t.Errorf("Pop() got = %v, want [d]", got)
+		}
+	})
+
+	t.Run("snapshots restore the state into another store", func(t *testing.T) {
+		store := NewMemoryStore()
+		_ = store.SaveCrawlInfo(CrawlInfo{URL: "https://test.com", Depth: 3, MaxConcurrency: 1, CurrentDepth: 1})
+		_, _ = store.MarkVisited("https://test.com")
+		_, _ = store.MarkFound("https://test.com/contact")
+		_, _ = store.MarkFound("https://test.com/about-us")
+		_ = store.Push(1, "https://test.com/contact", "https://test.com/about-us")
+
+		state, err := Snapshot(store)
+		if err != nil {
+			t.Fatalf("should not throw error at Snapshot. err: %v", err)
+		}
+		want := State{
+			CrawlInfo: &CrawlInfo{URL: "https://test.com", Depth: 3, MaxConcurrency: 1, CurrentDepth: 1},
+			Found:     []string{"https://test.com/about-us", "https://test.com/contact"},
+			Visited:   []string{"https://test.com"},
+			Frontiers: map[int][]string{1: {"https://test.com/contact", "https://test.com/about-us"}},
+		}
+		if !reflect.DeepEqual(state, want) {
+			t.Errorf("Snapshot() got = %+v, want %+v", state, want)
+		}
+
+		restored := NewMemoryStore()
+		_, _ = restored.MarkFound("https://other.com")
+		if err := Restore(restored, state); err != nil {
+			t.Fatalf("should not throw error at Restore. err: %v", err)
+		}
+		if restoredState, _ := Snapshot(restored); !reflect.DeepEqual(restoredState, want) {
+			t.Errorf("Snapshot() of the restored store got = %+v, want %+v", restoredState, want)
+		}
+	})
+
+	t.Run("snapshots are not supported by every store", func(t *testing.T) {
+		if _, err := Snapshot(NewBloomStore(100, 0.01)); !errors.Is(err, SnapshotNotSupported) {
+			t.Errorf("Snapshot() error = %v, want %v", err, SnapshotNotSupported)
+		}
+	})
+
+	t.Run("clear removes all the state", func(t *testing.T) {
+		store := NewMemoryStore()
+		_ = store.SaveCrawlInfo(CrawlInfo{URL: "https://test.com", Depth: 2, MaxConcurrency: 1})
+		_, _ = store.MarkFound("https://test.com")
+		_ = store.Clear()
+
+		if _, err := store.LoadCrawlInfo(); !errors.Is(err, NoCrawlInfo) {
+			t.Errorf("LoadCrawlInfo() error = %v, want %v", err, NoCrawlInfo)
+		}
+		if links, _ := store.Links(); len(links) != 0 {
+			t.Errorf("Links() got = %v, want no links", links)
+		}
+	})
+}
@@ -0,0 +1,376 @@
+package frontier
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedisTimeout is the default time to wait for the Redis server to accept a connection, or to reply to a command.
+const RedisTimeout = 5 * time.Second
+
+// RedisStore is a Store that keeps the state of the crawl in Redis, so many crawler processes can
+// share the same frontier and visited links. Popping links from the frontier is atomic, so every
+// link is crawled by only one process. Requires Redis 6.2 or newer.
+//
+// Commands that fail to be sent, or whose reply can't be read, close the connection, as it's unknown
+// what was left unread on it, and the next command connects to the server again.
+type RedisStore struct {
+	mu        sync.Mutex
+	conn      net.Conn
+	reader    *bufio.Reader
+	address   string
+	password  string
+	db        string
+	timeout   time.Duration
+	keyPrefix string
+}
+
+// NewRedisStore connects to the Redis server at redisURL, with the format redis://[:password@]host:port[/db],
+// and stores the state of the crawl under keys starting with keyPrefix.
+func NewRedisStore(redisURL string, keyPrefix string) (*RedisStore, error) {
+	parsedURL, err := url.Parse(redisURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsedURL.Scheme != "redis" {
+		return nil, fmt.Errorf("invalid redis URL scheme %q", parsedURL.Scheme)
+	}
+
+	store := &RedisStore{
+		address:   parsedURL.Host,
+		db:        strings.TrimPrefix(parsedURL.Path, "/"),
+		timeout:   RedisTimeout,
+		keyPrefix: keyPrefix,
+	}
+	store.password, _ = parsedURL.User.Password()
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if err := store.connect(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// connect opens a new connection to the Redis server, authenticating and selecting the database of the URL.
+func (s *RedisStore) connect() error {
+	conn, err := net.DialTimeout("tcp", s.address, s.timeout)
+	if err != nil {
+		return err
+	}
+	s.conn, s.reader = conn, bufio.NewReader(conn)
+
+	if s.password != "" {
+		if _, err := s.send("AUTH", s.password); err != nil {
+			s.disconnect()
+			return err
+		}
+	}
+	if s.db != "" {
+		if _, err := s.send("SELECT", s.db); err != nil {
+			s.disconnect()
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *RedisStore) disconnect() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+	}
+	s.conn, s.reader = nil, nil
+}
+
+func (s *RedisStore) MarkFound(link string) (bool, error) {
+	added, err := s.do("SADD", s.key("found"), link)
+	if err != nil {
+		return false, err
+	}
+	return added == int64(1), nil
+}
+
+func (s *RedisStore) MarkVisited(link string) (bool, error) {
+	if _, err := s.do("SADD", s.key("found"), link); err != nil {
+		return false, err
+	}
+	added, err := s.do("SADD", s.key("visited"), link)
+	if err != nil {
+		return false, err
+	}
+	return added == int64(1), nil
+}
+
+func (s *RedisStore) Push(depth int, links ...string) error {
+	if len(links) == 0 {
+		return nil
+	}
+	if _, err := s.do("SADD", s.key("depths"), strconv.Itoa(depth)); err != nil {
+		return err
+	}
+	_, err := s.do(append([]string{"RPUSH", s.frontierKey(depth)}, links...)...)
+	return err
+}
+
+func (s *RedisStore) Pop(depth int, n int) ([]string, error) {
+	reply, err := s.do("LPOP", s.frontierKey(depth), strconv.Itoa(n))
+	if err != nil || reply == nil {
+		return []string{}, err
+	}
+	return toStrings(reply)
+}
+
+func (s *RedisStore) Links() ([]string, error) {
+	reply, err := s.do("SMEMBERS", s.key("found"))
+	if err != nil {
+		return nil, err
+	}
+	return toStrings(reply)
+}
+
+func (s *RedisStore) SaveCrawlInfo(info CrawlInfo) error {
+	encodedInfo, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	_, err = s.do("SET", s.key("crawl_info"), string(encodedInfo))
+	return err
+}
+
+func (s *RedisStore) LoadCrawlInfo() (*CrawlInfo, error) {
+	reply, err := s.do("GET", s.key("crawl_info"))
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, NoCrawlInfo
+	}
+	var info CrawlInfo
+	if err := json.Unmarshal([]byte(reply.(string)), &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Snapshot returns the state of the store. It's not atomic, so it should be taken while no process is
+// crawling with the store.
+func (s *RedisStore) Snapshot() (State, error) {
+	state := State{Frontiers: make(map[int][]string)}
+	info, err := s.LoadCrawlInfo()
+	if err != nil && !errors.Is(err, NoCrawlInfo) {
+		return State{}, err
+	}
+	state.CrawlInfo = info
+
+	found, err := s.members("found")
+	if err != nil {
+		return State{}, err
+	}
+	state.Visited, err = s.members("visited")
+	if err != nil {
+		return State{}, err
+	}
+	visited := make(map[string]bool, len(state.Visited))
+	for _, link := range state.Visited {
+		visited[link] = true
+	}
+	for _, link := range found {
+		if !visited[link] {
+			state.Found = append(state.Found, link)
+		}
+	}
+
+	depths, err := s.members("depths")
+	if err != nil {
+		return State{}, err
+	}
+	for _, depth := range depths {
+		parsedDepth, err := strconv.Atoi(depth)
+		if err != nil {
+			return State{}, err
+		}
+		reply, err := s.do("LRANGE", s.frontierKey(parsedDepth), "0", "-1")
+		if err != nil {
+			return State{}, err
+		}
+		frontier, err := toStrings(reply)
+		if err != nil {
+			return State{}, err
+		}
+		if len(frontier) > 0 {
+			state.Frontiers[parsedDepth] = frontier
+		}
+	}
+	return state, nil
+}
+
+func (s *RedisStore) members(name string) ([]string, error) {
+	reply, err := s.do("SMEMBERS", s.key(name))
+	if err != nil {
+		return nil, err
+	}
+	return toStrings(reply)
+}
+
+func (s *RedisStore) Clear() error {
+	reply, err := s.do("SMEMBERS", s.key("depths"))
+	if err != nil {
+		return err
+	}
+	depths, err := toStrings(reply)
+	if err != nil {
+		return err
+	}
+
+	keys := []string{s.key("found"), s.key("visited"), s.key("crawl_info"), s.key("depths")}
+	for _, depth := range depths {
+		keys = append(keys, s.key("frontier:"+depth))
+	}
+	_, err = s.do(append([]string{"DEL"}, keys...)...)
+	return err
+}
+
+// Close closes the connection to the Redis server.
+func (s *RedisStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn, s.reader = nil, nil
+	return err
+}
+
+func (s *RedisStore) key(name string) string {
+	return s.keyPrefix + ":" + name
+}
+
+func (s *RedisStore) frontierKey(depth int) string {
+	return s.key("frontier:" + strconv.Itoa(depth))
+}
+
+// do sends a command using the RESP protocol and returns its reply, which is
+// a string, an int64, a []any, or nil. It connects to the server again if the
+// previous command broke the connection.
+func (s *RedisStore) do(args ...string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return nil, err
+		}
+	}
+	return s.send(args...)
+}
+
+// send writes the command to the connection and reads its reply, closing the connection on I/O errors.
+func (s *RedisStore) send(args ...string) (any, error) {
+	if err := s.conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		s.disconnect()
+		return nil, err
+	}
+
+	var command strings.Builder
+	command.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		command.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	if _, err := io.WriteString(s.conn, command.String()); err != nil {
+		s.disconnect()
+		return nil, err
+	}
+	reply, err := readReply(s.reader)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		s.disconnect()
+	}
+	return reply, err
+}
+
+// redisError is an error replied by the Redis server, which leaves the connection usable.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+func readReply(reader *bufio.Reader) (any, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		bulk := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, bulk); err != nil {
+			return nil, err
+		}
+		return string(bulk[:size]), nil
+	case '*':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		// errors replied as elements are returned once the whole array is read, so the connection stays usable
+		var elementErr error
+		elements := make([]any, size)
+		for i := range elements {
+			elements[i], err = readReply(reader)
+			var replyErr redisError
+			if errors.As(err, &replyErr) {
+				if elementErr == nil {
+					elementErr = err
+				}
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		if elementErr != nil {
+			return nil, elementErr
+		}
+		return elements, nil
+	}
+	return nil, fmt.Errorf("unknown redis reply %q", line)
+}
+
+func toStrings(reply any) ([]string, error) {
+	elements, ok := reply.([]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected redis reply %v", reply)
+	}
+	strs := make([]string, 0, len(elements))
+	for _, element := range elements {
+		str, ok := element.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected redis reply element %v", element)
+		}
+		strs = append(strs, str)
+	}
+	return strs, nil
+}
@@ -0,0 +1,247 @@
+package frontier
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a minimal Redis server that supports the commands used by the RedisStore.
+type fakeRedis struct {
+	mu      sync.Mutex
+	sets    map[string]map[string]bool
+	lists   map[string][]string
+	strings map[string]string
+}
+
+func startFakeRedis(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not start fake redis. err: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	server := &fakeRedis{sets: map[string]map[string]bool{}, lists: map[string][]string{}, strings: map[string]string{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	reader := bufio.NewReader(conn)
+	for {
+		command, err := readReply(reader)
+		if err != nil {
+			return
+		}
+		args, _ := toStrings(command)
+		_, _ = conn.Write([]byte(f.execute(args)))
+	}
+}
+
+func (f *fakeRedis) execute(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "AUTH", "SELECT":
+		return "+OK\r\n"
+	case "SADD":
+		if f.sets[args[1]] == nil {
+			f.sets[args[1]] = map[string]bool{}
+		}
+		added := 0
+		for _, member := range args[2:] {
+			if !f.sets[args[1]][member] {
+				f.sets[args[1]][member] = true
+				added++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", added)
+	case "SMEMBERS":
+		var members []string
+		for member := range f.sets[args[1]] {
+			members = append(members, member)
+		}
+		return encodeArray(members)
+	case "RPUSH":
+		f.lists[args[1]] = append(f.lists[args[1]], args[2:]...)
+		return fmt.Sprintf(":%d\r\n", len(f.lists[args[1]]))
+	case "LPOP":
+		list := f.lists[args[1]]
+		if len(list) == 0 {
+			return "*-1\r\n"
+		}
+		n, _ := strconv.Atoi(args[2])
+		if n > len(list) {
+			n = len(list)
+		}
+		f.lists[args[1]] = list[n:]
+		return encodeArray(list[:n])
+	case "LRANGE":
+		return encodeArray(f.lists[args[1]])
+	case "SET":
+		f.strings[args[1]] = args[2]
+		return "+OK\r\n"
+	case "GET":
+		value, ok := f.strings[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "DEL":
+		for _, key := range args[1:] {
+			delete(f.sets, key)
+			delete(f.lists, key)
+			delete(f.strings, key)
+		}
+		return fmt.Sprintf(":%d\r\n", len(args)-1)
+	}
+	return "-ERR unknown command\r\n"
+}
+
+func encodeArray(elements []string) string {
+	encoded := fmt.Sprintf("*%d\r\n", len(elements))
+	for _, element := range elements {
+		encoded += fmt.Sprintf("$%d\r\n%s\r\n", len(element), element)
+	}
+	return encoded
+}
+
+func TestRedisStore(t *testing.T) {
+	addr := startFakeRedis(t)
+
+	t.Run("shares the state between stores with the same key prefix", func(t *testing.T) {
+		store, err := NewRedisStore("redis://:secret@"+addr+"/1", "crawl")
+		if err != nil {
+			t.Fatalf("should not throw error at NewRedisStore. err: %v", err)
+		}
+		defer func() { _ = store.Close() }()
+		otherProcessStore, _ := NewRedisStore("redis://"+addr, "crawl")
+		defer func() { _ = otherProcessStore.Close() }()
+
+		_ = store.SaveCrawlInfo(CrawlInfo{URL: "https://test.com", Depth: 2, MaxConcurrency: 1})
+		_ = store.Push(0, "https://test.com", "https://test.com/contact")
+		if firstVisit, _ := store.MarkVisited("https://test.com"); !firstVisit {
+			t.Errorf("MarkVisited() should report the first visit")
+		}
+
+		if info, err := otherProcessStore.LoadCrawlInfo(); err != nil || info.URL != "https://test.com" {
+			t.Errorf("LoadCrawlInfo() got = %+v, err = %v", info, err)
+		}
+		if isNew, _ := otherProcessStore.MarkFound("https://test.com"); isNew {
+			t.Errorf("MarkFound() should not report a link visited by another process")
+		}
+		if got, _ := otherProcessStore.Pop(0, 1); !reflect.DeepEqual(got, []string{"https://test.com"}) {
+			t.Errorf("Pop() got = %v, want [https://test.com]", got)
+		}
+		if got, _ := store.Pop(0, 5); !reflect.DeepEqual(got, []string{"https://test.com/contact"}) {
+			t.Errorf("Pop() got = %v, want [https://test.com/contact]", got)
+		}
+		if got, _ := store.Pop(0, 5); len(got) != 0 {
+			t.Errorf("Pop() got = %v, want an empty frontier", got)
+		}
+	})
+
+	t.Run("snapshots the state", func(t *testing.T) {
+		store, _ := NewRedisStore("redis://"+addr, "snapshot")
+		defer func() { _ = store.Close() }()
+		_ = store.SaveCrawlInfo(CrawlInfo{URL: "https://test.com", Depth: 2})
+		_, _ = store.MarkVisited("https://test.com")
+		_, _ = store.MarkFound("https://test.com/contact")
+		_ = store.Push(1, "https://test.com/contact")
+
+		state, err := Snapshot(store)
+		if err != nil {
+			t.Fatalf("should not throw error at Snapshot. err: %v", err)
+		}
+		want := State{
+			CrawlInfo: &CrawlInfo{URL: "https://test.com", Depth: 2},
+			Found:     []string{"https://test.com/contact"},
+			Visited:   []string{"https://test.com"},
+			Frontiers: map[int][]string{1: {"https://test.com/contact"}},
+		}
+		if !reflect.DeepEqual(state, want) {
+			t.Errorf("Snapshot() got = %+v, want %+v", state, want)
+		}
+	})
+
+	t.Run("clear removes all the state", func(t *testing.T) {
+		store, _ := NewRedisStore("redis://"+addr, "cleared")
+		defer func() { _ = store.Close() }()
+		_ = store.SaveCrawlInfo(CrawlInfo{URL: "https://test.com"})
+		_ = store.Push(3, "https://test.com/depth3")
+		_, _ = store.MarkFound("https://test.com/depth3")
+
+		if err := store.Clear(); err != nil {
+			t.Fatalf("should not throw error at Clear. err: %v", err)
+		}
+		if _, err := store.LoadCrawlInfo(); !errors.Is(err, NoCrawlInfo) {
+			t.Errorf("LoadCrawlInfo() error = %v, want %v", err, NoCrawlInfo)
+		}
+		if got, _ := store.Pop(3, 1); len(got) != 0 {
+			t.Errorf("Pop() got = %v, want an empty frontier", got)
+		}
+		if links, _ := store.Links(); len(links) != 0 {
+			t.Errorf("Links() got = %v, want no links", links)
+		}
+	})
+
+	t.Run("connects again after the connection breaks", func(t *testing.T) {
+		store, _ := NewRedisStore("redis://:secret@"+addr+"/1", "reconnect")
+		defer func() { _ = store.Close() }()
+		_, _ = store.MarkFound("https://test.com")
+
+		_ = store.conn.Close()
+		if _, err := store.MarkFound("https://test.com/contact"); err == nil {
+			t.Errorf("MarkFound() should throw the error of the broken connection")
+		}
+		if isNew, err := store.MarkFound("https://test.com/about-us"); err != nil || !isNew {
+			t.Errorf("MarkFound() got = %v, err = %v, want a new link on a new connection", isNew, err)
+		}
+		if links, _ := store.Links(); len(links) != 2 {
+			t.Errorf("Links() got = %v, want 2 links", links)
+		}
+	})
+
+	t.Run("times out when the server does not reply", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("could not start listener. err: %v", err)
+		}
+		defer func() { _ = listener.Close() }()
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				defer func() { _ = conn.Close() }()
+			}
+		}()
+
+		store, _ := NewRedisStore("redis://"+listener.Addr().String(), "timeout")
+		defer func() { _ = store.Close() }()
+		store.timeout = 50 * time.Millisecond
+
+		var netErr net.Error
+		if _, err := store.MarkFound("https://test.com"); !errors.As(err, &netErr) || !netErr.Timeout() {
+			t.Errorf("MarkFound() error = %v, want a timeout", err)
+		}
+	})
+}
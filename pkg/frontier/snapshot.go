@@ -0,0 +1,77 @@
+package frontier
+
+import (
+	"errors"
+	"sort"
+)
+
+// SnapshotNotSupported indicates that the store can't export its state, like the BloomStore, which
+// only keeps the hashes of the links.
+var SnapshotNotSupported = errors.New("the store doesn't support snapshots")
+
+// State is the whole state held by a store, which can be persisted in any storage and restored into
+// any store with Restore.
+type State struct {
+	CrawlInfo *CrawlInfo `json:"crawl_info,omitempty"`
+	// Found are the links found and not visited yet.
+	Found []string `json:"found,omitempty"`
+	// Visited are the links visited.
+	Visited []string `json:"visited,omitempty"`
+	// Frontiers are the links pending to be crawled at every depth level, in the order they're popped.
+	Frontiers map[int][]string `json:"frontiers,omitempty"`
+}
+
+// Snapshotter is implemented by the stores that can export their whole state.
+type Snapshotter interface {
+	// Snapshot returns a copy of the state of the store.
+	Snapshot() (State, error)
+}
+
+// Snapshot returns a copy of the state of the store, or SnapshotNotSupported if the store can't
+// export it. The links are sorted, so snapshots of the same state are equal.
+func Snapshot(store Store) (State, error) {
+	snapshotter, ok := store.(Snapshotter)
+	if !ok {
+		return State{}, SnapshotNotSupported
+	}
+	state, err := snapshotter.Snapshot()
+	if err != nil {
+		return State{}, err
+	}
+	sort.Strings(state.Found)
+	sort.Strings(state.Visited)
+	return state, nil
+}
+
+// Restore replaces the state of the store with the given one.
+func Restore(store Store, state State) error {
+	if err := store.Clear(); err != nil {
+		return err
+	}
+	if state.CrawlInfo != nil {
+		if err := store.SaveCrawlInfo(*state.CrawlInfo); err != nil {
+			return err
+		}
+	}
+	for _, link := range state.Found {
+		if _, err := store.MarkFound(link); err != nil {
+			return err
+		}
+	}
+	for _, link := range state.Visited {
+		if _, err := store.MarkVisited(link); err != nil {
+			return err
+		}
+	}
+	depths := make([]int, 0, len(state.Frontiers))
+	for depth := range state.Frontiers {
+		depths = append(depths, depth)
+	}
+	sort.Ints(depths)
+	for _, depth := range depths {
+		if err := store.Push(depth, state.Frontiers[depth]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
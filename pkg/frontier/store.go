@@ -0,0 +1,40 @@
+package frontier
+
+import "errors"
+
+// NoCrawlInfo indicates that the store doesn't hold any crawl to resume.
+var NoCrawlInfo = errors.New("no crawl info found in the store")
+
+// CrawlInfo holds the parameters of a crawl and how far it got, so it can be resumed.
+type CrawlInfo struct {
+	URL            string `json:"url"`
+	Depth          int    `json:"depth"`
+	MaxConcurrency int    `json:"max_concurrency"`
+	CurrentDepth   int    `json:"current_depth"`
+	// RedirectedFrom is the key of the URL the crawl was started with, if it redirected to URL
+	RedirectedFrom string `json:"redirected_from,omitempty"`
+}
+
+// Store holds the state of a crawl: the links found so far, which of them were already
+// visited, and the links pending to be crawled at every depth level.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// MarkFound records the link as found. It reports whether the link is new, meaning
+	// it was neither found nor visited before.
+	MarkFound(link string) (bool, error)
+	// MarkVisited records the link as visited. It reports whether it's the first visit.
+	MarkVisited(link string) (bool, error)
+	// Push adds the links at the end of the frontier of the given depth level.
+	Push(depth int, links ...string) error
+	// Pop removes and returns up to n links from the beginning of the frontier of the given
+	// depth level. It returns an empty slice when the frontier is empty.
+	Pop(depth int, n int) ([]string, error)
+	// Links returns all the links found or visited.
+	Links() ([]string, error)
+	// SaveCrawlInfo records the parameters and progress of the crawl.
+	SaveCrawlInfo(info CrawlInfo) error
+	// LoadCrawlInfo returns the last saved crawl info, or NoCrawlInfo if none was saved.
+	LoadCrawlInfo() (*CrawlInfo, error)
+	// Clear removes all the state of the store.
+	Clear() error
+}
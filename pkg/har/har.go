@@ -0,0 +1,265 @@
+package har
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Log is the root object of a HAR 1.2 file.
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Entry holds a request/response pair along with its timings.
+type Entry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	Time            float64   `json:"time"`
+	Request         Request   `json:"request"`
+	Response        Response  `json:"response"`
+	Cache           struct{}  `json:"cache"`
+	Timings         Timings   `json:"timings"`
+	Comment         string    `json:"comment,omitempty"`
+}
+
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []NameValue `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []NameValue `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+type Content struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Timings holds the duration in milliseconds of every phase of a request. Phases that
+// didn't happen, like DNS resolution on a reused connection, are -1.
+type Timings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// Recorder is an http.RoundTripper that records every request/response pair that goes through it,
+// so they can be exported as a HAR file.
+type Recorder struct {
+	transport     http.RoundTripper
+	includeBodies bool
+	mu            sync.Mutex
+	entries       []Entry
+}
+
+// NewRecorder creates a new Recorder that sends the requests using the given transport. If includeBodies
+// is true, response bodies are fully read to be included in the HAR file.
+func NewRecorder(transport http.RoundTripper, includeBodies bool) *Recorder {
+	return &Recorder{transport: transport, includeBodies: includeBodies}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	timer := &phaseTimer{}
+	tracedReq := req.WithContext(httptrace.WithClientTrace(req.Context(), timer.clientTrace()))
+
+	entry := Entry{StartedDateTime: time.Now(), Request: buildRequest(req)}
+	res, err := r.transport.RoundTrip(tracedReq)
+	if err != nil {
+		entry.Comment = err.Error()
+		entry.Timings = timer.timings(time.Now())
+		entry.Time = milliseconds(time.Since(entry.StartedDateTime))
+		r.record(entry)
+		return nil, err
+	}
+
+	entry.Response = buildResponse(res)
+	if r.includeBodies {
+		body, readErr := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		entry.Response.Content.Size = int64(len(body))
+		entry.Response.Content.Text = string(body)
+		if readErr != nil {
+			entry.Comment = readErr.Error()
+		}
+	}
+	entry.Timings = timer.timings(time.Now())
+	entry.Time = milliseconds(time.Since(entry.StartedDateTime))
+	r.record(entry)
+
+	return res, nil
+}
+
+// Write writes all the recorded entries to the given writer in HAR 1.2 format.
+func (r *Recorder) Write(w io.Writer) error {
+	r.mu.Lock()
+	harLog := Log{
+		Version: "1.2",
+		Creator: Creator{Name: "website-crawler", Version: "1.0"},
+		Entries: append([]Entry{}, r.entries...),
+	}
+	r.mu.Unlock()
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Log Log `json:"log"`
+	}{Log: harLog})
+}
+
+func (r *Recorder) record(entry Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+}
+
+func buildRequest(req *http.Request) Request {
+	request := Request{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: req.Proto,
+		Cookies:     []NameValue{},
+		Headers:     headers(req.Header),
+		QueryString: []NameValue{},
+		HeadersSize: -1,
+		BodySize:    req.ContentLength,
+	}
+	for _, cookie := range req.Cookies() {
+		request.Cookies = append(request.Cookies, NameValue{Name: cookie.Name, Value: cookie.Value})
+	}
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			request.QueryString = append(request.QueryString, NameValue{Name: name, Value: value})
+		}
+	}
+	return request
+}
+
+func buildResponse(res *http.Response) Response {
+	response := Response{
+		Status:      res.StatusCode,
+		StatusText:  http.StatusText(res.StatusCode),
+		HTTPVersion: res.Proto,
+		Cookies:     []NameValue{},
+		Headers:     headers(res.Header),
+		Content:     Content{Size: res.ContentLength, MimeType: res.Header.Get("Content-Type")},
+		RedirectURL: res.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    res.ContentLength,
+	}
+	for _, cookie := range res.Cookies() {
+		response.Cookies = append(response.Cookies, NameValue{Name: cookie.Name, Value: cookie.Value})
+	}
+	return response
+}
+
+func headers(header http.Header) []NameValue {
+	nameValues := []NameValue{}
+	for name, values := range header {
+		for _, value := range values {
+			nameValues = append(nameValues, NameValue{Name: name, Value: value})
+		}
+	}
+	return nameValues
+}
+
+// phaseTimer records the start and end of every phase of a request using an httptrace.ClientTrace.
+type phaseTimer struct {
+	mu                                   sync.Mutex
+	start, dnsStart, dnsDone             time.Time
+	connectStart, connectDone            time.Time
+	tlsStart, tlsDone                    time.Time
+	gotConn, wroteRequest, firstResponse time.Time
+}
+
+func (p *phaseTimer) clientTrace() *httptrace.ClientTrace {
+	p.start = time.Now()
+	mark := func(t *time.Time) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if t.IsZero() {
+			*t = time.Now()
+		}
+	}
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { mark(&p.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { mark(&p.dnsDone) },
+		ConnectStart:         func(string, string) { mark(&p.connectStart) },
+		ConnectDone:          func(string, string, error) { mark(&p.connectDone) },
+		TLSHandshakeStart:    func() { mark(&p.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { mark(&p.tlsDone) },
+		GotConn:              func(httptrace.GotConnInfo) { mark(&p.gotConn) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { mark(&p.wroteRequest) },
+		GotFirstResponseByte: func() { mark(&p.firstResponse) },
+	}
+}
+
+func (p *phaseTimer) timings(end time.Time) Timings {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	connStart := p.start
+	if !p.dnsStart.IsZero() {
+		connStart = p.dnsStart
+	} else if !p.connectStart.IsZero() {
+		connStart = p.connectStart
+	}
+	return Timings{
+		Blocked: phase(p.start, connStart),
+		DNS:     phase(p.dnsStart, p.dnsDone),
+		Connect: phase(p.connectStart, p.connectDone),
+		SSL:     phase(p.tlsStart, p.tlsDone),
+		Send:    phase(p.gotConn, p.wroteRequest),
+		Wait:    phase(p.wroteRequest, p.firstResponse),
+		Receive: phase(p.firstResponse, end),
+	}
+}
+
+func phase(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() {
+		return -1
+	}
+	return milliseconds(end.Sub(start))
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
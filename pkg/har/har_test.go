@@ -0,0 +1,54 @@
+package har
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecorder_RoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("<p>not found</p>"))
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder(http.DefaultTransport, true)
+	client := &http.Client{Transport: recorder}
+	res, err := client.Get(server.URL + "/missing?ref=home")
+	if err != nil {
+		t.Fatalf("should not throw error at client.Get. err: %v", err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	if string(body) != "<p>not found</p>" {
+		t.Errorf("response body got = %v, want the original body", string(body))
+	}
+
+	var buffer bytes.Buffer
+	if err := recorder.Write(&buffer); err != nil {
+		t.Fatalf("should not throw error at recorder.Write. err: %v", err)
+	}
+	var harFile struct {
+		Log Log `json:"log"`
+	}
+	if err := json.Unmarshal(buffer.Bytes(), &harFile); err != nil {
+		t.Fatalf("HAR file is not valid JSON. err: %v", err)
+	}
+	if len(harFile.Log.Entries) != 1 {
+		t.Fatalf("HAR entries got = %v, want 1", len(harFile.Log.Entries))
+	}
+	entry := harFile.Log.Entries[0]
+	if entry.Request.URL != server.URL+"/missing?ref=home" || len(entry.Request.QueryString) != 1 {
+		t.Errorf("HAR request got = %+v", entry.Request)
+	}
+	if entry.Response.Status != http.StatusNotFound || entry.Response.Content.Text != "<p>not found</p>" {
+		t.Errorf("HAR response got = %+v", entry.Response)
+	}
+	if entry.Timings.Wait < 0 {
+		t.Errorf("HAR wait timing got = %v, want it to be measured", entry.Timings.Wait)
+	}
+}
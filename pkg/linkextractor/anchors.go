@@ -0,0 +1,63 @@
+package linkextractor
+
+import (
+	"net/url"
+
+	"golang.org/x/net/html"
+)
+
+// WithAnchors is an option to also extract the targets of the #fragment links of the page, the
+// id attributes of its elements and the name attributes of its <a> tags, into Page.IDs, and the
+// links with a fragment into Page.FragmentLinks, so the links to a section of a page can be validated.
+func WithAnchors() Option {
+	return func(config *config) {
+		config.anchors = true
+	}
+}
+
+// searchIDs returns the fragments the elements of the page can be linked with.
+func searchIDs(node *html.Node) []string {
+	var ids []string
+	if node.Type == html.ElementNode {
+		for _, attr := range node.Attr {
+			if attr.Key == "id" || (attr.Key == "name" && node.Data == "a") {
+				if attr.Val != "" {
+					ids = append(ids, attr.Val)
+				}
+			}
+		}
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		ids = append(ids, searchIDs(child)...)
+	}
+
+	return ids
+}
+
+// fragmentLinks returns the links of the anchors with a fragment, keeping the fragment. Hash routes
+// of single page applications, like #/settings, are not sections of a page, so they're left out.
+func fragmentLinks(anchors []anchor) []url.URL {
+	var links []url.URL
+	for _, anchor := range anchors {
+		if anchor.fragment == "" || isHashRoute(anchor.fragment) {
+			continue
+		}
+		link := anchor.link
+		link.Fragment = anchor.fragment
+		links = append(links, link)
+	}
+	return removeDuplicates(links)
+}
+
+func removeDuplicateStrings(values []string) []string {
+	unique := make(map[string]bool)
+	var uniqueSlice []string
+	for _, value := range values {
+		if !unique[value] {
+			unique[value] = true
+			uniqueSlice = append(uniqueSlice, value)
+		}
+	}
+	return uniqueSlice
+}
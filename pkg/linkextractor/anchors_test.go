@@ -0,0 +1,31 @@
+package linkextractor
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractPage_WithAnchors(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/docs/setup")
+	htmlWithAnchors := `<h1 id="setup">Setup</h1><a name="legacy"></a><h2 id="install">Install</h2><h2 id="install">Again</h2>
+		<a href="#install">Install</a><a href="#/settings">Settings</a><a href="/docs/api#auth">Auth</a>
+		<a href="/docs/api#auth">Auth</a><a href="/docs/api">API</a><a href="https://other.com#top">Other</a>`
+
+	got, err := ExtractPage(*testUrl, strings.NewReader(htmlWithAnchors), WithAnchors())
+	if err != nil {
+		t.Fatalf("should not throw error at ExtractPage. err: %v", err)
+	}
+	wantIDs := []string{"setup", "legacy", "install"}
+	if !reflect.DeepEqual(got.IDs, wantIDs) {
+		t.Errorf("ExtractPage() ids got = %v, want %v", got.IDs, wantIDs)
+	}
+	wantFragmentLinks := []url.URL{
+		{Scheme: "https", Host: "test.com", Path: "/docs/setup", Fragment: "install"},
+		{Scheme: "https", Host: "test.com", Path: "/docs/api", Fragment: "auth"},
+	}
+	if !reflect.DeepEqual(got.FragmentLinks, wantFragmentLinks) {
+		t.Errorf("ExtractPage() fragment links got = %v, want %v", got.FragmentLinks, wantFragmentLinks)
+	}
+}
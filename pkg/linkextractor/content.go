@@ -0,0 +1,106 @@
+package linkextractor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// WithContentHash is an option to also hash the main content of the page into Page.ContentHash, for
+// change monitoring. Only the text of the main content is hashed: the <main> element, the element with
+// role="main" or the <article> element, or the <body> without its <header>, <nav>, <aside> and <footer>
+// elements if the page has none of them. Scripts, styles and attributes are left out, so the rotating
+// nonces, timestamps and tracking markup of the boilerplate don't change the hash of a page on every
+// crawl, while any change in its text does.
+func WithContentHash() Option {
+	return func(config *config) {
+		config.contentHash = true
+	}
+}
+
+// hashContent returns the hex encoded SHA-256 hash of the text of the main content of the page.
+func hashContent(root *html.Node) string {
+	var text strings.Builder
+	if content := findMainContent(root); content != nil {
+		writeContentText(&text, content, false)
+	} else if body := findElement(root, "body"); body != nil {
+		writeContentText(&text, body, true)
+	}
+	hash := sha256.Sum256([]byte(strings.Join(strings.Fields(text.String()), " ")))
+	return hex.EncodeToString(hash[:])
+}
+
+// findMainContent returns the first <main> element or element with role="main" of the page, or
+// the first <article> element if there are none.
+func findMainContent(root *html.Node) *html.Node {
+	var main, article *html.Node
+	var search func(node *html.Node)
+	search = func(node *html.Node) {
+		if main != nil {
+			return
+		}
+		if node.Type == html.ElementNode {
+			switch {
+			case node.Data == "main" || strings.EqualFold(attrValue(node, "role"), "main"):
+				main = node
+				return
+			case node.Data == "article" && article == nil:
+				article = node
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			search(child)
+		}
+	}
+	search(root)
+	if main != nil {
+		return main
+	}
+	return article
+}
+
+func findElement(node *html.Node, tag string) *html.Node {
+	if node.Type == html.ElementNode && node.Data == tag {
+		return node
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if found := findElement(child, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// writeContentText writes the text of the node, separating the text of every element, without the
+// scripts and styles, nor the boilerplate sections of the page if skipBoilerplate is true.
+func writeContentText(text *strings.Builder, node *html.Node, skipBoilerplate bool) {
+	switch node.Type {
+	case html.TextNode:
+		text.WriteString(node.Data)
+		return
+	case html.ElementNode:
+		switch node.Data {
+		case "script", "style", "noscript", "template":
+			return
+		case "header", "nav", "aside", "footer":
+			if skipBoilerplate {
+				return
+			}
+		}
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeContentText(text, child, skipBoilerplate)
+	}
+	text.WriteString(" ")
+}
+
+func attrValue(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package linkextractor
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestExtractPage_WithContentHash(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/page")
+	contentHash := func(htmlContent string) string {
+		page, err := ExtractPage(*testUrl, strings.NewReader(htmlContent), WithContentHash())
+		if err != nil {
+			t.Fatalf("should not throw error at ExtractPage. err: %v", err)
+		}
+		return page.ContentHash
+	}
+
+	tests := []struct {
+		name      string
+		before    string
+		after     string
+		wantEqual bool
+	}{
+		{
+			name:      "ignores the boilerplate outside the main element",
+			before:    `<body><header>Rendered at 10:00</header><main><h1>Title</h1><p>Text</p></main><footer>nonce abc</footer></body>`,
+			after:     `<body><header>Rendered at 11:00</header><main><h1>Title</h1><p>Text</p></main><footer>nonce xyz</footer></body>`,
+			wantEqual: true,
+		},
+		{
+			name:      "ignores scripts and attributes",
+			before:    `<main><script nonce="abc">var t = 1;</script><p class="a">Text</p></main>`,
+			after:     `<main><script nonce="xyz">var t = 2;</script><p class="b">Text</p></main>`,
+			wantEqual: true,
+		},
+		{
+			name:      "ignores whitespace changes",
+			before:    `<article><p>Some   text</p></article>`,
+			after:     "<article>\n\t<p>Some text</p>\n</article>",
+			wantEqual: true,
+		},
+		{
+			name:      "uses the element with role main",
+			before:    `<div role="main">Text</div><div class="sidebar">Trending: a</div>`,
+			after:     `<div role="main">Text</div><div class="sidebar">Trending: b</div>`,
+			wantEqual: true,
+		},
+		{
+			name:      "falls back to the body without its boilerplate",
+			before:    `<body><nav>Menu 1</nav><p>Text</p><footer>2024</footer></body>`,
+			after:     `<body><nav>Menu 2</nav><p>Text</p><footer>2025</footer></body>`,
+			wantEqual: true,
+		},
+		{
+			name:      "detects changes in the main content",
+			before:    `<body><main><p>Price: 10</p></main></body>`,
+			after:     `<body><main><p>Price: 12</p></main></body>`,
+			wantEqual: false,
+		},
+		{
+			name:      "separates the text of adjacent elements",
+			before:    `<main><p>ab</p><p>c</p></main>`,
+			after:     `<main><p>a</p><p>bc</p></main>`,
+			wantEqual: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contentHash(tt.before) == contentHash(tt.after); got != tt.wantEqual {
+				t.Errorf("ExtractPage() content hashes equal = %v, want %v", got, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestExtractPage_WithoutContentHash(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/page")
+	page, err := ExtractPage(*testUrl, strings.NewReader(`<main>Text</main>`))
+	if err != nil {
+		t.Fatalf("should not throw error at ExtractPage. err: %v", err)
+	}
+	if page.ContentHash != "" {
+		t.Errorf("ExtractPage() content hash got = %v, want none", page.ContentHash)
+	}
+}
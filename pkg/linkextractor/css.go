@@ -0,0 +1,52 @@
+package linkextractor
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+)
+
+// cssURLPattern matches the url() references of CSS, quoted or not, and the strings of the @import rules.
+// The strings are only references after @import, which is checked when matching.
+var cssURLPattern = regexp.MustCompile(`(@import\s+)?(?:url\(\s*(?:"([^"]*)"|'([^']*)'|([^)"'\s]*))\s*\)|"([^"]*)"|'([^']*)')`)
+
+// ExtractStylesheet extracts the same-domain assets referenced by the given stylesheet content, like
+// background images, fonts and imported stylesheets, so the .css files found with the WithResources
+// option can be fetched and searched too. Relative URLs are resolved against the stylesheetURL, and
+// the imported stylesheets are tagged as StylesheetResource.
+func ExtractStylesheet(stylesheetURL url.URL, stylesheetContent io.Reader, opts ...Option) ([]Resource, error) {
+	css, err := io.ReadAll(stylesheetContent)
+	if err != nil {
+		return nil, err
+	}
+	return removeDuplicateResources(searchCSSResources(stylesheetURL, stylesheetURL, string(css), newConfig(opts))), nil
+}
+
+// searchCSSResources returns the same-domain assets referenced by the CSS of a stylesheet, a <style>
+// block or a style attribute, resolved against the base URL.
+func searchCSSResources(webpageURL, baseURL url.URL, css string, config config) []Resource {
+	var resources []Resource
+	for _, match := range cssURLPattern.FindAllStringSubmatch(css, -1) {
+		isImport, urlReference, stringReference := match[1] != "", match[2]+match[3]+match[4], match[5]+match[6]
+		reference := urlReference
+		if isImport && reference == "" {
+			reference = stringReference
+		}
+		if reference == "" {
+			continue
+		}
+		resourceType := StyleResource
+		if isImport {
+			resourceType = StylesheetResource
+		}
+		referenceUrl, err := url.Parse(reference)
+		if err != nil {
+			continue
+		}
+		normalizedLink := resolveLink(baseURL, referenceUrl, config)
+		if isValidLink(webpageURL, normalizedLink, config) {
+			resources = append(resources, Resource{Type: resourceType, URL: normalizedLink})
+		}
+	}
+	return resources
+}
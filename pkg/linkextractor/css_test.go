@@ -0,0 +1,50 @@
+package linkextractor
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractStylesheet(t *testing.T) {
+	stylesheetUrl, _ := url.Parse("https://test.com/css/site.css")
+	stylesheet := `@import "reset.css";
+		@import url('/css/print.css') print;
+		body { background: url(../images/bg.png) no-repeat; }
+		@font-face { src: url("/fonts/inter.woff2") format("woff2"), url(https://fonts.other.com/inter.woff); }
+		.icon { background-image: url(data:image/png;base64,iVBORw0KGgo=); }
+		.logo { background: url( "../images/bg.png" ); }`
+
+	got, err := ExtractStylesheet(*stylesheetUrl, strings.NewReader(stylesheet))
+	if err != nil {
+		t.Fatalf("should not throw error at ExtractStylesheet. err: %v", err)
+	}
+	want := []Resource{
+		{Type: StylesheetResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/css/reset.css"}},
+		{Type: StylesheetResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/css/print.css"}},
+		{Type: StyleResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/images/bg.png"}},
+		{Type: StyleResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/fonts/inter.woff2"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractStylesheet() got = %v, want %v", got, want)
+	}
+}
+
+func TestExtractPage_WithResourcesInStyles(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/blog/post")
+	htmlWithStyles := `<head><style>.hero { background: url("hero.jpg"); }</style></head>
+		<body><div style="background-image: url('/images/banner.png')"></div></body>`
+
+	got, err := ExtractPage(*testUrl, strings.NewReader(htmlWithStyles), WithResources())
+	if err != nil {
+		t.Fatalf("should not throw error at ExtractPage. err: %v", err)
+	}
+	want := []Resource{
+		{Type: StyleResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/blog/hero.jpg"}},
+		{Type: StyleResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/images/banner.png"}},
+	}
+	if !reflect.DeepEqual(got.Resources, want) {
+		t.Errorf("ExtractPage() resources got = %v, want %v", got.Resources, want)
+	}
+}
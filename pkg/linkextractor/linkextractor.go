@@ -8,54 +8,285 @@ import (
 	"golang.org/x/net/html"
 )
 
+// Option configures how the links are extracted and normalized.
+type Option func(config *config)
+
+type config struct {
+	keepHashRoutes          bool
+	trailingSlashPolicy     TrailingSlashPolicy
+	fragmentPolicy          FragmentPolicy
+	caseInsensitivePaths    bool
+	defaultDocuments        []string
+	keepQueryStrings        bool
+	strippedQueryParameters []string
+	resources               bool
+	thirdPartyResources     bool
+	externalLinks           bool
+	anchors                 bool
+	contentHash             bool
+	scope                   Scope
+}
+
+// TrailingSlashPolicy decides how the trailing slashes of the paths are handled, as /docs and
+// /docs/ are different URLs that some servers respond differently to.
+type TrailingSlashPolicy int
+
+const (
+	// StripTrailingSlash removes the trailing slashes, so /docs/ and /docs are the same link. It's the default.
+	StripTrailingSlash TrailingSlashPolicy = iota
+	// KeepTrailingSlash keeps the paths as written, so links are fetched exactly as they appear in the
+	// pages, but /docs/ and /docs are still the same page, identified by the Key of the link.
+	KeepTrailingSlash
+	// DistinctTrailingSlash keeps the paths as written and treats /docs/ and /docs as distinct pages.
+	DistinctTrailingSlash
+)
+
+// FragmentPolicy decides how the #fragments of the links are handled. As HTTP requests don't include
+// the fragment, /page and /page#section are the same document to a server.
+type FragmentPolicy int
+
+const (
+	// StripFragments removes the fragments, so /page and /page#section are the same link. It's the default.
+	StripFragments FragmentPolicy = iota
+	// KeepFragments keeps the fragments, so /page and /page#section are distinct links, e.g. to crawl the
+	// views of a page rendered by a fetcher that runs its scripts.
+	KeepFragments
+)
+
+func newConfig(opts []Option) config {
+	c := config{}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithHashRoutes is an option to keep the fragments that are routes of single page applications,
+// like /#/settings or /#!/settings, so every route is a distinct link. Other fragments are removed,
+// unless the WithFragmentPolicy option keeps them.
+// As HTTP requests don't include the fragment, it's only useful with a fetcher that renders the routes.
+func WithHashRoutes() Option {
+	return func(config *config) {
+		config.keepHashRoutes = true
+	}
+}
+
+// WithFragmentPolicy is an option to set how the fragments of the links are handled. Hash routes kept
+// with the WithHashRoutes option are kept with any policy.
+func WithFragmentPolicy(policy FragmentPolicy) Option {
+	return func(config *config) {
+		config.fragmentPolicy = policy
+	}
+}
+
+// WithTrailingSlashPolicy is an option to set how the trailing slashes of the paths are handled.
+// The trailing slash of the root path is always removed, as it makes no difference.
+func WithTrailingSlashPolicy(policy TrailingSlashPolicy) Option {
+	return func(config *config) {
+		config.trailingSlashPolicy = policy
+	}
+}
+
+// WithCaseInsensitivePaths is an option to treat the paths case-insensitively when deciding whether
+// two links are the same page, as IIS and other Windows hosts do, so /About and /about have the same
+// Key. The links are still fetched as written.
+func WithCaseInsensitivePaths() Option {
+	return func(config *config) {
+		config.caseInsensitivePaths = true
+	}
+}
+
+// WithQueryStrings is an option to keep the query strings of the links, so /search?q=shoes and
+// /search?q=hats are distinct pages. By default they're removed, as a crawl following every variant
+// of the parameterized pages, like internal search results, may never end.
+func WithQueryStrings() Option {
+	return func(config *config) {
+		config.keepQueryStrings = true
+	}
+}
+
+// DefaultDocuments are the file names usually served for a directory URL by web servers.
+var DefaultDocuments = []string{"index.html", "index.htm", "index.php", "default.aspx", "default.asp"}
+
+// WithDefaultDocuments is an option to fold the links to the given default documents, like
+// /docs/index.html, into their directory URL, /docs/, so the same page is not counted twice.
+// File names are matched case-insensitively. See DefaultDocuments for the usual ones.
+func WithDefaultDocuments(fileNames ...string) Option {
+	return func(config *config) {
+		config.defaultDocuments = append(config.defaultDocuments, fileNames...)
+	}
+}
+
 // Extract extracts URLs from the given webpage content and returns a slice of normalized URLs.
-// The function parses the HTML content of the webpage and searches for links within the same domain as the provided webpageURL.
-func Extract(webpageURL url.URL, webpageContent io.Reader) ([]url.URL, error) {
+// The function parses the HTML content of the webpage and searches for links within the same host as the provided webpageURL,
+// or within the scope set with the WithScope option.
+// Relative links are resolved against the <base href> of the page if it declares one, or against the webpageURL otherwise.
+// With the WithResources option, the URLs of the assets the page loads are returned too.
+func Extract(webpageURL url.URL, webpageContent io.Reader, opts ...Option) ([]url.URL, error) {
 	parsedHtmlContent, err := html.Parse(webpageContent)
 	if err != nil {
 		return nil, err
 	}
 
-	links := searchDomainMatchingLinks(webpageURL, parsedHtmlContent)
+	config := newConfig(opts)
+	baseURL := findBaseURL(webpageURL, parsedHtmlContent)
+	links := linksOf(searchDomainMatchingLinks(webpageURL, baseURL, parsedHtmlContent, config))
+	if config.resources {
+		links = append(links, resourceLinks(searchDomainMatchingResources(webpageURL, baseURL, parsedHtmlContent, config))...)
+	}
 	linksWithoutDuplicates := removeDuplicates(links)
 
 	return linksWithoutDuplicates, nil
 }
 
-// Normalize normalizes the provided URL by removing the "www." prefix from the host
-// and removing any trailing slashes from the path.
-func Normalize(urlToNormalize url.URL) url.URL {
-	return url.URL{
-		Scheme: urlToNormalize.Scheme,
-		Host:   strings.Replace(urlToNormalize.Host, "www.", "", -1),
-		Path:   strings.TrimRight(urlToNormalize.Path, "/"),
+// Normalize normalizes the provided URL following RFC 3986: the scheme and the host are lower-cased,
+// internationalized hosts are converted to punycode, the default ports (:80 for http and :443 for https)
+// are removed, and the percent-encoded unreserved characters are decoded while the rest of the escapes
+// are upper-cased. It also removes the "www." prefix from the host and any trailing slashes from the
+// path, unless the WithTrailingSlashPolicy option says otherwise. The query string is removed, unless the
+// WithQueryStrings option keeps it, without the parameters of the WithStrippedQueryParameters option.
+// The fragment is removed, unless the WithFragmentPolicy option keeps it, or it's a hash route kept
+// with the WithHashRoutes option.
+func Normalize(urlToNormalize url.URL, opts ...Option) url.URL {
+	return normalize(urlToNormalize, newConfig(opts))
+}
+
+// Key returns the identity of a normalized link, used to decide whether two links are the same
+// page. It's the link itself, unless the KeepTrailingSlash policy makes /docs/ and /docs the same
+// page, or the WithCaseInsensitivePaths option makes /About and /about the same page.
+func Key(link url.URL, opts ...Option) string {
+	config := newConfig(opts)
+	if config.trailingSlashPolicy == KeepTrailingSlash {
+		link.Path = strings.TrimRight(link.Path, "/")
+	}
+	if config.caseInsensitivePaths {
+		link.Path = strings.ToLower(link.Path)
+		link.RawPath = ""
 	}
+	return link.String()
 }
 
-func searchDomainMatchingLinks(webpageURL url.URL, node *html.Node) []url.URL {
-	var links []url.URL
-	if node.Type == html.ElementNode && node.Data == "a" {
+func normalize(urlToNormalize url.URL, config config) url.URL {
+	escapedPath := foldDefaultDocument(normalizePercentEncoding(urlToNormalize.EscapedPath()), config.defaultDocuments)
+	if config.trailingSlashPolicy == StripTrailingSlash || strings.TrimRight(escapedPath, "/") == "" {
+		escapedPath = strings.TrimRight(escapedPath, "/")
+	}
+	path, err := url.PathUnescape(escapedPath)
+	if err != nil {
+		path, escapedPath = urlToNormalize.Path, ""
+	}
+	if (&url.URL{Path: path}).EscapedPath() == escapedPath {
+		// the raw path is only needed when it's not the default encoding of the path
+		escapedPath = ""
+	}
+	scheme := strings.ToLower(urlToNormalize.Scheme)
+	normalizedURL := url.URL{
+		Scheme:  scheme,
+		Host:    normalizeHost(urlToNormalize.Host, scheme),
+		Path:    path,
+		RawPath: escapedPath,
+	}
+	if config.keepQueryStrings {
+		normalizedURL.RawQuery = normalizePercentEncoding(stripQueryParameters(urlToNormalize.RawQuery, config.strippedQueryParameters))
+	}
+	if config.fragmentPolicy == KeepFragments || (config.keepHashRoutes && isHashRoute(urlToNormalize.Fragment)) {
+		normalizedURL.Fragment = urlToNormalize.Fragment
+	}
+	return normalizedURL
+}
+
+// foldDefaultDocument removes the file name of the path if it's one of the default documents.
+func foldDefaultDocument(path string, defaultDocuments []string) string {
+	directory, fileName := path[:strings.LastIndex(path, "/")+1], path[strings.LastIndex(path, "/")+1:]
+	for _, defaultDocument := range defaultDocuments {
+		if strings.EqualFold(fileName, defaultDocument) {
+			return directory
+		}
+	}
+	return path
+}
+
+// isHashRoute reports whether the fragment is a route of a single page application, like #/settings or #!/settings.
+func isHashRoute(fragment string) bool {
+	return strings.HasPrefix(fragment, "/") || strings.HasPrefix(fragment, "!")
+}
+
+// anchor is a link found in an <a> or <area> tag.
+type anchor struct {
+	link     url.URL
+	nofollow bool
+	// fragment is the fragment of the link as written, before normalizing it
+	fragment string
+}
+
+func searchDomainMatchingLinks(webpageURL, baseURL url.URL, node *html.Node, config config) []anchor {
+	var anchors []anchor
+	// <area> elements are the links of image maps
+	if node.Type == html.ElementNode && (node.Data == "a" || node.Data == "area") {
+		nofollow := false
+		var hrefs []string
 		for _, attr := range node.Attr {
-			if attr.Key == "href" {
-				hrefUrl, err := url.Parse(attr.Val)
-				if err != nil {
-					continue
-				}
-				normalizedLink := handleRelativeLink(webpageURL, Normalize(*hrefUrl))
-				if isValidLink(webpageURL, normalizedLink) {
-					links = append(links, normalizedLink)
-				}
+			switch attr.Key {
+			case "href":
+				hrefs = append(hrefs, attr.Val)
+			case "rel":
+				nofollow = isNofollow(attr.Val)
+			}
+		}
+		for _, href := range hrefs {
+			hrefUrl, err := url.Parse(href)
+			if err != nil {
+				continue
+			}
+			normalizedLink := resolveLink(baseURL, hrefUrl, config)
+			if isValidLink(webpageURL, normalizedLink, config) {
+				anchors = append(anchors, anchor{link: normalizedLink, nofollow: nofollow, fragment: hrefUrl.Fragment})
 			}
 		}
 	}
 
 	for child := node.FirstChild; child != nil; child = child.NextSibling {
-		links = append(links, searchDomainMatchingLinks(webpageURL, child)...)
+		anchors = append(anchors, searchDomainMatchingLinks(webpageURL, baseURL, child, config)...)
 	}
 
+	return anchors
+}
+
+// isNofollow reports whether the rel attribute of a link asks crawlers not to follow it.
+func isNofollow(rel string) bool {
+	for _, value := range strings.Fields(strings.ToLower(rel)) {
+		if value == "nofollow" || value == "ugc" || value == "sponsored" {
+			return true
+		}
+	}
+	return false
+}
+
+func linksOf(anchors []anchor) []url.URL {
+	links := make([]url.URL, len(anchors))
+	for i, anchor := range anchors {
+		links[i] = anchor.link
+	}
 	return links
 }
 
+// nofollowLinks returns the links found only in anchors that ask crawlers not to follow them.
+func nofollowLinks(anchors []anchor) []url.URL {
+	followed := make(map[string]bool)
+	for _, anchor := range anchors {
+		if !anchor.nofollow {
+			followed[anchor.link.String()] = true
+		}
+	}
+	var links []url.URL
+	for _, anchor := range anchors {
+		if anchor.nofollow && !followed[anchor.link.String()] {
+			links = append(links, anchor.link)
+		}
+	}
+	return removeDuplicates(links)
+}
+
 func removeDuplicates(links []url.URL) []url.URL {
 	uniqueMap := make(map[string]bool)
 	uniqueSlice := make([]url.URL, 0)
@@ -70,18 +301,33 @@ func removeDuplicates(links []url.URL) []url.URL {
 	return uniqueSlice
 }
 
-func handleRelativeLink(baseLink url.URL, relativeLink url.URL) url.URL {
-	if relativeLink.Host == "" || relativeLink.Scheme == "" {
-		return url.URL{
-			Scheme: baseLink.Scheme,
-			Host:   baseLink.Host,
-			Path:   relativeLink.Path,
+// findBaseURL returns the URL the relative links of the page are resolved against: the href of the
+// first <base> element of the page, resolved against the page URL, or the page URL if there is none.
+func findBaseURL(webpageURL url.URL, node *html.Node) url.URL {
+	if node.Type == html.ElementNode && node.Data == "base" {
+		for _, attr := range node.Attr {
+			if attr.Key != "href" {
+				continue
+			}
+			if baseHref, err := url.Parse(strings.TrimSpace(attr.Val)); err == nil {
+				return *webpageURL.ResolveReference(baseHref)
+			}
 		}
 	}
-	return relativeLink
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if baseURL := findBaseURL(webpageURL, child); baseURL != webpageURL {
+			return baseURL
+		}
+	}
+	return webpageURL
+}
+
+// resolveLink resolves a link found in the page against the base URL of the page and normalizes it.
+func resolveLink(baseURL url.URL, hrefUrl *url.URL, config config) url.URL {
+	return normalize(*baseURL.ResolveReference(hrefUrl), config)
 }
 
-func isValidLink(webpageURL url.URL, hrefValue url.URL) bool {
-	return (webpageURL.Host == hrefValue.Host || hrefValue.Host == "") &&
+func isValidLink(webpageURL url.URL, hrefValue url.URL, config config) bool {
+	return (hrefValue.Host == "" || config.inScope(webpageURL, hrefValue)) &&
 		(hrefValue.Scheme == "http" || hrefValue.Scheme == "https")
 }
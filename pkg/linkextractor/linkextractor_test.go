@@ -15,6 +15,7 @@ const (
 	htmlWithLinksWithoutNormalizing = `<a href="https://test.com"/><a href="https://www.test.com/contact"/>`
 	htmlWithRelativeLinks           = `<a href="https://test.com"/><a href="/contact"/>`
 	htmlWithMailtoLinks             = `<a href="https://test.com"/><a href="mailto://test.com/contact"/>`
+	htmlWithQueryLinks              = `<a href="/search?q=shoes"/><a href="https://test.com/search/?q=hats"/>`
 )
 
 func TestExtract(t *testing.T) {
@@ -24,6 +25,7 @@ func TestExtract(t *testing.T) {
 	type args struct {
 		webpageURL     url.URL
 		webpageContent io.ReadCloser
+		opts           []Option
 	}
 	tests := []struct {
 		name    string
@@ -86,6 +88,30 @@ func TestExtract(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "removes the query string of the links",
+			args: args{
+				webpageURL:     *testUrl,
+				webpageContent: io.NopCloser(strings.NewReader(htmlWithQueryLinks)),
+			},
+			want: []url.URL{
+				{Scheme: "https", Host: "test.com", Path: "/search"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "keeps the query string of the links with the query strings option",
+			args: args{
+				webpageURL:     *testUrl,
+				webpageContent: io.NopCloser(strings.NewReader(htmlWithQueryLinks)),
+				opts:           []Option{WithQueryStrings()},
+			},
+			want: []url.URL{
+				{Scheme: "https", Host: "test.com", Path: "/search", RawQuery: "q=shoes"},
+				{Scheme: "https", Host: "test.com", Path: "/search", RawQuery: "q=hats"},
+			},
+			wantErr: false,
+		},
 		{
 			name: "ignores non http/https links",
 			args: args{
@@ -100,7 +126,7 @@ func TestExtract(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := Extract(tt.args.webpageURL, tt.args.webpageContent)
+			got, err := Extract(tt.args.webpageURL, tt.args.webpageContent, tt.args.opts...)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Extract() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -115,6 +141,7 @@ func TestExtract(t *testing.T) {
 func TestNormalize(t *testing.T) {
 	type args struct {
 		urlToNormalize string
+		opts           []Option
 	}
 	tests := []struct {
 		name string
@@ -135,6 +162,71 @@ func TestNormalize(t *testing.T) {
 			},
 			want: "https://google.com",
 		},
+		{
+			name: "only removes the www. prefix",
+			args: args{
+				urlToNormalize: "https://awww.example.com/www.html",
+			},
+			want: "https://awww.example.com/www.html",
+		},
+		{
+			name: "lower-cases the scheme and the host",
+			args: args{
+				urlToNormalize: "HTTPS://WWW.Example.COM/About",
+			},
+			want: "https://example.com/About",
+		},
+		{
+			name: "removes the default ports",
+			args: args{
+				urlToNormalize: "https://example.com:443/docs",
+			},
+			want: "https://example.com/docs",
+		},
+		{
+			name: "removes the default http port",
+			args: args{
+				urlToNormalize: "http://example.com:80/docs",
+			},
+			want: "http://example.com/docs",
+		},
+		{
+			name: "keeps the other ports",
+			args: args{
+				urlToNormalize: "https://www.example.com:8443/docs",
+			},
+			want: "https://example.com:8443/docs",
+		},
+		{
+			name: "converts internationalized hosts to punycode",
+			args: args{
+				urlToNormalize: "https://München.de/straße",
+			},
+			want: "https://xn--mnchen-3ya.de/stra%C3%9Fe",
+		},
+		{
+			name: "decodes the escaped unreserved characters",
+			args: args{
+				urlToNormalize: "https://example.com/%7Euser/a%2Db?q=%7e",
+				opts:           []Option{WithQueryStrings()},
+			},
+			want: "https://example.com/~user/a-b?q=~",
+		},
+		{
+			name: "upper-cases the hex digits of the escapes",
+			args: args{
+				urlToNormalize: "https://example.com/a%2fb?q=a%2bb",
+				opts:           []Option{WithQueryStrings()},
+			},
+			want: "https://example.com/a%2Fb?q=a%2Bb",
+		},
+		{
+			name: "keeps IPv6 hosts",
+			args: args{
+				urlToNormalize: "http://[::1]:80/docs",
+			},
+			want: "http://[::1]/docs",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -142,9 +234,210 @@ func TestNormalize(t *testing.T) {
 			if err != nil {
 				t.Errorf("input url is not valid. %v", err)
 			}
-			if got := Normalize(*inputUrl); !reflect.DeepEqual(got.String(), tt.want) {
+			if got := Normalize(*inputUrl, tt.args.opts...); !reflect.DeepEqual(got.String(), tt.want) {
 				t.Errorf("Normalize() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestExtract_WithHashRoutes(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/app/")
+	htmlWithHashRoutes := `<a href="#/settings"/><a href="/app#!/profile"/><a href="/about#team"/><a href="https://test.com/app/#/settings"/>`
+
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{name: "removes fragments by default", want: []string{"https://test.com/app", "https://test.com/about"}},
+		{name: "keeps hash routes with WithHashRoutes", opts: []Option{WithHashRoutes()}, want: []string{"https://test.com/app#/settings", "https://test.com/app#!/profile", "https://test.com/about"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, err := Extract(*testUrl, strings.NewReader(htmlWithHashRoutes), tt.opts...)
+			if err != nil {
+				t.Fatalf("should not throw error at Extract. err: %v", err)
+			}
+			var got []string
+			for _, link := range links {
+				got = append(got, link.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize_WithTrailingSlashPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  TrailingSlashPolicy
+		link    string
+		want    string
+		wantKey string
+	}{
+		{name: "strip removes the trailing slash", policy: StripTrailingSlash, link: "https://test.com/docs/", want: "https://test.com/docs", wantKey: "https://test.com/docs"},
+		{name: "keep keeps the trailing slash but not in the key", policy: KeepTrailingSlash, link: "https://test.com/docs/", want: "https://test.com/docs/", wantKey: "https://test.com/docs"},
+		{name: "distinct keeps the trailing slash in the key", policy: DistinctTrailingSlash, link: "https://test.com/docs/", want: "https://test.com/docs/", wantKey: "https://test.com/docs/"},
+		{name: "distinct removes the slash of the root path", policy: DistinctTrailingSlash, link: "https://test.com/", want: "https://test.com", wantKey: "https://test.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputUrl, _ := url.Parse(tt.link)
+			got := Normalize(*inputUrl, WithTrailingSlashPolicy(tt.policy))
+			if got.String() != tt.want {
+				t.Errorf("Normalize() = %v, want %v", got.String(), tt.want)
+			}
+			if gotKey := Key(got, WithTrailingSlashPolicy(tt.policy)); gotKey != tt.wantKey {
+				t.Errorf("Key() = %v, want %v", gotKey, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestNormalize_WithFragmentPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		link string
+		want string
+	}{
+		{name: "strips the fragments by default", link: "https://test.com/page#section", want: "https://test.com/page"},
+		{name: "strip removes the fragments", opts: []Option{WithFragmentPolicy(StripFragments)}, link: "https://test.com/page#section", want: "https://test.com/page"},
+		{name: "keep keeps the fragments", opts: []Option{WithFragmentPolicy(KeepFragments), WithQueryStrings()}, link: "https://www.test.com/page/?q=1#section", want: "https://test.com/page?q=1#section"},
+		{name: "keep has no effect without a fragment", opts: []Option{WithFragmentPolicy(KeepFragments)}, link: "https://test.com/page", want: "https://test.com/page"},
+		{name: "strip keeps the hash routes", opts: []Option{WithFragmentPolicy(StripFragments), WithHashRoutes()}, link: "https://test.com/#/settings", want: "https://test.com#/settings"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputUrl, _ := url.Parse(tt.link)
+			if got := Normalize(*inputUrl, tt.opts...); got.String() != tt.want {
+				t.Errorf("Normalize() = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestKey_WithCaseInsensitivePaths(t *testing.T) {
+	link, _ := url.Parse("https://test.com/About/Team?Tab=Sales")
+
+	if got := Key(*link); got != "https://test.com/About/Team?Tab=Sales" {
+		t.Errorf("Key() = %v, want the link unchanged", got)
+	}
+	if got := Key(*link, WithCaseInsensitivePaths()); got != "https://test.com/about/team?Tab=Sales" {
+		t.Errorf("Key() = %v, want the path lower-cased", got)
+	}
+}
+
+func TestExtract_WithBaseHref(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/blog/2024/post")
+	tests := []struct {
+		name string
+		html string
+		want []string
+	}{
+		{
+			name: "resolves relative links against the page without a base",
+			html: `<a href="next"/><a href="../archive"/><a href="/about"/>`,
+			want: []string{"https://test.com/blog/2024/next", "https://test.com/blog/archive", "https://test.com/about"},
+		},
+		{
+			name: "resolves relative links against the base href",
+			html: `<head><base href="/docs/v2/"></head><a href="intro"/><a href="../v1/intro"/><a href="/about"/><a href="?page=2"/>`,
+			want: []string{"https://test.com/docs/v2/intro", "https://test.com/docs/v1/intro", "https://test.com/about", "https://test.com/docs/v2?page=2"},
+		},
+		{
+			name: "resolves against an absolute base href of another host",
+			html: `<base href="https://cdn.test.com/"><a href="contact"/><a href="https://test.com/contact"/>`,
+			want: []string{"https://test.com/contact"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, err := Extract(*testUrl, strings.NewReader(tt.html), WithQueryStrings())
+			if err != nil {
+				t.Fatalf("should not throw error at Extract. err: %v", err)
+			}
+			var got []string
+			for _, link := range links {
+				got = append(got, link.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtract_WithImageMap(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+	htmlWithImageMap := `<img src="/map.png" usemap="#regions"><map name="regions">
+		<area shape="rect" coords="0,0,50,50" href="/north" alt="North">
+		<area shape="rect" coords="50,0,100,50" href="https://other.com/south" alt="South">
+		<area shape="default" nohref alt="Nowhere"></map><a href="/contact">Contact</a>`
+
+	links, err := Extract(*testUrl, strings.NewReader(htmlWithImageMap))
+	if err != nil {
+		t.Fatalf("should not throw error at Extract. err: %v", err)
+	}
+	var got []string
+	for _, link := range links {
+		got = append(got, link.String())
+	}
+	want := []string{"https://test.com/north", "https://test.com/contact"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract() got = %v, want %v", got, want)
+	}
+}
+
+func TestNormalize_WithDefaultDocuments(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{link: "https://test.com/index.html", want: "https://test.com"},
+		{link: "https://test.com/docs/index.php?lang=en", want: "https://test.com/docs?lang=en"},
+		{link: "https://test.com/shop/Default.aspx", want: "https://test.com/shop"},
+		{link: "https://test.com/docs/index.html.bak", want: "https://test.com/docs/index.html.bak"},
+		{link: "https://test.com/docs/about.html", want: "https://test.com/docs/about.html"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			inputUrl, _ := url.Parse(tt.link)
+			if got := Normalize(*inputUrl, WithDefaultDocuments(DefaultDocuments...), WithQueryStrings()); got.String() != tt.want {
+				t.Errorf("Normalize() = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize_WithStrippedQueryParameters(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{link: "https://test.com/pricing?utm_source=newsletter&utm_medium=email", want: "https://test.com/pricing"},
+		{link: "https://test.com/search?q=go&gclid=abc&page=2", want: "https://test.com/search?q=go&page=2"},
+		{link: "https://test.com/post?UTM_Campaign=x&fbclid=y", want: "https://test.com/post"},
+		{link: "https://test.com/post?utm%5Fsource=x&id=1", want: "https://test.com/post?id=1"},
+		{link: "https://test.com/post?utmost=1", want: "https://test.com/post?utmost=1"},
+		{link: "https://test.com/post?b=2&a=1", want: "https://test.com/post?b=2&a=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			inputUrl, _ := url.Parse(tt.link)
+			if got := Normalize(*inputUrl, WithQueryStrings(), WithStrippedQueryParameters(TrackingParameters...)); got.String() != tt.want {
+				t.Errorf("Normalize() = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run("doesn't keep the query strings by itself", func(t *testing.T) {
+		inputUrl, _ := url.Parse("https://test.com/search?q=go&utm_source=x&page=2")
+		if got := Normalize(*inputUrl, WithStrippedQueryParameters(TrackingParameters...)); got.String() != "https://test.com/search" {
+			t.Errorf("Normalize() = %v, want %v", got.String(), "https://test.com/search")
+		}
+	})
+}
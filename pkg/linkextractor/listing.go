@@ -0,0 +1,71 @@
+package linkextractor
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// directoryListingTitles are the beginnings of the titles and headings of the directory indexes
+// generated by the web servers: Apache, Nginx and lighttpd use "Index of /path", Python's http.server
+// "Directory listing for /path".
+var directoryListingTitles = []string{"index of /", "directory listing for /"}
+
+// iisParentDirectory is the text of the link to the parent directory of the IIS directory browsing pages.
+const iisParentDirectory = "[to parent directory]"
+
+// isDirectoryListing reports whether the page is a directory index generated by the web server, like
+// the Apache, Nginx, lighttpd, IIS or Python http.server listings, rather than a page of the site.
+func isDirectoryListing(root *html.Node) bool {
+	for _, tag := range []string{"title", "h1"} {
+		if element := findElement(root, tag); element != nil {
+			text := strings.ToLower(strings.TrimSpace(textContent(element)))
+			for _, title := range directoryListingTitles {
+				if strings.HasPrefix(text, title) {
+					return true
+				}
+			}
+		}
+	}
+	if body := findElement(root, "body"); body != nil {
+		return strings.Contains(strings.ToLower(textContent(body)), iisParentDirectory)
+	}
+	return false
+}
+
+// textContent returns the text of the node and its descendants.
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+	var text strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		text.WriteString(textContent(child))
+	}
+	return text.String()
+}
+
+// DirectoryEntries returns the links of a directory listing that are entries of the listed directory,
+// its files and subdirectories, leaving out the link to the parent directory and the links that only
+// sort the listing, like the ?C=M;O=D links of Apache.
+//
+// Example usage:
+//
+//	page, _ := linkextractor.ExtractPage(listingURL, body)
+//	if page.DirectoryListing {
+//		entries := linkextractor.DirectoryEntries(listingURL, page.Links)
+//	}
+func DirectoryEntries(listingURL url.URL, links []url.URL) []url.URL {
+	directory := strings.TrimSuffix(listingURL.Path, "/") + "/"
+	var entries []url.URL
+	for _, link := range links {
+		if link.Host != listingURL.Host || link.RawQuery != "" {
+			continue
+		}
+		if strings.HasPrefix(link.Path, directory) && len(link.Path) > len(directory) {
+			entries = append(entries, link)
+		}
+	}
+	return entries
+}
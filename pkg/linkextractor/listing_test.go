@@ -0,0 +1,65 @@
+package linkextractor
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const apacheListing = `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 3.2 Final//EN">
+<html>
+ <head><title>Index of /files</title></head>
+ <body>
+<h1>Index of /files</h1>
+<table>
+<tr><th><a href="?C=N;O=D">Name</a></th><th><a href="?C=M;O=A">Last modified</a></th></tr>
+<tr><td><a href="/">Parent Directory</a></td></tr>
+<tr><td><a href="report.pdf">report.pdf</a></td></tr>
+<tr><td><a href="old/">old/</a></td></tr>
+</table>
+</body></html>`
+
+func TestExtractPage_DirectoryListing(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/files/")
+
+	tests := []struct {
+		name        string
+		htmlContent string
+		want        bool
+	}{
+		{name: "apache", htmlContent: apacheListing, want: true},
+		{name: "nginx", htmlContent: `<html><head><title>Index of /files/</title></head><body><h1>Index of /files/</h1><hr><pre><a href="../">../</a></pre></body></html>`, want: true},
+		{name: "python http.server", htmlContent: `<html><head><title>Directory listing for /files/</title></head><body><h1>Directory listing for /files/</h1></body></html>`, want: true},
+		{name: "iis", htmlContent: `<html><head><title>test.com - /files/</title></head><body><pre><A HREF="/">[To Parent Directory]</A><br></pre></body></html>`, want: true},
+		{name: "page of the site", htmlContent: `<html><head><title>Files</title></head><body><h1>Index of our files</h1></body></html>`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := ExtractPage(*testUrl, strings.NewReader(tt.htmlContent))
+			if err != nil {
+				t.Fatalf("should not throw error at ExtractPage. err: %v", err)
+			}
+			if page.DirectoryListing != tt.want {
+				t.Errorf("DirectoryListing = %v, want %v", page.DirectoryListing, tt.want)
+			}
+		})
+	}
+}
+
+func TestDirectoryEntries(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/files/")
+	page, err := ExtractPage(*testUrl, strings.NewReader(apacheListing))
+	if err != nil {
+		t.Fatalf("should not throw error at ExtractPage. err: %v", err)
+	}
+
+	var got []string
+	for _, entry := range DirectoryEntries(*testUrl, page.Links) {
+		got = append(got, entry.String())
+	}
+	want := []string{"https://test.com/files/report.pdf", "https://test.com/files/old"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DirectoryEntries() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+package linkextractor
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Alternate is a localized version of a page, declared with a <link rel="alternate" hreflang="..."> tag.
+type Alternate struct {
+	Lang string
+	URL  url.URL
+}
+
+// Meta holds the metadata a page declares about itself in its HTML head.
+type Meta struct {
+	// Canonical is the URL of the <link rel="canonical"> tag, or nil if the page doesn't declare one.
+	Canonical *url.URL
+	// Alternates are the localized versions of the page.
+	Alternates []Alternate
+	// Robots are the lower-cased directives of the <meta name="robots"> tags, e.g. "noindex".
+	Robots []string
+}
+
+// NoIndex reports whether the page asks search engines not to index it.
+func (m Meta) NoIndex() bool {
+	for _, directive := range m.Robots {
+		if directive == "noindex" || directive == "none" {
+			return true
+		}
+	}
+	return false
+}
+
+// NoFollow reports whether the page asks crawlers not to follow its links.
+func (m Meta) NoFollow() bool {
+	for _, directive := range m.Robots {
+		if directive == "nofollow" || directive == "none" {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractMeta extracts the metadata declared in the given webpage content. The URLs are resolved
+// and normalized like the extracted links.
+func ExtractMeta(webpageURL url.URL, webpageContent io.Reader) (Meta, error) {
+	parsedHtmlContent, err := html.Parse(webpageContent)
+	if err != nil {
+		return Meta{}, err
+	}
+	meta := Meta{}
+	searchMeta(findBaseURL(webpageURL, parsedHtmlContent), parsedHtmlContent, newConfig(nil), &meta)
+	return meta, nil
+}
+
+// Page holds everything extracted from a webpage by ExtractPage.
+type Page struct {
+	// Links are the links of the page, the same ones returned by Extract.
+	Links []url.URL
+	// NofollowLinks are the links that are only found in anchors whose rel attribute asks crawlers not
+	// to follow them ("nofollow", "ugc" or "sponsored"). They're included in Links too.
+	NofollowLinks []url.URL
+	// ExternalLinks are the links to the hosts out of the scope, if ExtractPage was given the
+	// WithExternalLinks option. They're not included in Links.
+	ExternalLinks []url.URL
+	// Meta is the metadata of the page, the same one returned by ExtractMeta, except that its URLs are
+	// normalized with the options given to ExtractPage.
+	Meta Meta
+	// Resources are the assets the page loads, if ExtractPage was given the WithResources option.
+	// They're not included in Links.
+	Resources []Resource
+	// ThirdPartyResources are the assets the page loads from the hosts out of the scope, if ExtractPage
+	// was given the WithThirdPartyResources option.
+	ThirdPartyResources []Resource
+	// IDs are the fragments the sections of the page can be linked with, if ExtractPage was given the
+	// WithAnchors option.
+	IDs []string
+	// FragmentLinks are the links with a fragment, like /docs/setup#install, including the links to a
+	// section of the page itself, if ExtractPage was given the WithAnchors option.
+	FragmentLinks []url.URL
+	// ContentHash is the hash of the text of the main content of the page, if ExtractPage was given the
+	// WithContentHash option. It only changes when the text of the page does.
+	ContentHash string
+	// DirectoryListing tells whether the page is a directory index generated by the web server, like
+	// the "Index of /" pages of Apache and Nginx. See DirectoryEntries.
+	DirectoryListing bool
+}
+
+// ExtractPage extracts the links and the metadata of the given webpage content, parsing it only once.
+func ExtractPage(webpageURL url.URL, webpageContent io.Reader, opts ...Option) (Page, error) {
+	parsedHtmlContent, err := html.Parse(webpageContent)
+	if err != nil {
+		return Page{}, err
+	}
+	config := newConfig(opts)
+	baseURL := findBaseURL(webpageURL, parsedHtmlContent)
+	anchors := searchDomainMatchingLinks(webpageURL, baseURL, parsedHtmlContent, config)
+	page := Page{Links: removeDuplicates(linksOf(anchors)), NofollowLinks: nofollowLinks(anchors), DirectoryListing: isDirectoryListing(parsedHtmlContent)}
+	searchMeta(baseURL, parsedHtmlContent, config, &page.Meta)
+	if config.resources {
+		page.Resources = removeDuplicateResources(searchDomainMatchingResources(webpageURL, baseURL, parsedHtmlContent, config))
+	}
+	if config.thirdPartyResources {
+		page.ThirdPartyResources = removeDuplicateResources(searchThirdPartyResources(webpageURL, baseURL, parsedHtmlContent, config))
+	}
+	if config.externalLinks {
+		page.ExternalLinks = removeDuplicates(searchExternalLinks(webpageURL, baseURL, parsedHtmlContent, config))
+	}
+	if config.anchors {
+		page.IDs = removeDuplicateStrings(searchIDs(parsedHtmlContent))
+		page.FragmentLinks = fragmentLinks(anchors)
+	}
+	if config.contentHash {
+		page.ContentHash = hashContent(parsedHtmlContent)
+	}
+	return page, nil
+}
+
+func searchMeta(baseURL url.URL, node *html.Node, config config, meta *Meta) {
+	if node.Type == html.ElementNode && (node.Data == "link" || node.Data == "meta") {
+		attrs := make(map[string]string)
+		for _, attr := range node.Attr {
+			attrs[attr.Key] = attr.Val
+		}
+		switch {
+		case node.Data == "meta" && strings.EqualFold(attrs["name"], "robots"):
+			meta.Robots = append(meta.Robots, ParseRobotsDirectives(attrs["content"])...)
+		case node.Data == "link" && attrs["href"] != "":
+			hrefUrl, err := url.Parse(attrs["href"])
+			if err != nil {
+				break
+			}
+			link := resolveLink(baseURL, hrefUrl, config)
+			rel := strings.ToLower(attrs["rel"])
+			if rel == "canonical" && meta.Canonical == nil {
+				meta.Canonical = &link
+			}
+			if rel == "alternate" && attrs["hreflang"] != "" {
+				meta.Alternates = append(meta.Alternates, Alternate{Lang: attrs["hreflang"], URL: link})
+			}
+		}
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		searchMeta(baseURL, child, config, meta)
+	}
+}
+
+// ParseRobotsDirectives parses a comma separated list of robots directives, like the content of a
+// <meta name="robots"> tag or an X-Robots-Tag header, into lower-cased directives.
+func ParseRobotsDirectives(value string) []string {
+	var directives []string
+	for _, directive := range strings.Split(value, ",") {
+		if directive = strings.ToLower(strings.TrimSpace(directive)); directive != "" {
+			directives = append(directives, directive)
+		}
+	}
+	return directives
+}
@@ -0,0 +1,91 @@
+package linkextractor
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractMeta(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/en/about")
+	htmlWithMeta := `<head>
+		<link rel="canonical" href="/about/">
+		<link rel="canonical" href="/ignored">
+		<meta name="Robots" content="NoIndex, follow">
+		<link rel="alternate" hreflang="de" href="/de/about/">
+		<link rel="Alternate" hreflang="x-default" href="https://www.test.com/about">
+		<link rel="alternate" type="application/rss+xml" href="/feed">
+		<link rel="stylesheet" hreflang="en" href="/style.css">
+	</head>`
+
+	got, err := ExtractMeta(*testUrl, strings.NewReader(htmlWithMeta))
+	if err != nil {
+		t.Fatalf("should not throw error at ExtractMeta. err: %v", err)
+	}
+	want := Meta{
+		Canonical: &url.URL{Scheme: "https", Host: "test.com", Path: "/about"},
+		Alternates: []Alternate{
+			{Lang: "de", URL: url.URL{Scheme: "https", Host: "test.com", Path: "/de/about"}},
+			{Lang: "x-default", URL: url.URL{Scheme: "https", Host: "test.com", Path: "/about"}},
+		},
+		Robots: []string{"noindex", "follow"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractMeta() got = %+v, want %+v", got, want)
+	}
+	if !got.NoIndex() {
+		t.Errorf("NoIndex() should be true")
+	}
+	if got.NoFollow() {
+		t.Errorf("NoFollow() should be false")
+	}
+	if !(Meta{Robots: []string{"none"}}).NoFollow() {
+		t.Errorf("NoFollow() should be true for the none directive")
+	}
+}
+
+func TestExtractMeta_WithoutMeta(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com")
+
+	got, err := ExtractMeta(*testUrl, strings.NewReader(htmlWithLinks))
+	if err != nil {
+		t.Fatalf("should not throw error at ExtractMeta. err: %v", err)
+	}
+	if got.Canonical != nil || len(got.Alternates) != 0 || got.NoIndex() {
+		t.Errorf("ExtractMeta() got = %+v, want no meta", got)
+	}
+}
+
+func TestExtractPage(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/about?utm_source=newsletter")
+	htmlWithCanonical := `<head><link rel="canonical" href="https://test.com/about"></head>
+		<body><a href="/contact">Contact</a><a href="/contact">Contact</a>
+		<a href="/login" rel="nofollow">Login</a><a href="/forum" rel="UGC noopener">Forum</a>
+		<a href="/pricing" rel="sponsored">Pricing</a><a href="/pricing">Pricing</a></body>`
+
+	got, err := ExtractPage(*testUrl, strings.NewReader(htmlWithCanonical))
+	if err != nil {
+		t.Fatalf("should not throw error at ExtractPage. err: %v", err)
+	}
+	wantLinks := []url.URL{
+		{Scheme: "https", Host: "test.com", Path: "/contact"},
+		{Scheme: "https", Host: "test.com", Path: "/login"},
+		{Scheme: "https", Host: "test.com", Path: "/forum"},
+		{Scheme: "https", Host: "test.com", Path: "/pricing"},
+	}
+	if !reflect.DeepEqual(got.Links, wantLinks) {
+		t.Errorf("ExtractPage() links got = %v, want %v", got.Links, wantLinks)
+	}
+	wantNofollow := []url.URL{
+		{Scheme: "https", Host: "test.com", Path: "/login"},
+		{Scheme: "https", Host: "test.com", Path: "/forum"},
+	}
+	if !reflect.DeepEqual(got.NofollowLinks, wantNofollow) {
+		t.Errorf("ExtractPage() nofollow links got = %v, want %v", got.NofollowLinks, wantNofollow)
+	}
+	wantCanonical := &url.URL{Scheme: "https", Host: "test.com", Path: "/about"}
+	if !reflect.DeepEqual(got.Meta.Canonical, wantCanonical) {
+		t.Errorf("ExtractPage() canonical got = %v, want %v", got.Meta.Canonical, wantCanonical)
+	}
+}
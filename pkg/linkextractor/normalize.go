@@ -0,0 +1,79 @@
+package linkextractor
+
+import (
+	"net"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// defaultPorts are the ports implied by the schemes of the links, which are removed from their hosts.
+var defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+// normalizeHost lower-cases the host, removes its "www." prefix and the default port of the scheme,
+// and converts internationalized domain names to punycode, so münchen.de and xn--mnchen-3ya.de are
+// the same host.
+func normalizeHost(host, scheme string) string {
+	hostname, port := host, ""
+	if splitHostname, splitPort, err := net.SplitHostPort(host); err == nil {
+		hostname, port = splitHostname, splitPort
+	}
+	hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+	hostname = strings.TrimPrefix(hostname, "www.")
+	if asciiHostname, err := idna.ToASCII(hostname); err == nil {
+		hostname = asciiHostname
+	}
+	if strings.Contains(hostname, ":") {
+		// IPv6 addresses are enclosed in brackets, with or without a port
+		hostname = "[" + strings.Trim(hostname, "[]") + "]"
+	}
+	if port == "" || port == defaultPorts[scheme] {
+		return hostname
+	}
+	return hostname + ":" + port
+}
+
+// normalizePercentEncoding decodes the percent-encoded unreserved characters of an escaped path or
+// query, like %7E for "~", and upper-cases the hexadecimal digits of the rest of the escapes, so the
+// same URL written with different escapes is one link.
+func normalizePercentEncoding(escaped string) string {
+	if !strings.Contains(escaped, "%") {
+		return escaped
+	}
+	var normalized strings.Builder
+	for i := 0; i < len(escaped); i++ {
+		if escaped[i] != '%' || i+2 >= len(escaped) || !isHex(escaped[i+1]) || !isHex(escaped[i+2]) {
+			normalized.WriteByte(escaped[i])
+			continue
+		}
+		decoded := unhex(escaped[i+1])<<4 | unhex(escaped[i+2])
+		if isUnreserved(decoded) {
+			normalized.WriteByte(decoded)
+		} else {
+			normalized.WriteString(strings.ToUpper(escaped[i : i+3]))
+		}
+		i += 2
+	}
+	return normalized.String()
+}
+
+// isUnreserved reports whether the character can be written unescaped in any component of a URL,
+// as defined in RFC 3986.
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
@@ -0,0 +1,54 @@
+package linkextractor
+
+import (
+	"net/url"
+	"strings"
+)
+
+// TrackingParameters are the query parameters usually added to the links by marketing and analytics
+// tools, which don't change the page they point to.
+var TrackingParameters = []string{"utm_*", "gclid", "gbraid", "wbraid", "dclid", "fbclid", "msclkid", "yclid", "twclid", "igshid", "mc_cid", "mc_eid", "_ga", "_gl"}
+
+// WithStrippedQueryParameters is an option to remove the given query parameters of the links when
+// normalizing them, so the same page linked with different marketing parameters, like ?utm_source=x,
+// is not counted as many pages. Names are matched case-insensitively, and a name ending with "*"
+// matches every parameter with that prefix. See TrackingParameters for the usual ones.
+// The parameters are removed from the query strings kept with the WithQueryStrings option, this option
+// doesn't keep them by itself, as the query strings are removed otherwise.
+func WithStrippedQueryParameters(names ...string) Option {
+	return func(config *config) {
+		config.strippedQueryParameters = append(config.strippedQueryParameters, names...)
+	}
+}
+
+// stripQueryParameters removes the parameters matching the names from the raw query, keeping the
+// rest of it as written.
+func stripQueryParameters(rawQuery string, names []string) string {
+	if rawQuery == "" || len(names) == 0 {
+		return rawQuery
+	}
+	var kept []string
+	for _, parameter := range strings.Split(rawQuery, "&") {
+		name, _, _ := strings.Cut(parameter, "=")
+		if unescapedName, err := url.QueryUnescape(name); err == nil {
+			name = unescapedName
+		}
+		if !matchesParameterName(name, names) {
+			kept = append(kept, parameter)
+		}
+	}
+	return strings.Join(kept, "&")
+}
+
+func matchesParameterName(name string, names []string) bool {
+	for _, pattern := range names {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if len(name) >= len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+				return true
+			}
+		} else if strings.EqualFold(name, pattern) {
+			return true
+		}
+	}
+	return false
+}
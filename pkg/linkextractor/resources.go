@@ -0,0 +1,193 @@
+package linkextractor
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ResourceType is the kind of asset a resource link points to.
+type ResourceType string
+
+const (
+	// ImageResource is an image, from the src of an <img> tag or the srcset of an <img> or <source> tag.
+	ImageResource ResourceType = "image"
+	// ScriptResource is a script, from the src of a <script> tag.
+	ScriptResource ResourceType = "script"
+	// StylesheetResource is a stylesheet, from the href of a <link rel="stylesheet"> tag.
+	StylesheetResource ResourceType = "stylesheet"
+	// LinkResource is any other asset linked with a <link> tag, like an icon or a preloaded font.
+	LinkResource ResourceType = "link"
+	// FrameResource is a page embedded with an <iframe> tag.
+	FrameResource ResourceType = "frame"
+	// MediaResource is a video or audio file, from the src of a <source> tag.
+	MediaResource ResourceType = "media"
+	// StyleResource is an asset referenced with url() in CSS, like a background image or a web font.
+	StyleResource ResourceType = "style"
+)
+
+// Resource is an asset the page loads, like an image or a script, tagged by its type.
+type Resource struct {
+	Type ResourceType
+	URL  url.URL
+}
+
+// WithResources is an option to also extract the same-domain assets the page loads, from the src
+// of <img>, <script>, <iframe> and <source> tags, the srcset of <img> and <source> tags with the
+// responsive image variants, the href of <link> tags, and the url() references of the <style>
+// blocks and style attributes. Extract returns them along with the links, and ExtractPage returns
+// them tagged by type in Page.Resources. The canonical and alternate <link> tags are not assets,
+// so they're left out. Use ExtractStylesheet to search the fetched .css files.
+func WithResources() Option {
+	return func(config *config) {
+		config.resources = true
+	}
+}
+
+// WithThirdPartyResources is an option to also extract the assets the page loads from the hosts out of
+// the scope, like the scripts of analytics and ads providers, the fonts and the styles of CDNs, the
+// images and the embedded iframes. ExtractPage returns them tagged by type in Page.ThirdPartyResources,
+// for the supply-chain and privacy reviews of the site. They're never returned as links to crawl.
+func WithThirdPartyResources() Option {
+	return func(config *config) {
+		config.thirdPartyResources = true
+	}
+}
+
+// searchThirdPartyResources returns the assets the page loads from the hosts out of the scope.
+func searchThirdPartyResources(webpageURL, baseURL url.URL, node *html.Node, config config) []Resource {
+	anyScope := config
+	anyScope.scope = func(_, _ url.URL) bool { return true }
+	var resources []Resource
+	for _, resource := range searchDomainMatchingResources(webpageURL, baseURL, node, anyScope) {
+		if !config.inScope(webpageURL, resource.URL) {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+func searchDomainMatchingResources(webpageURL, baseURL url.URL, node *html.Node, config config) []Resource {
+	var resources []Resource
+	if node.Type == html.ElementNode {
+		for _, attr := range node.Attr {
+			if attr.Key == "style" {
+				resources = append(resources, searchCSSResources(webpageURL, baseURL, attr.Val, config)...)
+			}
+		}
+		if node.Data == "style" && node.FirstChild != nil && node.FirstChild.Type == html.TextNode {
+			resources = append(resources, searchCSSResources(webpageURL, baseURL, node.FirstChild.Data, config)...)
+		}
+		resourceType, attrKey, ok := resourceOf(node)
+		for _, attr := range node.Attr {
+			var references []string
+			referenceType := resourceType
+			switch {
+			case ok && attr.Key == attrKey:
+				references = []string{attr.Val}
+			case attr.Key == "srcset" && (node.Data == "img" || node.Data == "source"):
+				// the srcset of a <source> is only allowed inside a <picture>, so it's always an image
+				referenceType, references = ImageResource, parseSrcset(attr.Val)
+			}
+			for _, reference := range references {
+				srcUrl, err := url.Parse(strings.TrimSpace(reference))
+				if err != nil || strings.TrimSpace(reference) == "" {
+					continue
+				}
+				normalizedLink := resolveLink(baseURL, srcUrl, config)
+				if isValidLink(webpageURL, normalizedLink, config) {
+					resources = append(resources, Resource{Type: referenceType, URL: normalizedLink})
+				}
+			}
+		}
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		resources = append(resources, searchDomainMatchingResources(webpageURL, baseURL, child, config)...)
+	}
+
+	return resources
+}
+
+// resourceOf returns the type of the resource loaded by the element and the attribute holding its URL.
+func resourceOf(node *html.Node) (ResourceType, string, bool) {
+	switch node.Data {
+	case "img":
+		return ImageResource, "src", true
+	case "script":
+		return ScriptResource, "src", true
+	case "iframe":
+		return FrameResource, "src", true
+	case "source":
+		return MediaResource, "src", true
+	case "link":
+		var rel string
+		for _, attr := range node.Attr {
+			if attr.Key == "rel" {
+				rel = strings.ToLower(attr.Val)
+			}
+		}
+		for _, value := range strings.Fields(rel) {
+			switch value {
+			case "canonical", "alternate":
+				return "", "", false
+			case "stylesheet":
+				return StylesheetResource, "href", true
+			}
+		}
+		return LinkResource, "href", true
+	}
+	return "", "", false
+}
+
+// parseSrcset returns the URLs of the image candidates of a srcset attribute, like
+// "small.jpg 480w, large.jpg 1080w". As URLs can contain commas, a candidate URL is
+// everything up to the first whitespace, without its trailing commas.
+func parseSrcset(srcset string) []string {
+	var urls []string
+	for srcset != "" {
+		srcset = strings.TrimLeft(srcset, " \t\n\r\f,")
+		end := strings.IndexAny(srcset, " \t\n\r\f")
+		if end < 0 {
+			end = len(srcset)
+		}
+		candidateURL := srcset[:end]
+		srcset = srcset[end:]
+		if trimmedURL := strings.TrimRight(candidateURL, ","); trimmedURL != candidateURL {
+			// the commas after the URL end the candidate, which has no descriptors
+			candidateURL = trimmedURL
+		} else if descriptorsEnd := strings.Index(srcset, ","); descriptorsEnd >= 0 {
+			srcset = srcset[descriptorsEnd+1:]
+		} else {
+			srcset = ""
+		}
+		if candidateURL != "" {
+			urls = append(urls, candidateURL)
+		}
+	}
+	return urls
+}
+
+func removeDuplicateResources(resources []Resource) []Resource {
+	uniqueMap := make(map[string]bool)
+	uniqueSlice := make([]Resource, 0)
+
+	for _, resource := range resources {
+		key := string(resource.Type) + " " + resource.URL.String()
+		if !uniqueMap[key] {
+			uniqueMap[key] = true
+			uniqueSlice = append(uniqueSlice, resource)
+		}
+	}
+
+	return uniqueSlice
+}
+
+func resourceLinks(resources []Resource) []url.URL {
+	links := make([]url.URL, len(resources))
+	for i, resource := range resources {
+		links[i] = resource.URL
+	}
+	return links
+}
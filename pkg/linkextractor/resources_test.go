@@ -0,0 +1,135 @@
+package linkextractor
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const htmlWithResources = `<head>
+	<link rel="stylesheet" href="/css/site.css"><link rel="icon" href="/favicon.ico">
+	<link rel="canonical" href="/page"><script src="https://cdn.other.com/lib.js"></script>
+	<script src="/js/app.js"></script><script>inline()</script></head>
+	<body><a href="/contact">Contact</a><img src="images/logo.png"><img src="images/logo.png">
+	<iframe src="/embed/map"></iframe><video><source src="/media/intro.mp4"></video></body>`
+
+func TestExtractPage_WithResources(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/page")
+
+	got, err := ExtractPage(*testUrl, strings.NewReader(htmlWithResources), WithResources())
+	if err != nil {
+		t.Fatalf("should not throw error at ExtractPage. err: %v", err)
+	}
+	want := []Resource{
+		{Type: StylesheetResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/css/site.css"}},
+		{Type: LinkResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/favicon.ico"}},
+		{Type: ScriptResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/js/app.js"}},
+		{Type: ImageResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/images/logo.png"}},
+		{Type: FrameResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/embed/map"}},
+		{Type: MediaResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/media/intro.mp4"}},
+	}
+	if !reflect.DeepEqual(got.Resources, want) {
+		t.Errorf("ExtractPage() resources got = %v, want %v", got.Resources, want)
+	}
+	wantLinks := []url.URL{{Scheme: "https", Host: "test.com", Path: "/contact"}}
+	if !reflect.DeepEqual(got.Links, wantLinks) {
+		t.Errorf("ExtractPage() links got = %v, want %v", got.Links, wantLinks)
+	}
+}
+
+func TestExtract_WithResources(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/page")
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{
+			name: "returns only the anchors by default",
+			want: []string{"https://test.com/contact"},
+		},
+		{
+			name: "returns the resources after the anchors",
+			opts: []Option{WithResources()},
+			want: []string{"https://test.com/contact", "https://test.com/css/site.css", "https://test.com/favicon.ico",
+				"https://test.com/js/app.js", "https://test.com/images/logo.png", "https://test.com/embed/map", "https://test.com/media/intro.mp4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, err := Extract(*testUrl, strings.NewReader(htmlWithResources), tt.opts...)
+			if err != nil {
+				t.Fatalf("should not throw error at Extract. err: %v", err)
+			}
+			var got []string
+			for _, link := range links {
+				got = append(got, link.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSrcset(t *testing.T) {
+	tests := []struct {
+		srcset string
+		want   []string
+	}{
+		{srcset: "small.jpg 480w, large.jpg 1080w", want: []string{"small.jpg", "large.jpg"}},
+		{srcset: "logo.png, logo@2x.png 2x", want: []string{"logo.png", "logo@2x.png"}},
+		{srcset: "/img/w_480,h_320/photo.jpg 480w,/img/w_960,h_640/photo.jpg 960w", want: []string{"/img/w_480,h_320/photo.jpg", "/img/w_960,h_640/photo.jpg"}},
+		{srcset: "  photo.jpg  ", want: []string{"photo.jpg"}},
+		{srcset: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.srcset, func(t *testing.T) {
+			if got := parseSrcset(tt.srcset); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSrcset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractPage_WithResourcesInSrcset(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/gallery")
+	htmlWithSrcset := `<picture><source srcset="/img/hero.webp 1x, /img/hero@2x.webp 2x" type="image/webp">
+		<img src="/img/hero.jpg" srcset="/img/hero-480.jpg 480w, https://cdn.other.com/hero.jpg 1080w"></picture>`
+
+	got, err := ExtractPage(*testUrl, strings.NewReader(htmlWithSrcset), WithResources())
+	if err != nil {
+		t.Fatalf("should not throw error at ExtractPage. err: %v", err)
+	}
+	want := []Resource{
+		{Type: ImageResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/img/hero.webp"}},
+		{Type: ImageResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/img/hero@2x.webp"}},
+		{Type: ImageResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/img/hero.jpg"}},
+		{Type: ImageResource, URL: url.URL{Scheme: "https", Host: "test.com", Path: "/img/hero-480.jpg"}},
+	}
+	if !reflect.DeepEqual(got.Resources, want) {
+		t.Errorf("ExtractPage() resources got = %v, want %v", got.Resources, want)
+	}
+}
+
+func TestExtractPage_WithThirdPartyResources(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/page")
+	html := htmlWithResources + `<img src="https://pixel.tracker.com/p.gif"><iframe src="https://www.youtube.com/embed/x"></iframe>`
+
+	got, err := ExtractPage(*testUrl, strings.NewReader(html), WithThirdPartyResources())
+	if err != nil {
+		t.Fatalf("should not throw error at ExtractPage. err: %v", err)
+	}
+	want := []Resource{
+		{Type: ScriptResource, URL: url.URL{Scheme: "https", Host: "cdn.other.com", Path: "/lib.js"}},
+		{Type: ImageResource, URL: url.URL{Scheme: "https", Host: "pixel.tracker.com", Path: "/p.gif"}},
+		{Type: FrameResource, URL: url.URL{Scheme: "https", Host: "youtube.com", Path: "/embed/x"}},
+	}
+	if !reflect.DeepEqual(got.ThirdPartyResources, want) {
+		t.Errorf("ExtractPage() third-party resources got = %v, want %v", got.ThirdPartyResources, want)
+	}
+	if len(got.Resources) != 0 {
+		t.Errorf("ExtractPage() resources got = %v, want none without the WithResources option", got.Resources)
+	}
+}
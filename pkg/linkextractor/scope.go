@@ -0,0 +1,79 @@
+package linkextractor
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Scope decides whether a link found in a page is in the scope of the crawl. Any function with
+// this signature can be used as a custom scope.
+type Scope func(pageURL, link url.URL) bool
+
+// SameHost keeps the links of the same host as the page. It's the default scope.
+func SameHost(pageURL, link url.URL) bool {
+	return pageURL.Host == link.Host
+}
+
+// SameRegistrableDomain keeps the links of the same registrable domain as the page, as told by the
+// public suffix list, so blog.example.com and shop.example.com are in the scope of www.example.com,
+// but example.co.uk is not in the scope of example.com. Hosts without a registrable domain, like IP
+// addresses, fall back to SameHost.
+func SameRegistrableDomain(pageURL, link url.URL) bool {
+	if net.ParseIP(pageURL.Hostname()) != nil {
+		return SameHost(pageURL, link)
+	}
+	pageDomain, err := publicsuffix.EffectiveTLDPlusOne(pageURL.Hostname())
+	if err != nil {
+		return SameHost(pageURL, link)
+	}
+	linkDomain, err := publicsuffix.EffectiveTLDPlusOne(link.Hostname())
+	return err == nil && strings.EqualFold(pageDomain, linkDomain)
+}
+
+// WithScope is an option to set which links are extracted from a page, by default the ones of the
+// same host as the page. See SameHost and SameRegistrableDomain.
+func WithScope(scope Scope) Option {
+	return func(config *config) {
+		config.scope = scope
+	}
+}
+
+// WithExternalLinks is an option to also extract the links of the page to the hosts out of the scope,
+// like the references to other sites in the body of an article. ExtractPage returns them in
+// Page.ExternalLinks, so they can be verified without being crawled.
+func WithExternalLinks() Option {
+	return func(config *config) {
+		config.externalLinks = true
+	}
+}
+
+// searchExternalLinks returns the links of the page to the hosts out of the scope.
+func searchExternalLinks(webpageURL, baseURL url.URL, node *html.Node, config config) []url.URL {
+	anyScope := config
+	anyScope.scope = func(_, _ url.URL) bool { return true }
+	var links []url.URL
+	for _, anchor := range searchDomainMatchingLinks(webpageURL, baseURL, node, anyScope) {
+		if !config.inScope(webpageURL, anchor.link) {
+			links = append(links, anchor.link)
+		}
+	}
+	return links
+}
+
+// InScope reports whether the link is in the scope set with the WithScope option, for the given page.
+func InScope(pageURL, link url.URL, opts ...Option) bool {
+	return newConfig(opts).inScope(pageURL, link)
+}
+
+func (c config) inScope(pageURL, link url.URL) bool {
+	// the links are normalized, so the host of the page must be too
+	pageURL.Host = normalizeHost(pageURL.Host, strings.ToLower(pageURL.Scheme))
+	if c.scope == nil {
+		return SameHost(pageURL, link)
+	}
+	return c.scope(pageURL, link)
+}
@@ -0,0 +1,93 @@
+package linkextractor
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSameRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		page string
+		link string
+		want bool
+	}{
+		{page: "https://example.com", link: "https://blog.example.com/post", want: true},
+		{page: "https://shop.example.com", link: "https://blog.example.com/post", want: true},
+		{page: "https://example.com", link: "https://example.org", want: false},
+		{page: "https://example.co.uk", link: "https://blog.example.co.uk", want: true},
+		{page: "https://example.co.uk", link: "https://other.co.uk", want: false},
+		{page: "https://user.github.io", link: "https://other.github.io", want: false},
+		{page: "http://10.0.0.1:8080", link: "http://10.0.0.2:8080", want: false},
+		{page: "http://10.0.0.1:8080", link: "http://10.0.0.1:8080/about", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.page+" "+tt.link, func(t *testing.T) {
+			pageURL, _ := url.Parse(tt.page)
+			link, _ := url.Parse(tt.link)
+			if got := SameRegistrableDomain(*pageURL, *link); got != tt.want {
+				t.Errorf("SameRegistrableDomain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtract_WithScope(t *testing.T) {
+	testUrl, _ := url.Parse("https://example.com")
+	htmlWithSubdomains := `<a href="/about"/><a href="https://blog.example.com/post"/><a href="https://other.com"/>`
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{
+			name: "keeps the links of the same host by default",
+			want: []string{"https://example.com/about"},
+		},
+		{
+			name: "keeps the subdomains of the same registrable domain",
+			opts: []Option{WithScope(SameRegistrableDomain)},
+			want: []string{"https://example.com/about", "https://blog.example.com/post"},
+		},
+		{
+			name: "keeps the links of a custom scope",
+			opts: []Option{WithScope(func(_, link url.URL) bool { return strings.HasSuffix(link.Host, ".com") })},
+			want: []string{"https://example.com/about", "https://blog.example.com/post", "https://other.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, err := Extract(*testUrl, strings.NewReader(htmlWithSubdomains), tt.opts...)
+			if err != nil {
+				t.Fatalf("should not throw error at Extract. err: %v", err)
+			}
+			var got []string
+			for _, link := range links {
+				got = append(got, link.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractPage_WithExternalLinks(t *testing.T) {
+	testUrl, _ := url.Parse("https://example.com")
+	htmlWithExternalLinks := `<a href="/about"/><a href="https://blog.example.com/post"/><a href="https://other.com/ref"/><a href="https://other.com/ref"/><a href="mailto:hi@other.com"/>`
+	page, err := ExtractPage(*testUrl, strings.NewReader(htmlWithExternalLinks), WithScope(SameRegistrableDomain), WithExternalLinks())
+	if err != nil {
+		t.Fatalf("should not throw error at ExtractPage. err: %v", err)
+	}
+	var got []string
+	for _, link := range page.ExternalLinks {
+		got = append(got, link.String())
+	}
+	if want := []string{"https://other.com/ref"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractPage() ExternalLinks got = %v, want %v", got, want)
+	}
+	if len(page.Links) != 2 {
+		t.Errorf("ExtractPage() Links got = %v, want the links in the scope only", page.Links)
+	}
+}
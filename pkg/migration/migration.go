@@ -0,0 +1,150 @@
+package migration
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// InvalidMapping indicates that a row of the mappings CSV is not a pair of URLs.
+var InvalidMapping = errors.New("invalid mapping")
+
+type httpGetter interface {
+	Get(url string) (resp *http.Response, err error)
+}
+
+// Mapping is an entry of the redirect map of a site migration: OldURL must permanently redirect to NewURL.
+type Mapping struct {
+	OldURL url.URL
+	NewURL url.URL
+}
+
+// Result is the outcome of validating a Mapping.
+type Result struct {
+	Mapping
+	// StatusCode is the status the old URL responded with. 0 if the request failed.
+	StatusCode int
+	// Location is the resolved URL the old URL redirects to, if any.
+	Location string
+	// Err is the error of the request, if it failed.
+	Err error
+}
+
+// OK reports whether the old URL redirects with 301 Moved Permanently to exactly the new URL.
+func (r Result) OK() bool {
+	return r.Problem() == ""
+}
+
+// Problem describes why the mapping doesn't hold, or returns an empty string if it does.
+func (r Result) Problem() string {
+	switch {
+	case r.Err != nil:
+		return fmt.Sprintf("request failed: %v", r.Err)
+	case r.StatusCode != http.StatusMovedPermanently:
+		return fmt.Sprintf("responded with status %d, want %d", r.StatusCode, http.StatusMovedPermanently)
+	case r.Location != r.NewURL.String():
+		return fmt.Sprintf("redirects to %s, want %s", r.Location, r.NewURL.String())
+	}
+	return ""
+}
+
+// ParseMappings reads a CSV file with an old URL and its new URL per row. A first row that doesn't
+// start with an absolute URL is considered a header and skipped. New URLs can be relative to the old ones.
+func ParseMappings(r io.Reader) ([]Mapping, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var mappings []Mapping
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return mappings, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && len(record) > 0 && !isAbsoluteURL(record[0]) {
+			continue
+		}
+		if len(record) != 2 {
+			return nil, fmt.Errorf("%w at line %d: want 2 columns, got %d", InvalidMapping, line, len(record))
+		}
+
+		oldURL, err := url.Parse(strings.TrimSpace(record[0]))
+		if err != nil || !oldURL.IsAbs() {
+			return nil, fmt.Errorf("%w at line %d: invalid old URL %q", InvalidMapping, line, record[0])
+		}
+		newURL, err := oldURL.Parse(strings.TrimSpace(record[1]))
+		if err != nil {
+			return nil, fmt.Errorf("%w at line %d: invalid new URL %q", InvalidMapping, line, record[1])
+		}
+		mappings = append(mappings, Mapping{OldURL: *oldURL, NewURL: *newURL})
+	}
+}
+
+func isAbsoluteURL(rawURL string) bool {
+	parsedURL, err := url.Parse(strings.TrimSpace(rawURL))
+	return err == nil && parsedURL.IsAbs()
+}
+
+// Validator checks that the old URLs of a site migration redirect to their new URLs.
+type Validator struct {
+	httpClient     httpGetter
+	maxConcurrency int
+}
+
+// NewValidator creates a new Validator that sends up to maxConcurrency requests at the same time.
+// The HTTP client must not follow redirects, so the redirect of every old URL can be inspected, e.g.
+// an http.Client with a CheckRedirect function that returns http.ErrUseLastResponse.
+func NewValidator(httpClient httpGetter, maxConcurrency int) *Validator {
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	return &Validator{httpClient: httpClient, maxConcurrency: maxConcurrency}
+}
+
+// Validate requests every old URL and returns the results in the same order as the mappings.
+func (v *Validator) Validate(mappings []Mapping) []Result {
+	results := make([]Result, len(mappings))
+	semaphore := make(chan struct{}, v.maxConcurrency)
+	wg := sync.WaitGroup{}
+	for i, mapping := range mappings {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(i int, mapping Mapping) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			results[i] = v.validate(mapping)
+		}(i, mapping)
+	}
+	wg.Wait()
+	return results
+}
+
+func (v *Validator) validate(mapping Mapping) Result {
+	result := Result{Mapping: mapping}
+	res, err := v.httpClient.Get(mapping.OldURL.String())
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+
+	result.StatusCode = res.StatusCode
+	if location := res.Header.Get("Location"); location != "" {
+		if locationURL, err := mapping.OldURL.Parse(location); err == nil {
+			result.Location = locationURL.String()
+		} else {
+			result.Location = location
+		}
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package migration
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type mockHttpGetter struct {
+	redirects map[string]redirect
+}
+
+type redirect struct {
+	statusCode int
+	location   string
+}
+
+func (m mockHttpGetter) Get(url string) (*http.Response, error) {
+	r, ok := m.redirects[url]
+	if !ok {
+		return nil, errors.New("connection refused")
+	}
+	header := http.Header{}
+	if r.location != "" {
+		header.Set("Location", r.location)
+	}
+	return &http.Response{StatusCode: r.statusCode, Header: header, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestParseMappings(t *testing.T) {
+	t.Run("parses the mappings skipping the header", func(t *testing.T) {
+		csvContent := "old_url,new_url\nhttps://old.com/about,https://new.com/about-us\nhttps://old.com/blog, /news\n"
+
+		mappings, err := ParseMappings(strings.NewReader(csvContent))
+		if err != nil {
+			t.Fatalf("should not throw error at ParseMappings. err: %v", err)
+		}
+		if len(mappings) != 2 {
+			t.Fatalf("ParseMappings() got %d mappings, want 2", len(mappings))
+		}
+		if got := mappings[1].NewURL.String(); got != "https://old.com/news" {
+			t.Errorf("relative new URL got = %v, want https://old.com/news", got)
+		}
+	})
+
+	t.Run("fails on rows that are not a pair of URLs", func(t *testing.T) {
+		for _, csvContent := range []string{"https://old.com/about\n", "https://old.com/a,https://new.com/a\n/about,https://new.com/about\n"} {
+			if _, err := ParseMappings(strings.NewReader(csvContent)); !errors.Is(err, InvalidMapping) {
+				t.Errorf("ParseMappings(%q) error = %v, want %v", csvContent, err, InvalidMapping)
+			}
+		}
+	})
+}
+
+func TestValidator_Validate(t *testing.T) {
+	httpClient := mockHttpGetter{redirects: map[string]redirect{
+		"https://old.com/ok":        {statusCode: http.StatusMovedPermanently, location: "https://new.com/ok"},
+		"https://old.com/relative":  {statusCode: http.StatusMovedPermanently, location: "/relative"},
+		"https://old.com/temporary": {statusCode: http.StatusFound, location: "https://new.com/temporary"},
+		"https://old.com/wrong":     {statusCode: http.StatusMovedPermanently, location: "https://new.com/"},
+		"https://old.com/missing":   {statusCode: http.StatusNotFound},
+	}}
+	csvContent := `https://old.com/ok,https://new.com/ok
+https://old.com/relative,https://old.com/relative
+https://old.com/temporary,https://new.com/temporary
+https://old.com/wrong,https://new.com/wrong
+https://old.com/missing,https://new.com/missing
+https://old.com/down,https://new.com/down`
+	mappings, _ := ParseMappings(strings.NewReader(csvContent))
+
+	results := NewValidator(httpClient, 2).Validate(mappings)
+
+	wantOK := []bool{true, true, false, false, false, false}
+	for i, result := range results {
+		if result.OK() != wantOK[i] {
+			t.Errorf("Validate() result for %s OK() = %v, want %v. problem: %s", result.OldURL.String(), result.OK(), wantOK[i], result.Problem())
+		}
+	}
+	if got := results[3].Problem(); got != "redirects to https://new.com/, want https://new.com/wrong" {
+		t.Errorf("Problem() got = %v", got)
+	}
+}
@@ -0,0 +1,99 @@
+package ownership
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// InvalidRule indicates that a line of an ownership file is not a path pattern followed by its owners.
+var InvalidRule = errors.New("invalid rule")
+
+type rule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// Rules map the paths of a site to their owners, like the CODEOWNERS file of a repository maps files
+// to the teams maintaining them, so the findings of an audit can be grouped and routed per owner.
+type Rules struct {
+	rules []rule
+}
+
+// Parse reads an ownership file with a rule per line: a path pattern followed by one or more owners,
+// separated by whitespace, e.g. "/docs/api/ @api-team". Empty lines and lines starting with # are
+// skipped. Like in CODEOWNERS files:
+//   - a pattern starting with "/" matches from the root of the site, otherwise it matches at any depth,
+//   - a pattern matches the path itself and everything under it,
+//   - "*" matches any sequence of characters within a path segment, and "**" across segments,
+//   - the last matching rule wins, so the most specific rules go at the end of the file.
+func Parse(r io.Reader) (*Rules, error) {
+	rules := &Rules{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%w at line %d: want a path pattern and its owners", InvalidRule, line)
+		}
+		pattern, err := compilePattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w at line %d: %v", InvalidRule, line, err)
+		}
+		rules.rules = append(rules.rules, rule{pattern: pattern, owners: fields[1:]})
+	}
+	return rules, scanner.Err()
+}
+
+// Owners returns the owners of the link, from the last rule matching its path, or nil if no rule matches it.
+func (r *Rules) Owners(link url.URL) []string {
+	path := link.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	for i := len(r.rules) - 1; i >= 0; i-- {
+		if r.rules[i].pattern.MatchString(path) {
+			return r.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// compilePattern converts a path pattern into a regular expression matching the paths it owns.
+func compilePattern(rawPattern string) (*regexp.Regexp, error) {
+	anchored := strings.HasPrefix(rawPattern, "/")
+	body := strings.Trim(rawPattern, "/")
+
+	var expression strings.Builder
+	expression.WriteString("^")
+	if !anchored {
+		expression.WriteString("(?:.*/)?")
+	} else {
+		expression.WriteString("/")
+	}
+	for i := 0; i < len(body); i++ {
+		switch {
+		case strings.HasPrefix(body[i:], "**"):
+			expression.WriteString(".*")
+			i++
+		case body[i] == '*':
+			expression.WriteString("[^/]*")
+		case body[i] == '?':
+			expression.WriteString("[^/]")
+		default:
+			expression.WriteString(regexp.QuoteMeta(body[i : i+1]))
+		}
+	}
+	if body == "" {
+		expression.WriteString(".*")
+	} else {
+		expression.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(expression.String())
+}
@@ -0,0 +1,65 @@
+package ownership
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testRules = `# owners of the site
+/            @web-team
+/docs/       @docs-team
+/docs/api/   @api-team @docs-team
+*.pdf        @legal
+/blog/*/drafts @editors
+/shop/**/reviews @community
+`
+
+func TestRules_Owners(t *testing.T) {
+	rules, err := Parse(strings.NewReader(testRules))
+	if err != nil {
+		t.Fatalf("should not throw error at Parse. err: %v", err)
+	}
+	tests := []struct {
+		link string
+		want []string
+	}{
+		{link: "https://test.com", want: []string{"@web-team"}},
+		{link: "https://test.com/pricing", want: []string{"@web-team"}},
+		{link: "https://test.com/docs", want: []string{"@docs-team"}},
+		{link: "https://test.com/docs/guides/start", want: []string{"@docs-team"}},
+		{link: "https://test.com/docs/api/auth", want: []string{"@api-team", "@docs-team"}},
+		{link: "https://test.com/docs/apis", want: []string{"@docs-team"}},
+		{link: "https://test.com/docs/api/terms.pdf", want: []string{"@legal"}},
+		{link: "https://test.com/blog/2024/drafts/post", want: []string{"@editors"}},
+		{link: "https://test.com/blog/2024/06/drafts", want: []string{"@web-team"}},
+		{link: "https://test.com/shop/shoes/red/reviews", want: []string{"@community"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			link, _ := url.Parse(tt.link)
+			if got := rules.Owners(*link); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Owners() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRules_OwnersWithoutMatchingRule(t *testing.T) {
+	rules, err := Parse(strings.NewReader("/docs/ @docs-team"))
+	if err != nil {
+		t.Fatalf("should not throw error at Parse. err: %v", err)
+	}
+	link, _ := url.Parse("https://test.com/pricing")
+	if got := rules.Owners(*link); got != nil {
+		t.Errorf("Owners() = %v, want nil", got)
+	}
+}
+
+func TestParse_InvalidRule(t *testing.T) {
+	if _, err := Parse(strings.NewReader("/docs/ @docs-team\n/blog/\n")); !errors.Is(err, InvalidRule) {
+		t.Errorf("Parse() error = %v, want %v", err, InvalidRule)
+	}
+}
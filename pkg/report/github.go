@@ -0,0 +1,112 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/andiblas/website-crawler/pkg/audit"
+)
+
+// githubWriter writes the errors and findings as GitHub Actions workflow commands, so they show up
+// as annotations of the checks of a pull request, and optionally a Markdown job summary.
+type githubWriter struct {
+	mu         sync.Mutex
+	w          io.Writer
+	jobSummary io.Writer
+	errors     []Error
+	findings   []audit.Finding
+}
+
+// NewGitHubWriter creates a Writer of the github format that also writes a Markdown summary of the
+// crawl to jobSummary, usually the file named by the GITHUB_STEP_SUMMARY environment variable of
+// GitHub Actions, so it's shown in the summary of the job. A nil jobSummary writes no summary.
+func NewGitHubWriter(w io.Writer, jobSummary io.Writer) Writer {
+	return &githubWriter{w: w, jobSummary: jobSummary}
+}
+
+func (g *githubWriter) WriteLink(_ url.URL) error {
+	return nil
+}
+
+func (g *githubWriter) WriteError(link url.URL, err error) error {
+	e := NewError(link, err)
+	message := e.URL + ": " + e.Error
+	if e.Cause != "" {
+		message += ". likely cause: " + e.Cause
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.errors = append(g.errors, e)
+	return g.command("error", "Broken link", message)
+}
+
+func (g *githubWriter) WriteFinding(finding audit.Finding) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.findings = append(g.findings, finding)
+	command := "warning"
+	if finding.Severity == audit.HighSeverity {
+		command = "error"
+	}
+	return g.command(command, finding.Check, finding.URL+": "+finding.Detail+findingAttribution(finding))
+}
+
+func (g *githubWriter) WriteSummary(summary Summary) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	message := fmt.Sprintf("%d links found, %d errors, %d findings", summary.TotalLinks, len(g.errors), summary.Findings)
+	if err := g.command("notice", "Crawl summary", message); err != nil {
+		return err
+	}
+	if g.jobSummary == nil {
+		return nil
+	}
+	_, err := io.WriteString(g.jobSummary, g.markdownSummary(summary))
+	return err
+}
+
+// command writes a workflow command, e.g. ::error title=Broken link::https://example.com/about: unexpected status 404 Not Found
+func (g *githubWriter) command(name, title, message string) error {
+	_, err := fmt.Fprintf(g.w, "::%s title=%s::%s\n", name, escapeProperty(title), escapeData(message))
+	return err
+}
+
+func (g *githubWriter) markdownSummary(summary Summary) string {
+	var markdown strings.Builder
+	markdown.WriteString("## Crawl results\n\n")
+	fmt.Fprintf(&markdown, "| Links found | Errors | Findings |\n| --- | --- | --- |\n| %d | %d | %d |\n", summary.TotalLinks, len(g.errors), summary.Findings)
+	if len(g.errors) > 0 {
+		markdown.WriteString("\n### Broken links\n\n| URL | Error |\n| --- | --- |\n")
+		for _, e := range g.errors {
+			fmt.Fprintf(&markdown, "| %s | %s |\n", escapeTableCell(e.URL), escapeTableCell(e.Error))
+		}
+	}
+	if len(g.findings) > 0 {
+		markdown.WriteString("\n### Audit findings\n\n| Check | URL | Detail |\n| --- | --- | --- |\n")
+		for _, finding := range g.findings {
+			fmt.Fprintf(&markdown, "| %s | %s | %s |\n", escapeTableCell(finding.Check), escapeTableCell(finding.URL), escapeTableCell(finding.Detail+findingAttribution(finding)))
+		}
+	}
+	return markdown.String()
+}
+
+// escapeData escapes the message of a workflow command, which ends at the end of the line.
+func escapeData(data string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(data)
+}
+
+// escapeProperty escapes a property of a workflow command, which also ends at a colon or a comma.
+func escapeProperty(property string) string {
+	return strings.NewReplacer(":", "%3A", ",", "%2C").Replace(escapeData(property))
+}
+
+func escapeTableCell(cell string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(cell)
+}
@@ -0,0 +1,44 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGitHubWriter(t *testing.T) {
+	want := `::error title=Broken link::https://test.com/about: unexpected status 404 Not Found
+::warning title=canonical::https://test.com/about: canonical https://test.com/ redirects (owner=web-team, section=about) (owners: @web-team)
+::notice title=Crawl summary::2 links found, 1 errors, 1 findings
+`
+	if got := writeAll(t, "github"); got != want {
+		t.Errorf("github output got\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestGitHubWriter_JobSummary(t *testing.T) {
+	var output, jobSummary bytes.Buffer
+	writer := NewGitHubWriter(&output, &jobSummary)
+	_ = writer.WriteLink(testLink)
+	_ = writer.WriteError(testLink, testError)
+	_ = writer.WriteFinding(testFinding)
+	if err := writer.WriteSummary(testSummary); err != nil {
+		t.Fatalf("should not throw error at WriteSummary. err: %v", err)
+	}
+
+	for _, want := range []string{
+		"| 2 | 1 | 1 |",
+		"| https://test.com/about | unexpected status 404 Not Found |",
+		"| canonical | https://test.com/about | canonical https://test.com/ redirects (owner=web-team, section=about) (owners: @web-team) |",
+	} {
+		if !strings.Contains(jobSummary.String(), want) {
+			t.Errorf("job summary got\n%v\nwant it to contain %v", jobSummary.String(), want)
+		}
+	}
+}
+
+func TestEscapeProperty(t *testing.T) {
+	if got, want := escapeProperty("a: b, 100%\nc"), "a%3A b%2C 100%25%0Ac"; got != want {
+		t.Errorf("escapeProperty() = %v, want %v", got, want)
+	}
+}
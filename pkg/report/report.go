@@ -0,0 +1,499 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andiblas/website-crawler/pkg/audit"
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+	"github.com/andiblas/website-crawler/pkg/fingerprint"
+	"github.com/andiblas/website-crawler/pkg/sampling"
+)
+
+// Formats are the supported output formats.
+var Formats = []string{"text", "json", "ndjson", "csv", "github"}
+
+// UnknownFormat indicates that the requested output format is not one of the Formats.
+var UnknownFormat = errors.New("unknown format. must be one of text, json, ndjson, csv or github")
+
+// Error is a page that failed to be crawled.
+type Error struct {
+	URL        string `json:"url"`
+	Error      string `json:"error"`
+	StatusCode int    `json:"status_code,omitempty"`
+	BodySample string `json:"body_sample,omitempty"`
+	Cause      string `json:"cause,omitempty"`
+}
+
+// NewError builds the Error of a page, including the status code, body sample and likely cause
+// when the server responded with an error status.
+func NewError(link url.URL, err error) Error {
+	e := Error{URL: link.String(), Error: err.Error()}
+	var statusErr *fetcher.UnexpectedStatusError
+	if errors.As(err, &statusErr) {
+		e.StatusCode = statusErr.StatusCode
+		e.BodySample = string(statusErr.BodySample)
+	}
+	if errorPage, ok := fingerprint.IdentifyError(err); ok {
+		e.Cause = errorPage.Cause
+	}
+	return e
+}
+
+// Finding is an issue found by an audit of the crawled site.
+type Finding struct {
+	Check    string            `json:"check"`
+	URL      string            `json:"url"`
+	Detail   string            `json:"detail"`
+	Severity string            `json:"severity,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Owners   []string          `json:"owners,omitempty"`
+}
+
+// Owner is the number of findings of an owner of the site.
+type Owner struct {
+	Owner    string `json:"owner"`
+	Findings int    `json:"findings"`
+}
+
+// Pattern is the number of links found and crawled of a parameterized URL pattern.
+type Pattern struct {
+	Pattern string `json:"pattern"`
+	Found   int    `json:"found"`
+	Sampled int    `json:"sampled"`
+}
+
+// ThirdParty is a host out of the site that the crawled pages load assets from.
+type ThirdParty struct {
+	Host string `json:"host"`
+	// Resources is the number of distinct assets loaded from the host, by type.
+	Resources    map[string]int `json:"resources"`
+	Pages        int            `json:"pages"`
+	ExamplePages []string       `json:"example_pages"`
+}
+
+// Host holds the statistics of the pages crawled from a host.
+type Host struct {
+	Host             string `json:"host"`
+	Pages            int    `json:"pages"`
+	Errors           int    `json:"errors"`
+	AverageLatencyMs int64  `json:"average_latency_ms"`
+	Bytes            int64  `json:"bytes"`
+	// RateLimit is the rate limit the host advertised in its responses, if any.
+	RateLimit *RateLimit `json:"rate_limit,omitempty"`
+}
+
+// RateLimit is the rate limit a host advertised in the headers of its responses.
+type RateLimit struct {
+	// Limit is the number of requests allowed per window, or 0 if unknown.
+	Limit int `json:"limit,omitempty"`
+	// WindowSeconds is the time window of the limit, or 0 if unknown.
+	WindowSeconds int64 `json:"window_seconds,omitempty"`
+	// MinRemaining is the lowest number of remaining requests advertised during the crawl, if any.
+	MinRemaining *int `json:"min_remaining,omitempty"`
+}
+
+// Summary holds the totals of a crawl.
+type Summary struct {
+	TotalLinks   int          `json:"total_links"`
+	Hosts        []Host       `json:"hosts,omitempty"`
+	Patterns     []Pattern    `json:"patterns,omitempty"`
+	Owners       []Owner      `json:"owners,omitempty"`
+	ThirdParties []ThirdParty `json:"third_parties,omitempty"`
+	Findings     int          `json:"findings"`
+}
+
+// NewOwners counts the findings of every owner, sorted by owner. A finding with many owners
+// is counted for every one of them, and the findings without owners are not counted.
+func NewOwners(findings []audit.Finding) []Owner {
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		for _, owner := range finding.Owners {
+			counts[owner]++
+		}
+	}
+	owners := make([]Owner, 0, len(counts))
+	for owner, count := range counts {
+		owners = append(owners, Owner{Owner: owner, Findings: count})
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		return owners[i].Owner < owners[j].Owner
+	})
+	return owners
+}
+
+// NewHosts converts the statistics of a fetcher.StatsFetcher into the Hosts of a Summary.
+func NewHosts(stats []fetcher.HostStats) []Host {
+	hosts := make([]Host, len(stats))
+	for i, hostStats := range stats {
+		hosts[i] = Host{
+			Host:             hostStats.Host,
+			Pages:            hostStats.Pages,
+			Errors:           hostStats.Errors,
+			AverageLatencyMs: hostStats.AverageLatency().Milliseconds(),
+			Bytes:            hostStats.Bytes,
+		}
+	}
+	return hosts
+}
+
+// WithRateLimits sets the rate limits recorded by a fetcher.RateLimitRecorder to the Hosts of a
+// Summary. The hosts that were not crawled, like the ones only fetched for their robots.txt, are left out.
+func WithRateLimits(hosts []Host, limits []fetcher.HostRateLimit) []Host {
+	for _, limit := range limits {
+		for i := range hosts {
+			if hosts[i].Host != limit.Host {
+				continue
+			}
+			rateLimit := &RateLimit{Limit: limit.Limit, WindowSeconds: int64(limit.Window / time.Second)}
+			if limit.MinRemaining >= 0 {
+				minRemaining := limit.MinRemaining
+				rateLimit.MinRemaining = &minRemaining
+			}
+			hosts[i].RateLimit = rateLimit
+		}
+	}
+	return hosts
+}
+
+// NewPatterns converts the counts of a sampling.Sampler into the Patterns of a Summary.
+func NewPatterns(counts []sampling.PatternCount) []Pattern {
+	patterns := make([]Pattern, len(counts))
+	for i, count := range counts {
+		patterns[i] = Pattern{Pattern: count.Pattern, Found: count.Found, Sampled: count.Sampled}
+	}
+	return patterns
+}
+
+// thirdPartyExamples is the number of pages listed in a ThirdParty.
+const thirdPartyExamples = 3
+
+// NewThirdParties converts the inventory of the third-party hosts of audit.ThirdPartyInventory into the
+// ThirdParties of a Summary, listing the first pages that load assets from every host.
+func NewThirdParties(inventory []audit.ThirdPartyHost) []ThirdParty {
+	thirdParties := make([]ThirdParty, len(inventory))
+	for i, host := range inventory {
+		resources := make(map[string]int, len(host.Resources))
+		for resourceType, count := range host.Resources {
+			resources[string(resourceType)] = count
+		}
+		thirdParties[i] = ThirdParty{
+			Host:         host.Host,
+			Resources:    resources,
+			Pages:        len(host.Pages),
+			ExamplePages: host.Pages[:min(len(host.Pages), thirdPartyExamples)],
+		}
+	}
+	return thirdParties
+}
+
+// Writer writes the results of a crawl in an output format. Links and errors can be written
+// concurrently, as they're usually reported from the callbacks of the crawler.
+type Writer interface {
+	WriteLink(link url.URL) error
+	WriteError(link url.URL, err error) error
+	WriteFinding(finding audit.Finding) error
+	// WriteSummary writes the totals of the crawl and flushes the output. It must be called last.
+	WriteSummary(summary Summary) error
+}
+
+// NewWriter creates a Writer of the given format, one of the Formats:
+//   - text: human readable lines, written as the results are found.
+//   - json: a single JSON document with all the results, written by WriteSummary.
+//   - ndjson: a JSON object per line, with a "type" field, written as the results are found.
+//   - csv: a row per result with the type, url, check, status_code and detail columns.
+//   - github: GitHub Actions workflow commands, so the errors and findings show up as annotations
+//     of the checks of a pull request. See NewGitHubWriter to write a job summary too.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case "text":
+		return &textWriter{w: w}, nil
+	case "json":
+		return &jsonWriter{w: w, document: Document{SchemaVersion: SchemaVersion, Links: []string{}, Errors: []Error{}, Findings: []Finding{}}}, nil
+	case "ndjson":
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}, nil
+	case "csv":
+		writer := csv.NewWriter(w)
+		err := writer.Write([]string{"type", "url", "check", "status_code", "detail"})
+		return &csvWriter{w: writer}, err
+	case "github":
+		return NewGitHubWriter(w, nil), nil
+	}
+	return nil, UnknownFormat
+}
+
+type textWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (t *textWriter) WriteLink(link url.URL) error {
+	return t.printf("[LINK] Link found: %s\n", link.String())
+}
+
+func (t *textWriter) WriteError(link url.URL, err error) error {
+	e := NewError(link, err)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, _ = fmt.Fprintf(t.w, "[ERROR] error while crawling [%s] err: %v\n", e.URL, e.Error)
+	if e.BodySample != "" {
+		_, _ = fmt.Fprintf(t.w, "[ERROR] response body of [%s]: %s\n", e.URL, e.BodySample)
+	}
+	if e.Cause != "" {
+		_, _ = fmt.Fprintf(t.w, "[ERROR] likely cause of [%s]: %s\n", e.URL, e.Cause)
+	}
+	return nil
+}
+
+func (t *textWriter) WriteFinding(finding audit.Finding) error {
+	return t.printf("[AUDIT] %s [%s]: %s%s\n", findingCheck(finding), finding.URL, finding.Detail, findingAttribution(finding))
+}
+
+func (t *textWriter) WriteSummary(summary Summary) error {
+	if err := t.printf("Total links found: %d\n", summary.TotalLinks); err != nil {
+		return err
+	}
+	for _, host := range summary.Hosts {
+		if err := t.printf("[HOST] %s: %s\n", host.Host, hostDetail(host)); err != nil {
+			return err
+		}
+	}
+	for _, owner := range summary.Owners {
+		if err := t.printf("[OWNER] %s: %d findings\n", owner.Owner, owner.Findings); err != nil {
+			return err
+		}
+	}
+	for _, thirdParty := range summary.ThirdParties {
+		if err := t.printf("[THIRD PARTY] %s: %s\n", thirdParty.Host, thirdPartyDetail(thirdParty)); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range summary.Patterns {
+		if err := t.printf("[PATTERN] %s: %d links found, %d crawled\n", pattern.Pattern, pattern.Found, pattern.Sampled); err != nil {
+			return err
+		}
+	}
+	if summary.Findings > 0 {
+		return t.printf("Audit findings: %d\n", summary.Findings)
+	}
+	return nil
+}
+
+func (t *textWriter) printf(format string, a ...any) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, err := fmt.Fprintf(t.w, format, a...)
+	return err
+}
+
+type jsonWriter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	document Document
+}
+
+func (j *jsonWriter) WriteLink(link url.URL) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.document.Links = append(j.document.Links, link.String())
+	return nil
+}
+
+func (j *jsonWriter) WriteError(link url.URL, err error) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.document.Errors = append(j.document.Errors, NewError(link, err))
+	return nil
+}
+
+func (j *jsonWriter) WriteFinding(finding audit.Finding) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.document.Findings = append(j.document.Findings, Finding(finding))
+	return nil
+}
+
+func (j *jsonWriter) WriteSummary(summary Summary) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.document.Summary = summary
+	encoder := json.NewEncoder(j.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(j.document)
+}
+
+type ndjsonWriter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func (n *ndjsonWriter) WriteLink(link url.URL) error {
+	return n.encode(struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	}{Type: "link", URL: link.String()})
+}
+
+func (n *ndjsonWriter) WriteError(link url.URL, err error) error {
+	return n.encode(struct {
+		Type string `json:"type"`
+		Error
+	}{Type: "error", Error: NewError(link, err)})
+}
+
+func (n *ndjsonWriter) WriteFinding(finding audit.Finding) error {
+	return n.encode(struct {
+		Type string `json:"type"`
+		Finding
+	}{Type: "finding", Finding: Finding(finding)})
+}
+
+func (n *ndjsonWriter) WriteSummary(summary Summary) error {
+	return n.encode(struct {
+		Type string `json:"type"`
+		Summary
+	}{Type: "summary", Summary: summary})
+}
+
+func (n *ndjsonWriter) encode(value any) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.encoder.Encode(value)
+}
+
+type csvWriter struct {
+	mu sync.Mutex
+	w  *csv.Writer
+}
+
+func (c *csvWriter) WriteLink(link url.URL) error {
+	return c.write("link", link.String(), "", "", "")
+}
+
+func (c *csvWriter) WriteError(link url.URL, err error) error {
+	e := NewError(link, err)
+	statusCode := ""
+	if e.StatusCode != 0 {
+		statusCode = strconv.Itoa(e.StatusCode)
+	}
+	detail := e.Error
+	if e.Cause != "" {
+		detail += ". likely cause: " + e.Cause
+	}
+	return c.write("error", e.URL, "", statusCode, detail)
+}
+
+func (c *csvWriter) WriteFinding(finding audit.Finding) error {
+	return c.write("finding", finding.URL, finding.Check, "", finding.Detail+findingAttribution(finding))
+}
+
+func (c *csvWriter) WriteSummary(summary Summary) error {
+	for _, host := range summary.Hosts {
+		if err := c.write("host", host.Host, "", "", hostDetail(host)); err != nil {
+			return err
+		}
+	}
+	for _, owner := range summary.Owners {
+		if err := c.write("owner", owner.Owner, "", "", fmt.Sprintf("%d findings", owner.Findings)); err != nil {
+			return err
+		}
+	}
+	for _, thirdParty := range summary.ThirdParties {
+		if err := c.write("third_party", thirdParty.Host, "", "", thirdPartyDetail(thirdParty)); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range summary.Patterns {
+		detail := fmt.Sprintf("%d links found, %d crawled", pattern.Found, pattern.Sampled)
+		if err := c.write("pattern", pattern.Pattern, "", "", detail); err != nil {
+			return err
+		}
+	}
+	detail := fmt.Sprintf("%d links found, %d findings", summary.TotalLinks, summary.Findings)
+	if err := c.write("summary", "", "", "", detail); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) write(record ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.w.Write(record)
+}
+
+func hostDetail(host Host) string {
+	detail := fmt.Sprintf("%d pages, %d errors, %dms average latency, %d bytes", host.Pages, host.Errors, host.AverageLatencyMs, host.Bytes)
+	if rateLimit := host.RateLimit; rateLimit != nil {
+		if rateLimit.Limit > 0 {
+			detail += fmt.Sprintf(", rate limit %d requests", rateLimit.Limit)
+			if rateLimit.WindowSeconds > 0 {
+				detail += fmt.Sprintf(" per %ds", rateLimit.WindowSeconds)
+			}
+		}
+		if rateLimit.MinRemaining != nil {
+			detail += fmt.Sprintf(", %d remaining at the lowest", *rateLimit.MinRemaining)
+		}
+	}
+	return detail
+}
+
+// thirdPartyDetail formats the assets loaded from a third-party host, sorted by type, and the pages that load them.
+func thirdPartyDetail(thirdParty ThirdParty) string {
+	resourceTypes := make([]string, 0, len(thirdParty.Resources))
+	for resourceType := range thirdParty.Resources {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+	resources := make([]string, len(resourceTypes))
+	for i, resourceType := range resourceTypes {
+		resources[i] = fmt.Sprintf("%s %d", resourceType, thirdParty.Resources[resourceType])
+	}
+	return fmt.Sprintf("%s, loaded by %d pages, like %s", strings.Join(resources, ", "), thirdParty.Pages, strings.Join(thirdParty.ExamplePages, ", "))
+}
+
+// findingCheck formats the check of a finding along with its severity, if rated.
+func findingCheck(finding audit.Finding) string {
+	if finding.Severity == "" {
+		return finding.Check
+	}
+	return finding.Check + " (" + finding.Severity + ")"
+}
+
+// findingAttribution formats the metadata of a finding, sorted by key, and its owners as a suffix of its detail.
+func findingAttribution(finding audit.Finding) string {
+	var attribution string
+	if len(finding.Metadata) > 0 {
+		pairs := make([]string, 0, len(finding.Metadata))
+		for key, value := range finding.Metadata {
+			pairs = append(pairs, key+"="+value)
+		}
+		sort.Strings(pairs)
+		attribution += " (" + strings.Join(pairs, ", ") + ")"
+	}
+	if len(finding.Owners) > 0 {
+		attribution += " (owners: " + strings.Join(finding.Owners, " ") + ")"
+	}
+	return attribution
+}
@@ -0,0 +1,168 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andiblas/website-crawler/pkg/audit"
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+	"github.com/andiblas/website-crawler/pkg/linkextractor"
+)
+
+var (
+	testLink    = url.URL{Scheme: "https", Host: "test.com", Path: "/about"}
+	testError   = &fetcher.UnexpectedStatusError{StatusCode: http.StatusNotFound, Status: "404 Not Found", BodySample: []byte("not here")}
+	testFinding = audit.Finding{Check: "canonical", URL: "https://test.com/about", Detail: "canonical https://test.com/ redirects", Metadata: map[string]string{"section": "about", "owner": "web-team"}, Owners: []string{"@web-team"}}
+	testSummary = Summary{TotalLinks: 2, Hosts: []Host{{Host: "test.com", Pages: 2, Errors: 1, AverageLatencyMs: 120, Bytes: 2048}}, Patterns: []Pattern{{Pattern: "/search?q=*", Found: 10, Sampled: 5}}, Owners: []Owner{{Owner: "@web-team", Findings: 1}}, ThirdParties: []ThirdParty{{Host: "cdn.com", Resources: map[string]int{"script": 2, "image": 1}, Pages: 2, ExamplePages: []string{"https://test.com", "https://test.com/about"}}}, Findings: 1}
+)
+
+func writeAll(t *testing.T, format string) string {
+	var buffer bytes.Buffer
+	writer, err := NewWriter(format, &buffer)
+	if err != nil {
+		t.Fatalf("should not throw error at NewWriter. err: %v", err)
+	}
+	_ = writer.WriteLink(testLink)
+	_ = writer.WriteError(testLink, testError)
+	_ = writer.WriteFinding(testFinding)
+	if err := writer.WriteSummary(testSummary); err != nil {
+		t.Fatalf("should not throw error at WriteSummary. err: %v", err)
+	}
+	return buffer.String()
+}
+
+func TestNewWriter(t *testing.T) {
+	if _, err := NewWriter("yaml", &bytes.Buffer{}); !errors.Is(err, UnknownFormat) {
+		t.Errorf("NewWriter() error = %v, want %v", err, UnknownFormat)
+	}
+}
+
+func TestNewHosts(t *testing.T) {
+	got := NewHosts([]fetcher.HostStats{{Host: "test.com", Pages: 4, Errors: 1, TotalLatency: time.Second, Bytes: 2048}})
+	want := []Host{{Host: "test.com", Pages: 4, Errors: 1, AverageLatencyMs: 250, Bytes: 2048}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewHosts() got = %v, want %v", got, want)
+	}
+}
+
+func TestWithRateLimits(t *testing.T) {
+	hosts := []Host{{Host: "test.com", Pages: 4}, {Host: "docs.test.com", Pages: 2}}
+	got := WithRateLimits(hosts, []fetcher.HostRateLimit{
+		{Host: "cdn.test.com", Limit: 50, MinRemaining: -1},
+		{Host: "test.com", Limit: 100, Window: time.Minute, MinRemaining: 3},
+	})
+	if got[1].RateLimit != nil {
+		t.Errorf("WithRateLimits() got = %+v for a host without rate limit, want nil", got[1].RateLimit)
+	}
+	if rateLimit := got[0].RateLimit; rateLimit == nil || rateLimit.Limit != 100 || rateLimit.WindowSeconds != 60 || rateLimit.MinRemaining == nil || *rateLimit.MinRemaining != 3 {
+		t.Errorf("WithRateLimits() got = %+v, want 100 requests per 60s with 3 remaining", rateLimit)
+	}
+	if detail := hostDetail(got[0]); detail != "4 pages, 0 errors, 0ms average latency, 0 bytes, rate limit 100 requests per 60s, 3 remaining at the lowest" {
+		t.Errorf("hostDetail() got = %v", detail)
+	}
+}
+
+func TestNewOwners(t *testing.T) {
+	findings := []audit.Finding{
+		{Check: "canonical", URL: "https://test.com/docs/api", Owners: []string{"@docs-team", "@api-team"}},
+		{Check: "canonical", URL: "https://test.com/docs", Owners: []string{"@docs-team"}},
+		{Check: "canonical", URL: "https://test.com/pricing"},
+	}
+	got := NewOwners(findings)
+	want := []Owner{{Owner: "@api-team", Findings: 1}, {Owner: "@docs-team", Findings: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewOwners() got = %v, want %v", got, want)
+	}
+}
+
+func TestNewThirdParties(t *testing.T) {
+	inventory := []audit.ThirdPartyHost{{
+		Host:      "cdn.com",
+		Resources: map[linkextractor.ResourceType]int{linkextractor.ScriptResource: 2},
+		Pages:     []string{"https://test.com", "https://test.com/a", "https://test.com/b", "https://test.com/c"},
+	}}
+	got := NewThirdParties(inventory)
+	want := []ThirdParty{{Host: "cdn.com", Resources: map[string]int{"script": 2}, Pages: 4, ExamplePages: []string{"https://test.com", "https://test.com/a", "https://test.com/b"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewThirdParties() got = %v, want %v", got, want)
+	}
+}
+
+func TestTextWriter(t *testing.T) {
+	want := `[LINK] Link found: https://test.com/about
+[ERROR] error while crawling [https://test.com/about] err: unexpected status 404 Not Found
+[ERROR] response body of [https://test.com/about]: not here
+[AUDIT] canonical [https://test.com/about]: canonical https://test.com/ redirects (owner=web-team, section=about) (owners: @web-team)
+Total links found: 2
+[HOST] test.com: 2 pages, 1 errors, 120ms average latency, 2048 bytes
+[OWNER] @web-team: 1 findings
+[THIRD PARTY] cdn.com: image 1, script 2, loaded by 2 pages, like https://test.com, https://test.com/about
+[PATTERN] /search?q=*: 10 links found, 5 crawled
+Audit findings: 1
+`
+	if got := writeAll(t, "text"); got != want {
+		t.Errorf("text output got\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	var document Document
+	if err := json.Unmarshal([]byte(writeAll(t, "json")), &document); err != nil {
+		t.Fatalf("json output should be a JSON document. err: %v", err)
+	}
+	if len(document.Links) != 1 || document.Links[0] != "https://test.com/about" {
+		t.Errorf("json links got = %v", document.Links)
+	}
+	if len(document.Errors) != 1 || document.Errors[0].StatusCode != http.StatusNotFound || document.Errors[0].BodySample != "not here" {
+		t.Errorf("json errors got = %+v", document.Errors)
+	}
+	if len(document.Findings) != 1 || document.Findings[0].Check != "canonical" || document.Findings[0].Metadata["owner"] != "web-team" {
+		t.Errorf("json findings got = %+v", document.Findings)
+	}
+	if document.Summary.TotalLinks != 2 || len(document.Summary.Patterns) != 1 || len(document.Summary.Hosts) != 1 || len(document.Summary.ThirdParties) != 1 {
+		t.Errorf("json summary got = %+v", document.Summary)
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(writeAll(t, "ndjson")), "\n")
+	wantTypes := []string{"link", "error", "finding", "summary"}
+	if len(lines) != len(wantTypes) {
+		t.Fatalf("ndjson output got %d lines, want %d", len(lines), len(wantTypes))
+	}
+	for i, line := range lines {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("ndjson line %d should be a JSON object. err: %v", i, err)
+		}
+		if record["type"] != wantTypes[i] {
+			t.Errorf("ndjson line %d type got = %v, want %v", i, record["type"], wantTypes[i])
+		}
+	}
+	if !strings.Contains(lines[1], `"status_code":404`) {
+		t.Errorf("ndjson error line got = %v, want the status code", lines[1])
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	want := `type,url,check,status_code,detail
+link,https://test.com/about,,,
+error,https://test.com/about,,404,unexpected status 404 Not Found
+finding,https://test.com/about,canonical,,"canonical https://test.com/ redirects (owner=web-team, section=about) (owners: @web-team)"
+host,test.com,,,"2 pages, 1 errors, 120ms average latency, 2048 bytes"
+owner,@web-team,,,1 findings
+third_party,cdn.com,,,"image 1, script 2, loaded by 2 pages, like https://test.com, https://test.com/about"
+pattern,/search?q=*,,,"10 links found, 5 crawled"
+summary,,,,"2 links found, 1 findings"
+`
+	if got := writeAll(t, "csv"); got != want {
+		t.Errorf("csv output got\n%v\nwant\n%v", got, want)
+	}
+}
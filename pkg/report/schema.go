@@ -0,0 +1,91 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SchemaVersion is the version of the layout of the JSON documents written by the json format. It's
+// increased with every incompatible change of the layout, along with a migration from the previous one,
+// so the documents stored by older releases remain readable.
+const SchemaVersion = 2
+
+// Document is the JSON document with all the results of a crawl written by the json format.
+type Document struct {
+	SchemaVersion int       `json:"schema_version"`
+	Links         []string  `json:"links"`
+	Errors        []Error   `json:"errors"`
+	Findings      []Finding `json:"findings"`
+	Summary       Summary   `json:"summary"`
+}
+
+// UnsupportedSchemaVersionError is returned when a document was written by a newer release, with a
+// schema version this one doesn't know.
+type UnsupportedSchemaVersionError struct {
+	SchemaVersion int
+}
+
+func (e *UnsupportedSchemaVersionError) Error() string {
+	return fmt.Sprintf("unsupported schema version %d. must be %d or lower", e.SchemaVersion, SchemaVersion)
+}
+
+// migrations upgrade the fields of a document to the next schema version, keyed by the version they
+// upgrade from.
+var migrations = map[int]func(document map[string]json.RawMessage) error{
+	// the documents without a schema version were written before it was added, with the same layout
+	1: func(document map[string]json.RawMessage) error {
+		return nil
+	},
+}
+
+// ReadDocument reads a JSON document written by the json format of any release, migrating it to the
+// current SchemaVersion.
+//
+// Example usage:
+//
+//	file, err := os.Open("results.json")
+//	document, err := report.ReadDocument(file)
+//	fmt.Println(len(document.Links))
+func ReadDocument(r io.Reader) (Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Document{}, err
+	}
+	migrated, err := Migrate(data)
+	if err != nil {
+		return Document{}, err
+	}
+	var document Document
+	if err := json.Unmarshal(migrated, &document); err != nil {
+		return Document{}, err
+	}
+	return document, nil
+}
+
+// Migrate upgrades a JSON document written by the json format of any release to the current
+// SchemaVersion, applying the migrations of every version in between. The documents without a schema
+// version are of the version 1. It fails with an UnsupportedSchemaVersionError if the document is newer.
+func Migrate(data []byte) ([]byte, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	version := 1
+	if rawVersion, ok := document["schema_version"]; ok {
+		if err := json.Unmarshal(rawVersion, &version); err != nil {
+			return nil, fmt.Errorf("invalid schema version: %w", err)
+		}
+	}
+	if version < 1 || version > SchemaVersion {
+		return nil, &UnsupportedSchemaVersionError{SchemaVersion: version}
+	}
+
+	for ; version < SchemaVersion; version++ {
+		if err := migrations[version](document); err != nil {
+			return nil, fmt.Errorf("error migrating schema version %d: %w", version, err)
+		}
+	}
+	document["schema_version"] = json.RawMessage(fmt.Sprint(SchemaVersion))
+	return json.Marshal(document)
+}
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestReadDocument(t *testing.T) {
+	t.Run("reads the documents of the json format", func(t *testing.T) {
+		var output bytes.Buffer
+		writer, _ := NewWriter("json", &output)
+		_ = writer.WriteLink(url.URL{Scheme: "https", Host: "test.com", Path: "/about"})
+		_ = writer.WriteSummary(Summary{TotalLinks: 1})
+
+		document, err := ReadDocument(&output)
+		if err != nil {
+			t.Fatalf("should not throw error at ReadDocument. err: %v", err)
+		}
+		if document.SchemaVersion != SchemaVersion || len(document.Links) != 1 || document.Summary.TotalLinks != 1 {
+			t.Errorf("ReadDocument() got = %+v", document)
+		}
+	})
+
+	t.Run("migrates the documents without a schema version", func(t *testing.T) {
+		legacy := `{"links": ["https://test.com/about"], "errors": [], "findings": [], "summary": {"total_links": 1, "findings": 0}}`
+		document, err := ReadDocument(strings.NewReader(legacy))
+		if err != nil {
+			t.Fatalf("should not throw error at ReadDocument. err: %v", err)
+		}
+		if document.SchemaVersion != SchemaVersion || len(document.Links) != 1 {
+			t.Errorf("ReadDocument() got = %+v, want the migrated document", document)
+		}
+	})
+
+	t.Run("fails with the documents of newer releases", func(t *testing.T) {
+		_, err := ReadDocument(strings.NewReader(`{"schema_version": 99, "links": []}`))
+		var versionErr *UnsupportedSchemaVersionError
+		if !errors.As(err, &versionErr) || versionErr.SchemaVersion != 99 {
+			t.Errorf("ReadDocument() error = %v, want an UnsupportedSchemaVersionError", err)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+package resolver
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+type hostLookuper interface {
+	LookupHost(ctx context.Context, host string) (addrs []string, err error)
+}
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// DefaultTTL is the time the successful lookups are cached for by default, so the DNS changes and
+// failovers of a host are picked up during long crawls.
+const DefaultTTL = 5 * time.Minute
+
+type lookupResult struct {
+	done  chan struct{}
+	addrs []string
+	err   error
+	// expires is when the lookup must be resolved again, zero while it's in flight or if it never expires
+	expires time.Time
+}
+
+// CachingResolver resolves hostnames ahead of time and caches the results, so connection
+// setup of a new host doesn't have to wait for its DNS lookup.
+type CachingResolver struct {
+	lookuper      hostLookuper
+	semaphore     chan struct{}
+	lookupTimeout time.Duration
+	ttl           time.Duration
+	now           func() time.Time
+	mu            sync.Mutex
+	lookups       map[string]*lookupResult
+}
+
+type Option func(resolver *CachingResolver)
+
+// WithLookupTimeout is an option to fail the lookups that take longer than the given timeout, so a
+// slow DNS server fails the connections to a host fast instead of taking the whole timeout of the
+// request. 0 doesn't limit them.
+func WithLookupTimeout(timeout time.Duration) Option {
+	return func(resolver *CachingResolver) {
+		resolver.lookupTimeout = timeout
+	}
+}
+
+// WithTTL is an option to cache the successful lookups for the given time instead of DefaultTTL, after
+// which the host is resolved again. 0 caches them until the resolver is discarded.
+func WithTTL(ttl time.Duration) Option {
+	return func(resolver *CachingResolver) {
+		resolver.ttl = ttl
+	}
+}
+
+// NewCachingResolver creates a new CachingResolver that uses the given lookuper to resolve hosts.
+// maxConcurrentLookups bounds the number of background lookups that can run at the same time.
+// If maxConcurrentLookups is zero or negative, only one lookup runs at a time. The successful lookups
+// are cached for DefaultTTL, see WithTTL.
+func NewCachingResolver(lookuper hostLookuper, maxConcurrentLookups int, opts ...Option) *CachingResolver {
+	if maxConcurrentLookups <= 0 {
+		maxConcurrentLookups = 1
+	}
+	r := &CachingResolver{
+		lookuper:  lookuper,
+		semaphore: make(chan struct{}, maxConcurrentLookups),
+		lookups:   make(map[string]*lookupResult),
+		ttl:       DefaultTTL,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Prefetch starts resolving the given hosts in the background and returns immediately.
+// Hosts that are already resolved, and not expired, or being resolved are ignored.
+func (r *CachingResolver) Prefetch(hosts ...string) {
+	for _, host := range hosts {
+		result, isNew := r.startLookup(host)
+		if !isNew {
+			continue
+		}
+		go r.resolve(context.Background(), host, result)
+	}
+}
+
+// LookupHost returns the addresses of the given host. If the host was prefetched the cached
+// result is returned, waiting for the in-flight lookup if needed.
+func (r *CachingResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	result, isNew := r.startLookup(host)
+	if isNew {
+		// resolved in the background, so the caller stops waiting for a slow lookup once its context is done
+		go r.resolve(ctx, host, result)
+	}
+
+	select {
+	case <-result.done:
+		return result.addrs, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// WrapDialContext returns a dial function that resolves the host of the address using the
+// cached lookups and then dials the resolved IPs using the given dial function, one by one,
+// until a connection succeeds.
+func (r *CachingResolver) WrapDialContext(dial dialFunc) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if net.ParseIP(host) != nil {
+			return dial(ctx, network, addr)
+		}
+
+		ips, err := r.LookupHost(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var lastError error = &net.DNSError{Err: "no addresses found", Name: host, IsNotFound: true}
+		for _, ip := range ips {
+			conn, err := dial(ctx, network, net.JoinHostPort(ip, port))
+			if err != nil {
+				lastError = err
+				continue
+			}
+			return conn, nil
+		}
+		return nil, lastError
+	}
+}
+
+func (r *CachingResolver) startLookup(host string) (*lookupResult, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if result, ok := r.lookups[host]; ok && (result.expires.IsZero() || r.now().Before(result.expires)) {
+		return result, false
+	}
+	result := &lookupResult{done: make(chan struct{})}
+	r.lookups[host] = result
+	return result, true
+}
+
+// resolve looks the host up once one of the concurrent lookups is free, unless the context is done
+// first, which fails the lookup with the error of the context. Once started, the lookup isn't tied to
+// the context, as other callers may be waiting for it.
+func (r *CachingResolver) resolve(ctx context.Context, host string, result *lookupResult) {
+	select {
+	case r.semaphore <- struct{}{}:
+		defer func() { <-r.semaphore }()
+		lookupCtx := context.Background()
+		if r.lookupTimeout > 0 {
+			var cancel context.CancelFunc
+			lookupCtx, cancel = context.WithTimeout(lookupCtx, r.lookupTimeout)
+			defer cancel()
+		}
+		result.addrs, result.err = r.lookuper.LookupHost(lookupCtx, host)
+	case <-ctx.Done():
+		result.err = ctx.Err()
+	}
+	r.mu.Lock()
+	switch {
+	case result.err != nil:
+		// failed lookups are not cached so the next lookup can try again
+		if r.lookups[host] == result {
+			delete(r.lookups, host)
+		}
+	case r.ttl > 0:
+		result.expires = r.now().Add(r.ttl)
+	}
+	r.mu.Unlock()
+	close(result.done)
+}
@@ -0,0 +1,170 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+type mockLookuper struct {
+	mu          sync.Mutex
+	addrs       map[string][]string
+	throwError  error
+	lookupCalls map[string]int
+}
+
+func newMockLookuper(throwError error) *mockLookuper {
+	return &mockLookuper{
+		addrs: map[string][]string{
+			"test.com": {"10.0.0.1", "10.0.0.2"},
+		},
+		throwError:  throwError,
+		lookupCalls: make(map[string]int),
+	}
+}
+
+func (m *mockLookuper) LookupHost(_ context.Context, host string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lookupCalls[host]++
+	return m.addrs[host], m.throwError
+}
+
+func (m *mockLookuper) calls(host string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lookupCalls[host]
+}
+
+func TestCachingResolver_LookupHost(t *testing.T) {
+	t.Run("prefetched hosts are resolved only once", func(t *testing.T) {
+		lookuper := newMockLookuper(nil)
+		cachingResolver := NewCachingResolver(lookuper, 2)
+
+		cachingResolver.Prefetch("test.com", "test.com")
+		addrs, err := cachingResolver.LookupHost(context.Background(), "test.com")
+		if err != nil {
+			t.Errorf("should not throw error at LookupHost. err: %v", err)
+		}
+		if len(addrs) != 2 {
+			t.Errorf("LookupHost() got %v addresses, want 2", len(addrs))
+		}
+		_, _ = cachingResolver.LookupHost(context.Background(), "test.com")
+		if calls := lookuper.calls("test.com"); calls != 1 {
+			t.Errorf("LookupHost() resolved host %v times, want 1", calls)
+		}
+	})
+
+	t.Run("failed lookups are not cached", func(t *testing.T) {
+		lookuper := newMockLookuper(errors.New("lookup error"))
+		cachingResolver := NewCachingResolver(lookuper, 1)
+
+		for i := 0; i < 2; i++ {
+			if _, err := cachingResolver.LookupHost(context.Background(), "test.com"); err == nil {
+				t.Errorf("should throw error at LookupHost for mocked lookuper")
+			}
+		}
+		if calls := lookuper.calls("test.com"); calls != 2 {
+			t.Errorf("LookupHost() resolved host %v times, want 2", calls)
+		}
+	})
+
+	t.Run("lookups are resolved again once expired", func(t *testing.T) {
+		lookuper := newMockLookuper(nil)
+		cachingResolver := NewCachingResolver(lookuper, 1, WithTTL(time.Minute))
+		now := time.Now()
+		cachingResolver.now = func() time.Time { return now }
+
+		_, _ = cachingResolver.LookupHost(context.Background(), "test.com")
+		now = now.Add(59 * time.Second)
+		_, _ = cachingResolver.LookupHost(context.Background(), "test.com")
+		if calls := lookuper.calls("test.com"); calls != 1 {
+			t.Errorf("LookupHost() resolved host %v times before the TTL, want 1", calls)
+		}
+		now = now.Add(2 * time.Second)
+		cachingResolver.Prefetch("test.com")
+		_, _ = cachingResolver.LookupHost(context.Background(), "test.com")
+		if calls := lookuper.calls("test.com"); calls != 2 {
+			t.Errorf("LookupHost() resolved host %v times after the TTL, want 2", calls)
+		}
+	})
+
+	t.Run("lookups slower than the lookup timeout fail", func(t *testing.T) {
+		cachingResolver := NewCachingResolver(slowLookuper{}, 1, WithLookupTimeout(10*time.Millisecond))
+
+		if _, err := cachingResolver.LookupHost(context.Background(), "test.com"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("LookupHost() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("lookups waiting for a free lookup end once the context is done", func(t *testing.T) {
+		cachingResolver := NewCachingResolver(slowLookuper{}, 1)
+		// the only lookup is taken by a host that never resolves
+		cachingResolver.Prefetch("slow.com")
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := cachingResolver.LookupHost(ctx, "test.com")
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("LookupHost() error = %v, want %v", err, context.DeadlineExceeded)
+			}
+		case <-time.After(2 * time.Second):
+			t.Error("LookupHost() kept waiting for a free lookup after the context was done")
+		}
+	})
+}
+
+// slowLookuper never resolves a host, waiting until the lookup is canceled.
+type slowLookuper struct{}
+
+func (slowLookuper) LookupHost(ctx context.Context, _ string) ([]string, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestCachingResolver_WrapDialContext(t *testing.T) {
+	t.Run("dials resolved addresses until one succeeds", func(t *testing.T) {
+		cachingResolver := NewCachingResolver(newMockLookuper(nil), 1)
+		var dialedAddrs []string
+		dial := cachingResolver.WrapDialContext(func(_ context.Context, _, addr string) (net.Conn, error) {
+			dialedAddrs = append(dialedAddrs, addr)
+			if addr == "10.0.0.1:443" {
+				return nil, errors.New("connection refused")
+			}
+			conn, _ := net.Pipe()
+			return conn, nil
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		conn, err := dial(ctx, "tcp", "test.com:443")
+		if err != nil {
+			t.Errorf("should not throw error when dialing. err: %v", err)
+		}
+		if conn != nil {
+			_ = conn.Close()
+		}
+		if len(dialedAddrs) != 2 || dialedAddrs[1] != "10.0.0.2:443" {
+			t.Errorf("dial got addresses %v, want [10.0.0.1:443 10.0.0.2:443]", dialedAddrs)
+		}
+	})
+
+	t.Run("returns an error when the host has no addresses", func(t *testing.T) {
+		cachingResolver := NewCachingResolver(newMockLookuper(nil), 1)
+		dial := cachingResolver.WrapDialContext(func(_ context.Context, _, _ string) (net.Conn, error) {
+			return nil, nil
+		})
+		if _, err := dial(context.Background(), "tcp", "unknown.com:443"); err == nil {
+			t.Errorf("should throw error when dialing a host without addresses")
+		}
+	})
+}
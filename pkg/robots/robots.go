@@ -0,0 +1,254 @@
+package robots
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+type rule struct {
+	allow   bool
+	pattern string
+}
+
+type group struct {
+	userAgents []string
+	rules      []rule
+	crawlDelay time.Duration
+}
+
+// Rules holds the parsed contents of a robots.txt file.
+type Rules struct {
+	groups   []group
+	Sitemaps []string
+}
+
+// Policy decides whether a link can be crawled according to the robots.txt file of its host.
+// robots.txt files are fetched lazily the first time a host is checked and cached afterward.
+type Policy struct {
+	fetcher   fetcher.Fetcher
+	userAgent string
+	mu        sync.Mutex
+	rules     map[string]*hostRules
+}
+
+// hostRules holds the rules of a host, fetched once by the first worker that checks the host.
+type hostRules struct {
+	once  sync.Once
+	rules *Rules
+}
+
+// NewPolicy creates a new Policy that fetches robots.txt files using the given fetcher and
+// applies the rules that match the given user agent.
+func NewPolicy(fetcher fetcher.Fetcher, userAgent string) *Policy {
+	return &Policy{fetcher: fetcher, userAgent: userAgent, rules: make(map[string]*hostRules)}
+}
+
+// Allowed reports whether the given link can be crawled. Following RFC 9309, if the robots.txt file
+// of the host responds with a 4xx status, or can't be parsed, every link of that host is allowed, and
+// if it responds with a 5xx status or the host is unreachable, every link of that host is disallowed.
+func (p *Policy) Allowed(link url.URL) bool {
+	return p.RulesFor(link).Allowed(p.userAgent, link)
+}
+
+// DisallowedBy returns the pattern of the robots.txt rule that disallows crawling the given
+// link, and whether the link is disallowed at all.
+func (p *Policy) DisallowedBy(link url.URL) (string, bool) {
+	return p.RulesFor(link).DisallowedBy(p.userAgent, link)
+}
+
+// RulesFor returns the robots.txt rules of the host of the given link, fetching them if they
+// were not fetched yet. The links of other hosts are not held up while the rules are fetched, only
+// the ones of the same host, which wait for them.
+func (p *Policy) RulesFor(link url.URL) *Rules {
+	robotsURL := url.URL{Scheme: link.Scheme, Host: link.Host, Path: "/robots.txt"}
+
+	p.mu.Lock()
+	entry, ok := p.rules[robotsURL.String()]
+	if !ok {
+		entry = &hostRules{}
+		p.rules[robotsURL.String()] = entry
+	}
+	p.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.rules = p.fetchRules(robotsURL)
+	})
+	return entry.rules
+}
+
+func (p *Policy) fetchRules(robotsURL url.URL) *Rules {
+	robotsReader, err := p.fetcher.FetchWebpageContent(robotsURL)
+	if err != nil {
+		var statusErr *fetcher.UnexpectedStatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode >= 400 && statusErr.StatusCode < 500 {
+			// an unavailable robots.txt allows everything
+			return &Rules{}
+		}
+		// an unreachable robots.txt, or a server error, disallows everything
+		return disallowAll()
+	}
+	defer func(robotsReader io.ReadCloser) {
+		_ = robotsReader.Close()
+	}(robotsReader)
+
+	rules, err := Parse(robotsReader)
+	if err != nil {
+		return &Rules{}
+	}
+	return rules
+}
+
+// disallowAll returns the rules that disallow crawling any link of the host.
+func disallowAll() *Rules {
+	return &Rules{groups: []group{{userAgents: []string{"*"}, rules: []rule{{allow: false, pattern: "/"}}}}}
+}
+
+// Parse parses the contents of a robots.txt file. Unknown directives and malformed lines are ignored.
+func Parse(robotsContent io.Reader) (*Rules, error) {
+	rules := &Rules{}
+	var current *group
+	lastLineWasUserAgent := false
+
+	scanner := bufio.NewScanner(robotsContent)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if current == nil || !lastLineWasUserAgent {
+				rules.groups = append(rules.groups, group{})
+				current = &rules.groups[len(rules.groups)-1]
+			}
+			current.userAgents = append(current.userAgents, strings.ToLower(value))
+			lastLineWasUserAgent = true
+			continue
+		case "allow", "disallow":
+			// an empty disallow means everything is allowed, so it adds no rule
+			if current != nil && value != "" {
+				current.rules = append(current.rules, rule{allow: key == "allow", pattern: value})
+			}
+		case "crawl-delay":
+			if seconds, err := strconv.ParseFloat(value, 64); current != nil && err == nil {
+				current.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		case "sitemap":
+			rules.Sitemaps = append(rules.Sitemaps, value)
+		}
+		lastLineWasUserAgent = false
+	}
+
+	return rules, scanner.Err()
+}
+
+// Allowed reports whether the given user agent can crawl the given link. The most specific
+// (longest) matching rule wins, and allow rules win over disallow rules of the same length.
+func (r *Rules) Allowed(userAgent string, link url.URL) bool {
+	_, disallowed := r.DisallowedBy(userAgent, link)
+	return !disallowed
+}
+
+// DisallowedBy returns the pattern of the rule that disallows the given user agent to crawl
+// the given link, and whether the link is disallowed at all, following the same precedence
+// as Allowed. It's useful to group the disallowed links by the section of the site they belong to.
+func (r *Rules) DisallowedBy(userAgent string, link url.URL) (string, bool) {
+	matchingGroup := r.groupFor(userAgent)
+	if matchingGroup == nil {
+		return "", false
+	}
+
+	path := link.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if link.RawQuery != "" {
+		path += "?" + link.RawQuery
+	}
+
+	var winningRule *rule
+	for i, rule := range matchingGroup.rules {
+		if !matchPattern(rule.pattern, path) {
+			continue
+		}
+		if winningRule == nil || len(rule.pattern) > len(winningRule.pattern) ||
+			(len(rule.pattern) == len(winningRule.pattern) && rule.allow) {
+			winningRule = &matchingGroup.rules[i]
+		}
+	}
+	if winningRule == nil || winningRule.allow {
+		return "", false
+	}
+	return winningRule.pattern, true
+}
+
+// CrawlDelay returns the crawl delay requested for the given user agent, or zero if none was requested.
+func (r *Rules) CrawlDelay(userAgent string) time.Duration {
+	matchingGroup := r.groupFor(userAgent)
+	if matchingGroup == nil {
+		return 0
+	}
+	return matchingGroup.crawlDelay
+}
+
+func (r *Rules) groupFor(userAgent string) *group {
+	userAgent = strings.ToLower(userAgent)
+	var wildcardGroup *group
+	for i := range r.groups {
+		for _, groupUserAgent := range r.groups[i].userAgents {
+			if groupUserAgent == "*" {
+				if wildcardGroup == nil {
+					wildcardGroup = &r.groups[i]
+				}
+				continue
+			}
+			if groupUserAgent != "" && strings.Contains(userAgent, groupUserAgent) {
+				return &r.groups[i]
+			}
+		}
+	}
+	return wildcardGroup
+}
+
+// matchPattern matches a robots.txt path pattern supporting the * wildcard and the $ end anchor.
+func matchPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	remaining := path[len(parts[0]):]
+	for _, part := range parts[1:] {
+		i := strings.Index(remaining, part)
+		if i < 0 {
+			return false
+		}
+		remaining = remaining[i+len(part):]
+	}
+	if !anchored {
+		return true
+	}
+	if remaining == "" {
+		return true
+	}
+	// the last part must match at the very end of the path
+	lastPart := parts[len(parts)-1]
+	return len(parts) > 1 && strings.HasSuffix(path, lastPart)
+}
@@ -0,0 +1,191 @@
+package robots
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+const robotsContent = `# robots.txt for test.com
+User-agent: *
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+Crawl-delay: 2
+
+User-agent: BadBot
+User-agent: OtherBadBot
+Disallow: /
+
+Sitemap: https://test.com/sitemap.xml
+`
+
+func TestRules_Allowed(t *testing.T) {
+	rules, err := Parse(strings.NewReader(robotsContent))
+	if err != nil {
+		t.Fatalf("should not throw error at Parse. err: %v", err)
+	}
+	tests := []struct {
+		name      string
+		userAgent string
+		link      string
+		want      bool
+	}{
+		{name: "allows paths without matching rules", userAgent: "website-crawler", link: "https://test.com/contact", want: true},
+		{name: "disallows paths matching a disallow rule", userAgent: "website-crawler", link: "https://test.com/private/data", want: false},
+		{name: "longest matching rule wins", userAgent: "website-crawler", link: "https://test.com/private/public/data", want: true},
+		{name: "supports wildcards and end anchors", userAgent: "website-crawler", link: "https://test.com/docs/file.pdf", want: false},
+		{name: "end anchor doesn't match longer paths", userAgent: "website-crawler", link: "https://test.com/docs/file.pdf.html", want: true},
+		{name: "uses the group of the matching user agent", userAgent: "BadBot/1.0", link: "https://test.com/contact", want: false},
+		{name: "groups can have many user agents", userAgent: "otherbadbot", link: "https://test.com/contact", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, _ := url.Parse(tt.link)
+			if got := rules.Allowed(tt.userAgent, *link); got != tt.want {
+				t.Errorf("Allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRules_DisallowedBy(t *testing.T) {
+	rules, err := Parse(strings.NewReader(robotsContent))
+	if err != nil {
+		t.Fatalf("should not throw error at Parse. err: %v", err)
+	}
+	tests := []struct {
+		name           string
+		link           string
+		wantPattern    string
+		wantDisallowed bool
+	}{
+		{name: "allowed links have no rule", link: "https://test.com/contact", wantPattern: "", wantDisallowed: false},
+		{name: "returns the pattern of the disallow rule", link: "https://test.com/private/data", wantPattern: "/private", wantDisallowed: true},
+		{name: "allow rules override it", link: "https://test.com/private/public/data", wantPattern: "", wantDisallowed: false},
+		{name: "returns wildcard patterns", link: "https://test.com/docs/file.pdf", wantPattern: "/*.pdf$", wantDisallowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, _ := url.Parse(tt.link)
+			gotPattern, gotDisallowed := rules.DisallowedBy("website-crawler", *link)
+			if gotPattern != tt.wantPattern || gotDisallowed != tt.wantDisallowed {
+				t.Errorf("DisallowedBy() = %v, %v, want %v, %v", gotPattern, gotDisallowed, tt.wantPattern, tt.wantDisallowed)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	rules, err := Parse(strings.NewReader(robotsContent))
+	if err != nil {
+		t.Fatalf("should not throw error at Parse. err: %v", err)
+	}
+	if len(rules.Sitemaps) != 1 || rules.Sitemaps[0] != "https://test.com/sitemap.xml" {
+		t.Errorf("Parse() sitemaps got = %v, want [https://test.com/sitemap.xml]", rules.Sitemaps)
+	}
+	if got := rules.CrawlDelay("website-crawler"); got != 2*time.Second {
+		t.Errorf("CrawlDelay() got = %v, want %v", got, 2*time.Second)
+	}
+}
+
+type mockFetcher struct {
+	robotsContent string
+	throwError    error
+	fetchCalls    int
+}
+
+func (m *mockFetcher) FetchWebpageContent(_ url.URL) (io.ReadCloser, error) {
+	m.fetchCalls++
+	return io.NopCloser(strings.NewReader(m.robotsContent)), m.throwError
+}
+
+func TestPolicy_Allowed(t *testing.T) {
+	t.Run("fetches robots.txt once per host", func(t *testing.T) {
+		robotsFetcher := &mockFetcher{robotsContent: robotsContent}
+		policy := NewPolicy(robotsFetcher, "website-crawler")
+
+		privateUrl, _ := url.Parse("https://test.com/private")
+		contactUrl, _ := url.Parse("https://test.com/contact")
+		if policy.Allowed(*privateUrl) {
+			t.Errorf("Allowed() should disallow %v", privateUrl)
+		}
+		if !policy.Allowed(*contactUrl) {
+			t.Errorf("Allowed() should allow %v", contactUrl)
+		}
+		if robotsFetcher.fetchCalls != 1 {
+			t.Errorf("robots.txt fetched %v times, want 1", robotsFetcher.fetchCalls)
+		}
+	})
+
+	t.Run("allows everything when robots.txt is not found", func(t *testing.T) {
+		policy := NewPolicy(&mockFetcher{throwError: &fetcher.UnexpectedStatusError{StatusCode: http.StatusNotFound, Status: "404 Not Found"}}, "website-crawler")
+
+		privateUrl, _ := url.Parse("https://test.com/private")
+		if !policy.Allowed(*privateUrl) {
+			t.Errorf("Allowed() should allow %v", privateUrl)
+		}
+	})
+
+	t.Run("disallows everything when robots.txt fails with a server error", func(t *testing.T) {
+		policy := NewPolicy(&mockFetcher{throwError: &fetcher.UnexpectedStatusError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}}, "website-crawler")
+
+		contactUrl, _ := url.Parse("https://test.com/contact")
+		if policy.Allowed(*contactUrl) {
+			t.Errorf("Allowed() should disallow %v", contactUrl)
+		}
+	})
+
+	t.Run("disallows everything when robots.txt can't be fetched", func(t *testing.T) {
+		policy := NewPolicy(&mockFetcher{throwError: errors.New("error fetching")}, "website-crawler")
+
+		contactUrl, _ := url.Parse("https://test.com/contact")
+		if policy.Allowed(*contactUrl) {
+			t.Errorf("Allowed() should disallow %v", contactUrl)
+		}
+	})
+
+	t.Run("doesn't hold up the other hosts while fetching robots.txt", func(t *testing.T) {
+		slowFetcher := &blockingFetcher{release: make(chan struct{}), started: make(chan struct{}, 2)}
+		policy := NewPolicy(slowFetcher, "website-crawler")
+
+		slowUrl, _ := url.Parse("https://slow.com/contact")
+		done := make(chan bool)
+		go func() { done <- policy.Allowed(*slowUrl) }()
+		<-slowFetcher.started
+
+		fastUrl, _ := url.Parse("https://fast.com/contact")
+		fastDone := make(chan struct{})
+		go func() {
+			policy.Allowed(*fastUrl)
+			close(fastDone)
+		}()
+		<-slowFetcher.started
+		close(slowFetcher.release)
+		<-fastDone
+		if !<-done {
+			t.Errorf("Allowed() should allow %v", slowUrl)
+		}
+	})
+}
+
+// blockingFetcher responds with an empty robots.txt once released, and with a 404 for the fast hosts.
+type blockingFetcher struct {
+	release chan struct{}
+	started chan struct{}
+}
+
+func (b *blockingFetcher) FetchWebpageContent(link url.URL) (io.ReadCloser, error) {
+	b.started <- struct{}{}
+	if link.Host == "fast.com" {
+		return nil, &fetcher.UnexpectedStatusError{URL: link, StatusCode: http.StatusNotFound, Status: "404 Not Found"}
+	}
+	<-b.release
+	return io.NopCloser(strings.NewReader("")), nil
+}
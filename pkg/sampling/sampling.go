@@ -0,0 +1,113 @@
+package sampling
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// InvalidPattern indicates that a parameterized URL pattern is empty or doesn't start with "/".
+var InvalidPattern = errors.New("invalid pattern. must start with /")
+
+// PatternCount reports how many distinct links matched a parameterized pattern and how many
+// of them were sampled to be crawled.
+type PatternCount struct {
+	Pattern string
+	Found   int
+	Sampled int
+}
+
+type pattern struct {
+	raw   string
+	links map[string]bool
+}
+
+// Sampler limits the crawl of parameterized pages, like internal search results (/search?q=*),
+// to a small sample of every URL pattern, so the crawl reports the existence of the pattern
+// instead of enumerating every variant of it.
+type Sampler struct {
+	sampleSize int
+	mu         sync.Mutex
+	patterns   []*pattern
+}
+
+// NewSampler creates a new Sampler that lets sampleSize distinct links of every pattern be crawled.
+// Patterns match the path and query of the links, e.g. "/search?q=*" or "/products/*/reviews",
+// where "*" matches any sequence of characters.
+func NewSampler(sampleSize int, patterns ...string) (*Sampler, error) {
+	s := &Sampler{sampleSize: sampleSize}
+	for _, rawPattern := range patterns {
+		if !strings.HasPrefix(rawPattern, "/") {
+			return nil, InvalidPattern
+		}
+		s.patterns = append(s.patterns, &pattern{raw: rawPattern, links: make(map[string]bool)})
+	}
+	return s, nil
+}
+
+// Sample reports whether the link should be crawled: links that don't match any pattern always are,
+// while only the first sampleSize distinct links of every pattern are. Every matching link is counted.
+func (s *Sampler) Sample(link url.URL) bool {
+	pathAndQuery := link.EscapedPath()
+	if pathAndQuery == "" {
+		pathAndQuery = "/"
+	}
+	if link.RawQuery != "" {
+		pathAndQuery += "?" + link.RawQuery
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.patterns {
+		if !matchPattern(p.raw, pathAndQuery) {
+			continue
+		}
+		if sampled, ok := p.links[link.String()]; ok {
+			return sampled
+		}
+		sampled := len(p.links) < s.sampleSize
+		p.links[link.String()] = sampled
+		return sampled
+	}
+	return true
+}
+
+// Counts returns how many links matched every pattern, in the order the patterns were provided.
+func (s *Sampler) Counts() []PatternCount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make([]PatternCount, len(s.patterns))
+	for i, p := range s.patterns {
+		counts[i] = PatternCount{Pattern: p.raw, Found: len(p.links)}
+		for _, sampled := range p.links {
+			if sampled {
+				counts[i].Sampled++
+			}
+		}
+	}
+	return counts
+}
+
+// matchPattern reports whether the whole value matches the pattern, where "*" matches any
+// sequence of characters.
+func matchPattern(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	if len(parts) == 1 {
+		return value == pattern
+	}
+	remaining := value[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(remaining, part)
+		if i < 0 {
+			return false
+		}
+		remaining = remaining[i+len(part):]
+	}
+	return strings.HasSuffix(remaining, parts[len(parts)-1])
+}
@@ -0,0 +1,70 @@
+package sampling
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestSampler_Sample(t *testing.T) {
+	sampler, err := NewSampler(2, "/search?q=*", "/products/*/reviews")
+	if err != nil {
+		t.Fatalf("should not throw error at NewSampler. err: %v", err)
+	}
+
+	tests := []struct {
+		link string
+		want bool
+	}{
+		{link: "https://test.com/search?q=shoes", want: true},
+		{link: "https://test.com/search?q=hats", want: true},
+		{link: "https://test.com/search?q=socks", want: false},
+		{link: "https://test.com/search?q=shoes", want: true},
+		{link: "https://test.com/search", want: true},
+		{link: "https://test.com/products/1/reviews", want: true},
+		{link: "https://test.com/products/1/reviews/2", want: true},
+		{link: "https://test.com/about", want: true},
+	}
+	for _, tt := range tests {
+		link, _ := url.Parse(tt.link)
+		if got := sampler.Sample(*link); got != tt.want {
+			t.Errorf("Sample(%v) got = %v, want %v", tt.link, got, tt.want)
+		}
+	}
+
+	want := []PatternCount{
+		{Pattern: "/search?q=*", Found: 3, Sampled: 2},
+		{Pattern: "/products/*/reviews", Found: 1, Sampled: 1},
+	}
+	if got := sampler.Counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Counts() got = %v, want %v", got, want)
+	}
+}
+
+func TestNewSampler(t *testing.T) {
+	if _, err := NewSampler(1, "search?q=*"); !errors.Is(err, InvalidPattern) {
+		t.Errorf("NewSampler() error = %v, want %v", err, InvalidPattern)
+	}
+}
+
+func Test_matchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{pattern: "/search", value: "/search", want: true},
+		{pattern: "/search", value: "/search/more", want: false},
+		{pattern: "/tag/*", value: "/tag/go", want: true},
+		{pattern: "/tag/*", value: "/tags", want: false},
+		{pattern: "/*/edit", value: "/posts/1/edit", want: true},
+		{pattern: "/*/edit", value: "/posts/1/edit/history", want: false},
+		{pattern: "/*?page=*", value: "/blog?page=2", want: true},
+	}
+	for _, tt := range tests {
+		if got := matchPattern(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("matchPattern(%v, %v) got = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
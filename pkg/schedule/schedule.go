@@ -0,0 +1,131 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InvalidWindow indicates that a time window is not in the HH:MM-HH:MM format, or it's empty.
+var InvalidWindow = errors.New("invalid window. must be in the HH:MM-HH:MM format")
+
+// Window is a time-of-day window, as offsets from midnight. Windows ending before they start wrap
+// around midnight, like 22:00-02:00.
+type Window struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// contains reports whether the offset from midnight is in the window.
+func (w Window) contains(offset time.Duration) bool {
+	if w.Start < w.End {
+		return w.Start <= offset && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// Schedule confines a crawl to some time-of-day windows in the local time, like the low-traffic hours
+// of the crawled site, so heavy crawls of production sites don't compete with its visitors.
+type Schedule struct {
+	windows []Window
+	now     func() time.Time
+}
+
+// NewSchedule creates a new Schedule open during the given windows.
+func NewSchedule(windows ...Window) *Schedule {
+	return &Schedule{windows: windows, now: time.Now}
+}
+
+// Parse creates a new Schedule from a comma separated list of windows in the HH:MM-HH:MM format,
+// e.g. "01:00-05:00,22:30-23:30". 24:00 is accepted as the end of the day.
+func Parse(windows string) (*Schedule, error) {
+	var parsedWindows []Window
+	for _, rawWindow := range strings.Split(windows, ",") {
+		rawStart, rawEnd, found := strings.Cut(strings.TrimSpace(rawWindow), "-")
+		if !found {
+			return nil, InvalidWindow
+		}
+		start, err := parseTimeOfDay(rawStart)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseTimeOfDay(rawEnd)
+		if err != nil {
+			return nil, err
+		}
+		if start == end || start == 24*time.Hour {
+			return nil, InvalidWindow
+		}
+		parsedWindows = append(parsedWindows, Window{Start: start, End: end})
+	}
+	return NewSchedule(parsedWindows...), nil
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	rawHours, rawMinutes, found := strings.Cut(strings.TrimSpace(value), ":")
+	if !found || len(rawMinutes) != 2 {
+		return 0, InvalidWindow
+	}
+	hours, err := strconv.Atoi(rawHours)
+	if err != nil || hours < 0 || hours > 24 {
+		return 0, InvalidWindow
+	}
+	minutes, err := strconv.Atoi(rawMinutes)
+	if err != nil || minutes < 0 || minutes > 59 || (hours == 24 && minutes > 0) {
+		return 0, InvalidWindow
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+// Open reports whether the time is in any of the windows of the schedule.
+func (s *Schedule) Open(t time.Time) bool {
+	offset := t.Sub(midnight(t))
+	for _, window := range s.windows {
+		if window.contains(offset) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen returns the time the schedule opens next, which is the given time if it's already open.
+func (s *Schedule) NextOpen(t time.Time) time.Time {
+	if s.Open(t) || len(s.windows) == 0 {
+		return t
+	}
+	var next time.Time
+	for _, window := range s.windows {
+		start := midnight(t).Add(window.Start)
+		if start.Before(t) {
+			start = midnight(t.AddDate(0, 0, 1)).Add(window.Start)
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return next
+}
+
+// Wait blocks until the schedule is open, returning early if the context is done.
+func (s *Schedule) Wait(ctx context.Context) {
+	for {
+		now := s.now()
+		next := s.NextOpen(now)
+		if !next.After(now) {
+			return
+		}
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,108 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2024, time.March, 10, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		windows string
+		want    []Window
+		wantErr bool
+	}{
+		{windows: "01:00-05:00", want: []Window{{Start: time.Hour, End: 5 * time.Hour}}},
+		{windows: "22:30-02:00, 12:00-24:00", want: []Window{{Start: 22*time.Hour + 30*time.Minute, End: 2 * time.Hour}, {Start: 12 * time.Hour, End: 24 * time.Hour}}},
+		{windows: "", wantErr: true},
+		{windows: "01:00", wantErr: true},
+		{windows: "1-5", wantErr: true},
+		{windows: "01:00-01:00", wantErr: true},
+		{windows: "25:00-05:00", wantErr: true},
+		{windows: "01:60-05:00", wantErr: true},
+		{windows: "24:00-05:00", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.windows, func(t *testing.T) {
+			got, err := Parse(tt.windows)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got.windows) != len(tt.want) {
+				t.Fatalf("Parse() got = %v, want %v", got.windows, tt.want)
+			}
+			for i := range tt.want {
+				if got.windows[i] != tt.want[i] {
+					t.Errorf("Parse() got = %v, want %v", got.windows, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedule_Open(t *testing.T) {
+	schedule, _ := Parse("01:00-05:00,22:00-00:30")
+	tests := []struct {
+		time time.Time
+		want bool
+	}{
+		{time: at(0, 59), want: false},
+		{time: at(1, 0), want: true},
+		{time: at(4, 59), want: true},
+		{time: at(5, 0), want: false},
+		{time: at(12, 0), want: false},
+		{time: at(23, 0), want: true},
+		{time: at(0, 15), want: true},
+		{time: at(0, 30), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.time.Format("15:04"), func(t *testing.T) {
+			if got := schedule.Open(tt.time); got != tt.want {
+				t.Errorf("Open() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_NextOpen(t *testing.T) {
+	schedule, _ := Parse("01:00-05:00,22:00-23:00")
+	tests := []struct {
+		time time.Time
+		want time.Time
+	}{
+		{time: at(2, 0), want: at(2, 0)},
+		{time: at(12, 0), want: at(22, 0)},
+		{time: at(23, 30), want: at(1, 0).AddDate(0, 0, 1)},
+		{time: at(0, 30), want: at(1, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.time.Format("15:04"), func(t *testing.T) {
+			if got := schedule.NextOpen(tt.time); !got.Equal(tt.want) {
+				t.Errorf("NextOpen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_Wait(t *testing.T) {
+	schedule, _ := Parse("01:00-05:00")
+
+	schedule.now = func() time.Time { return at(2, 0) }
+	schedule.Wait(context.Background())
+
+	schedule.now = func() time.Time { return at(12, 0) }
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	schedule.Wait(ctx)
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("Wait() should return once the context is done, waited %v", elapsed)
+	}
+}
@@ -0,0 +1,176 @@
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	saltSize            = 16
+	keySize             = 32
+	keyDerivationRounds = 100_000
+)
+
+// InvalidCookieFile indicates that the cookie file can't be decrypted, either because it is
+// corrupted or because the passphrase is not the one used to save it.
+var InvalidCookieFile = errors.New("invalid cookie file or passphrase")
+
+type storedCookie struct {
+	URL    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+// Jar is an http.CookieJar that keeps track of every cookie it stores so they can be saved to
+// an encrypted file and loaded back on a later run.
+type Jar struct {
+	jar     *cookiejar.Jar
+	mu      sync.Mutex
+	cookies map[string]storedCookie
+}
+
+func NewJar() *Jar {
+	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	return &Jar{jar: jar, cookies: make(map[string]storedCookie)}
+}
+
+// SetCookies implements the http.CookieJar interface.
+func (j *Jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.jar.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, cookie := range cookies {
+		key := u.Hostname() + "|" + cookie.Domain + "|" + cookie.Path + "|" + cookie.Name
+		if cookie.MaxAge < 0 || (!cookie.Expires.IsZero() && cookie.Expires.Before(time.Now())) {
+			delete(j.cookies, key)
+			continue
+		}
+		storedCopy := *cookie
+		if storedCopy.MaxAge > 0 {
+			storedCopy.Expires = time.Now().Add(time.Duration(storedCopy.MaxAge) * time.Second)
+			storedCopy.MaxAge = 0
+		}
+		j.cookies[key] = storedCookie{URL: u.String(), Cookie: &storedCopy}
+	}
+}
+
+// Cookies implements the http.CookieJar interface.
+func (j *Jar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
+
+// Save encrypts the cookies of the jar with the given passphrase and writes them to the file at path.
+func (j *Jar) Save(path string, passphrase string) error {
+	j.mu.Lock()
+	cookies := make([]storedCookie, 0, len(j.cookies))
+	for _, cookie := range j.cookies {
+		cookies = append(cookies, cookie)
+	}
+	j.mu.Unlock()
+
+	plaintext, err := json.Marshal(cookies)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+	gcm, err := newCipher(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+
+	content := append(salt, nonce...)
+	content = gcm.Seal(content, nonce, plaintext, nil)
+	return os.WriteFile(path, content, 0600)
+}
+
+// Load decrypts the cookie file at path with the given passphrase and adds its cookies to the jar.
+// Cookies that expired since they were saved are ignored.
+func (j *Jar) Load(path string, passphrase string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if len(content) < saltSize {
+		return InvalidCookieFile
+	}
+	gcm, err := newCipher(passphrase, content[:saltSize])
+	if err != nil {
+		return err
+	}
+	content = content[saltSize:]
+	if len(content) < gcm.NonceSize() {
+		return InvalidCookieFile
+	}
+	plaintext, err := gcm.Open(nil, content[:gcm.NonceSize()], content[gcm.NonceSize():], nil)
+	if err != nil {
+		return InvalidCookieFile
+	}
+
+	var cookies []storedCookie
+	if err := json.Unmarshal(plaintext, &cookies); err != nil {
+		return InvalidCookieFile
+	}
+	for _, cookie := range cookies {
+		cookieURL, err := url.Parse(cookie.URL)
+		if err != nil || cookie.Cookie == nil {
+			continue
+		}
+		j.SetCookies(cookieURL, []*http.Cookie{cookie.Cookie})
+	}
+	return nil
+}
+
+func newCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(deriveKey([]byte(passphrase), salt))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// deriveKey derives the encryption key from the passphrase using PBKDF2 with HMAC-SHA256 (RFC 8018).
+// As the key is exactly one SHA-256 block long, only the first block of the algorithm is needed.
+func deriveKey(passphrase, salt []byte) []byte {
+	prf := hmac.New(sha256.New, passphrase)
+	blockIndex := make([]byte, 4)
+	binary.BigEndian.PutUint32(blockIndex, 1)
+
+	prf.Write(salt)
+	prf.Write(blockIndex)
+	u := prf.Sum(nil)
+	key := make([]byte, keySize)
+	copy(key, u)
+	for i := 1; i < keyDerivationRounds; i++ {
+		prf.Reset()
+		prf.Write(u)
+		u = prf.Sum(u[:0])
+		for j := range key {
+			key[j] ^= u[j]
+		}
+	}
+	return key
+}
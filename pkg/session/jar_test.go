@@ -0,0 +1,51 @@
+package session
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+)
+
+func TestJar_SaveAndLoad(t *testing.T) {
+	testUrl, _ := url.Parse("https://test.com/admin")
+	cookieFile := filepath.Join(t.TempDir(), "cookies")
+
+	jar := NewJar()
+	jar.SetCookies(testUrl, []*http.Cookie{
+		{Name: "session", Value: "abc123", Path: "/"},
+		{Name: "preferences", Value: "dark", Path: "/", MaxAge: 3600},
+		{Name: "deleted", Value: "", Path: "/", MaxAge: -1},
+	})
+	if err := jar.Save(cookieFile, "passphrase"); err != nil {
+		t.Fatalf("should not throw error at Save. err: %v", err)
+	}
+
+	t.Run("loads the saved cookies", func(t *testing.T) {
+		loadedJar := NewJar()
+		if err := loadedJar.Load(cookieFile, "passphrase"); err != nil {
+			t.Fatalf("should not throw error at Load. err: %v", err)
+		}
+		cookies := loadedJar.Cookies(testUrl)
+		if len(cookies) != 2 {
+			t.Errorf("Cookies() got = %v, want session and preferences cookies", cookies)
+		}
+	})
+
+	t.Run("fails with a wrong passphrase", func(t *testing.T) {
+		loadedJar := NewJar()
+		if err := loadedJar.Load(cookieFile, "wrong passphrase"); !errors.Is(err, InvalidCookieFile) {
+			t.Errorf("Load() error = %v, want %v", err, InvalidCookieFile)
+		}
+	})
+}
+
+func TestDeriveKey(t *testing.T) {
+	// PBKDF2-HMAC-SHA256 test vector with 100000 iterations
+	want := "0394a2ede332c9a13eb82e9b24631604c31df978b4e2f0fbd2c549944f9d79a5"
+	if got := hex.EncodeToString(deriveKey([]byte("password"), []byte("salt"))); got != want {
+		t.Errorf("deriveKey() = %v, want %v", got, want)
+	}
+}
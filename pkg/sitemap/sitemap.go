@@ -0,0 +1,135 @@
+package sitemap
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/andiblas/website-crawler/pkg/fetcher"
+)
+
+// maxSitemapIndexDepth limits how many levels of nested sitemap indexes are followed.
+const maxSitemapIndexDepth = 3
+
+// TooManyNestedIndexes indicates that a sitemap index references other sitemap indexes
+// deeper than the supported nesting level.
+var TooManyNestedIndexes = errors.New("too many nested sitemap indexes")
+
+type location struct {
+	Loc string `xml:"loc"`
+}
+
+type document struct {
+	XMLName  xml.Name
+	URLs     []location `xml:"url"`
+	Sitemaps []location `xml:"sitemap"`
+}
+
+// Seeder retrieves the URLs listed in the sitemaps of a site so they can be used as crawl seeds.
+type Seeder struct {
+	fetcher     fetcher.Fetcher
+	sitemapURLs []url.URL
+}
+
+// NewSeeder creates a new Seeder that downloads sitemaps using the given fetcher. If no sitemap URLs
+// are provided, the /sitemap.xml file at the root of the crawled site is used.
+func NewSeeder(fetcher fetcher.Fetcher, sitemapURLs ...url.URL) *Seeder {
+	return &Seeder{fetcher: fetcher, sitemapURLs: sitemapURLs}
+}
+
+// Seeds returns all the URLs listed in the sitemaps of the site of the given URL.
+// If a sitemap fails to download or parse, the URLs of the rest of the sitemaps are still
+// returned along with the last error found.
+func (s *Seeder) Seeds(siteURL url.URL) ([]url.URL, error) {
+	sitemapURLs := s.sitemapURLs
+	if len(sitemapURLs) == 0 {
+		sitemapURLs = []url.URL{{Scheme: siteURL.Scheme, Host: siteURL.Host, Path: "/sitemap.xml"}}
+	}
+
+	var seeds []url.URL
+	var lastError error
+	for _, sitemapURL := range sitemapURLs {
+		urls, err := Fetch(s.fetcher, sitemapURL)
+		if err != nil {
+			lastError = err
+		}
+		seeds = append(seeds, urls...)
+	}
+	return seeds, lastError
+}
+
+// Fetch downloads the sitemap at the given URL and returns the URLs it lists. Sitemap indexes
+// are followed, and gzip compressed sitemaps are decompressed transparently.
+func Fetch(fetcher fetcher.Fetcher, sitemapURL url.URL) ([]url.URL, error) {
+	return fetchSitemap(fetcher, sitemapURL, 0)
+}
+
+// Parse parses a sitemap or a sitemap index. It returns the page URLs of a sitemap, or the
+// sitemap URLs of a sitemap index.
+func Parse(sitemapContent io.Reader) (pageURLs []url.URL, sitemapURLs []url.URL, err error) {
+	var doc document
+	if err := xml.NewDecoder(sitemapContent).Decode(&doc); err != nil {
+		return nil, nil, err
+	}
+
+	return parseLocations(doc.URLs), parseLocations(doc.Sitemaps), nil
+}
+
+func fetchSitemap(fetcher fetcher.Fetcher, sitemapURL url.URL, indexDepth int) ([]url.URL, error) {
+	if indexDepth > maxSitemapIndexDepth {
+		return nil, TooManyNestedIndexes
+	}
+
+	sitemapReader, err := fetcher.FetchWebpageContent(sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+	defer func(sitemapReader io.ReadCloser) {
+		_ = sitemapReader.Close()
+	}(sitemapReader)
+
+	content, err := decompress(sitemapReader)
+	if err != nil {
+		return nil, err
+	}
+
+	pageURLs, sitemapURLs, err := Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastError error
+	for _, nestedSitemapURL := range sitemapURLs {
+		nestedPageURLs, err := fetchSitemap(fetcher, nestedSitemapURL, indexDepth+1)
+		if err != nil {
+			lastError = err
+		}
+		pageURLs = append(pageURLs, nestedPageURLs...)
+	}
+	return pageURLs, lastError
+}
+
+func decompress(sitemapReader io.Reader) (io.Reader, error) {
+	bufferedReader := bufio.NewReader(sitemapReader)
+	magicNumber, err := bufferedReader.Peek(2)
+	if err != nil || magicNumber[0] != 0x1f || magicNumber[1] != 0x8b {
+		return bufferedReader, nil
+	}
+	return gzip.NewReader(bufferedReader)
+}
+
+func parseLocations(locations []location) []url.URL {
+	var urls []url.URL
+	for _, loc := range locations {
+		parsedUrl, err := url.Parse(strings.TrimSpace(loc.Loc))
+		if err != nil || parsedUrl.Host == "" {
+			continue
+		}
+		urls = append(urls, *parsedUrl)
+	}
+	return urls
+}
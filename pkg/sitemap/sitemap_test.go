@@ -0,0 +1,75 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const (
+	sitemapIndex = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap><loc>https://test.com/sitemap-pages.xml</loc></sitemap>
+	<sitemap><loc>https://test.com/sitemap-blog.xml.gz</loc></sitemap>
+</sitemapindex>`
+	sitemapPages = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>https://test.com/contact</loc></url>
+	<url><loc> https://test.com/about-us </loc></url>
+</urlset>`
+	sitemapBlog = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>https://test.com/blog/hidden-post</loc></url>
+</urlset>`
+)
+
+type mockFetcher struct {
+	sitemaps map[string]string
+}
+
+func (m mockFetcher) FetchWebpageContent(sitemapURL url.URL) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(m.sitemaps[sitemapURL.String()])), nil
+}
+
+func gzipped(content string) string {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	_, _ = writer.Write([]byte(content))
+	_ = writer.Close()
+	return buffer.String()
+}
+
+func TestSeeder_Seeds(t *testing.T) {
+	sitemapFetcher := mockFetcher{sitemaps: map[string]string{
+		"https://test.com/sitemap.xml":         sitemapIndex,
+		"https://test.com/sitemap-pages.xml":   sitemapPages,
+		"https://test.com/sitemap-blog.xml.gz": gzipped(sitemapBlog),
+	}}
+	siteUrl, _ := url.Parse("https://test.com/some/page")
+
+	seeds, err := NewSeeder(sitemapFetcher).Seeds(*siteUrl)
+	if err != nil {
+		t.Fatalf("should not throw error at Seeds. err: %v", err)
+	}
+
+	want := []string{"https://test.com/contact", "https://test.com/about-us", "https://test.com/blog/hidden-post"}
+	if len(seeds) != len(want) {
+		t.Fatalf("Seeds() got = %v, want %v", seeds, want)
+	}
+	for i, seed := range seeds {
+		if seed.String() != want[i] {
+			t.Errorf("Seeds() got = %v, want %v", seed.String(), want[i])
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Run("returns an error for invalid sitemaps", func(t *testing.T) {
+		if _, _, err := Parse(strings.NewReader("<html><body>not found</html>")); err == nil {
+			t.Errorf("should throw error at Parse for an invalid sitemap")
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package staticsite
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// NotADirectory indicates that the build output to serve is not a directory.
+var NotADirectory = errors.New("not a directory")
+
+// Server serves the build output directory of a static site generator, like Hugo, Jekyll or
+// Docusaurus, on an ephemeral local listener, so the site can be crawled before deploying it.
+type Server struct {
+	baseURL  url.URL
+	listener net.Listener
+	server   *http.Server
+}
+
+// Serve starts serving the directory on a local listener, as if it was deployed at the base URL,
+// e.g. https://example.com/docs for a site deployed under /docs. Like most static hosts, a
+// directory is served with its index.html, and /about with about.html when there is no /about.
+func Serve(dir string, baseURL url.URL) (*Server, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, NotADirectory
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{baseURL: baseURL, listener: listener, server: &http.Server{Handler: &fileHandler{dir: dir}}}
+	go func() { _ = s.server.Serve(listener) }()
+	return s, nil
+}
+
+// Addr returns the address of the local listener, e.g. 127.0.0.1:51234.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Transport returns a round tripper that sends the requests for the URLs of the site to the local
+// listener, and the rest of the requests through the next round tripper. Set it as the Transport of
+// the client of the fetcher, so the site is crawled at its base URL.
+func (s *Server) Transport(next http.RoundTripper) http.RoundTripper {
+	return &transport{server: s, next: next}
+}
+
+// Close stops serving the directory.
+func (s *Server) Close() error {
+	return s.server.Close()
+}
+
+type transport struct {
+	server *Server
+	next   http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	sitePath, ok := t.server.sitePath(*req.URL)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+	localReq := req.Clone(req.Context())
+	localReq.URL.Scheme = "http"
+	localReq.URL.Host = t.server.Addr()
+	localReq.URL.Path = sitePath
+	localReq.URL.RawPath = ""
+	localReq.Host = t.server.Addr()
+	res, err := t.next.RoundTrip(localReq)
+	if err != nil {
+		return nil, err
+	}
+	// the response is the one of the URL of the site, not of the local listener
+	res.Request = req
+	return res, nil
+}
+
+// sitePath returns the path of the link relative to the root of the site, if the link is a URL of the site.
+func (s *Server) sitePath(link url.URL) (string, bool) {
+	if strings.TrimPrefix(link.Host, "www.") != strings.TrimPrefix(s.baseURL.Host, "www.") {
+		return "", false
+	}
+	basePath := strings.TrimRight(s.baseURL.Path, "/")
+	if link.Path != basePath && !strings.HasPrefix(link.Path, basePath+"/") {
+		return "", false
+	}
+	sitePath := strings.TrimPrefix(link.Path, basePath)
+	if !strings.HasPrefix(sitePath, "/") {
+		sitePath = "/" + sitePath
+	}
+	return sitePath, true
+}
+
+// fileHandler serves the files of the directory, falling back to the .html file of extensionless paths.
+type fileHandler struct {
+	dir string
+}
+
+func (h *fileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cleanPath := path.Clean("/" + r.URL.Path)
+	if _, err := os.Stat(filepath.Join(h.dir, filepath.FromSlash(cleanPath))); errors.Is(err, os.ErrNotExist) && path.Ext(cleanPath) == "" {
+		if _, err := os.Stat(filepath.Join(h.dir, filepath.FromSlash(cleanPath)+".html")); err == nil {
+			http.ServeFile(w, r, filepath.Join(h.dir, filepath.FromSlash(cleanPath)+".html"))
+			return
+		}
+	}
+	http.FileServer(http.Dir(h.dir)).ServeHTTP(w, r)
+}
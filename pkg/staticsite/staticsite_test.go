@@ -0,0 +1,103 @@
+package staticsite
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.html":      "home",
+		"about.html":      "about",
+		"blog/index.html": "blog",
+		"css/site.css":    "body {}",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("should not throw error at MkdirAll. err: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("should not throw error at WriteFile. err: %v", err)
+		}
+	}
+	baseURL, _ := url.Parse("https://example.com/docs")
+	site, err := Serve(dir, *baseURL)
+	if err != nil {
+		t.Fatalf("should not throw error at Serve. err: %v", err)
+	}
+	defer site.Close()
+	client := &http.Client{Transport: site.Transport(http.DefaultTransport)}
+
+	tests := []struct {
+		link       string
+		wantStatus int
+		wantBody   string
+	}{
+		{link: "https://example.com/docs/", wantStatus: http.StatusOK, wantBody: "home"},
+		{link: "https://www.example.com/docs/about", wantStatus: http.StatusOK, wantBody: "about"},
+		{link: "https://example.com/docs/about.html", wantStatus: http.StatusOK, wantBody: "about"},
+		{link: "https://example.com/docs/blog/", wantStatus: http.StatusOK, wantBody: "blog"},
+		{link: "https://example.com/docs/css/site.css", wantStatus: http.StatusOK, wantBody: "body {}"},
+		{link: "https://example.com/docs/missing", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			res, err := client.Get(tt.link)
+			if err != nil {
+				t.Fatalf("should not throw error at Get. err: %v", err)
+			}
+			defer res.Body.Close()
+			body, _ := io.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("Get() status got = %v, want %v", res.StatusCode, tt.wantStatus)
+			}
+			if tt.wantBody != "" && string(body) != tt.wantBody {
+				t.Errorf("Get() body got = %q, want %q", body, tt.wantBody)
+			}
+			if res.Request.URL.Host != "example.com" && res.Request.URL.Host != "www.example.com" {
+				t.Errorf("Get() request URL got = %v, want the URL of the site", res.Request.URL)
+			}
+		})
+	}
+}
+
+func TestServer_sitePath(t *testing.T) {
+	baseURL, _ := url.Parse("https://example.com/docs/")
+	s := &Server{baseURL: *baseURL}
+	tests := []struct {
+		link   string
+		want   string
+		wantOk bool
+	}{
+		{link: "https://example.com/docs", want: "/", wantOk: true},
+		{link: "https://example.com/docs/guide/", want: "/guide/", wantOk: true},
+		{link: "https://www.example.com/docs/guide", want: "/guide", wantOk: true},
+		{link: "https://example.com/docsearch", wantOk: false},
+		{link: "https://example.com/blog", wantOk: false},
+		{link: "https://other.com/docs", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			link, _ := url.Parse(tt.link)
+			got, ok := s.sitePath(*link)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("sitePath() got = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestServe_NotADirectory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "index.html")
+	_ = os.WriteFile(file, []byte("home"), 0o644)
+	baseURL, _ := url.Parse("https://example.com")
+	if _, err := Serve(file, *baseURL); err != NotADirectory {
+		t.Errorf("Serve() err got = %v, want %v", err, NotADirectory)
+	}
+}